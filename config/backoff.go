@@ -0,0 +1,68 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrInvalidBackoff occurs when the base delay or the jitter of a [Backoff] is negative.
+var ErrInvalidBackoff = errors.New("invalid backoff")
+
+// Backoff defines the delay before a connector-level retry. A random jitter up to the Jitter is added
+// to the Base delay, so retries of multiple connectors after a coordinated failure, e.g. a cluster failover,
+// spread out instead of hitting the server at once.
+type Backoff struct {
+	Base   time.Duration
+	Jitter time.Duration
+}
+
+// Validate checks that the base delay and the jitter are not negative.
+func (b Backoff) Validate() error {
+	if b.Base < 0 || b.Jitter < 0 {
+		return fmt.Errorf("%w: the base delay %s and the jitter %s must not be negative",
+			ErrInvalidBackoff, b.Base, b.Jitter)
+	}
+
+	return nil
+}
+
+// Delay returns the base delay with a random jitter, it's between the Base and the Base plus the Jitter.
+func (b Backoff) Delay() time.Duration {
+	if b.Jitter <= 0 {
+		return b.Base
+	}
+
+	//nolint:gosec // the jitter only spreads retries out, it doesn't need a cryptographically secure source
+	return b.Base + rand.N(b.Jitter+1)
+}
+
+// Wait blocks for the [Backoff.Delay], or until the context is done, in which case it returns the context error.
+func (b Backoff) Wait(ctx context.Context) error {
+	timer := time.NewTimer(b.Delay())
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // there's no much to wrap here
+
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	backoff := Backoff{Base: time.Second, Jitter: 500 * time.Millisecond}
+
+	// the delays are within the bounds, and they aren't all the same, so retries spread out
+	delays := make(map[time.Duration]struct{})
+	for i := 0; i < 100; i++ {
+		delay := backoff.Delay()
+		is.True(delay >= backoff.Base)
+		is.True(delay <= backoff.Base+backoff.Jitter)
+
+		delays[delay] = struct{}{}
+	}
+
+	is.True(len(delays) > 1)
+}
+
+func TestBackoff_Delay_noJitter(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.Equal(Backoff{Base: time.Second}.Delay(), time.Second)
+}
+
+func TestBackoff_Validate(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.NoErr(Backoff{Base: time.Second, Jitter: time.Second}.Validate())
+	is.NoErr(Backoff{}.Validate())
+	is.True(errors.Is(Backoff{Base: -time.Second}.Validate(), ErrInvalidBackoff))
+	is.True(errors.Is(Backoff{Jitter: -time.Second}.Validate(), ErrInvalidBackoff))
+}
+
+func TestBackoff_Wait_canceled(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Backoff{Base: time.Hour}.Wait(ctx)
+	is.True(errors.Is(err, context.Canceled))
+}
@@ -15,7 +15,16 @@
 // Package config implements configurations shared between different parts of the connector.
 package config
 
-import "github.com/neo4j/neo4j-go-driver/v5/neo4j"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
 
 const (
 	// KeyURI is a config field name for a connection URI.
@@ -32,6 +41,68 @@ const (
 	KeyAuthPassword = "auth.password"
 	// KeyAuthRealm is a config field name for a basic auth realm.
 	KeyAuthRealm = "auth.realm"
+	// KeyAuthScheme is a config field name for an auth scheme.
+	KeyAuthScheme = "auth.scheme"
+	// KeyAuthToken is a config field name for a bearer auth token.
+	KeyAuthToken = "auth.token"
+	// KeyAuthTicket is a config field name for a kerberos auth ticket.
+	KeyAuthTicket = "auth.ticket"
+	// KeyConnectionLivenessCheckTimeout is a config field name for a connection liveness check timeout.
+	KeyConnectionLivenessCheckTimeout = "connectionLivenessCheckTimeout"
+	// KeyMaxConnectionLifetime is a config field name for a max connection lifetime.
+	KeyMaxConnectionLifetime = "maxConnectionLifetime"
+	// KeyMaxConnectionPoolSize is a config field name for a max connection pool size.
+	KeyMaxConnectionPoolSize = "maxConnectionPoolSize"
+	// KeyWarmupConnections is a config field name for a number of connections opened on start.
+	KeyWarmupConnections = "warmupConnections"
+	// KeyConnectionAcquisitionTimeout is a config field name for a connection acquisition timeout.
+	KeyConnectionAcquisitionTimeout = "connectionAcquisitionTimeout"
+	// KeyConnectionTimeout is a config field name for a connection timeout.
+	KeyConnectionTimeout = "connectionTimeout"
+	// KeyQueryTimeout is a config field name for a query timeout.
+	KeyQueryTimeout = "queryTimeout"
+	// KeyStartupRetryTimeout is a config field name for a startup retry timeout.
+	KeyStartupRetryTimeout = "startupRetryTimeout"
+	// KeySkipDatabaseCheck is a config field name for a skip database check flag.
+	KeySkipDatabaseCheck = "skipDatabaseCheck"
+	// KeyLogRedactProperties is a config field name for a list of properties redacted in logs.
+	KeyLogRedactProperties = "logRedactProperties"
+	// KeyVectorProperties is a config field name for a list of vector properties.
+	KeyVectorProperties = "vectorProperties"
+	// KeyVectorDimensions is a config field name for a number of dimensions of vector properties.
+	KeyVectorDimensions = "vectorDimensions"
+	// KeyResolver is a config field name for a list of resolver entries.
+	KeyResolver = "resolver"
+	// KeyTLSEnabled is a config field name for a TLS enabled flag.
+	KeyTLSEnabled = "tls.enabled"
+	// KeyTLSCAFile is a config field name for a path to a TLS CA file.
+	KeyTLSCAFile = "tls.caFile"
+	// KeyTLSInsecureSkipVerify is a config field name for a TLS insecure skip verify flag.
+	KeyTLSInsecureSkipVerify = "tls.insecureSkipVerify"
+)
+
+var (
+	// ErrNoEntityLabels occurs when the entityLabels contains no non-empty labels.
+	ErrNoEntityLabels = errors.New("entityLabels must contain at least one non-empty label")
+	// ErrInvalidAuth occurs when the auth fields don't match the auth scheme.
+	ErrInvalidAuth = errors.New("invalid auth configuration")
+	// ErrInvalidConnectionPool occurs when the connection pool size or timeouts are out of range.
+	ErrInvalidConnectionPool = errors.New("invalid connection pool configuration")
+	// ErrInvalidQueryTimeout occurs when the queryTimeout is negative.
+	ErrInvalidQueryTimeout = errors.New("queryTimeout must not be negative")
+	// ErrInvalidStartupRetryTimeout occurs when the startupRetryTimeout is negative.
+	ErrInvalidStartupRetryTimeout = errors.New("startupRetryTimeout must not be negative")
+)
+
+// AuthScheme defines a scheme of the authentication.
+type AuthScheme string
+
+// The available auth schemes are listed below.
+const (
+	AuthSchemeBasic    AuthScheme = "basic"
+	AuthSchemeBearer   AuthScheme = "bearer"
+	AuthSchemeKerberos AuthScheme = "kerberos"
+	AuthSchemeNone     AuthScheme = "none"
 )
 
 // EntityType defines a Neo4j entity type.
@@ -50,28 +121,180 @@ type Config struct {
 	// Defines an entity type the connector should work with.
 	EntityType EntityType `json:"entityType" validate:"required,inclusion=node|relationship"`
 	// Holds a list of labels belonging to an entity.
-	EntityLabels []string `json:"entityLabels" validate:"required"`
+	EntityLabels []string `json:"entityLabels"`
 	// The name of a database the connector should work with.
 	Database string `json:"database" default:"neo4j"`
 	// Auth holds auth-specific configurable values.
 	Auth AuthConfig `json:"auth"`
+	// TLS holds configurable values of the connection encryption.
+	TLS TLSConfig `json:"tls"`
+	// The duration after which an idle pooled connection is tested for liveness before it's reused.
+	// If it's not set, idle connections are not tested.
+	ConnectionLivenessCheckTimeout time.Duration `json:"connectionLivenessCheckTimeout"`
+	// The maximum lifetime of a pooled connection, after which it's closed and replaced with a new one.
+	MaxConnectionLifetime time.Duration `json:"maxConnectionLifetime" default:"1h"`
+	// The maximum number of connections in the pool per server.
+	MaxConnectionPoolSize int `json:"maxConnectionPoolSize" default:"100"`
+	// The number of connections opened and verified concurrently on start to pre-fill the pool,
+	// so the first queries don't wait for connections to be established. If it's not set, no connections are
+	// opened in advance.
+	WarmupConnections int `json:"warmupConnections" default:"0"`
+	// The maximum amount of time to wait for a pooled connection to become available,
+	// including the time to establish a new connection.
+	ConnectionAcquisitionTimeout time.Duration `json:"connectionAcquisitionTimeout" default:"1m"`
+	// The maximum amount of time to wait for a TCP connection to a server to be established.
+	ConnectionTimeout time.Duration `json:"connectionTimeout" default:"5s"`
+	// The maximum amount of time a transaction that reads a batch or writes records may run,
+	// after which Neo4j terminates it and the error is treated as a transient one.
+	// If it's not set, the server's default transaction timeout applies.
+	QueryTimeout time.Duration `json:"queryTimeout"`
+	// The maximum amount of time to retry connecting to Neo4j on start with an exponential backoff,
+	// so the connector waits for a server that is still starting up. If it's not set, connecting is not retried.
+	StartupRetryTimeout time.Duration `json:"startupRetryTimeout"`
+	// Determines whether or not the connector will skip checking that the database exists on start.
+	SkipDatabaseCheck bool `json:"skipDatabaseCheck" default:"false"`
+	// Holds a list of property names which values are replaced with "***" in logged queries and parameters.
+	LogRedactProperties []string `json:"logRedactProperties"`
+	// Holds a list of property names which values are vectors, e.g. node embeddings, that are read
+	// and written as lists of floats, including the ones stored as JSON strings.
+	VectorProperties []string `json:"vectorProperties"`
+	// The number of dimensions each of the vectorProperties must have, a vector of another size fails the record.
+	// If it's 0, the dimensions are not validated.
+	VectorDimensions int `json:"vectorDimensions" validate:"gt=-1" default:"0"`
+	// Holds a list of "advertised=actual" address entries, e.g. "neo4j.example.com:7687=10.0.0.1:7687",
+	// the initial address of the uri is resolved to the actual addresses of its entries.
+	// The driver resolves only routed neo4j://, neo4j+s:// and neo4j+ssc:// uris, so it fails with other schemes.
+	Resolver []string `json:"resolver"`
+
+	// resolvedAddresses holds the actual addresses of the resolver entries by their advertised addresses.
+	resolvedAddresses map[string][]neo4j.ServerAddress
+}
+
+// NormalizeEntityLabels trims whitespace around the entity labels and drops the empty ones,
+// as they produce invalid label expressions, e.g. "Person::Writer".
+// It returns the [ErrNoEntityLabels] if no labels are left, the labels are normalized anyway.
+func (c *Config) NormalizeEntityLabels() error {
+	labels := make([]string, 0, len(c.EntityLabels))
+	for _, label := range c.EntityLabels {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	c.EntityLabels = labels
+
+	if len(labels) == 0 {
+		return ErrNoEntityLabels
+	}
+
+	return nil
+}
+
+// NormalizeLabels trims the labels, drops the empty and duplicated ones, and sorts the rest,
+// so the same set of labels is always joined into the same query, whatever order it comes in.
+func NormalizeLabels(labels []string) []string {
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label = strings.TrimSpace(label); label != "" {
+			normalized = append(normalized, label)
+		}
+	}
+
+	slices.Sort(normalized)
+
+	return slices.Compact(normalized)
+}
+
+// Vectors returns the vectorProperties along with their dimensions.
+func (c Config) Vectors() schema.Vectors {
+	return schema.Vectors{Properties: c.VectorProperties, Dimensions: c.VectorDimensions}
 }
 
 // AuthConfig holds auth-specific configurable values.
 type AuthConfig struct {
+	// The scheme of the authentication. If it's "basic", the username, password and realm are used,
+	// or no auth is performed if they're all empty, if it's "bearer", the token is used,
+	// if it's "kerberos", the ticket is used, and if it's "none", no auth is performed.
+	Scheme AuthScheme `json:"scheme" validate:"inclusion=basic|bearer|kerberos|none" default:"basic"`
 	// The username to use when performing basic auth.
 	Username string `json:"username"`
 	// The password to use when performing basic auth.
 	Password string `json:"password"`
 	// The realm to use when performing basic auth.
 	Realm string `json:"realm"`
+	// The token to use when performing bearer auth, e.g. an SSO access token.
+	Token string `json:"token"`
+	// The base64-encoded ticket to use when performing kerberos auth.
+	Ticket string `json:"ticket"`
+}
+
+// Validate checks that only the fields of the auth scheme are set,
+// and the token or the ticket is set if the scheme requires it.
+func (c AuthConfig) Validate() error {
+	var (
+		basic   = c.Username != "" || c.Password != "" || c.Realm != ""
+		invalid string
+	)
+
+	switch c.Scheme {
+	case AuthSchemeBearer:
+		if c.Token == "" {
+			invalid = "the token is required"
+		} else if basic || c.Ticket != "" {
+			invalid = "only the token can be set"
+		}
+
+	case AuthSchemeKerberos:
+		if c.Ticket == "" {
+			invalid = "the ticket is required"
+		} else if basic || c.Token != "" {
+			invalid = "only the ticket can be set"
+		}
+
+	case AuthSchemeNone:
+		if basic || c.Token != "" || c.Ticket != "" {
+			invalid = "no credentials can be set"
+		}
+
+	default:
+		if c.Token != "" || c.Ticket != "" {
+			invalid = "only the username, password and realm can be set"
+		}
+	}
+
+	if invalid != "" {
+		return fmt.Errorf("%w: %s with the %q scheme", ErrInvalidAuth, invalid, c.scheme())
+	}
+
+	return nil
 }
 
 // AuthToken returns [neo4j.AuthToken] based on the [AuthConfig] values.
 func (c AuthConfig) AuthToken() neo4j.AuthToken {
-	if c.Username != "" || c.Password != "" || c.Realm != "" {
-		return neo4j.BasicAuth(c.Username, c.Password, c.Realm)
+	switch c.Scheme {
+	case AuthSchemeBearer:
+		return neo4j.BearerAuth(c.Token)
+
+	case AuthSchemeKerberos:
+		return neo4j.KerberosAuth(c.Ticket)
+
+	case AuthSchemeNone:
+		return neo4j.NoAuth()
+
+	default:
+		if c.Username != "" || c.Password != "" || c.Realm != "" {
+			return neo4j.BasicAuth(c.Username, c.Password, c.Realm)
+		}
+
+		return neo4j.NoAuth()
+	}
+}
+
+// scheme returns the auth scheme, the empty one means the basic auth.
+func (c AuthConfig) scheme() AuthScheme {
+	if c.Scheme == "" {
+		return AuthSchemeBasic
 	}
 
-	return neo4j.NoAuth()
+	return c.Scheme
 }
@@ -15,10 +15,12 @@
 package config
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
 	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
 func TestParseConfig(t *testing.T) {
@@ -74,3 +76,210 @@ func TestParseConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		labels []string
+		want   []string
+	}{
+		{
+			name:   "success_sorted",
+			labels: []string{"Person", "Writer"},
+			want:   []string{"Person", "Writer"},
+		},
+		{
+			name:   "success_unsorted_duplicated",
+			labels: []string{"Writer", " Person", "Writer", "", "Author"},
+			want:   []string{"Author", "Person", "Writer"},
+		},
+		{
+			name:   "success_empty",
+			labels: nil,
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NormalizeLabels(tt.labels); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_NormalizeEntityLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		entityLabels string
+		want         []string
+		wantErr      error
+	}{
+		{
+			name:         "success",
+			entityLabels: "Person,Writer",
+			want:         []string{"Person", "Writer"},
+		},
+		{
+			name:         "success_empty_elements",
+			entityLabels: "Person,,Writer,",
+			want:         []string{"Person", "Writer"},
+		},
+		{
+			name:         "success_whitespace",
+			entityLabels: " Person , ,\tWriter",
+			want:         []string{"Person", "Writer"},
+		},
+		{
+			name:         "fail_empty",
+			entityLabels: "",
+			wantErr:      ErrNoEntityLabels,
+		},
+		{
+			name:         "fail_only_empty_elements",
+			entityLabels: " ,,",
+			wantErr:      ErrNoEntityLabels,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+			if err := sdk.Util.ParseConfig(map[string]string{KeyEntityLabels: tt.entityLabels}, &cfg); err != nil {
+				t.Fatalf("ParseConfig() error = %v", err)
+			}
+
+			err := cfg.NormalizeEntityLabels()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NormalizeEntityLabels() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(cfg.EntityLabels, tt.want) {
+				t.Errorf("NormalizeEntityLabels() = %v, want %v", cfg.EntityLabels, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		auth    AuthConfig
+		wantErr error
+	}{
+		{
+			name: "success_basic",
+			auth: AuthConfig{Scheme: AuthSchemeBasic, Username: "neo4j", Password: "secret"},
+		},
+		{
+			name: "success_legacy_empty_scheme",
+			auth: AuthConfig{Username: "neo4j", Password: "secret"},
+		},
+		{
+			name: "success_bearer",
+			auth: AuthConfig{Scheme: AuthSchemeBearer, Token: "token"},
+		},
+		{
+			name: "success_kerberos",
+			auth: AuthConfig{Scheme: AuthSchemeKerberos, Ticket: "dGlja2V0"},
+		},
+		{
+			name: "success_none",
+			auth: AuthConfig{Scheme: AuthSchemeNone},
+		},
+		{
+			name:    "fail_basic_token",
+			auth:    AuthConfig{Scheme: AuthSchemeBasic, Username: "neo4j", Token: "token"},
+			wantErr: ErrInvalidAuth,
+		},
+		{
+			name:    "fail_bearer_username",
+			auth:    AuthConfig{Scheme: AuthSchemeBearer, Username: "neo4j", Token: "token"},
+			wantErr: ErrInvalidAuth,
+		},
+		{
+			name:    "fail_bearer_no_token",
+			auth:    AuthConfig{Scheme: AuthSchemeBearer},
+			wantErr: ErrInvalidAuth,
+		},
+		{
+			name:    "fail_kerberos_no_ticket",
+			auth:    AuthConfig{Scheme: AuthSchemeKerberos, Token: "token"},
+			wantErr: ErrInvalidAuth,
+		},
+		{
+			name:    "fail_none_password",
+			auth:    AuthConfig{Scheme: AuthSchemeNone, Password: "secret"},
+			wantErr: ErrInvalidAuth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tt.auth.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthConfig_AuthToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		auth AuthConfig
+		want neo4j.AuthToken
+	}{
+		{
+			name: "basic",
+			auth: AuthConfig{Scheme: AuthSchemeBasic, Username: "neo4j", Password: "secret"},
+			want: neo4j.BasicAuth("neo4j", "secret", ""),
+		},
+		{
+			name: "basic_empty",
+			auth: AuthConfig{Scheme: AuthSchemeBasic},
+			want: neo4j.NoAuth(),
+		},
+		{
+			name: "bearer",
+			auth: AuthConfig{Scheme: AuthSchemeBearer, Token: "token"},
+			want: neo4j.BearerAuth("token"),
+		},
+		{
+			name: "kerberos",
+			auth: AuthConfig{Scheme: AuthSchemeKerberos, Ticket: "dGlja2V0"},
+			want: neo4j.KerberosAuth("dGlja2V0"),
+		},
+		{
+			name: "none",
+			auth: AuthConfig{Scheme: AuthSchemeNone},
+			want: neo4j.NoAuth(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.auth.AuthToken(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AuthToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
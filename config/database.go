@@ -0,0 +1,64 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	// verifyDatabaseQuery is a trivial Cypher query that is run against the configured database to check it exists.
+	verifyDatabaseQuery = "RETURN 1"
+	// neo4jDatabaseNotFoundCode is a code of an error that Neo4j returns when the database doesn't exist.
+	neo4jDatabaseNotFoundCode = "Neo.ClientError.Database.DatabaseNotFound"
+)
+
+// ErrDatabaseNotFound occurs when the configured database doesn't exist.
+var ErrDatabaseNotFound = errors.New("database not found")
+
+// VerifyDatabase checks that the configured database exists by running a trivial query against it.
+// It doesn't need access to the system database, so it works for least-privilege accounts as well.
+// The query is retried on start for up to the startupRetryTimeout, as a database of a server
+// that is starting up may be unavailable for a while.
+func (c Config) VerifyDatabase(ctx context.Context, driver neo4j.DriverWithContext) error {
+	err := c.retryOnStartup(ctx, func(ctx context.Context) error {
+		_, err := neo4j.ExecuteQuery(ctx, driver, verifyDatabaseQuery, nil, neo4j.EagerResultTransformer,
+			neo4j.ExecuteQueryWithDatabase(c.Database),
+			neo4j.ExecuteQueryWithReadersRouting(),
+		)
+
+		return err //nolint:wrapcheck // the error is wrapped below
+	})
+	if err != nil {
+		if isDatabaseNotFound(err) {
+			return fmt.Errorf("%w: %q", ErrDatabaseNotFound, c.Database)
+		}
+
+		return fmt.Errorf("execute query: %w", c.RoutingError(err))
+	}
+
+	return nil
+}
+
+// isDatabaseNotFound checks if the error is a Neo4j error about a nonexistent database.
+func isDatabaseNotFound(err error) bool {
+	var neo4jError *neo4j.Neo4jError
+
+	return errors.As(err, &neo4jError) && neo4jError.Code == neo4jDatabaseNotFoundCode
+}
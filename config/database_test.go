@@ -0,0 +1,59 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestIsDatabaseNotFound(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "database_not_found",
+			err:  fmt.Errorf("execute query: %w", &neo4j.Neo4jError{Code: neo4jDatabaseNotFoundCode}),
+			want: true,
+		},
+		{
+			name: "another_neo4j_error",
+			err:  &neo4j.Neo4jError{Code: "Neo.ClientError.Security.Unauthorized"},
+			want: false,
+		},
+		{
+			name: "another_error",
+			err:  errors.New("connection refused"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isDatabaseNotFound(tt.err); got != tt.want {
+				t.Errorf("isDatabaseNotFound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
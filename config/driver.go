@@ -0,0 +1,153 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	// URI schemes of routed connections to a cluster and direct connections to a single instance.
+	routingScheme = "neo4j"
+	directScheme  = "bolt"
+	// routingErrorMessage is a part of messages of errors the driver returns when it fails
+	// to retrieve a routing table, the error type is internal to the driver, so it's matched by the message.
+	routingErrorMessage = "routing table"
+)
+
+// ErrRoutingFailed occurs when the driver fails to retrieve a routing table of a neo4j:// URI,
+// which usually means the URI points to a single instance rather than a cluster.
+var ErrRoutingFailed = errors.New("failed to retrieve the routing table")
+
+// NewDriver creates a new [neo4j.DriverWithContext] based on the [Config] values.
+func (c Config) NewDriver() (neo4j.DriverWithContext, error) {
+	driver, err := neo4j.NewDriverWithContext(c.DriverURI(), c.Auth.AuthToken(), c.DriverConfigurers()...)
+	if err != nil {
+		return nil, fmt.Errorf("new driver with context: %w", err)
+	}
+
+	return driver, nil
+}
+
+// VerifyConnectivity checks that the driver can connect to the server, retrying it on start
+// for up to the startupRetryTimeout, and explains routing failures, see the [Config.RoutingError].
+func (c Config) VerifyConnectivity(ctx context.Context, driver neo4j.DriverWithContext) error {
+	if err := c.retryOnStartup(ctx, driver.VerifyConnectivity); err != nil {
+		return c.RoutingError(err)
+	}
+
+	return nil
+}
+
+// RoutingError wraps the error with the [ErrRoutingFailed] and suggests the bolt:// scheme
+// if the URI is a routed neo4j:// one and the driver failed to retrieve its routing table,
+// otherwise the error is returned as it is.
+func (c Config) RoutingError(err error) error {
+	scheme, address, ok := strings.Cut(c.URI, schemeSeparator)
+	if !ok || !strings.HasPrefix(scheme, routingScheme) ||
+		!strings.Contains(strings.ToLower(err.Error()), routingErrorMessage) {
+		return err
+	}
+
+	// the encryption suffix of the scheme is kept, e.g. neo4j+s:// becomes bolt+s://
+	directURI := directScheme + strings.TrimPrefix(scheme, routingScheme) + schemeSeparator + address
+
+	return fmt.Errorf("%w of %q, if it's a single instance rather than a cluster, use %q instead: %w",
+		ErrRoutingFailed, c.URI, directURI, err,
+	)
+}
+
+// ValidateConnectionPool checks that the connection pool size and timeouts are not negative,
+// zero values keep the driver defaults, and that the warmed up connections fit into the pool.
+func (c Config) ValidateConnectionPool() error {
+	if c.MaxConnectionPoolSize < 0 {
+		return fmt.Errorf("%w: %s must be positive", ErrInvalidConnectionPool, KeyMaxConnectionPoolSize)
+	}
+
+	if c.ConnectionAcquisitionTimeout < 0 {
+		return fmt.Errorf("%w: %s must not be negative", ErrInvalidConnectionPool, KeyConnectionAcquisitionTimeout)
+	}
+
+	if c.ConnectionTimeout < 0 {
+		return fmt.Errorf("%w: %s must not be negative", ErrInvalidConnectionPool, KeyConnectionTimeout)
+	}
+
+	if c.WarmupConnections < 0 {
+		return fmt.Errorf("%w: %s must not be negative", ErrInvalidConnectionPool, KeyWarmupConnections)
+	}
+
+	if c.MaxConnectionPoolSize > 0 && c.WarmupConnections > c.MaxConnectionPoolSize {
+		return fmt.Errorf("%w: %s must not be greater than %s",
+			ErrInvalidConnectionPool, KeyWarmupConnections, KeyMaxConnectionPoolSize,
+		)
+	}
+
+	return nil
+}
+
+// DriverConfigurers returns a list of [neo4j.Config] configurers based on the [Config] values.
+// Zero values are skipped so the driver defaults are kept.
+func (c Config) DriverConfigurers() []func(*neo4j.Config) {
+	var configurers []func(*neo4j.Config)
+
+	if c.ConnectionLivenessCheckTimeout > 0 {
+		configurers = append(configurers, func(cfg *neo4j.Config) {
+			cfg.ConnectionLivenessCheckTimeout = c.ConnectionLivenessCheckTimeout
+		})
+	}
+
+	if c.MaxConnectionLifetime > 0 {
+		configurers = append(configurers, func(cfg *neo4j.Config) {
+			cfg.MaxConnectionLifetime = c.MaxConnectionLifetime
+		})
+	}
+
+	if c.MaxConnectionPoolSize > 0 {
+		configurers = append(configurers, func(cfg *neo4j.Config) {
+			cfg.MaxConnectionPoolSize = c.MaxConnectionPoolSize
+		})
+	}
+
+	if c.ConnectionAcquisitionTimeout > 0 {
+		configurers = append(configurers, func(cfg *neo4j.Config) {
+			cfg.ConnectionAcquisitionTimeout = c.ConnectionAcquisitionTimeout
+		})
+	}
+
+	if c.ConnectionTimeout > 0 {
+		configurers = append(configurers, func(cfg *neo4j.Config) {
+			cfg.SocketConnectTimeout = c.ConnectionTimeout
+		})
+	}
+
+	if len(c.resolvedAddresses) > 0 {
+		configurers = append(configurers, func(cfg *neo4j.Config) {
+			cfg.AddressResolver = c.resolver()
+		})
+	}
+
+	if c.TLS.rootCAs != nil {
+		configurers = append(configurers, func(cfg *neo4j.Config) {
+			cfg.TlsConfig = c.tlsConfig()
+		})
+	}
+
+	return configurers
+}
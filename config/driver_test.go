@@ -0,0 +1,168 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestConfig_DriverConfigurers(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	cfg := Config{
+		ConnectionLivenessCheckTimeout: 30 * time.Second,
+		MaxConnectionLifetime:          10 * time.Minute,
+		MaxConnectionPoolSize:          200,
+		ConnectionAcquisitionTimeout:   2 * time.Minute,
+		ConnectionTimeout:              10 * time.Second,
+	}
+
+	driverConfig := new(neo4j.Config)
+	for _, configurer := range cfg.DriverConfigurers() {
+		configurer(driverConfig)
+	}
+
+	is.Equal(driverConfig.ConnectionLivenessCheckTimeout, 30*time.Second)
+	is.Equal(driverConfig.MaxConnectionLifetime, 10*time.Minute)
+	is.Equal(driverConfig.MaxConnectionPoolSize, 200)
+	is.Equal(driverConfig.ConnectionAcquisitionTimeout, 2*time.Minute)
+	is.Equal(driverConfig.SocketConnectTimeout, 10*time.Second)
+}
+
+func TestConfig_DriverConfigurers_empty(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.Equal(len(Config{}.DriverConfigurers()), 0)
+}
+
+func TestConfig_ValidateConnectionPool(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr error
+	}{
+		{
+			name: "success_defaults",
+		},
+		{
+			name: "success",
+			cfg:  Config{MaxConnectionPoolSize: 50, ConnectionAcquisitionTimeout: time.Minute, ConnectionTimeout: time.Second},
+		},
+		{
+			name:    "fail_pool_size",
+			cfg:     Config{MaxConnectionPoolSize: -1},
+			wantErr: ErrInvalidConnectionPool,
+		},
+		{
+			name:    "fail_acquisition_timeout",
+			cfg:     Config{ConnectionAcquisitionTimeout: -time.Second},
+			wantErr: ErrInvalidConnectionPool,
+		},
+		{
+			name:    "fail_connection_timeout",
+			cfg:     Config{ConnectionTimeout: -time.Second},
+			wantErr: ErrInvalidConnectionPool,
+		},
+		{
+			name: "success_warmup",
+			cfg:  Config{MaxConnectionPoolSize: 10, WarmupConnections: 10},
+		},
+		{
+			name:    "fail_warmup_negative",
+			cfg:     Config{WarmupConnections: -1},
+			wantErr: ErrInvalidConnectionPool,
+		},
+		{
+			name:    "fail_warmup_exceeds_pool",
+			cfg:     Config{MaxConnectionPoolSize: 10, WarmupConnections: 11},
+			wantErr: ErrInvalidConnectionPool,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tt.cfg.ValidateConnectionPool(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateConnectionPool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_RoutingError(t *testing.T) {
+	t.Parallel()
+
+	// the driver's routing table error is internal, so it's simulated by its message
+	routingErr := errors.New("unable to retrieve routing table from localhost:7687: ProcedureNotFound")
+
+	tests := []struct {
+		name        string
+		uri         string
+		err         error
+		wantRouting bool
+		wantMessage string
+	}{
+		{
+			name:        "routed",
+			uri:         "neo4j://localhost:7687",
+			err:         routingErr,
+			wantRouting: true,
+			wantMessage: `use "bolt://localhost:7687" instead`,
+		},
+		{
+			name:        "routed_encrypted",
+			uri:         "neo4j+s://localhost:7687",
+			err:         routingErr,
+			wantRouting: true,
+			wantMessage: `use "bolt+s://localhost:7687" instead`,
+		},
+		{
+			name: "direct",
+			uri:  "bolt://localhost:7687",
+			err:  routingErr,
+		},
+		{
+			name: "another_error",
+			uri:  "neo4j://localhost:7687",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			err := Config{URI: tt.uri}.RoutingError(tt.err)
+			is.True(errors.Is(err, tt.err))
+			is.Equal(errors.Is(err, ErrRoutingFailed), tt.wantRouting)
+			is.True(strings.Contains(err.Error(), tt.wantMessage))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	// resolverMappingSeparator separates an advertised address from an actual one in a resolver entry.
+	resolverMappingSeparator = "="
+	// defaultBoltPort is a port of addresses that don't specify it.
+	defaultBoltPort = "7687"
+)
+
+var (
+	// ErrInvalidResolverEntry occurs when a resolver entry isn't in the "advertised=actual" format.
+	ErrInvalidResolverEntry = errors.New("resolver entry must be in the advertised=actual format")
+	// ErrResolverDirectURI occurs when the resolver is set for a URI of a direct connection,
+	// e.g. bolt://, as the driver resolves only the initial address of routed neo4j:// URIs.
+	ErrResolverDirectURI = errors.New("resolver requires a neo4j://, neo4j+s:// or neo4j+ssc:// uri")
+)
+
+// ValidateResolver parses the resolver entries, so invalid ones fail the configuration,
+// and keeps the parsed addresses for the driver. The resolver of a URI other than a routed neo4j:// one
+// fails the configuration, as the driver would silently ignore it.
+func (c *Config) ValidateResolver() error {
+	if len(c.Resolver) == 0 {
+		return nil
+	}
+
+	if scheme, _, _ := strings.Cut(c.URI, schemeSeparator); !strings.HasPrefix(scheme, routingScheme) {
+		return fmt.Errorf("%w: %q", ErrResolverDirectURI, c.URI)
+	}
+
+	addresses := make(map[string][]neo4j.ServerAddress, len(c.Resolver))
+	for _, entry := range c.Resolver {
+		advertised, actual, ok := strings.Cut(strings.TrimSpace(entry), resolverMappingSeparator)
+		if !ok || strings.TrimSpace(advertised) == "" || strings.TrimSpace(actual) == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidResolverEntry, entry)
+		}
+
+		advertisedKey := addressKey(serverAddress(advertised))
+		addresses[advertisedKey] = append(addresses[advertisedKey], serverAddress(actual))
+	}
+
+	c.resolvedAddresses = addresses
+
+	return nil
+}
+
+// resolver returns the driver address resolver that remaps the advertised addresses to the actual ones,
+// other addresses are resolved to themselves.
+func (c Config) resolver() neo4j.ServerAddressResolver {
+	return func(address neo4j.ServerAddress) []neo4j.ServerAddress {
+		if actual, ok := c.resolvedAddresses[addressKey(address)]; ok {
+			return actual
+		}
+
+		return []neo4j.ServerAddress{address}
+	}
+}
+
+// serverAddress parses the "host:port" address, the port defaults to the default Bolt port.
+func serverAddress(address string) neo4j.ServerAddress {
+	address = strings.TrimSpace(address)
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return neo4j.NewServerAddress(address, defaultBoltPort)
+	}
+
+	return neo4j.NewServerAddress(host, port)
+}
+
+// addressKey returns a key of the address the advertised addresses are looked up by.
+func addressKey(address neo4j.ServerAddress) string {
+	return net.JoinHostPort(address.Hostname(), address.Port())
+}
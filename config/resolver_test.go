@@ -0,0 +1,112 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestConfig_ValidateResolver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		uri      string
+		resolver []string
+		wantErr  error
+	}{
+		{
+			name: "success_empty",
+		},
+		{
+			name:     "success",
+			uri:      "neo4j://neo4j.example.com:7687",
+			resolver: []string{"neo4j.example.com:7687=10.0.0.1:7687", "neo4j.example.com=10.0.0.2"},
+		},
+		{
+			name:     "success_secure",
+			uri:      "neo4j+s://neo4j.example.com:7687",
+			resolver: []string{"neo4j.example.com:7687=10.0.0.1:7687"},
+		},
+		{
+			name: "success_empty_bolt",
+			uri:  "bolt://neo4j.example.com:7687",
+		},
+		{
+			name:     "fail_bolt",
+			uri:      "bolt://neo4j.example.com:7687",
+			resolver: []string{"neo4j.example.com:7687=10.0.0.1:7687"},
+			wantErr:  ErrResolverDirectURI,
+		},
+		{
+			name:     "fail_no_separator",
+			uri:      "neo4j://neo4j.example.com:7687",
+			resolver: []string{"neo4j.example.com:7687"},
+			wantErr:  ErrInvalidResolverEntry,
+		},
+		{
+			name:     "fail_empty_actual",
+			uri:      "neo4j://neo4j.example.com:7687",
+			resolver: []string{"neo4j.example.com:7687= "},
+			wantErr:  ErrInvalidResolverEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{URI: tt.uri, Resolver: tt.resolver}
+			if err := cfg.ValidateResolver(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateResolver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_DriverConfigurers_resolver(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	cfg := &Config{URI: "neo4j://neo4j.example.com:7687", Resolver: []string{
+		"neo4j.example.com:7687=10.0.0.1:7687",
+		"neo4j.example.com=10.0.0.2:7688",
+	}}
+	is.NoErr(cfg.ValidateResolver())
+
+	driverConfig := new(neo4j.Config)
+	for _, configurer := range cfg.DriverConfigurers() {
+		configurer(driverConfig)
+	}
+
+	is.True(driverConfig.AddressResolver != nil)
+
+	// the advertised address is remapped to all its actual addresses,
+	// the missing port of an entry defaults to the Bolt one
+	addresses := driverConfig.AddressResolver(neo4j.NewServerAddress("neo4j.example.com", "7687"))
+	is.Equal(len(addresses), 2)
+	is.Equal(addressKey(addresses[0]), "10.0.0.1:7687")
+	is.Equal(addressKey(addresses[1]), "10.0.0.2:7688")
+
+	// other addresses are resolved to themselves
+	addresses = driverConfig.AddressResolver(neo4j.NewServerAddress("other.example.com", "7687"))
+	is.Equal(len(addresses), 1)
+	is.Equal(addressKey(addresses[0]), "other.example.com:7687")
+}
@@ -0,0 +1,84 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// startupRetryBaseDelay and startupRetryMaxDelay are the first and the max delays
+// between retries on start, the delay doubles after each retry.
+const (
+	startupRetryBaseDelay = 500 * time.Millisecond
+	startupRetryMaxDelay  = 10 * time.Second
+)
+
+// ValidateStartupRetryTimeout checks that the startup retry timeout is not negative, the zero one disables retries.
+func (c Config) ValidateStartupRetryTimeout() error {
+	if c.StartupRetryTimeout < 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidStartupRetryTimeout, c.StartupRetryTimeout)
+	}
+
+	return nil
+}
+
+// retryOnStartup runs the check and retries it with an exponential backoff while it fails with errors
+// that may go away once the server is up, see the [isStartupRetryable], until the startupRetryTimeout elapses,
+// so the connector waits for a server that is still starting up, e.g. in a container started along with it.
+// If the startupRetryTimeout is zero, the check is run once.
+func (c Config) retryOnStartup(ctx context.Context, check func(context.Context) error) error {
+	err := check(ctx)
+	if err == nil || c.StartupRetryTimeout <= 0 {
+		return err
+	}
+
+	deadline := time.Now().Add(c.StartupRetryTimeout)
+
+	for delay := startupRetryBaseDelay; err != nil; delay = min(2*delay, startupRetryMaxDelay) {
+		remaining := time.Until(deadline)
+		if !isStartupRetryable(err) || remaining <= 0 {
+			return fmt.Errorf("retried for %s: %w", c.StartupRetryTimeout, err)
+		}
+
+		delay = min(delay, remaining)
+
+		sdk.Logger(ctx).Warn().Err(err).Dur("delay", delay).Msg("failed to connect to neo4j, retrying")
+
+		if waitErr := (Backoff{Base: delay}).Wait(ctx); waitErr != nil {
+			return fmt.Errorf("wait to retry: %w", waitErr)
+		}
+
+		err = check(ctx)
+	}
+
+	return nil
+}
+
+// isStartupRetryable checks if the error may go away once the server is up, which is the case for all errors
+// but the ones Neo4j returns for invalid requests, e.g. authentication failures, that are not retried.
+func isStartupRetryable(err error) bool {
+	var neo4jError *neo4j.Neo4jError
+	if errors.As(err, &neo4jError) {
+		return neo4jError.IsRetriable()
+	}
+
+	return true
+}
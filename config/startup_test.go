@@ -0,0 +1,139 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// failingCheck returns a check that fails with the err the number of times, or always if it's negative,
+// and counts its calls.
+func failingCheck(err error, times int, calls *int) func(context.Context) error {
+	return func(context.Context) error {
+		*calls++
+		if times < 0 || *calls <= times {
+			return err
+		}
+
+		return nil
+	}
+}
+
+func TestConfig_retryOnStartup(t *testing.T) {
+	t.Parallel()
+
+	unavailableErr := &neo4j.ConnectivityError{Inner: errors.New("connection refused")}
+	unauthorizedErr := &neo4j.Neo4jError{Code: "Neo.ClientError.Security.Unauthorized"}
+
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		err       error
+		failures  int
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "success_retried",
+			timeout:   time.Minute,
+			err:       unavailableErr,
+			failures:  1,
+			wantCalls: 2,
+		},
+		{
+			name:      "success_transient",
+			timeout:   time.Minute,
+			err:       &neo4j.Neo4jError{Code: "Neo.TransientError.General.DatabaseUnavailable"},
+			failures:  1,
+			wantCalls: 2,
+		},
+		{
+			name:      "fail_not_retried",
+			err:       unavailableErr,
+			failures:  1,
+			wantCalls: 1,
+			wantErr:   unavailableErr,
+		},
+		{
+			name:      "fail_not_retryable",
+			timeout:   time.Minute,
+			err:       unauthorizedErr,
+			failures:  1,
+			wantCalls: 1,
+			wantErr:   unauthorizedErr,
+		},
+		{
+			name:      "fail_timeout",
+			timeout:   100 * time.Millisecond,
+			err:       unavailableErr,
+			failures:  -1,
+			wantCalls: 2,
+			wantErr:   unavailableErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			var calls int
+
+			err := Config{StartupRetryTimeout: tt.timeout}.
+				retryOnStartup(context.Background(), failingCheck(tt.err, tt.failures, &calls))
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+			} else {
+				is.NoErr(err)
+			}
+
+			is.Equal(calls, tt.wantCalls)
+		})
+	}
+}
+
+func TestConfig_retryOnStartup_canceled(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls int
+
+	err := Config{StartupRetryTimeout: time.Hour}.
+		retryOnStartup(ctx, failingCheck(&neo4j.ConnectivityError{Inner: context.Canceled}, -1, &calls))
+	is.True(errors.Is(err, context.Canceled))
+	is.Equal(calls, 1)
+}
+
+func TestConfig_ValidateStartupRetryTimeout(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.NoErr(Config{}.ValidateStartupRetryTimeout())
+	is.NoErr(Config{StartupRetryTimeout: time.Minute}.ValidateStartupRetryTimeout())
+	is.True(errors.Is(Config{StartupRetryTimeout: -time.Second}.ValidateStartupRetryTimeout(),
+		ErrInvalidStartupRetryTimeout))
+}
@@ -0,0 +1,62 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// the codes of the errors Neo4j terminates transactions that exceed their timeout with are listed below,
+// the first one is returned by Neo4j 4, the second one by Neo4j 5.
+const (
+	transactionTimedOutCode                    = "Neo.ClientError.Transaction.TransactionTimedOut"
+	transactionTimedOutClientConfigurationCode = "Neo.ClientError.Transaction.TransactionTimedOutClientConfiguration"
+)
+
+// ValidateQueryTimeout checks that the query timeout is not negative, the zero one keeps the server's default.
+func (c Config) ValidateQueryTimeout() error {
+	if c.QueryTimeout < 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidQueryTimeout, c.QueryTimeout)
+	}
+
+	return nil
+}
+
+// TxTimeout returns a transaction config option that makes Neo4j terminate the transaction
+// if it runs longer than the timeout, so a long-running query doesn't hang the connector.
+// The zero timeout leaves the config as it is, so the server's default transaction timeout applies.
+func TxTimeout(timeout time.Duration) func(*neo4j.TransactionConfig) {
+	return func(config *neo4j.TransactionConfig) {
+		if timeout > 0 {
+			config.Timeout = timeout
+		}
+	}
+}
+
+// IsQueryTimeout checks if the error occurred because Neo4j terminated a transaction that exceeded its timeout.
+// The driver doesn't retry such transactions, as the error is a client one,
+// but it's transient, as the query may complete in time once the load of the database is lower.
+func IsQueryTimeout(err error) bool {
+	var neo4jError *neo4j.Neo4jError
+	if !errors.As(err, &neo4jError) {
+		return false
+	}
+
+	return neo4jError.Code == transactionTimedOutCode || neo4jError.Code == transactionTimedOutClientConfigurationCode
+}
@@ -0,0 +1,97 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestTxTimeout(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	txConfig := neo4j.TransactionConfig{Timeout: math.MinInt}
+	TxTimeout(time.Minute)(&txConfig)
+	is.Equal(txConfig.Timeout, time.Minute)
+
+	// the zero timeout keeps the server's default, rather than disabling the timeout
+	txConfig = neo4j.TransactionConfig{Timeout: math.MinInt}
+	TxTimeout(0)(&txConfig)
+	is.Equal(txConfig.Timeout, time.Duration(math.MinInt))
+}
+
+func TestIsQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "timed_out",
+			err:  &neo4j.Neo4jError{Code: "Neo.ClientError.Transaction.TransactionTimedOut"},
+			want: true,
+		},
+		{
+			name: "timed_out_client_configuration",
+			err:  &neo4j.Neo4jError{Code: "Neo.ClientError.Transaction.TransactionTimedOutClientConfiguration"},
+			want: true,
+		},
+		{
+			name: "wrapped",
+			err: fmt.Errorf("load batch: %w",
+				&neo4j.Neo4jError{Code: "Neo.ClientError.Transaction.TransactionTimedOutClientConfiguration"},
+			),
+			want: true,
+		},
+		{
+			name: "other_code",
+			err:  &neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError"},
+		},
+		{
+			name: "not_neo4j",
+			err:  errors.New("test error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			is.Equal(IsQueryTimeout(tt.err), tt.want)
+		})
+	}
+}
+
+func TestConfig_ValidateQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.NoErr(Config{QueryTimeout: time.Minute}.ValidateQueryTimeout())
+	is.NoErr(Config{}.ValidateQueryTimeout())
+	is.True(errors.Is(Config{QueryTimeout: -time.Second}.ValidateQueryTimeout(), ErrInvalidQueryTimeout))
+}
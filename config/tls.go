@@ -0,0 +1,119 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// URI scheme suffixes that make the driver encrypt connections,
+	// with full verification of the server certificate or with trusting a self-signed one.
+	secureSchemeSuffix     = "+s"
+	selfSignedSchemeSuffix = "+ssc"
+	schemeSeparator        = "://"
+)
+
+var (
+	// ErrInvalidCAFile occurs when the tls.caFile doesn't contain any PEM encoded certificates.
+	ErrInvalidCAFile = errors.New("tls.caFile doesn't contain PEM encoded certificates")
+	// ErrTLSNotEnabled occurs when the TLS settings are set, but the connection isn't encrypted.
+	ErrTLSNotEnabled = errors.New("tls.enabled must be true or the uri scheme must end with +s or +ssc")
+)
+
+// TLSConfig holds configurable values of the connection encryption.
+type TLSConfig struct {
+	// Determines whether or not the connector will encrypt the connection.
+	// If it's true, the "bolt" and "neo4j" URI schemes are replaced with "bolt+s" and "neo4j+s".
+	Enabled bool `json:"enabled" default:"false"`
+	// The path to a PEM file with certificates of the authorities the connector trusts,
+	// instead of the system ones, e.g. if the server certificate is signed by a custom CA.
+	CAFile string `json:"caFile"`
+	// Determines whether or not the connector will skip verifying the server certificate.
+	// If it's true, the URI scheme is replaced with "bolt+ssc" or "neo4j+ssc".
+	InsecureSkipVerify bool `json:"insecureSkipVerify" default:"false"`
+
+	// rootCAs holds the certificates loaded from the CAFile.
+	rootCAs *x509.CertPool
+}
+
+// ValidateTLS checks that the TLS settings are applicable to the URI and loads the certificates
+// of the tls.caFile, so the missing or invalid file fails the configuration instead of the connection.
+func (c *Config) ValidateTLS() error {
+	if (c.TLS.CAFile != "" || c.TLS.InsecureSkipVerify) && !c.encrypted() {
+		return ErrTLSNotEnabled
+	}
+
+	if c.TLS.CAFile == "" {
+		return nil
+	}
+
+	caCerts, err := os.ReadFile(c.TLS.CAFile)
+	if err != nil {
+		return fmt.Errorf("read tls.caFile: %w", err)
+	}
+
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caCerts) {
+		return fmt.Errorf("%w: %q", ErrInvalidCAFile, c.TLS.CAFile)
+	}
+
+	c.TLS.rootCAs = rootCAs
+
+	return nil
+}
+
+// DriverURI returns the URI with the scheme that makes the driver encrypt connections
+// according to the TLS settings, as the driver derives the encryption from the scheme.
+func (c Config) DriverURI() string {
+	scheme, address, ok := strings.Cut(c.URI, schemeSeparator)
+	if !ok || !c.encrypted() {
+		return c.URI
+	}
+
+	// the self-signed scheme of the URI is kept, as it already skips the verification
+	selfSigned := c.TLS.InsecureSkipVerify || strings.HasSuffix(scheme, selfSignedSchemeSuffix)
+
+	scheme = strings.TrimSuffix(scheme, selfSignedSchemeSuffix)
+	scheme = strings.TrimSuffix(scheme, secureSchemeSuffix)
+
+	if selfSigned {
+		return scheme + selfSignedSchemeSuffix + schemeSeparator + address
+	}
+
+	return scheme + secureSchemeSuffix + schemeSeparator + address
+}
+
+// encrypted checks if the connection is encrypted, either by the tls.enabled or by the URI scheme.
+func (c Config) encrypted() bool {
+	scheme, _, _ := strings.Cut(c.URI, schemeSeparator)
+
+	return c.TLS.Enabled ||
+		strings.HasSuffix(scheme, secureSchemeSuffix) || strings.HasSuffix(scheme, selfSignedSchemeSuffix)
+}
+
+// tlsConfig returns the [tls.Config] that trusts the certificates of the tls.caFile.
+// The verification of the server certificate is derived by the driver from the URI scheme.
+func (c Config) tlsConfig() *tls.Config {
+	return &tls.Config{
+		RootCAs:    c.TLS.rootCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+}
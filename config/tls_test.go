@@ -0,0 +1,191 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestConfig_DriverURI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		uri  string
+		tls  TLSConfig
+		want string
+	}{
+		{
+			name: "disabled",
+			uri:  "bolt://localhost:7687",
+			want: "bolt://localhost:7687",
+		},
+		{
+			name: "enabled",
+			uri:  "neo4j://localhost:7687",
+			tls:  TLSConfig{Enabled: true},
+			want: "neo4j+s://localhost:7687",
+		},
+		{
+			name: "enabled_insecure_skip_verify",
+			uri:  "bolt://localhost:7687",
+			tls:  TLSConfig{Enabled: true, InsecureSkipVerify: true},
+			want: "bolt+ssc://localhost:7687",
+		},
+		{
+			name: "secure_scheme_insecure_skip_verify",
+			uri:  "neo4j+s://localhost:7687",
+			tls:  TLSConfig{InsecureSkipVerify: true},
+			want: "neo4j+ssc://localhost:7687",
+		},
+		{
+			name: "self_signed_scheme",
+			uri:  "bolt+ssc://localhost:7687",
+			tls:  TLSConfig{Enabled: true},
+			want: "bolt+ssc://localhost:7687",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := (Config{URI: tt.uri, TLS: tt.tls}).DriverURI(); got != tt.want {
+				t.Errorf("DriverURI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateTLS(t *testing.T) {
+	t.Parallel()
+
+	invalidCAFile := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalidCAFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("write invalid ca file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		uri     string
+		tls     TLSConfig
+		wantErr error
+	}{
+		{
+			name: "success_disabled",
+			uri:  "bolt://localhost:7687",
+		},
+		{
+			name: "success_ca_file",
+			uri:  "bolt+s://localhost:7687",
+			tls:  TLSConfig{CAFile: writeCAFile(t)},
+		},
+		{
+			name:    "fail_not_enabled",
+			uri:     "bolt://localhost:7687",
+			tls:     TLSConfig{CAFile: writeCAFile(t)},
+			wantErr: ErrTLSNotEnabled,
+		},
+		{
+			name:    "fail_ca_file_not_exist",
+			uri:     "bolt://localhost:7687",
+			tls:     TLSConfig{Enabled: true, CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+			wantErr: fs.ErrNotExist,
+		},
+		{
+			name:    "fail_invalid_ca_file",
+			uri:     "bolt://localhost:7687",
+			tls:     TLSConfig{Enabled: true, CAFile: invalidCAFile},
+			wantErr: ErrInvalidCAFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{URI: tt.uri, TLS: tt.tls}
+
+			err := cfg.ValidateTLS()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateTLS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_DriverConfigurers_tls(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	cfg := &Config{URI: "bolt://localhost:7687", TLS: TLSConfig{Enabled: true, CAFile: writeCAFile(t)}}
+	is.NoErr(cfg.ValidateTLS())
+
+	driverConfig := new(neo4j.Config)
+	for _, configurer := range cfg.DriverConfigurers() {
+		configurer(driverConfig)
+	}
+
+	is.True(driverConfig.TlsConfig != nil)
+	is.True(driverConfig.TlsConfig.RootCAs.Equal(cfg.TLS.rootCAs))
+}
+
+// writeCAFile writes a PEM file with a self-signed CA certificate and returns its path.
+func writeCAFile(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0o600); err != nil {
+		t.Fatalf("write ca file: %v", err)
+	}
+
+	return path
+}
@@ -0,0 +1,103 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// warmupQuery is a trivial Cypher query that is run in each of the warmed up connections to verify it.
+const warmupQuery = "RETURN 1"
+
+// WarmUpConnections pre-fills the connection pool by opening the configured number of connections concurrently
+// and verifying each of them with a trivial query, so the first queries of the connector don't pay the cost
+// of establishing connections. The access mode routes the connections to the cluster members
+// the queries of the connector are routed to.
+func (c Config) WarmUpConnections(
+	ctx context.Context, driver neo4j.DriverWithContext, accessMode neo4j.AccessMode,
+) error {
+	if c.WarmupConnections <= 0 {
+		return nil
+	}
+
+	var (
+		verified sync.WaitGroup
+		done     sync.WaitGroup
+		errs     = make([]error, c.WarmupConnections)
+	)
+
+	verified.Add(c.WarmupConnections)
+	done.Add(c.WarmupConnections)
+
+	for i := range c.WarmupConnections {
+		go func() {
+			defer done.Done()
+
+			errs[i] = c.warmUpConnection(ctx, driver, accessMode, &verified)
+		}()
+	}
+
+	done.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return c.RoutingError(err)
+	}
+
+	return nil
+}
+
+// warmUpConnection opens a connection by beginning a transaction and verifies it with the warmupQuery.
+// The transaction is held open until all the other connections are verified, so the driver establishes
+// a separate connection for each of them rather than reusing the first one returned to the pool.
+func (c Config) warmUpConnection(
+	ctx context.Context, driver neo4j.DriverWithContext, accessMode neo4j.AccessMode, verified *sync.WaitGroup,
+) error {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: c.Database,
+		AccessMode:   accessMode,
+	})
+	defer session.Close(ctx)
+
+	tx, err := session.BeginTransaction(ctx)
+	if err != nil {
+		verified.Done()
+
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Close(ctx)
+
+	result, err := tx.Run(ctx, warmupQuery, nil)
+	if err == nil {
+		_, err = result.Consume(ctx)
+	}
+
+	verified.Done()
+	verified.Wait()
+
+	if err != nil {
+		return fmt.Errorf("run warm-up query: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/mock"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/mock/gomock"
+)
+
+var errTestWarmup = errors.New("test warmup")
+
+// warmupPool tracks how many transactions of the sessions are open at the same time,
+// the sessions fail to begin transactions after the failAfter number of them, if it's set.
+type warmupPool struct {
+	mu        sync.Mutex
+	configs   []neo4j.SessionConfig
+	open      int
+	maxOpen   int
+	failAfter int
+}
+
+// newWarmupDriver returns a driver which sessions are tracked by the pool.
+func newWarmupDriver(ctrl *gomock.Controller, pool *warmupPool) *mock.MockDriver {
+	driver := mock.NewMockDriver(ctrl)
+	driver.EXPECT().NewSession(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, config neo4j.SessionConfig) neo4j.SessionWithContext {
+			pool.mu.Lock()
+			defer pool.mu.Unlock()
+
+			pool.configs = append(pool.configs, config)
+
+			session, sessionMock := mock.NewSession(ctrl)
+			sessionMock.EXPECT().BeginTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(context.Context, ...func(*neo4j.TransactionConfig)) (neo4j.ExplicitTransaction, error) {
+					return pool.begin(ctrl)
+				},
+			).AnyTimes()
+			sessionMock.EXPECT().Close(gomock.Any()).Return(nil).AnyTimes()
+
+			return session
+		},
+	).AnyTimes()
+
+	return driver
+}
+
+func (p *warmupPool) begin(ctrl *gomock.Controller) (neo4j.ExplicitTransaction, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failAfter > 0 && p.open >= p.failAfter {
+		return nil, errTestWarmup
+	}
+
+	p.open++
+	p.maxOpen = max(p.maxOpen, p.open)
+
+	var closed bool
+	closeTx := func(context.Context) error {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if !closed {
+			closed = true
+			p.open--
+		}
+
+		return nil
+	}
+
+	result, resultMock := mock.NewResult(ctrl)
+	resultMock.EXPECT().Consume(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	tx, txMock := mock.NewExplicitTransaction(ctrl)
+	txMock.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).Return(result, nil).AnyTimes()
+	txMock.EXPECT().Commit(gomock.Any()).DoAndReturn(closeTx).AnyTimes()
+	txMock.EXPECT().Close(gomock.Any()).DoAndReturn(closeTx).AnyTimes()
+
+	return tx, nil
+}
+
+func TestConfig_WarmUpConnections(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctrl := gomock.NewController(t)
+
+	pool := &warmupPool{}
+	cfg := Config{Database: "neo4j", WarmupConnections: 5}
+
+	err := cfg.WarmUpConnections(context.Background(), newWarmupDriver(ctrl, pool), neo4j.AccessModeWrite)
+	is.NoErr(err)
+
+	// all the connections are held at the same time, so the pool has to establish each of them
+	is.Equal(pool.maxOpen, 5)
+	is.Equal(pool.open, 0)
+	is.Equal(len(pool.configs), 5)
+
+	for _, config := range pool.configs {
+		is.Equal(config.DatabaseName, "neo4j")
+		is.Equal(config.AccessMode, neo4j.AccessModeWrite)
+	}
+}
+
+func TestConfig_WarmUpConnections_disabled(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctrl := gomock.NewController(t)
+
+	// the driver doesn't expect any sessions
+	err := Config{}.WarmUpConnections(context.Background(), mock.NewMockDriver(ctrl), neo4j.AccessModeRead)
+	is.NoErr(err)
+}
+
+func TestConfig_WarmUpConnections_fail(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctrl := gomock.NewController(t)
+
+	pool := &warmupPool{failAfter: 3}
+	cfg := Config{WarmupConnections: 5}
+
+	// the verified connections don't wait for the failed ones forever
+	err := cfg.WarmUpConnections(context.Background(), newWarmupDriver(ctrl, pool), neo4j.AccessModeRead)
+	is.True(errors.Is(err, errTestWarmup))
+	is.Equal(pool.open, 0)
+}
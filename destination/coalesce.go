@@ -0,0 +1,113 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destination
+
+import (
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// coalescedBatch holds records of a batch without the create-delete pairs that cancel each other out.
+type coalescedBatch struct {
+	records []sdk.Record
+	// indexes holds the indexes of the records in the original batch.
+	indexes []int
+	// pairs holds the indexes of the cancelled creates and deletes in the original batch.
+	pairs [][2]int
+	size  int
+}
+
+// coalesceCreateDelete drops the creates followed by a delete of the same key within the batch,
+// along with the deletes, as writing them would leave the graph as it was.
+// A pair is only dropped if there are no other records of the key between the create and the delete,
+// and records without keys are never dropped.
+func coalesceCreateDelete(records []sdk.Record) *coalescedBatch {
+	var (
+		pairs   [][2]int
+		dropped = make(map[int]bool)
+		// creates holds the indexes of the creates not followed by other records of their keys yet
+		creates = make(map[string]int)
+	)
+
+	for i, record := range records {
+		if record.Key == nil || len(record.Key.Bytes()) == 0 {
+			continue
+		}
+
+		key := string(record.Key.Bytes())
+
+		switch record.Operation {
+		case sdk.OperationCreate, sdk.OperationSnapshot:
+			creates[key] = i
+
+		case sdk.OperationDelete:
+			if create, ok := creates[key]; ok {
+				pairs = append(pairs, [2]int{create, i})
+				dropped[create] = true
+				dropped[i] = true
+			}
+
+			delete(creates, key)
+
+		default:
+			delete(creates, key)
+		}
+	}
+
+	batch := &coalescedBatch{records: records, pairs: pairs, size: len(records)}
+	if len(pairs) == 0 {
+		return batch
+	}
+
+	batch.records = make([]sdk.Record, 0, len(records)-len(dropped))
+	batch.indexes = make([]int, 0, len(records)-len(dropped))
+
+	for i, record := range records {
+		if !dropped[i] {
+			batch.records = append(batch.records, record)
+			batch.indexes = append(batch.indexes, i)
+		}
+	}
+
+	return batch
+}
+
+// written converts the number of written records of the coalesced batch
+// to the number of processed records of the original batch.
+// A dropped create is only counted if its delete is counted as well,
+// so a failed batch is never acknowledged up to a create that wasn't written.
+func (b *coalescedBatch) written(n int) int {
+	if len(b.pairs) == 0 {
+		return n
+	}
+
+	if n >= len(b.records) {
+		return b.size
+	}
+
+	// moving the number back can split another pair, so it's repeated until no pair is split
+	written := b.indexes[n]
+	for split := true; split; {
+		split = false
+
+		for _, pair := range b.pairs {
+			if pair[0] < written && pair[1] >= written {
+				written = pair[0]
+				split = true
+			}
+		}
+	}
+
+	return written
+}
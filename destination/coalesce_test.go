@@ -0,0 +1,51 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destination
+
+import (
+	"testing"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+func TestCoalescedBatch_written(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	record := func(operation sdk.Operation, name string) sdk.Record {
+		return sdk.Record{Operation: operation, Key: sdk.StructuredData{"name": name}}
+	}
+
+	// 0: the create of Alice, 1: the create of Bob, 2: the create of Carol,
+	// 3: the delete of Alice, 4: the update of Bob, 5: the delete of Carol
+	batch := coalesceCreateDelete([]sdk.Record{
+		record(sdk.OperationCreate, "Alice"),
+		record(sdk.OperationCreate, "Bob"),
+		record(sdk.OperationCreate, "Carol"),
+		record(sdk.OperationDelete, "Alice"),
+		record(sdk.OperationUpdate, "Bob"),
+		record(sdk.OperationDelete, "Carol"),
+	})
+	is.Equal(batch.records, []sdk.Record{record(sdk.OperationCreate, "Bob"), record(sdk.OperationUpdate, "Bob")})
+	is.Equal(batch.pairs, [][2]int{{0, 3}, {2, 5}})
+
+	is.Equal(batch.written(0), 0)
+	// the update of Bob failed, so the create of Carol is not acknowledged, as its delete isn't either,
+	// which in turn leaves the delete of Alice out, so her create is not acknowledged as well
+	is.Equal(batch.written(1), 0)
+	is.Equal(batch.written(2), 6)
+}
@@ -14,9 +14,222 @@
 
 package destination
 
-import "github.com/conduitio-labs/conduit-connector-neo4j/config"
+import (
+	"errors"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/destination/writer"
+)
+
+const (
+	// ConfigKeyAppendProperties is a config name for an appendProperties field.
+	ConfigKeyAppendProperties = "appendProperties"
+	// ConfigKeyEndpointsOnNode is a config name for an endpointsOnNode field.
+	ConfigKeyEndpointsOnNode = "endpointsOnNode"
+	// ConfigKeyEndpointMatchPropertiesSource is a config name for a source endpoint match properties field.
+	ConfigKeyEndpointMatchPropertiesSource = "endpointMatchProperties.source"
+	// ConfigKeyEndpointMatchPropertiesTarget is a config name for a target endpoint match properties field.
+	ConfigKeyEndpointMatchPropertiesTarget = "endpointMatchProperties.target"
+	// ConfigKeyRetryBatch is a config name for a retryBatch field.
+	ConfigKeyRetryBatch = "retryBatch"
+	// ConfigKeyRetryBatchMaxAttempts is a config name for a retryBatchMaxAttempts field.
+	ConfigKeyRetryBatchMaxAttempts = "retryBatchMaxAttempts"
+	// ConfigKeyRetryBatchBackoff is a config name for a retryBatchBackoff field.
+	ConfigKeyRetryBatchBackoff = "retryBatchBackoff"
+	// ConfigKeyRetryBatchJitter is a config name for a retryBatchJitter field.
+	ConfigKeyRetryBatchJitter = "retryBatchJitter"
+	// ConfigKeyLabelConflictBehavior is a config name for a labelConflictBehavior field.
+	ConfigKeyLabelConflictBehavior = "labelConflictBehavior"
+	// ConfigKeyLabelField is a config name for a labelField field.
+	ConfigKeyLabelField = "labelField"
+	// ConfigKeyProcessedAtProperty is a config name for a processedAtProperty field.
+	ConfigKeyProcessedAtProperty = "processedAtProperty"
+	// ConfigKeyMetadataToProperties is a config name for a metadataToProperties field.
+	ConfigKeyMetadataToProperties = "metadataToProperties"
+	// ConfigKeyMetadataPropertyPrefix is a config name for a metadataPropertyPrefix field.
+	ConfigKeyMetadataPropertyPrefix = "metadataPropertyPrefix"
+	// ConfigKeyCreateEndpoints is a config name for a createEndpoints field.
+	ConfigKeyCreateEndpoints = "createEndpoints"
+	// ConfigKeyDetachDelete is a config name for a detachDelete field.
+	ConfigKeyDetachDelete = "detachDelete"
+	// ConfigKeyKeyProperties is a config name for a keyProperties field.
+	ConfigKeyKeyProperties = "keyProperties"
+	// ConfigKeyTransactionSize is a config name for a transactionSize field.
+	ConfigKeyTransactionSize = "transactionSize"
+	// ConfigKeyServerComputedProperties is a config name for a serverComputedProperties field.
+	ConfigKeyServerComputedProperties = "serverComputedProperties"
+	// ConfigKeyCreateConstraints is a config name for a createConstraints field.
+	ConfigKeyCreateConstraints = "createConstraints"
+	// ConfigKeyCoalesceCreateDelete is a config name for a coalesceCreateDelete field.
+	ConfigKeyCoalesceCreateDelete = "coalesceCreateDelete"
+	// ConfigKeyMatchRelationshipsByEndpoints is a config name for a matchRelationshipsByEndpoints field.
+	ConfigKeyMatchRelationshipsByEndpoints = "matchRelationshipsByEndpoints"
+	// ConfigKeyStrictCardinality is a config name for a strictCardinality field.
+	ConfigKeyStrictCardinality = "strictCardinality"
+	// ConfigKeySkipUnchanged is a config name for a skipUnchanged field.
+	ConfigKeySkipUnchanged = "skipUnchanged"
+	// ConfigKeyRemoveNullProperties is a config name for a removeNullProperties field.
+	ConfigKeyRemoveNullProperties = "removeNullProperties"
+	// ConfigKeyEntityTypeFromMetadata is a config name for an entityTypeFromMetadata field.
+	ConfigKeyEntityTypeFromMetadata = "entityTypeFromMetadata"
+	// ConfigKeyMaxLabels is a config name for a maxLabels field.
+	ConfigKeyMaxLabels = "maxLabels"
+	// ConfigKeyMaxProperties is a config name for a maxProperties field.
+	ConfigKeyMaxProperties = "maxProperties"
+	// ConfigKeyOnError is a config name for an onError field.
+	ConfigKeyOnError = "onError"
+	// ConfigKeyCountMutations is a config name for a countMutations field.
+	ConfigKeyCountMutations = "countMutations"
+	// ConfigKeyNestedObjects is a config name for a nestedObjects field.
+	ConfigKeyNestedObjects = "nestedObjects"
+	// ConfigKeyNestedObjectsFallback is a config name for a nestedObjectsFallback field.
+	ConfigKeyNestedObjectsFallback = "nestedObjectsFallback"
+	// ConfigKeyWriteRateLimit is a config name for a writeRateLimit field.
+	ConfigKeyWriteRateLimit = "writeRateLimit"
+	// ConfigKeySkipPermissionCheck is a config name for a skipPermissionCheck field.
+	ConfigKeySkipPermissionCheck = "skipPermissionCheck"
+)
+
+var (
+	// errConstraintsNoKeyProperties occurs when the createConstraints is enabled but the keyProperties is empty.
+	errConstraintsNoKeyProperties = errors.New("createConstraints requires keyProperties")
+	// errConstraintsNoEntityLabels occurs when the createConstraints is enabled but only the labelField is set,
+	// as the labels of the constraints aren't known in advance.
+	errConstraintsNoEntityLabels = errors.New("createConstraints requires entityLabels")
+	// errNoLabels occurs when neither the entityLabels nor the labelField is set.
+	errNoLabels = errors.New("entityLabels or labelField must be set")
+	// errEntityLabelsExceedMaxLabels occurs when the configured entityLabels alone exceed the maxLabels,
+	// so every record would be rejected.
+	errEntityLabelsExceedMaxLabels = errors.New("entityLabels exceed maxLabels")
+	// errNegativeWriteRateLimit occurs when the writeRateLimit is negative.
+	errNegativeWriteRateLimit = errors.New("writeRateLimit must not be negative")
+)
 
 // Config holds configurable values specific to destination.
 type Config struct {
 	config.Config
+
+	// The list of property names which values are appended to a list property on updates
+	// instead of overwriting it. The properties must not be a part of a record key.
+	AppendProperties []string `json:"appendProperties"`
+	// Determines what to do if the entityType is node but the payload of a create record contains
+	// the relationship-specific sourceNode or targetNode fields.
+	// If it's "strip", the fields are removed with a warning, if it's "error", the record fails.
+	// Updates of nodes always strip the fields silently.
+	EndpointsOnNode writer.EndpointsOnNode `json:"endpointsOnNode" validate:"inclusion=strip|error" default:"error"`
+	// EndpointMatchProperties holds properties that are used to match relationship endpoints.
+	EndpointMatchProperties EndpointMatchPropertiesConfig `json:"endpointMatchProperties"`
+	// Determines whether or not the connector will replay the whole batch of records if it fails transiently.
+	// Records written before the failure are written again, so it's only safe if writes are idempotent.
+	RetryBatch bool `json:"retryBatch" default:"false"`
+	// The maximum number of attempts to write a batch of records, including the first one.
+	RetryBatchMaxAttempts int `json:"retryBatchMaxAttempts" validate:"gt=0" default:"3"`
+	// The duration to wait before replaying a batch of records.
+	RetryBatchBackoff time.Duration `json:"retryBatchBackoff" default:"1s"`
+	// The max random duration added to the retryBatchBackoff, so replays of multiple connectors
+	// after a coordinated failure, e.g. a cluster failover, spread out.
+	RetryBatchJitter time.Duration `json:"retryBatchJitter" default:"500ms"`
+	// Determines what to do if the record metadata field "neo4j.entityLabels" contains labels
+	// that differ from the entityLabels. If it's "configWins", the entityLabels are used,
+	// if it's "metadataWins", the labels from the metadata are used, if it's "merge", both are used,
+	// and if it's "error", the record fails.
+	LabelConflictBehavior writer.LabelConflictBehavior `json:"labelConflictBehavior" validate:"inclusion=metadataWins|configWins|merge|error" default:"configWins"` //nolint:lll // the tag is long
+	// The name of a payload field which holds the labels of each record's element, a string of labels
+	// separated by ":" or a list of them. The field isn't written as a property.
+	// Records without the field are written with the entityLabels.
+	LabelField string `json:"labelField"`
+	// The name of a property that is set to the current time on each create and update,
+	// so it reflects when the connector processed a record. If it's empty, no property is set.
+	ProcessedAtProperty string `json:"processedAtProperty"`
+	// The record metadata fields which values are written as properties on each create and update,
+	// e.g. "source.region". The properties are named by the fields with the metadataPropertyPrefix.
+	// The fields reserved for internal use, which start with "opencdc.", "conduit." or "neo4j.", are skipped.
+	MetadataToProperties []string `json:"metadataToProperties"`
+	// The prefix of the names of the properties the metadataToProperties are written to,
+	// so they don't collide with the payload properties.
+	MetadataPropertyPrefix string `json:"metadataPropertyPrefix" default:"metadata_"`
+	// Determines whether or not the connector will create relationship endpoints along with relationships
+	// from their labels, key and properties instead of matching existing nodes.
+	CreateEndpoints bool `json:"createEndpoints" default:"false"`
+	// Determines whether or not the connector will delete relationships of a node along with the node.
+	// If it's false, deleting a node that still has relationships fails.
+	DetachDelete bool `json:"detachDelete" default:"true"`
+	// The list of property names that are used to derive a key from the payload of an update or delete record
+	// if the record has no key. If it's empty, such records fail.
+	KeyProperties []string `json:"keyProperties"`
+	// The maximum number of records written in a single transaction. If it's 0,
+	// all records of a batch are written in a single transaction.
+	TransactionSize int `json:"transactionSize" default:"0"`
+	// The properties which values are computed server-side on each create and update
+	// instead of being taken from the payload, e.g. "serverComputedProperties.id" set to "randomUUID()".
+	// The allowed functions are randomUUID, timestamp, datetime, localdatetime, date, time and localtime.
+	ServerComputedProperties map[string]string `json:"serverComputedProperties"`
+	// Determines whether or not the connector will create uniqueness constraints of the keyProperties
+	// on open if they don't exist, so concurrent writes cannot create duplicate elements.
+	// Relationship constraints require Neo4j 5.7 or later and are skipped on older servers.
+	CreateConstraints bool `json:"createConstraints" default:"false"`
+	// Determines whether or not the connector will drop a create followed by a delete of the same key
+	// within a batch, so neither of them is written. The pair is kept if other records of the key are between them.
+	CoalesceCreateDelete bool `json:"coalesceCreateDelete" default:"false"`
+	// Determines whether or not the connector will match relationships on updates and deletes
+	// by the keys of their sourceNode and targetNode along with their own key.
+	// Deletes take the endpoints from the payload before the change.
+	MatchRelationshipsByEndpoints bool `json:"matchRelationshipsByEndpoints" default:"false"`
+	// Determines whether or not the connector will fail an update or delete that affects
+	// not exactly one element, e.g. if the key properties don't identify elements uniquely.
+	StrictCardinality bool `json:"strictCardinality" default:"false"`
+	// Determines whether or not the connector will set properties of an update only if any of them
+	// differs from the current state of the element, so unchanged updates don't produce writes.
+	SkipUnchanged bool `json:"skipUnchanged" default:"false"`
+	// Determines whether or not the connector will remove the properties that are null in the payload of an update
+	// from the element. If it's false, null properties are ignored and the element keeps their current values.
+	RemoveNullProperties bool `json:"removeNullProperties" default:"false"`
+	// Determines whether or not the connector will take the entity type of each record from
+	// the "neo4j.entityType" metadata, falling back to the entityType if it's missing.
+	// Records of the other entity type are written with the labels from the "neo4j.entityLabels" metadata.
+	EntityTypeFromMetadata bool `json:"entityTypeFromMetadata" default:"false"`
+	// The max number of labels of a written node, including the labels from metadata and of relationship endpoints
+	// created with the createEndpoints. Records exceeding it fail. If it's 0, the number is unlimited.
+	MaxLabels int `json:"maxLabels" validate:"gt=-1" default:"0"`
+	// The max number of payload properties of a written element, including relationship endpoints
+	// created with the createEndpoints. Records exceeding it fail. If it's 0, the number is unlimited.
+	MaxProperties int `json:"maxProperties" validate:"gt=-1" default:"0"`
+	// Determines what to do if a record fails with a permanent error, e.g. a malformed payload
+	// or a constraint violation. If it's "stop", the write fails, if it's "skip", the record is logged and skipped.
+	// Transient errors always fail the write.
+	OnError writer.OnError `json:"onError" validate:"inclusion=stop|skip" default:"stop"`
+	// Determines whether or not the connector will aggregate the counters of graph mutations Neo4j reports
+	// for each batch, i.e. created and deleted nodes and relationships, set properties, added and removed labels,
+	// and log them along with the number of written records.
+	CountMutations bool `json:"countMutations" default:"false"`
+	// Determines how nested objects of payloads are written, as Neo4j cannot store maps as property values.
+	// If it's "keep", they're written as they are, so records with them fail. If it's "json", they're serialized
+	// into JSON string properties, and if it's "flatten", their properties are written as separate properties
+	// with prefixed names, e.g. "address.city". If it's "apoc", they're serialized server-side
+	// with apoc.convert.toJson, or with the nestedObjectsFallback if the APOC isn't installed.
+	NestedObjects writer.NestedObjects `json:"nestedObjects" validate:"inclusion=keep|json|flatten|apoc" default:"keep"`
+	// Determines how nested objects are written if the nestedObjects is "apoc" but the APOC isn't installed.
+	NestedObjectsFallback writer.NestedObjects `json:"nestedObjectsFallback" validate:"inclusion=json|flatten" default:"json"` //nolint:lll // the tag is long
+	// The max number of records written per second, which can be fractional, e.g. 0.5 permits a record
+	// every two seconds, so the writes don't overwhelm a shared cluster. Each transaction waits until
+	// all of its records are permitted before it starts. If it's 0, the writes are not limited.
+	WriteRateLimit float64 `json:"writeRateLimit" default:"0"`
+	// Determines whether or not the connector will skip checking on open that the user can write
+	// the entityLabels into the database, which creates an element in a transaction that is rolled back.
+	SkipPermissionCheck bool `json:"skipPermissionCheck" default:"false"`
+}
+
+// EndpointMatchPropertiesConfig holds lists of endpoint key properties any of which is enough
+// to match a relationship endpoint. If a list is empty, the whole endpoint key must match.
+type EndpointMatchPropertiesConfig struct {
+	// The list of source node key properties any of which is enough to match the source node.
+	Source []string `json:"source"`
+	// The list of target node key properties any of which is enough to match the target node.
+	Target []string `json:"target"`
+}
+
+// retryBatchBackoff returns the [config.Backoff] of batch replays.
+func (c Config) retryBatchBackoff() config.Backoff {
+	return config.Backoff{Base: c.RetryBatchBackoff, Jitter: c.RetryBatchJitter}
 }
@@ -17,6 +17,7 @@ package destination
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/conduitio-labs/conduit-connector-neo4j/destination/writer"
@@ -26,7 +27,7 @@ import (
 
 // Writer is a writer interface needed for the [Destination].
 type Writer interface {
-	Write(ctx context.Context, record sdk.Record) error
+	Write(ctx context.Context, records []sdk.Record) (int, error)
 }
 
 // Destination Neo4j Connector persists records to a Neo4j.
@@ -49,45 +50,213 @@ func (d *Destination) Parameters() map[string]sdk.Parameter {
 }
 
 // Configure parses and initializes the [Destination] config.
-func (d *Destination) Configure(_ context.Context, raw map[string]string) error {
+func (d *Destination) Configure(ctx context.Context, raw map[string]string) error {
 	if err := sdk.Util.ParseConfig(raw, &d.config); err != nil {
 		return fmt.Errorf("parse config: %w", err)
 	}
 
+	// the entityLabels may be empty if the labels are taken from the labelField
+	if err := d.config.NormalizeEntityLabels(); err != nil && d.config.LabelField == "" {
+		return fmt.Errorf("%w: %w", errNoLabels, err)
+	}
+
+	if err := d.config.ValidateTLS(); err != nil {
+		return fmt.Errorf("validate tls: %w", err)
+	}
+
+	if err := d.config.ValidateResolver(); err != nil {
+		return fmt.Errorf("validate resolver: %w", err)
+	}
+
+	if err := d.config.ValidateConnectionPool(); err != nil {
+		return fmt.Errorf("validate connection pool: %w", err)
+	}
+
+	if err := d.config.ValidateQueryTimeout(); err != nil {
+		return fmt.Errorf("validate query timeout: %w", err)
+	}
+
+	if err := d.config.ValidateStartupRetryTimeout(); err != nil {
+		return fmt.Errorf("validate startup retry timeout: %w", err)
+	}
+
+	if err := d.config.Auth.Validate(); err != nil {
+		return fmt.Errorf("validate auth: %w", err)
+	}
+
+	if err := writer.ValidateServerComputedProperties(d.config.ServerComputedProperties); err != nil {
+		return fmt.Errorf("validate server computed properties: %w", err)
+	}
+
+	if err := d.config.retryBatchBackoff().Validate(); err != nil {
+		return fmt.Errorf("validate retry batch backoff: %w", err)
+	}
+
+	if d.config.CreateConstraints && len(d.config.KeyProperties) == 0 {
+		return errConstraintsNoKeyProperties
+	}
+
+	if d.config.CreateConstraints && len(d.config.EntityLabels) == 0 {
+		return errConstraintsNoEntityLabels
+	}
+
+	if d.config.MaxLabels > 0 && len(d.config.EntityLabels) > d.config.MaxLabels {
+		return fmt.Errorf("%w: %d > %d", errEntityLabelsExceedMaxLabels, len(d.config.EntityLabels), d.config.MaxLabels)
+	}
+
+	for _, field := range d.config.MetadataToProperties {
+		if writer.IsReservedMetadata(field) {
+			sdk.Logger(ctx).Warn().Str("field", field).
+				Msg("the metadata field is reserved for internal use, so it isn't written as a property")
+		}
+	}
+
+	if d.config.WriteRateLimit < 0 {
+		return fmt.Errorf("%w: %v", errNegativeWriteRateLimit, d.config.WriteRateLimit)
+	}
+
 	return nil
 }
 
 // Open makes sure everything is prepared to receive records.
 func (d *Destination) Open(ctx context.Context) error {
-	driver, err := neo4j.NewDriverWithContext(d.config.URI, d.config.Auth.AuthToken())
+	driver, err := d.config.NewDriver()
 	if err != nil {
 		return fmt.Errorf("create neo4j driver: %w", err)
 	}
 
-	if err := driver.VerifyConnectivity(ctx); err != nil {
+	if err := d.config.VerifyConnectivity(ctx, driver); err != nil {
 		return fmt.Errorf("ping neo4j instance: %w", err)
 	}
 
 	d.driver = driver
+
+	if !d.config.SkipDatabaseCheck {
+		if err := d.config.VerifyDatabase(ctx, d.driver); err != nil {
+			return fmt.Errorf("verify database: %w", err)
+		}
+	}
+
+	if err := d.config.WarmUpConnections(ctx, d.driver, neo4j.AccessModeWrite); err != nil {
+		return fmt.Errorf("warm up connections: %w", err)
+	}
+
+	if !d.config.SkipPermissionCheck {
+		if err := writer.CheckWritePermission(ctx, writer.PermissionParams{
+			Driver:       d.driver,
+			DatabaseName: d.config.Database,
+			EntityType:   d.config.EntityType,
+			EntityLabels: d.config.EntityLabels,
+		}); err != nil {
+			return fmt.Errorf("check write permission: %w", err)
+		}
+	}
+
+	if d.config.CreateConstraints {
+		if err := writer.CreateConstraints(ctx, writer.ConstraintsParams{
+			Driver:        d.driver,
+			DatabaseName:  d.config.Database,
+			EntityType:    d.config.EntityType,
+			EntityLabels:  d.config.EntityLabels,
+			KeyProperties: d.config.KeyProperties,
+		}); err != nil {
+			return fmt.Errorf("create constraints: %w", err)
+		}
+	}
+
+	nestedObjects, err := writer.ResolveNestedObjects(ctx, d.driver, d.config.Database,
+		d.config.NestedObjects, d.config.NestedObjectsFallback,
+	)
+	if err != nil {
+		return fmt.Errorf("resolve nested objects: %w", err)
+	}
+
+	var rateLimiter *writer.RateLimiter
+	if d.config.WriteRateLimit > 0 {
+		rateLimiter = writer.NewRateLimiter(d.config.WriteRateLimit)
+	}
+
 	d.writer = writer.New(writer.Params{
-		Driver:       d.driver,
-		DatabaseName: d.config.Database,
-		EntityType:   d.config.EntityType,
-		EntityLabels: d.config.EntityLabels,
+		Driver:           d.driver,
+		DatabaseName:     d.config.Database,
+		EntityType:       d.config.EntityType,
+		EntityLabels:     d.config.EntityLabels,
+		AppendProperties: d.config.AppendProperties,
+		EndpointsOnNode:  d.config.EndpointsOnNode,
+
+		LabelConflictBehavior: d.config.LabelConflictBehavior,
+		LabelField:            d.config.LabelField,
+		Vectors:               d.config.Vectors(),
+		ProcessedAtProperty:   d.config.ProcessedAtProperty,
+		MetadataToProperties:  d.config.MetadataToProperties,
+		SourceMatchProperties: d.config.EndpointMatchProperties.Source,
+		TargetMatchProperties: d.config.EndpointMatchProperties.Target,
+		LogRedactProperties:   d.config.LogRedactProperties,
+		CreateEndpoints:       d.config.CreateEndpoints,
+		DetachDelete:          d.config.DetachDelete,
+		KeyProperties:         d.config.KeyProperties,
+		TransactionSize:       d.config.TransactionSize,
+
+		ServerComputedProperties:      d.config.ServerComputedProperties,
+		MetadataPropertyPrefix:        d.config.MetadataPropertyPrefix,
+		MatchRelationshipsByEndpoints: d.config.MatchRelationshipsByEndpoints,
+		StrictCardinality:             d.config.StrictCardinality,
+		SkipUnchanged:                 d.config.SkipUnchanged,
+		RemoveNullProperties:          d.config.RemoveNullProperties,
+		EntityTypeFromMetadata:        d.config.EntityTypeFromMetadata,
+		MaxLabels:                     d.config.MaxLabels,
+		MaxProperties:                 d.config.MaxProperties,
+		OnError:                       d.config.OnError,
+		CountMutations:                d.config.CountMutations,
+		NestedObjects:                 nestedObjects,
+		QueryTimeout:                  d.config.QueryTimeout,
+		RateLimiter:                   rateLimiter,
 	})
 
 	return nil
 }
 
 // Write writes a record into a [Destination].
+// If the coalesceCreateDelete is enabled, creates followed by deletes of the same keys are dropped first.
+// If the retryBatch is enabled, the whole batch is replayed when it fails transiently.
 func (d *Destination) Write(ctx context.Context, records []sdk.Record) (int, error) {
-	for i, record := range records {
-		if err := d.writer.Write(ctx, record); err != nil {
-			return i, fmt.Errorf("write record: %w", err)
+	batch := &coalescedBatch{records: records, size: len(records)}
+	if d.config.CoalesceCreateDelete {
+		batch = coalesceCreateDelete(records)
+		if len(batch.pairs) > 0 {
+			sdk.Logger(ctx).Debug().Int("pairs", len(batch.pairs)).Msg("dropped create-delete pairs from the batch")
+		}
+	}
+
+	// all records cancelled each other out, so there's nothing to write
+	if len(batch.records) == 0 {
+		return batch.size, nil
+	}
+
+	n, err := d.writeBatch(ctx, batch.records)
+
+	backoff := d.config.retryBatchBackoff()
+	for attempt := 1; d.config.RetryBatch && attempt < d.config.RetryBatchMaxAttempts && isTransient(err); attempt++ {
+		sdk.Logger(ctx).Warn().Err(err).Int("attempt", attempt).Msg("batch write failed transiently, replaying it")
+
+		if waitErr := backoff.Wait(ctx); waitErr != nil {
+			return batch.written(n), waitErr //nolint:wrapcheck // it's the context error
 		}
+
+		n, err = d.writeBatch(ctx, batch.records)
 	}
 
-	return len(records), nil
+	return batch.written(n), err
+}
+
+// writeBatch writes records and returns the number of records committed before a failure.
+func (d *Destination) writeBatch(ctx context.Context, records []sdk.Record) (int, error) {
+	n, err := d.writer.Write(ctx, records)
+	if err != nil {
+		return n, fmt.Errorf("write records: %w", err)
+	}
+
+	return n, nil
 }
 
 // Teardown gracefully closes connections.
@@ -100,3 +269,29 @@ func (d *Destination) Teardown(ctx context.Context) error {
 
 	return nil
 }
+
+// isTransient checks if the error is transient, so the batch can be replayed.
+// The writer classifies transient errors as the [writer.ErrTransient], the other error types are checked
+// for errors that aren't classified, as the driver's [neo4j.IsRetryable] doesn't unwrap all of them.
+func isTransient(err error) bool {
+	var (
+		connectivityError *neo4j.ConnectivityError
+		executionLimit    *neo4j.TransactionExecutionLimit
+		neo4jError        *neo4j.Neo4jError
+	)
+
+	switch {
+	case errors.Is(err, writer.ErrTransient):
+		return true
+
+	case errors.As(err, &connectivityError), errors.As(err, &executionLimit):
+		// the driver stops retrying a transaction on the execution limit only if errors are retryable
+		return true
+
+	case errors.As(err, &neo4jError):
+		return neo4jError.IsRetriable()
+
+	default:
+		return false
+	}
+}
@@ -16,20 +16,32 @@ package destination
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/destination/writer"
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	"github.com/conduitio-labs/conduit-connector-neo4j/source"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"github.com/matryer/is"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
 )
 
 const (
 	// field names that are used within the integration tests.
-	idFieldName   = "id"
-	nameFieldName = "name"
+	idFieldName     = "id"
+	nameFieldName   = "name"
+	eventsFieldName = "events"
+	// processedAtFieldName is a name of the processedAtProperty used within the integration tests.
+	processedAtFieldName = "processedAt"
 	// testURI is a connection URI pointed to a local Neo4j instance.
 	testURI = "bolt://localhost:7687"
 	// testLabel is a label that is used for integration tests.
@@ -129,7 +141,1309 @@ func TestDestination_Write(t *testing.T) {
 	is.Equal(usageError.Message, "Result contains no more records")
 }
 
+func TestDestination_Write_appendProperties(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyAppendProperties] = eventsFieldName
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	id := "append"
+	records := []sdk.Record{
+		{
+			Operation: sdk.OperationCreate,
+			Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: id, eventsFieldName: "created"}},
+		},
+		{
+			Operation: sdk.OperationUpdate,
+			Key:       sdk.StructuredData{idFieldName: id},
+			Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: id, eventsFieldName: "logged_in"}},
+		},
+		{
+			Operation: sdk.OperationUpdate,
+			Key:       sdk.StructuredData{idFieldName: id},
+			Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: id, eventsFieldName: "logged_out"}},
+		},
+	}
+
+	n, err := destination.Write(ctx, records)
+	is.NoErr(err)
+	is.Equal(n, len(records))
+
+	events, err := findProperty(ctx, driver, id, eventsFieldName)
+	is.NoErr(err)
+	is.Equal(events, []any{"created", "logged_in", "logged_out"})
+
+	// the key property cannot be appended
+	_, err = destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationUpdate,
+		Key:       sdk.StructuredData{eventsFieldName: "created"},
+		Payload:   sdk.Change{After: sdk.StructuredData{nameFieldName: "Bob"}},
+	}})
+	is.True(errors.Is(err, writer.ErrAppendKeyProperty))
+}
+
 // prepareConfig creates a config with the test values and the provided entityType.
+func TestDestination_Write_processedAtProperty(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyProcessedAtProperty] = processedAtFieldName
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	id := "processed"
+	n, err := destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: id, nameFieldName: "Bob"}},
+	}})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	createdAt, err := findProperty(ctx, driver, id, processedAtFieldName)
+	is.NoErr(err)
+
+	n, err = destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationUpdate,
+		Key:       sdk.StructuredData{idFieldName: id},
+		Payload:   sdk.Change{After: sdk.StructuredData{nameFieldName: "NewBob"}},
+	}})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	updatedAt, err := findProperty(ctx, driver, id, processedAtFieldName)
+	is.NoErr(err)
+
+	// the property is set on each write, so the update moves it forward
+	is.True(updatedAt.(time.Time).After(createdAt.(time.Time)))
+}
+
+func TestDestination_Write_metadataToProperties(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyMetadataToProperties] = "source.region,opencdc.readAt"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	id := "metadata"
+	n, err := destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Metadata:  sdk.Metadata{"source.region": "eu-west-1", "opencdc.readAt": "1700000000000000000"},
+		Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: id, nameFieldName: "Bob"}},
+	}})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	// the custom field is written with the default prefix, the names are escaped as they contain dots
+	region, err := findProperty(ctx, driver, id, "`metadata_source.region`")
+	is.NoErr(err)
+	is.Equal(region, "eu-west-1")
+
+	// the reserved field is skipped
+	readAt, err := findProperty(ctx, driver, id, "`metadata_opencdc.readAt`")
+	is.NoErr(err)
+	is.Equal(readAt, nil)
+}
+
+func TestDestination_Write_serverComputedProperties(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyServerComputedProperties+".uuid"] = "randomUUID()"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	id := "computed"
+	n, err := destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: id, nameFieldName: "Bob", "uuid": "client"}},
+	}})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	// the value from the payload is replaced with the one generated by Neo4j
+	uuid, err := findProperty(ctx, driver, id, "uuid")
+	is.NoErr(err)
+	is.True(uuid != "client")
+	is.Equal(len(uuid.(string)), 36)
+}
+
+func TestDestination_Write_point(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	// the payload is in the form the source emits points in
+	id := "located"
+	n, err := destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload: sdk.Change{After: sdk.RawData(
+			`{"id":"located","location":{"srid":4326,"x":30.52,"y":50.45}}`,
+		)},
+	}})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	location, err := findProperty(ctx, driver, id, "location")
+	is.NoErr(err)
+	is.Equal(location, dbtype.Point2D{X: 30.52, Y: 50.45, SpatialRefId: 4326})
+
+	// the point is stored as a spatial value, so spatial functions can be applied to it
+	result, err := neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("MATCH (obj:%s {id: $id}) RETURN obj.location.latitude AS latitude", testLabel),
+		map[string]any{"id": id}, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+
+	latitude, _ := result.Records[0].Get("latitude")
+	is.Equal(latitude, 50.45)
+}
+
+func TestDestination_Write_listProperties(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	n, err := destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload: sdk.Change{After: sdk.RawData(
+			`{"id":"lists","tags":["a","b"],"scores":[1,2.5]}`,
+		)},
+	}})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	tags, err := findProperty(ctx, driver, "lists", "tags")
+	is.NoErr(err)
+	is.Equal(tags, []any{"a", "b"})
+
+	scores, err := findProperty(ctx, driver, "lists", "scores")
+	is.NoErr(err)
+	is.Equal(scores, []any{float64(1), 2.5})
+
+	// Neo4j cannot store lists of maps, so the record fails before its query is run
+	_, err = destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.RawData(`{"id":"lists_maps","items":[{"name":"a"}]}`)},
+	}})
+	is.True(errors.Is(err, writer.ErrUnsupportedList))
+}
+
+func TestDestination_Write_vectorProperties(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[config.KeyVectorProperties] = "embedding"
+	cfg[config.KeyVectorDimensions] = "384"
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	// integral floats are serialized without fractions, so they must still be written as floats
+	embedding := make([]float64, 384)
+	for i := range embedding {
+		embedding[i] = float64(i%7) / 4
+	}
+
+	sourceLabel := fmt.Sprintf("Embedded_%d", time.Now().UnixNano())
+	_, err = neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("CREATE (:%s {id: 'vector', embedding: $embedding})", sourceLabel),
+		map[string]any{"embedding": embedding}, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+
+	// read the node with the source and write its record with the destination
+	src := source.New()
+	is.NoErr(src.Configure(ctx, map[string]string{
+		config.KeyURI:                    cfg[config.KeyURI],
+		config.KeyEntityType:             string(config.EntityTypeNode),
+		config.KeyEntityLabels:           sourceLabel,
+		config.KeyAuthUsername:           cfg[config.KeyAuthUsername],
+		config.KeyAuthPassword:           cfg[config.KeyAuthPassword],
+		config.KeyVectorProperties:       "embedding",
+		config.KeyVectorDimensions:       "384",
+		source.ConfigKeyOrderingProperty: idFieldName,
+	}))
+	is.NoErr(src.Open(ctx, nil))
+	t.Cleanup(func() {
+		is.NoErr(src.Teardown(ctx))
+	})
+
+	record, err := src.Read(ctx)
+	is.NoErr(err)
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	n, err := destination.Write(ctx, []sdk.Record{record})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	written, err := findProperty(ctx, driver, "vector", "embedding")
+	is.NoErr(err)
+
+	want := make([]any, len(embedding))
+	for i, value := range embedding {
+		want[i] = value
+	}
+
+	is.Equal(written, want)
+
+	// a vector of other dimensions fails the record
+	_, err = destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.RawData(`{"id":"short_vector","embedding":[0.5,1]}`)},
+	}})
+	is.True(errors.Is(err, schema.ErrInvalidVector))
+}
+
+func TestDestination_Write_nestedObjects(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyNestedObjects] = string(writer.NestedObjectsAPOC)
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	// the test server has the APOC installed, so the object is serialized by apoc.convert.toJson
+	n, err := destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload: sdk.Change{After: sdk.RawData(
+			`{"id":"nested","address":{"city":"Kyiv","phones":[{"number":"1"}]}}`,
+		)},
+	}})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	address, err := findProperty(ctx, driver, "nested", "address")
+	is.NoErr(err)
+
+	serialized, ok := address.(string)
+	is.True(ok)
+
+	var object map[string]any
+	is.NoErr(json.Unmarshal([]byte(serialized), &object))
+	is.Equal(object, map[string]any{"city": "Kyiv", "phones": []any{map[string]any{"number": "1"}}})
+}
+
+func TestDestination_Write_endpointsOnNode(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	endpoint := map[string]any{"labels": []any{testLabel}, "key": map[string]any{idFieldName: "other"}}
+
+	// the endpointsOnNode applies only to creates, so a node update strips the endpoints silently
+	id := "endpoints_on_node"
+	n, err := destination.Write(ctx, []sdk.Record{
+		{
+			Operation: sdk.OperationCreate,
+			Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: id, nameFieldName: "Bob"}},
+		},
+		{
+			Operation: sdk.OperationUpdate,
+			Key:       sdk.StructuredData{idFieldName: id},
+			Payload:   sdk.Change{After: sdk.StructuredData{nameFieldName: "NewBob", "sourceNode": endpoint}},
+		},
+	})
+	is.NoErr(err)
+	is.Equal(n, 2)
+
+	name, err := findProperty(ctx, driver, id, nameFieldName)
+	is.NoErr(err)
+	is.Equal(name, "NewBob")
+
+	sourceNode, err := findProperty(ctx, driver, id, "sourceNode")
+	is.NoErr(err)
+	is.Equal(sourceNode, nil)
+
+	// a node create with the endpoints fails with the default error one
+	_, err = destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: "endpoints_on_create", "targetNode": endpoint}},
+	}})
+	is.True(errors.Is(err, writer.ErrEndpointsOnNode))
+}
+
+func TestDestination_Write_endpointMatchProperties(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeRelationship)
+	cfg[config.KeyEntityLabels] = "KNOWS"
+	cfg[ConfigKeyEndpointMatchPropertiesSource] = "email,username"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	_, err = neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("CREATE (:%[1]s {id: 'endpoint_a', username: 'alice'}), (:%[1]s {id: 'endpoint_b'})", testLabel),
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+
+	// the source node is matched by its username, even though its email is unknown
+	record := sdk.Record{
+		Operation: sdk.OperationCreate,
+		Payload: sdk.Change{After: sdk.StructuredData{
+			"sourceNode": map[string]any{
+				"labels": []string{testLabel},
+				"key":    map[string]any{"email": "unknown@example.com", "username": "alice"},
+			},
+			"targetNode": map[string]any{
+				"labels": []string{testLabel},
+				"key":    map[string]any{idFieldName: "endpoint_b"},
+			},
+		}},
+	}
+
+	n, err := destination.Write(ctx, []sdk.Record{record})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	result, err := neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("MATCH (:%[1]s {id: 'endpoint_a'})-[obj:KNOWS]->(:%[1]s {id: 'endpoint_b'}) RETURN count(obj) AS count",
+			testLabel,
+		),
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+
+	count, _ := result.Records[0].Get("count")
+	is.Equal(count, int64(1))
+}
+
+func TestDestination_Write_createEndpoints(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeRelationship)
+	cfg[config.KeyEntityLabels] = "KNOWS"
+	cfg[ConfigKeyCreateEndpoints] = "true"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	// neither endpoint exists, so both are created along with the relationship
+	record := sdk.Record{
+		Operation: sdk.OperationCreate,
+		Payload: sdk.Change{After: sdk.StructuredData{
+			"since": 2020,
+			"sourceNode": map[string]any{
+				"labels":     []string{testLabel},
+				"key":        map[string]any{idFieldName: "standalone_a"},
+				"properties": map[string]any{"name": "Alice"},
+			},
+			"targetNode": map[string]any{
+				"labels": []string{testLabel},
+				"key":    map[string]any{idFieldName: "standalone_b"},
+			},
+		}},
+	}
+
+	n, err := destination.Write(ctx, []sdk.Record{record})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	result, err := neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("MATCH (src:%[1]s {id: 'standalone_a'})-[obj:KNOWS]->(:%[1]s {id: 'standalone_b'}) "+
+			"RETURN src.name AS name, obj.since AS since",
+			testLabel,
+		),
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+	is.Equal(len(result.Records), 1)
+
+	name, _ := result.Records[0].Get("name")
+	is.Equal(name, "Alice")
+
+	since, _ := result.Records[0].Get("since")
+	is.Equal(since, int64(2020))
+}
+
+func TestDestination_Write_createConstraints(t *testing.T) {
+	const writers = 5
+
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeRelationship)
+	// a dedicated type, so the constraint doesn't affect other tests
+	cfg[config.KeyEntityLabels] = "OWNS_UNIQUE"
+	cfg[ConfigKeyKeyProperties] = idFieldName
+	cfg[ConfigKeyCreateConstraints] = "true"
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	_, err = neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("CREATE (:%[1]s {id: 'owner'}), (:%[1]s {id: 'owned'})", testLabel),
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+
+	destinations := make([]sdk.Destination, writers)
+	for i := range destinations {
+		destinations[i] = New()
+		is.NoErr(destinations[i].Configure(ctx, cfg))
+		is.NoErr(destinations[i].Open(ctx))
+
+		destination := destinations[i]
+		t.Cleanup(func() {
+			is.NoErr(destination.Teardown(ctx))
+		})
+	}
+
+	record := sdk.Record{
+		Operation: sdk.OperationCreate,
+		Payload: sdk.Change{After: sdk.StructuredData{
+			idFieldName: "ownership",
+			"sourceNode": map[string]any{
+				"labels": []string{testLabel},
+				"key":    map[string]any{idFieldName: "owner"},
+			},
+			"targetNode": map[string]any{
+				"labels": []string{testLabel},
+				"key":    map[string]any{idFieldName: "owned"},
+			},
+		}},
+	}
+
+	// all destinations write the same relationship concurrently, only one of them succeeds
+	var (
+		wg       sync.WaitGroup
+		written  atomic.Int64
+		failures atomic.Int64
+	)
+
+	for _, destination := range destinations {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if n, err := destination.Write(ctx, []sdk.Record{record}); err != nil {
+				failures.Add(1)
+			} else {
+				written.Add(int64(n))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	is.Equal(written.Load(), int64(1))
+	is.Equal(failures.Load(), int64(writers-1))
+
+	result, err := neo4j.ExecuteQuery(ctx, driver,
+		"MATCH ()-[obj:OWNS_UNIQUE {id: 'ownership'}]->() RETURN count(obj) AS count",
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+
+	count, _ := result.Records[0].Get("count")
+	is.Equal(count, int64(1))
+}
+
+func TestDestination_Write_detachDelete(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyDetachDelete] = "true"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	// the deleted node has an inbound relationship
+	_, err = neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("CREATE (:%[1]s {id: 'detach_a'})-[:KNOWS]->(:%[1]s {id: 'detach_b'})", testLabel),
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+
+	n, err := destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationDelete,
+		Key:       sdk.StructuredData{idFieldName: "detach_b"},
+	}})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	_, err = findRecord(ctx, driver, "detach_b")
+	var usageError *neo4j.UsageError
+	is.True(errors.As(err, &usageError))
+
+	// the other endpoint of the deleted relationship is kept
+	_, err = findRecord(ctx, driver, "detach_a")
+	is.NoErr(err)
+}
+
+func TestDestination_Write_matchRelationshipsByEndpoints(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeRelationship)
+	cfg[config.KeyEntityLabels] = "KNOWS"
+	cfg[ConfigKeyMatchRelationshipsByEndpoints] = "true"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	// both relationships have the same key, only their target nodes differ
+	_, err = neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("CREATE (a:%[1]s {id: 'pair_a'}), (b:%[1]s {id: 'pair_b'}), (c:%[1]s {id: 'pair_c'}), "+
+			"(a)-[:KNOWS {id: 'pair_rel'}]->(b), (a)-[:KNOWS {id: 'pair_rel'}]->(c)",
+			testLabel,
+		),
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+
+	endpoints := func(target string) sdk.StructuredData {
+		return sdk.StructuredData{
+			"sourceNode": map[string]any{"labels": []string{testLabel}, "key": map[string]any{idFieldName: "pair_a"}},
+			"targetNode": map[string]any{"labels": []string{testLabel}, "key": map[string]any{idFieldName: target}},
+		}
+	}
+
+	update := endpoints("pair_c")
+	update["note"] = "updated"
+
+	n, err := destination.Write(ctx, []sdk.Record{
+		{
+			Operation: sdk.OperationUpdate,
+			Key:       sdk.StructuredData{idFieldName: "pair_rel"},
+			Payload:   sdk.Change{After: update},
+		},
+		{
+			Operation: sdk.OperationDelete,
+			Key:       sdk.StructuredData{idFieldName: "pair_rel"},
+			Payload:   sdk.Change{Before: endpoints("pair_b")},
+		},
+	})
+	is.NoErr(err)
+	is.Equal(n, 2)
+
+	result, err := neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("MATCH (:%[1]s {id: 'pair_a'})-[obj:KNOWS {id: 'pair_rel'}]->(trgt:%[1]s) "+
+			"RETURN trgt.id AS target, obj.note AS note",
+			testLabel,
+		),
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+	is.Equal(len(result.Records), 1)
+
+	target, _ := result.Records[0].Get("target")
+	is.Equal(target, "pair_c")
+
+	note, _ := result.Records[0].Get("note")
+	is.Equal(note, "updated")
+}
+
+func TestDestination_Write_strictCardinality(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyStrictCardinality] = "true"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	// the key doesn't identify the nodes uniquely
+	_, err = neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("CREATE (:%[1]s {id: 'strict_a', name: 'first'}), (:%[1]s {id: 'strict_a', name: 'second'}), "+
+			"(:%[1]s {id: 'strict_b'})",
+			testLabel,
+		),
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+
+	update := sdk.Record{
+		Operation: sdk.OperationUpdate,
+		Key:       sdk.StructuredData{idFieldName: "strict_a"},
+		Payload:   sdk.Change{After: sdk.StructuredData{"name": "updated"}},
+	}
+
+	n, err := destination.Write(ctx, []sdk.Record{update})
+	is.True(errors.Is(err, writer.ErrCardinalityViolation))
+	is.Equal(n, 0)
+
+	// the failed update is rolled back, so neither node is updated
+	result, err := neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("MATCH (obj:%s {id: 'strict_a', name: 'updated'}) RETURN count(obj) AS count", testLabel),
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+
+	count, _ := result.Records[0].Get("count")
+	is.Equal(count, int64(0))
+
+	// a delete of a missing node fails too, and a delete of a single one succeeds
+	n, err = destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationDelete,
+		Key:       sdk.StructuredData{idFieldName: "strict_missing"},
+	}})
+	is.True(errors.Is(err, writer.ErrCardinalityViolation))
+	is.Equal(n, 0)
+
+	n, err = destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationDelete,
+		Key:       sdk.StructuredData{idFieldName: "strict_b"},
+	}})
+	is.NoErr(err)
+	is.Equal(n, 1)
+}
+
+func TestDestination_Write_skipUnchanged(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeySkipUnchanged] = "true"
+	cfg[ConfigKeyProcessedAtProperty] = processedAtFieldName
+	cfg[ConfigKeyStrictCardinality] = "true"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	id := "unchanged"
+	n, err := destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: id, nameFieldName: "Bob"}},
+	}})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	createdAt, err := findProperty(ctx, driver, id, processedAtFieldName)
+	is.NoErr(err)
+
+	update := func(name string) {
+		n, err = destination.Write(ctx, []sdk.Record{{
+			Operation: sdk.OperationUpdate,
+			Key:       sdk.StructuredData{idFieldName: id},
+			Payload:   sdk.Change{After: sdk.StructuredData{nameFieldName: name}},
+		}})
+		is.NoErr(err)
+		is.Equal(n, 1)
+	}
+
+	// the unchanged update sets no properties, so the processedAtProperty stays the same,
+	// and the matched node still satisfies the strictCardinality
+	update("Bob")
+
+	unchangedAt, err := findProperty(ctx, driver, id, processedAtFieldName)
+	is.NoErr(err)
+	is.Equal(unchangedAt, createdAt)
+
+	update("NewBob")
+
+	updatedAt, err := findProperty(ctx, driver, id, processedAtFieldName)
+	is.NoErr(err)
+	is.True(updatedAt.(time.Time).After(createdAt.(time.Time)))
+
+	name, err := findProperty(ctx, driver, id, nameFieldName)
+	is.NoErr(err)
+	is.Equal(name, "NewBob")
+}
+
+func TestDestination_Write_nullProperties(t *testing.T) {
+	tests := []struct {
+		name                 string
+		removeNullProperties bool
+		want                 any
+	}{
+		{
+			name:                 "ignored",
+			removeNullProperties: false,
+			want:                 "Bob",
+		},
+		{
+			name:                 "removed",
+			removeNullProperties: true,
+			want:                 nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+			ctx := context.Background()
+			cfg := prepareConfig(t, config.EntityTypeNode)
+			cfg[ConfigKeyRemoveNullProperties] = strconv.FormatBool(tt.removeNullProperties)
+
+			destination := New()
+			is.NoErr(destination.Configure(ctx, cfg))
+			is.NoErr(destination.Open(ctx))
+			t.Cleanup(func() {
+				is.NoErr(destination.Teardown(ctx))
+			})
+
+			driver, err := neo4j.NewDriverWithContext(
+				cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+			)
+			is.NoErr(err)
+			t.Cleanup(func() {
+				is.NoErr(driver.Close(ctx))
+			})
+
+			id := "null_" + tt.name
+			n, err := destination.Write(ctx, []sdk.Record{
+				{
+					Operation: sdk.OperationCreate,
+					Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: id, nameFieldName: "Bob"}},
+				},
+				{
+					Operation: sdk.OperationUpdate,
+					Key:       sdk.StructuredData{idFieldName: id},
+					Payload:   sdk.Change{After: sdk.RawData(`{"name":null}`)},
+				},
+			})
+			is.NoErr(err)
+			is.Equal(n, 2)
+
+			name, err := findProperty(ctx, driver, id, nameFieldName)
+			is.NoErr(err)
+			is.Equal(name, tt.want)
+		})
+	}
+}
+
+func TestDestination_Write_countMutations(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyCountMutations] = "true"
+
+	// the destination isn't wrapped with middleware, so its writer is accessible
+	destination := &Destination{}
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	n, err := destination.Write(ctx, []sdk.Record{
+		{
+			Operation: sdk.OperationCreate,
+			Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: "mutations_1", nameFieldName: "Alice"}},
+		},
+		{
+			Operation: sdk.OperationCreate,
+			Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: "mutations_2", nameFieldName: "Bob"}},
+		},
+		{
+			Operation: sdk.OperationUpdate,
+			Key:       sdk.StructuredData{idFieldName: "mutations_1"},
+			Payload:   sdk.Change{After: sdk.StructuredData{nameFieldName: "Carol"}},
+		},
+		{
+			Operation: sdk.OperationDelete,
+			Key:       sdk.StructuredData{idFieldName: "mutations_2"},
+		},
+	})
+	is.NoErr(err)
+	is.Equal(n, 4)
+
+	mutations := destination.writer.(*writer.Writer).Mutations()
+	is.Equal(mutations, writer.MutationCounts{
+		NodesCreated:  2,
+		NodesDeleted:  1,
+		PropertiesSet: 5,
+		LabelsAdded:   2,
+	})
+}
+
+func TestDestination_Open_warmupConnections(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[config.KeyWarmupConnections] = "5"
+
+	destination := &Destination{}
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	// the driver doesn't expose pool metrics, so the server is asked for its bolt connections instead,
+	// other tests may hold connections as well, so it's only checked there are at least the warmed up ones
+	result, err := neo4j.ExecuteQuery(ctx, destination.driver,
+		"CALL dbms.listConnections() YIELD connector WHERE connector = 'bolt' RETURN count(*) AS connections",
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+	is.Equal(len(result.Records), 1)
+
+	connections, ok := result.Records[0].Get("connections")
+	is.True(ok)
+	is.True(connections.(int64) >= 5)
+}
+
+func TestDestination_Write_entityTypeFromMetadata(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyEntityTypeFromMetadata] = "true"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	node := func(id string) sdk.Record {
+		return sdk.Record{
+			Operation: sdk.OperationCreate,
+			Metadata:  sdk.Metadata{"neo4j.entityType": string(config.EntityTypeNode)},
+			Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: id, nameFieldName: id}},
+		}
+	}
+
+	// the relationship is written in the same batch as its endpoints,
+	// with the labels from its metadata instead of the configured ones
+	n, err := destination.Write(ctx, []sdk.Record{
+		node("mixed_a"),
+		node("mixed_b"),
+		{
+			Operation: sdk.OperationCreate,
+			Metadata: sdk.Metadata{
+				"neo4j.entityType":   string(config.EntityTypeRelationship),
+				"neo4j.entityLabels": "KNOWS",
+			},
+			Payload: sdk.Change{After: sdk.StructuredData{
+				"since": 2020,
+				"sourceNode": map[string]any{
+					"labels": []string{testLabel},
+					"key":    map[string]any{idFieldName: "mixed_a"},
+				},
+				"targetNode": map[string]any{
+					"labels": []string{testLabel},
+					"key":    map[string]any{idFieldName: "mixed_b"},
+				},
+			}},
+		},
+	})
+	is.NoErr(err)
+	is.Equal(n, 3)
+
+	result, err := neo4j.ExecuteQuery(ctx, driver,
+		fmt.Sprintf("MATCH (:%[1]s {id: 'mixed_a'})-[obj:KNOWS]->(:%[1]s {id: 'mixed_b'}) "+
+			"RETURN obj.since AS since",
+			testLabel,
+		),
+		nil, neo4j.EagerResultTransformer,
+	)
+	is.NoErr(err)
+	is.Equal(len(result.Records), 1)
+
+	since, _ := result.Records[0].Get("since")
+	is.Equal(since, int64(2020))
+}
+
+func TestDestination_Write_onErrorSkip(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyOnError] = string(writer.OnErrorSkip)
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	// the update in the middle has no key, so it's skipped, and the records around it are written
+	records := []sdk.Record{
+		{Operation: sdk.OperationCreate, Payload: sdk.Change{After: sdk.StructuredData{idFieldName: "skip_a"}}},
+		{Operation: sdk.OperationUpdate, Payload: sdk.Change{After: sdk.StructuredData{idFieldName: "skip_b"}}},
+		{Operation: sdk.OperationCreate, Payload: sdk.Change{After: sdk.StructuredData{idFieldName: "skip_c"}}},
+	}
+
+	n, err := destination.Write(ctx, records)
+	is.NoErr(err)
+	is.Equal(n, 3)
+
+	for _, id := range []string{"skip_a", "skip_c"} {
+		_, err = findRecord(ctx, driver, id)
+		is.NoErr(err)
+	}
+}
+
+func TestDestination_Write_failMaxLabelsAndProperties(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyLabelConflictBehavior] = string(writer.LabelConflictBehaviorMetadataWins)
+	cfg[ConfigKeyMaxLabels] = "2"
+	cfg[ConfigKeyMaxProperties] = "2"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	_, err = destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: "limits_a", nameFieldName: "Alice", "age": 30}},
+	}})
+	is.True(errors.Is(err, writer.ErrTooManyProperties))
+
+	_, err = destination.Write(ctx, []sdk.Record{{
+		Operation: sdk.OperationCreate,
+		Metadata:  sdk.Metadata{"neo4j.entityLabels": testLabel + ":Writer:Poet"},
+		Payload:   sdk.Change{After: sdk.StructuredData{idFieldName: "limits_b"}},
+	}})
+	is.True(errors.Is(err, writer.ErrTooManyLabels))
+
+	// the rejected records aren't written
+	for _, id := range []string{"limits_a", "limits_b"} {
+		_, err = findRecord(ctx, driver, id)
+		is.True(err != nil)
+	}
+}
+
+func TestDestination_Write_failMidBatch(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[ConfigKeyTransactionSize] = "2"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	// the update of the fourth record has no key, so it fails
+	// and rolls back the second transaction along with the third record
+	records := []sdk.Record{
+		{Operation: sdk.OperationCreate, Payload: sdk.Change{After: sdk.StructuredData{idFieldName: "batch_a"}}},
+		{Operation: sdk.OperationCreate, Payload: sdk.Change{After: sdk.StructuredData{idFieldName: "batch_b"}}},
+		{Operation: sdk.OperationCreate, Payload: sdk.Change{After: sdk.StructuredData{idFieldName: "batch_c"}}},
+		{Operation: sdk.OperationUpdate, Payload: sdk.Change{After: sdk.StructuredData{idFieldName: "batch_d"}}},
+	}
+
+	n, err := destination.Write(ctx, records)
+	is.True(errors.Is(err, writer.ErrMissingKeyForUpdate))
+	is.Equal(n, 2)
+
+	for _, id := range []string{"batch_a", "batch_b"} {
+		_, err = findRecord(ctx, driver, id)
+		is.NoErr(err)
+	}
+
+	_, err = findRecord(ctx, driver, "batch_c")
+	var usageError *neo4j.UsageError
+	is.True(errors.As(err, &usageError))
+}
+
+func TestDestination_Open_failReadOnlyUser(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+
+	driver, err := neo4j.NewDriverWithContext(
+		cfg[config.KeyURI], neo4j.BasicAuth(cfg[config.KeyAuthUsername], cfg[config.KeyAuthPassword], ""),
+	)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(ctx))
+	})
+
+	username := fmt.Sprintf("reader_%d", time.Now().UnixNano())
+	params := map[string]any{"username": username, "password": testPassword}
+
+	_, err = neo4j.ExecuteQuery(ctx, driver, "CREATE USER $username SET PASSWORD $password CHANGE NOT REQUIRED",
+		params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("system"),
+	)
+	if err != nil {
+		t.Skipf("the server doesn't support creating users: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_, err := neo4j.ExecuteQuery(ctx, driver, "DROP USER $username",
+			params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("system"),
+		)
+		is.NoErr(err)
+	})
+
+	// roles are available only in the Enterprise Edition, where all users are admins otherwise
+	_, err = neo4j.ExecuteQuery(ctx, driver, "GRANT ROLE reader TO $username",
+		params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("system"),
+	)
+	if err != nil {
+		t.Skipf("the server doesn't support roles: %v", err)
+	}
+
+	cfg[config.KeyAuthUsername] = username
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+
+	err = destination.Open(ctx)
+	is.True(errors.Is(err, writer.ErrPermissionDenied))
+	is.NoErr(destination.Teardown(ctx))
+
+	// the check is skipped, so the destination opens and fails only on the first write
+	cfg[ConfigKeySkipPermissionCheck] = "true"
+
+	destination = New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	is.NoErr(destination.Open(ctx))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+}
+
+func TestDestination_Open_failDatabaseNotFound(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	cfg := prepareConfig(t, config.EntityTypeNode)
+	cfg[config.KeyDatabase] = "nonexistent"
+
+	destination := New()
+	is.NoErr(destination.Configure(ctx, cfg))
+	t.Cleanup(func() {
+		is.NoErr(destination.Teardown(ctx))
+	})
+
+	err := destination.Open(ctx)
+	is.True(errors.Is(err, config.ErrDatabaseNotFound))
+}
+
 func prepareConfig(t *testing.T, entityType config.EntityType) map[string]string {
 	t.Helper()
 
@@ -178,3 +1492,34 @@ func findRecord(ctx context.Context, driver neo4j.DriverWithContext, id any) (ma
 
 	return record, nil
 }
+
+// findProperty finds a property value of a record in Neo4j database by the provided id.
+func findProperty(ctx context.Context, driver neo4j.DriverWithContext, id any, property string) (any, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{})
+	defer session.Close(ctx)
+
+	value, err := neo4j.ExecuteRead(ctx, session, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := fmt.Sprintf("MATCH (p:%s {%s: $%s}) RETURN p.%s AS value",
+			testLabel, idFieldName, idFieldName, property,
+		)
+
+		result, err := tx.Run(ctx, query, map[string]any{idFieldName: id})
+		if err != nil {
+			return nil, fmt.Errorf("run transaction: %w", err)
+		}
+
+		record, err := result.Single(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("collect record: %w", err)
+		}
+
+		value, _ := record.Get("value")
+
+		return value, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("execute read: %w", err)
+	}
+
+	return value, nil
+}
@@ -9,6 +9,12 @@ import (
 
 func (Config) Parameters() map[string]sdk.Parameter {
 	return map[string]sdk.Parameter{
+		"appendProperties": {
+			Default:     "",
+			Description: "The list of property names which values are appended to a list property on updates instead of overwriting it. The properties must not be a part of a record key.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
 		"auth.password": {
 			Default:     "",
 			Description: "The password to use when performing basic auth.",
@@ -21,26 +27,112 @@ func (Config) Parameters() map[string]sdk.Parameter {
 			Type:        sdk.ParameterTypeString,
 			Validations: []sdk.Validation{},
 		},
+		"auth.scheme": {
+			Default:     "basic",
+			Description: "The scheme of the authentication. If it's \"basic\", the username, password and realm are used, or no auth is performed if they're all empty, if it's \"bearer\", the token is used, if it's \"kerberos\", the ticket is used, and if it's \"none\", no auth is performed.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"basic", "bearer", "kerberos", "none"}},
+			},
+		},
+		"auth.ticket": {
+			Default:     "",
+			Description: "The base64-encoded ticket to use when performing kerberos auth.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"auth.token": {
+			Default:     "",
+			Description: "The token to use when performing bearer auth, e.g. an SSO access token.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
 		"auth.username": {
 			Default:     "",
 			Description: "The username to use when performing basic auth.",
 			Type:        sdk.ParameterTypeString,
 			Validations: []sdk.Validation{},
 		},
+		"coalesceCreateDelete": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will drop a create followed by a delete of the same key within a batch, so neither of them is written. The pair is kept if other records of the key are between them.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"connectionAcquisitionTimeout": {
+			Default:     "1m",
+			Description: "The maximum amount of time to wait for a pooled connection to become available, including the time to establish a new connection.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"connectionLivenessCheckTimeout": {
+			Default:     "",
+			Description: "The duration after which an idle pooled connection is tested for liveness before it's reused. If it's not set, idle connections are not tested.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"connectionTimeout": {
+			Default:     "5s",
+			Description: "The maximum amount of time to wait for a TCP connection to a server to be established.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"countMutations": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will aggregate the counters of graph mutations Neo4j reports for each batch, i.e. created and deleted nodes and relationships, set properties, added and removed labels, and log them along with the number of written records.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"createConstraints": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will create uniqueness constraints of the keyProperties on open if they don't exist, so concurrent writes cannot create duplicate elements. Relationship constraints require Neo4j 5.7 or later and are skipped on older servers.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"createEndpoints": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will create relationship endpoints along with relationships from their labels, key and properties instead of matching existing nodes.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
 		"database": {
 			Default:     "neo4j",
 			Description: "The name of a database the connector should work with.",
 			Type:        sdk.ParameterTypeString,
 			Validations: []sdk.Validation{},
 		},
-		"entityLabels": {
+		"detachDelete": {
+			Default:     "true",
+			Description: "Determines whether or not the connector will delete relationships of a node along with the node. If it's false, deleting a node that still has relationships fails.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"endpointMatchProperties.source": {
 			Default:     "",
-			Description: "Holds a list of labels belonging to an entity.",
+			Description: "The list of source node key properties any of which is enough to match the source node.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"endpointMatchProperties.target": {
+			Default:     "",
+			Description: "The list of target node key properties any of which is enough to match the target node.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"endpointsOnNode": {
+			Default:     "error",
+			Description: "Determines what to do if the entityType is node but the payload of a create record contains the relationship-specific sourceNode or targetNode fields. If it's \"strip\", the fields are removed with a warning, if it's \"error\", the record fails. Updates of nodes always strip the fields silently.",
 			Type:        sdk.ParameterTypeString,
 			Validations: []sdk.Validation{
-				sdk.ValidationRequired{},
+				sdk.ValidationInclusion{List: []string{"strip", "error"}},
 			},
 		},
+		"entityLabels": {
+			Default:     "",
+			Description: "Holds a list of labels belonging to an entity.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
 		"entityType": {
 			Default:     "",
 			Description: "Defines an entity type the connector should work with.",
@@ -50,6 +142,218 @@ func (Config) Parameters() map[string]sdk.Parameter {
 				sdk.ValidationInclusion{List: []string{"node", "relationship"}},
 			},
 		},
+		"entityTypeFromMetadata": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will take the entity type of each record from the \"neo4j.entityType\" metadata, falling back to the entityType if it's missing. Records of the other entity type are written with the labels from the \"neo4j.entityLabels\" metadata.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"keyProperties": {
+			Default:     "",
+			Description: "The list of property names that are used to derive a key from the payload of an update or delete record if the record has no key. If it's empty, such records fail.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"labelConflictBehavior": {
+			Default:     "configWins",
+			Description: "Determines what to do if the record metadata field \"neo4j.entityLabels\" contains labels that differ from the entityLabels. If it's \"configWins\", the entityLabels are used, if it's \"metadataWins\", the labels from the metadata are used, if it's \"merge\", both are used, and if it's \"error\", the record fails.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"metadataWins", "configWins", "merge", "error"}},
+			},
+		},
+		"labelField": {
+			Default:     "",
+			Description: "The name of a payload field which holds the labels of each record's element, a string of labels separated by \":\" or a list of them. The field isn't written as a property. Records without the field are written with the entityLabels.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"logRedactProperties": {
+			Default:     "",
+			Description: "Holds a list of property names which values are replaced with \"***\" in logged queries and parameters.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"matchRelationshipsByEndpoints": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will match relationships on updates and deletes by the keys of their sourceNode and targetNode along with their own key. Deletes take the endpoints from the payload before the change.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"maxConnectionLifetime": {
+			Default:     "1h",
+			Description: "The maximum lifetime of a pooled connection, after which it's closed and replaced with a new one.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"maxConnectionPoolSize": {
+			Default:     "100",
+			Description: "The maximum number of connections in the pool per server.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{},
+		},
+		"maxLabels": {
+			Default:     "0",
+			Description: "The max number of labels of a written node, including the labels from metadata and of relationship endpoints created with the createEndpoints. Records exceeding it fail. If it's 0, the number is unlimited.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{
+				sdk.ValidationGreaterThan{Value: -1},
+			},
+		},
+		"maxProperties": {
+			Default:     "0",
+			Description: "The max number of payload properties of a written element, including relationship endpoints created with the createEndpoints. Records exceeding it fail. If it's 0, the number is unlimited.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{
+				sdk.ValidationGreaterThan{Value: -1},
+			},
+		},
+		"metadataPropertyPrefix": {
+			Default:     "metadata_",
+			Description: "The prefix of the names of the properties the metadataToProperties are written to, so they don't collide with the payload properties.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"metadataToProperties": {
+			Default:     "",
+			Description: "The record metadata fields which values are written as properties on each create and update, e.g. \"source.region\". The properties are named by the fields with the metadataPropertyPrefix. The fields reserved for internal use, which start with \"opencdc.\", \"conduit.\" or \"neo4j.\", are skipped.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"nestedObjects": {
+			Default:     "keep",
+			Description: "Determines how nested objects of payloads are written, as Neo4j cannot store maps as property values. If it's \"keep\", they're written as they are, so records with them fail. If it's \"json\", they're serialized into JSON string properties, and if it's \"flatten\", their properties are written as separate properties with prefixed names, e.g. \"address.city\". If it's \"apoc\", they're serialized server-side with apoc.convert.toJson, or with the nestedObjectsFallback if the APOC isn't installed.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"keep", "json", "flatten", "apoc"}},
+			},
+		},
+		"nestedObjectsFallback": {
+			Default:     "json",
+			Description: "Determines how nested objects are written if the nestedObjects is \"apoc\" but the APOC isn't installed.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"json", "flatten"}},
+			},
+		},
+		"onError": {
+			Default:     "stop",
+			Description: "Determines what to do if a record fails with a permanent error, e.g. a malformed payload or a constraint violation. If it's \"stop\", the write fails, if it's \"skip\", the record is logged and skipped. Transient errors always fail the write.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"stop", "skip"}},
+			},
+		},
+		"processedAtProperty": {
+			Default:     "",
+			Description: "The name of a property that is set to the current time on each create and update, so it reflects when the connector processed a record. If it's empty, no property is set.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"queryTimeout": {
+			Default:     "",
+			Description: "The maximum amount of time a transaction that reads a batch or writes records may run, after which Neo4j terminates it and the error is treated as a transient one. If it's not set, the server's default transaction timeout applies.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"removeNullProperties": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will remove the properties that are null in the payload of an update from the element. If it's false, null properties are ignored and the element keeps their current values.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"resolver": {
+			Default:     "",
+			Description: "Holds a list of \"advertised=actual\" address entries, e.g. \"neo4j.example.com:7687=10.0.0.1:7687\", the initial address of the uri is resolved to the actual addresses of its entries. The driver resolves only routed neo4j://, neo4j+s:// and neo4j+ssc:// uris, so it fails with other schemes.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"retryBatch": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will replay the whole batch of records if it fails transiently. Records written before the failure are written again, so it's only safe if writes are idempotent.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"retryBatchBackoff": {
+			Default:     "1s",
+			Description: "The duration to wait before replaying a batch of records.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"retryBatchJitter": {
+			Default:     "500ms",
+			Description: "The max random duration added to the retryBatchBackoff, so replays of multiple connectors after a coordinated failure, e.g. a cluster failover, spread out.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"retryBatchMaxAttempts": {
+			Default:     "3",
+			Description: "The maximum number of attempts to write a batch of records, including the first one.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{
+				sdk.ValidationGreaterThan{Value: 0},
+			},
+		},
+		"serverComputedProperties.*": {
+			Default:     "",
+			Description: "The properties which values are computed server-side on each create and update instead of being taken from the payload, e.g. \"serverComputedProperties.id\" set to \"randomUUID()\". The allowed functions are randomUUID, timestamp, datetime, localdatetime, date, time and localtime.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"skipDatabaseCheck": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will skip checking that the database exists on start.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"skipPermissionCheck": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will skip checking on open that the user can write the entityLabels into the database, which creates an element in a transaction that is rolled back.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"skipUnchanged": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will set properties of an update only if any of them differs from the current state of the element, so unchanged updates don't produce writes.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"startupRetryTimeout": {
+			Default:     "",
+			Description: "The maximum amount of time to retry connecting to Neo4j on start with an exponential backoff, so the connector waits for a server that is still starting up. If it's not set, connecting is not retried.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"strictCardinality": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will fail an update or delete that affects not exactly one element, e.g. if the key properties don't identify elements uniquely.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"tls.caFile": {
+			Default:     "",
+			Description: "The path to a PEM file with certificates of the authorities the connector trusts, instead of the system ones, e.g. if the server certificate is signed by a custom CA.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"tls.enabled": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will encrypt the connection. If it's true, the \"bolt\" and \"neo4j\" URI schemes are replaced with \"bolt+s\" and \"neo4j+s\".",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"tls.insecureSkipVerify": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will skip verifying the server certificate. If it's true, the URI scheme is replaced with \"bolt+ssc\" or \"neo4j+ssc\".",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"transactionSize": {
+			Default:     "0",
+			Description: "The maximum number of records written in a single transaction. If it's 0, all records of a batch are written in a single transaction.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{},
+		},
 		"uri": {
 			Default:     "",
 			Description: "The connection uri pointed to a Neo4j instance.",
@@ -58,5 +362,31 @@ func (Config) Parameters() map[string]sdk.Parameter {
 				sdk.ValidationRequired{},
 			},
 		},
+		"vectorDimensions": {
+			Default:     "0",
+			Description: "The number of dimensions each of the vectorProperties must have, a vector of another size fails the record. If it's 0, the dimensions are not validated.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{
+				sdk.ValidationGreaterThan{Value: -1},
+			},
+		},
+		"vectorProperties": {
+			Default:     "",
+			Description: "Holds a list of property names which values are vectors, e.g. node embeddings, that are read and written as lists of floats, including the ones stored as JSON strings.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"warmupConnections": {
+			Default:     "0",
+			Description: "The number of connections opened and verified concurrently on start to pre-fill the pool, so the first queries don't wait for connections to be established. If it's not set, no connections are opened in advance.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{},
+		},
+		"writeRateLimit": {
+			Default:     "0",
+			Description: "The max number of records written per second, which can be fractional, e.g. 0.5 permits a record every two seconds, so the writes don't overwhelm a shared cluster. Each transaction waits until all of its records are permitted before it starts. If it's 0, the writes are not limited.",
+			Type:        sdk.ParameterTypeFloat,
+			Validations: []sdk.Validation{},
+		},
 	}
 }
@@ -17,14 +17,87 @@ package destination
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
 	"github.com/conduitio-labs/conduit-connector-neo4j/destination/mock"
+	"github.com/conduitio-labs/conduit-connector-neo4j/destination/writer"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/mock/gomock"
 )
 
+func TestDestination_Configure_entityLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		raw     map[string]string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "success",
+			raw:  map[string]string{config.KeyEntityLabels: " Person ,,Writer"},
+			want: []string{"Person", "Writer"},
+		},
+		{
+			name: "success_labelField",
+			raw:  map[string]string{config.KeyEntityLabels: "", ConfigKeyLabelField: "labels"},
+			want: []string{},
+		},
+		{
+			name:    "fail_empty",
+			raw:     map[string]string{config.KeyEntityLabels: ""},
+			wantErr: errNoLabels,
+		},
+		{
+			name:    "fail_only_empty_elements",
+			raw:     map[string]string{config.KeyEntityLabels: " , "},
+			wantErr: config.ErrNoEntityLabels,
+		},
+		{
+			name: "fail_labelField_createConstraints",
+			raw: map[string]string{
+				ConfigKeyLabelField:        "labels",
+				ConfigKeyCreateConstraints: "true",
+				ConfigKeyKeyProperties:     "id",
+			},
+			wantErr: errConstraintsNoEntityLabels,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			raw := map[string]string{
+				config.KeyURI:        "bolt://localhost:7687",
+				config.KeyEntityType: string(config.EntityTypeNode),
+			}
+			for key, value := range tt.raw {
+				raw[key] = value
+			}
+
+			d := Destination{}
+
+			err := d.Configure(context.Background(), raw)
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(d.config.EntityLabels, tt.want)
+		})
+	}
+}
+
 func TestDestination_Write_success(t *testing.T) {
 	t.Parallel()
 
@@ -34,7 +107,7 @@ func TestDestination_Write_success(t *testing.T) {
 	ctx := context.Background()
 
 	it := mock.NewMockWriter(ctrl)
-	it.EXPECT().Write(ctx, sdk.Record{}).Return(nil)
+	it.EXPECT().Write(ctx, []sdk.Record{{}}).Return(1, nil)
 
 	d := Destination{writer: it}
 
@@ -52,10 +125,185 @@ func TestDestination_Write_failInsertRecord(t *testing.T) {
 	ctx := context.Background()
 
 	it := mock.NewMockWriter(ctrl)
-	it.EXPECT().Write(ctx, sdk.Record{}).Return(errors.New("insert record: fail"))
+	it.EXPECT().Write(ctx, []sdk.Record{{}}).Return(0, errors.New("insert record: fail"))
 
 	d := Destination{writer: it}
 
 	_, err := d.Write(ctx, []sdk.Record{{}})
 	is.True(err != nil)
 }
+
+func TestDestination_Write_successRetryBatch(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	first := sdk.Record{Position: sdk.Position("1")}
+	second := sdk.Record{Position: sdk.Position("2")}
+
+	// the first attempt fails transiently on the second record,
+	// so the whole batch is replayed
+	it := mock.NewMockWriter(ctrl)
+	gomock.InOrder(
+		it.EXPECT().Write(ctx, []sdk.Record{first, second}).
+			Return(1, &neo4j.Neo4jError{Code: "Neo.TransientError.General.DatabaseUnavailable"}),
+		it.EXPECT().Write(ctx, []sdk.Record{first, second}).Return(2, nil),
+	)
+
+	d := Destination{
+		config: Config{RetryBatch: true, RetryBatchMaxAttempts: 2},
+		writer: it,
+	}
+
+	records, err := d.Write(ctx, []sdk.Record{first, second})
+	is.NoErr(err)
+	is.Equal(records, 2)
+}
+
+func TestDestination_Write_successRetryBatchClassified(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	// the writer classifies the error as transient, so the batch is replayed
+	it := mock.NewMockWriter(ctrl)
+	gomock.InOrder(
+		it.EXPECT().Write(ctx, []sdk.Record{{}}).Return(0, fmt.Errorf("%w: deadlock detected", writer.ErrTransient)),
+		it.EXPECT().Write(ctx, []sdk.Record{{}}).Return(1, nil),
+	)
+
+	d := Destination{
+		config: Config{RetryBatch: true, RetryBatchMaxAttempts: 2},
+		writer: it,
+	}
+
+	records, err := d.Write(ctx, []sdk.Record{{}})
+	is.NoErr(err)
+	is.Equal(records, 1)
+}
+
+func TestDestination_Write_failRetryBatchConstraintViolation(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	// the constraint violation is permanent, so the batch isn't replayed, and the class is kept for callers
+	it := mock.NewMockWriter(ctrl)
+	it.EXPECT().Write(ctx, []sdk.Record{{}}).
+		Return(0, fmt.Errorf("%w: duplicate key", writer.ErrConstraintViolation))
+
+	d := Destination{
+		config: Config{RetryBatch: true, RetryBatchMaxAttempts: 2},
+		writer: it,
+	}
+
+	records, err := d.Write(ctx, []sdk.Record{{}})
+	is.True(errors.Is(err, writer.ErrConstraintViolation))
+	is.Equal(records, 0)
+}
+
+func TestDestination_Write_failRetryBatchNotTransient(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	it := mock.NewMockWriter(ctrl)
+	it.EXPECT().Write(ctx, []sdk.Record{{}}).Return(0, errors.New("insert record: fail"))
+
+	d := Destination{
+		config: Config{RetryBatch: true, RetryBatchMaxAttempts: 2},
+		writer: it,
+	}
+
+	records, err := d.Write(ctx, []sdk.Record{{}})
+	is.True(err != nil)
+	is.Equal(records, 0)
+}
+
+func TestDestination_Write_failPartialBatch(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	records := []sdk.Record{{Position: sdk.Position("1")}, {Position: sdk.Position("2")}}
+
+	// the transaction of the first record is committed before the second one fails
+	it := mock.NewMockWriter(ctrl)
+	it.EXPECT().Write(ctx, records).Return(1, errors.New("insert record: fail"))
+
+	d := Destination{writer: it}
+
+	n, err := d.Write(ctx, records)
+	is.True(err != nil)
+	is.Equal(n, 1)
+}
+
+func TestDestination_Write_coalesceCreateDelete(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	createAlice := sdk.Record{Operation: sdk.OperationCreate, Key: sdk.StructuredData{"name": "Alice"}}
+	createBob := sdk.Record{Operation: sdk.OperationCreate, Key: sdk.StructuredData{"name": "Bob"}}
+	deleteAlice := sdk.Record{Operation: sdk.OperationDelete, Key: sdk.StructuredData{"name": "Alice"}}
+
+	// the create and the delete of Alice cancel each other out, so only Bob is written,
+	// but all records are reported as written
+	it := mock.NewMockWriter(ctrl)
+	it.EXPECT().Write(ctx, []sdk.Record{createBob}).Return(1, nil)
+
+	d := Destination{
+		config: Config{CoalesceCreateDelete: true},
+		writer: it,
+	}
+
+	records, err := d.Write(ctx, []sdk.Record{createAlice, createBob, deleteAlice})
+	is.NoErr(err)
+	is.Equal(records, 3)
+}
+
+func TestDestination_Write_coalesceCreateDeleteIntervened(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	batch := []sdk.Record{
+		{Operation: sdk.OperationCreate, Key: sdk.StructuredData{"name": "Alice"}},
+		{Operation: sdk.OperationUpdate, Key: sdk.StructuredData{"name": "Alice"}},
+		{Operation: sdk.OperationDelete, Key: sdk.StructuredData{"name": "Alice"}},
+	}
+
+	// the update between the create and the delete keeps all of them
+	it := mock.NewMockWriter(ctrl)
+	it.EXPECT().Write(ctx, batch).Return(3, nil)
+
+	d := Destination{
+		config: Config{CoalesceCreateDelete: true},
+		writer: it,
+	}
+
+	records, err := d.Write(ctx, batch)
+	is.NoErr(err)
+	is.Equal(records, 3)
+}
@@ -0,0 +1,169 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	// nodeConstraintQueryTemplate is a template of a query that creates a uniqueness constraint
+	// of the key properties of nodes with a label.
+	nodeConstraintQueryTemplate = "CREATE CONSTRAINT IF NOT EXISTS FOR (obj:%s) REQUIRE (%s) IS UNIQUE"
+	// relationshipConstraintQueryTemplate is a template of a query that creates a uniqueness constraint
+	// of the key properties of relationships with a type.
+	relationshipConstraintQueryTemplate = "CREATE CONSTRAINT IF NOT EXISTS FOR ()-[obj:%s]-() REQUIRE (%s) IS UNIQUE"
+	// serverAgentFormat is a format of the server agent which the server version is parsed from.
+	serverAgentFormat = "Neo4j/%d.%d"
+	// enterpriseEditionMessage is a part of the message of the error the Community Edition fails
+	// enterprise-only constraints with, e.g. "Node Key constraint requires Neo4j Enterprise Edition".
+	enterpriseEditionMessage = "Enterprise Edition"
+)
+
+// minRelationshipConstraintVersion is the minimum Neo4j version, major and minor,
+// that supports relationship uniqueness constraints.
+var minRelationshipConstraintVersion = [2]int{5, 7}
+
+// ConstraintsParams holds parameters of the [CreateConstraints].
+type ConstraintsParams struct {
+	Driver        neo4j.DriverWithContext
+	DatabaseName  string
+	EntityType    config.EntityType
+	EntityLabels  []string
+	KeyProperties []string
+}
+
+// CreateConstraints creates uniqueness constraints of the key properties if they don't exist,
+// so concurrent writes of the same element fail instead of creating duplicates.
+//
+// A constraint is created for each of the entity labels of nodes, or for the relationship type.
+// Relationship constraints are skipped with a warning if the server doesn't support them,
+// as well as constraints that require the Enterprise Edition, e.g. composite ones on Neo4j 4.x Community.
+func CreateConstraints(ctx context.Context, params ConstraintsParams) error {
+	if params.EntityType == config.EntityTypeRelationship {
+		supported, err := supportsRelationshipConstraints(ctx, params.Driver)
+		if err != nil {
+			return fmt.Errorf("check relationship constraints support: %w", err)
+		}
+
+		if !supported {
+			sdk.Logger(ctx).Warn().
+				Msgf("the server doesn't support relationship constraints, they require Neo4j %d.%d or later",
+					minRelationshipConstraintVersion[0], minRelationshipConstraintVersion[1])
+
+			return nil
+		}
+	}
+
+	queries, err := constraintQueries(params.EntityType, params.EntityLabels, params.KeyProperties)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range queries {
+		result, err := neo4j.ExecuteQuery(ctx, params.Driver, query, nil, neo4j.EagerResultTransformer,
+			neo4j.ExecuteQueryWithDatabase(params.DatabaseName),
+		)
+
+		switch {
+		case requiresEnterpriseEdition(err):
+			sdk.Logger(ctx).Warn().Err(err).Str("query", query).
+				Msg("skipped the constraint, it isn't supported by the server edition")
+
+			continue
+
+		case err != nil:
+			return fmt.Errorf("execute query %q: %w", query, permissionError(err))
+		}
+
+		// the constraint isn't added if it already exists
+		if result.Summary.Counters().ConstraintsAdded() > 0 {
+			sdk.Logger(ctx).Info().Str("query", query).Msg("created the constraint")
+		} else {
+			sdk.Logger(ctx).Debug().Str("query", query).Msg("the constraint already exists")
+		}
+	}
+
+	return nil
+}
+
+// constraintQueries returns queries that create the constraints of the key properties.
+func constraintQueries(entityType config.EntityType, entityLabels, keyProperties []string) ([]string, error) {
+	properties := make([]string, len(keyProperties))
+	for i, keyProperty := range keyProperties {
+		properties[i] = "obj." + escapeIdentifier(keyProperty)
+	}
+
+	joinedProperties := strings.Join(properties, ", ")
+
+	switch entityType {
+	case config.EntityTypeNode:
+		queries := make([]string, len(entityLabels))
+		for i, label := range entityLabels {
+			queries[i] = fmt.Sprintf(nodeConstraintQueryTemplate, escapeIdentifier(label), joinedProperties)
+		}
+
+		return queries, nil
+
+	case config.EntityTypeRelationship:
+		return []string{
+			fmt.Sprintf(relationshipConstraintQueryTemplate, cypherLabels(entityLabels), joinedProperties),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEntityType, entityType)
+	}
+}
+
+// requiresEnterpriseEdition checks if the error is a Neo4j error about a constraint
+// that is available only in the Enterprise Edition.
+func requiresEnterpriseEdition(err error) bool {
+	var neo4jError *neo4j.Neo4jError
+
+	return errors.As(err, &neo4jError) && strings.Contains(neo4jError.Msg, enterpriseEditionMessage)
+}
+
+// supportsRelationshipConstraints checks if the server version is not lower than the minimum one.
+// Servers with an agent that cannot be parsed are considered supporting them.
+func supportsRelationshipConstraints(ctx context.Context, driver neo4j.DriverWithContext) (bool, error) {
+	serverInfo, err := driver.GetServerInfo(ctx)
+	if err != nil {
+		return false, fmt.Errorf("get server info: %w", err)
+	}
+
+	return agentSupportsRelationshipConstraints(serverInfo.Agent()), nil
+}
+
+// agentSupportsRelationshipConstraints checks if the server version from the agent
+// is not lower than the minimum one.
+func agentSupportsRelationshipConstraints(agent string) bool {
+	var major, minor int
+	if _, err := fmt.Sscanf(agent, serverAgentFormat, &major, &minor); err != nil {
+		return true
+	}
+
+	if major != minRelationshipConstraintVersion[0] {
+		return major > minRelationshipConstraintVersion[0]
+	}
+
+	return minor >= minRelationshipConstraintVersion[1]
+}
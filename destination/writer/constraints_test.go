@@ -0,0 +1,99 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestConstraintQueries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		entityType   config.EntityType
+		entityLabels []string
+		want         []string
+	}{
+		{
+			name:         "node",
+			entityType:   config.EntityTypeNode,
+			entityLabels: []string{"Person", "Writer"},
+			want: []string{
+				"CREATE CONSTRAINT IF NOT EXISTS FOR (obj:`Person`) REQUIRE (obj.`id`, obj.`region`) IS UNIQUE",
+				"CREATE CONSTRAINT IF NOT EXISTS FOR (obj:`Writer`) REQUIRE (obj.`id`, obj.`region`) IS UNIQUE",
+			},
+		},
+		{
+			name:         "relationship",
+			entityType:   config.EntityTypeRelationship,
+			entityLabels: []string{"KNOWS"},
+			want: []string{
+				"CREATE CONSTRAINT IF NOT EXISTS FOR ()-[obj:`KNOWS`]-() REQUIRE (obj.`id`, obj.`region`) IS UNIQUE",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			got, err := constraintQueries(tt.entityType, tt.entityLabels, []string{"id", "region"})
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestAgentSupportsRelationshipConstraints(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.True(!agentSupportsRelationshipConstraints("Neo4j/4.4.30"))
+	is.True(!agentSupportsRelationshipConstraints("Neo4j/5.6.0"))
+	is.True(agentSupportsRelationshipConstraints("Neo4j/5.7.0"))
+	is.True(agentSupportsRelationshipConstraints("Neo4j/5.26-aura"))
+	is.True(agentSupportsRelationshipConstraints("Neo4j/2025.01.0"))
+	is.True(agentSupportsRelationshipConstraints("unknown"))
+}
+
+func TestRequiresEnterpriseEdition(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	enterpriseErr := &neo4j.Neo4jError{
+		Code: "Neo.DatabaseError.Schema.ConstraintCreationFailed",
+		Msg:  "Unable to create Constraint( type='NODE KEY' ): Node Key constraint requires Neo4j Enterprise Edition",
+	}
+
+	is.True(requiresEnterpriseEdition(enterpriseErr))
+	is.True(requiresEnterpriseEdition(fmt.Errorf("execute query: %w", enterpriseErr)))
+	is.True(!requiresEnterpriseEdition(&neo4j.Neo4jError{
+		Code: "Neo.ClientError.Schema.EquivalentSchemaRuleAlreadyExists",
+		Msg:  "An equivalent constraint already exists",
+	}))
+	is.True(!requiresEnterpriseEdition(errors.New("Node Key constraint requires Neo4j Enterprise Edition")))
+	is.True(!requiresEnterpriseEdition(nil))
+}
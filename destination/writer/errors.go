@@ -14,15 +14,118 @@
 
 package writer
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	// parts of Neo4j error codes, e.g. "Neo.ClientError.Schema.ConstraintValidationFailed",
+	// that are used to classify errors.
+	clientErrorClassification      = "ClientError"
+	statementErrorCategory         = "Statement"
+	constraintValidationFailedCode = "Neo.ClientError.Schema.ConstraintValidationFailed"
+)
 
 var (
 	// ErrEmptyRawData occurs when trying to structurize empty [sdk.RawData].
 	ErrEmptyRawData = errors.New("empty raw data")
 	// ErrUnsupportedEntityType occurs when the entityType is unsupported by the [Writer].
 	ErrUnsupportedEntityType = errors.New("unsupported entity type")
+	// ErrMissingEntityLabels occurs when the entity type of a record is taken from its metadata
+	// and differs from the configured one, but the metadata doesn't contain the entity labels.
+	ErrMissingEntityLabels = errors.New("missing entity labels in metadata")
 	// ErrEmptySourceNode occurs when the entityType is relationship but a payload doesn't contain sourceNode.
 	ErrEmptySourceNode = errors.New("empty source node")
 	// ErrEmptyTargetNode occurs when the entityType is relationship but a payload doesn't contain targetNode.
 	ErrEmptyTargetNode = errors.New("empty target node")
+	// ErrAppendKeyProperty occurs when an append property is a part of a record key.
+	ErrAppendKeyProperty = errors.New("append property is a part of the record key")
+	// ErrEndpointsOnNode occurs when the entityType is node but a payload contains sourceNode or targetNode.
+	ErrEndpointsOnNode = errors.New("relationship endpoints in a node payload")
+	// ErrIntegerOverflow occurs when a payload contains an integer exceeding the int64 range,
+	// which Neo4j cannot store.
+	ErrIntegerOverflow = errors.New("integer exceeds the int64 range")
+	// ErrUnsupportedList occurs when a payload contains a list Neo4j cannot store as an array property,
+	// i.e. one with null, nested list or map items, or items of different types.
+	ErrUnsupportedList = errors.New("unsupported list")
+	// ErrNestedPropertyCollision occurs when a flattened property of a nested object
+	// has the same name as another property of a payload.
+	ErrNestedPropertyCollision = errors.New("flattened nested property collides with another property")
+	// ErrLabelConflict occurs when labels from record metadata differ from the configured ones
+	// and the conflict cannot be resolved according to the label conflict behavior.
+	ErrLabelConflict = errors.New("labels from metadata conflict with the configured labels")
+	// ErrEmptyLabels occurs when no labels are resolved for a record, e.g. if the entityLabels are empty,
+	// as an element without labels would make an invalid query.
+	ErrEmptyLabels = errors.New("empty labels")
+	// ErrMissingLabelField occurs when a record has no labelField and there are no labels to fall back to.
+	ErrMissingLabelField = errors.New("missing label field")
+	// ErrInvalidLabelField occurs when the labelField of a record isn't a string or a list of strings of labels,
+	// or it holds more than one type of a relationship.
+	ErrInvalidLabelField = errors.New("invalid label field")
+	// ErrTooManyLabels occurs when an element of a record has more labels than the maxLabels.
+	ErrTooManyLabels = errors.New("too many labels")
+	// ErrTooManyProperties occurs when an element of a record has more properties than the maxProperties.
+	ErrTooManyProperties = errors.New("too many properties")
+	// ErrMissingKeyForUpdate occurs when an update record has no key and it cannot be derived from the payload.
+	ErrMissingKeyForUpdate = errors.New("missing key for update")
+	// ErrMissingKeyForDelete occurs when a delete record has no key and it cannot be derived from the payload.
+	ErrMissingKeyForDelete = errors.New("missing key for delete")
+	// ErrUnsupportedServerFunction occurs when a server computed property uses a function
+	// that isn't in the list of the allowed ones.
+	ErrUnsupportedServerFunction = errors.New("unsupported server function")
+	// ErrCardinalityViolation occurs when the strictCardinality is enabled
+	// and an update or delete affected not exactly one element.
+	ErrCardinalityViolation = errors.New("the record affected not exactly one element")
+
+	// ErrTransient wraps errors of writes that may succeed if they're retried,
+	// e.g. deadlocks, connectivity errors and cluster leader switches.
+	ErrTransient = errors.New("transient error")
+	// ErrConstraintViolation wraps errors of writes that violate a schema constraint, e.g. a uniqueness one.
+	ErrConstraintViolation = errors.New("constraint violation")
+	// ErrPermissionDenied occurs when the user isn't allowed to write into the database,
+	// e.g. if it's a read-only account or the database is read-only.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrInvalidQuery wraps errors of writes which queries Neo4j rejects,
+	// e.g. syntax errors and values of unsupported types.
+	ErrInvalidQuery = errors.New("invalid query")
 )
+
+// classifyError wraps the error of a write with the [ErrTransient], the [ErrConstraintViolation]
+// or the [ErrInvalidQuery] depending on the underlying Neo4j error, so callers can tell them apart with errors.Is,
+// and the underlying error can still be unwrapped. Other errors are returned as they are.
+func classifyError(err error) error {
+	var (
+		connectivityError *neo4j.ConnectivityError
+		executionLimit    *neo4j.TransactionExecutionLimit
+		neo4jError        *neo4j.Neo4jError
+	)
+
+	switch {
+	case errors.As(err, &connectivityError), errors.As(err, &executionLimit):
+		// the driver stops retrying a transaction on the execution limit only if errors are retryable
+		return fmt.Errorf("%w: %w", ErrTransient, err)
+
+	case config.IsQueryTimeout(err):
+		// the driver doesn't retry transactions that exceeded the timeout, but they may succeed once replayed
+		return fmt.Errorf("%w: %w", ErrTransient, err)
+
+	case !errors.As(err, &neo4jError):
+		return err
+
+	case neo4jError.IsRetriable():
+		return fmt.Errorf("%w: %w", ErrTransient, err)
+
+	case neo4jError.Code == constraintValidationFailedCode:
+		return fmt.Errorf("%w: %w", ErrConstraintViolation, err)
+
+	case neo4jError.Classification() == clientErrorClassification && neo4jError.Category() == statementErrorCategory:
+		return fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+
+	default:
+		return err
+	}
+}
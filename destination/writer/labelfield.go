@@ -0,0 +1,125 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// recordLabels returns the labels of a record's element joined with ":". If the labelField is set,
+// they're taken from the field of the properties, which is a string of labels separated by ":"
+// or a list of labels, and the field is removed from the properties, so it isn't written as a property.
+// Otherwise, or if the field is missing, they're resolved with the [Writer.resolveLabels].
+//
+// An element without labels would make an invalid query, e.g. "CREATE (obj: {...})",
+// so if no labels are resolved, the [ErrMissingLabelField] or the [ErrEmptyLabels] is returned.
+func (w *Writer) recordLabels(properties map[string]any, metadata sdk.Metadata) (string, error) {
+	if w.labelField != "" {
+		value, ok := properties[w.labelField]
+		delete(properties, w.labelField)
+
+		if ok && value != nil {
+			return w.fieldLabels(value)
+		}
+	}
+
+	labels, err := w.resolveLabels(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case labels != "":
+		return labels, nil
+
+	// there are neither the entityLabels nor the labels from the metadata to fall back to
+	case w.labelField != "":
+		return "", fmt.Errorf("%w: %q", ErrMissingLabelField, w.labelField)
+
+	default:
+		return "", ErrEmptyLabels
+	}
+}
+
+// fieldLabels returns the labels of a labelField value joined with ":".
+func (w *Writer) fieldLabels(value any) (string, error) {
+	labels, err := parseFieldLabels(value)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %w", ErrInvalidLabelField, w.labelField, err)
+	}
+
+	if len(labels) == 0 {
+		return "", fmt.Errorf("%w %q: it contains no labels", ErrInvalidLabelField, w.labelField)
+	}
+
+	if w.entityType == config.EntityTypeRelationship && len(labels) > 1 {
+		return "", fmt.Errorf("%w %q: a relationship has a single type, got %q",
+			ErrInvalidLabelField, w.labelField, strings.Join(labels, labelsSeparator))
+	}
+
+	if err := w.checkLabels(len(labels)); err != nil {
+		return "", err
+	}
+
+	return cypherLabels(labels), nil
+}
+
+// deleteLabels returns the labels of a delete record's element joined with ":",
+// the labelField is taken from the payload before the change, as deletes have no payload after it.
+func (w *Writer) deleteLabels(record sdk.Record) (string, error) {
+	var properties map[string]any
+	if w.labelField != "" && record.Payload.Before != nil && len(record.Payload.Before.Bytes()) > 0 {
+		var err error
+
+		properties, err = w.structurizeRawData(record.Payload.Before.Bytes())
+		if err != nil {
+			return "", fmt.Errorf("structurize record payload before: %w", err)
+		}
+	}
+
+	return w.recordLabels(properties, record.Metadata)
+}
+
+// parseFieldLabels returns the normalized labels of a labelField value,
+// which is either a string of labels separated by ":" or a list of label strings.
+func parseFieldLabels(value any) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return config.NormalizeLabels(strings.Split(v, labelsSeparator)), nil
+
+	case []string:
+		return config.NormalizeLabels(v), nil
+
+	case []any:
+		labels := make([]string, len(v))
+		for i, item := range v {
+			label, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("the list item %v is a %T, not a string", item, item)
+			}
+
+			labels[i] = label
+		}
+
+		return config.NormalizeLabels(labels), nil
+
+	default:
+		return nil, fmt.Errorf("the value %v is a %T, not a string or a list of strings", value, value)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+func TestWriter_recordLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		entityType   config.EntityType
+		entityLabels []string
+		maxLabels    int
+		properties   map[string]any
+		want         string
+		wantErr      error
+	}{
+		{
+			name:       "string",
+			properties: map[string]any{"labels": "Writer:Person", "name": "Alice"},
+			want:       "`Person`:`Writer`",
+		},
+		{
+			name:       "list",
+			properties: map[string]any{"labels": []any{"Writer", " Person ", "Writer"}, "name": "Alice"},
+			want:       "`Person`:`Writer`",
+		},
+		{
+			name:       "escaped",
+			properties: map[string]any{"labels": "Person`) DETACH DELETE (n", "name": "Alice"},
+			want:       "`Person``) DETACH DELETE (n`",
+		},
+		{
+			name:         "missing_field",
+			entityLabels: []string{"Person"},
+			properties:   map[string]any{"name": "Alice"},
+			want:         "`Person`",
+		},
+		{
+			name:         "null_field",
+			entityLabels: []string{"Person"},
+			properties:   map[string]any{"labels": nil, "name": "Alice"},
+			want:         "`Person`",
+		},
+		{
+			name:       "fail_missing_field_no_entityLabels",
+			properties: map[string]any{"name": "Alice"},
+			wantErr:    ErrMissingLabelField,
+		},
+		{
+			name:       "fail_not_string",
+			properties: map[string]any{"labels": int64(1), "name": "Alice"},
+			wantErr:    ErrInvalidLabelField,
+		},
+		{
+			name:       "fail_list_item_not_string",
+			properties: map[string]any{"labels": []any{"Person", int64(1)}, "name": "Alice"},
+			wantErr:    ErrInvalidLabelField,
+		},
+		{
+			name:       "fail_empty",
+			properties: map[string]any{"labels": " : ", "name": "Alice"},
+			wantErr:    ErrInvalidLabelField,
+		},
+		{
+			name:       "fail_relationship_types",
+			entityType: config.EntityTypeRelationship,
+			properties: map[string]any{"labels": "KNOWS:LIKES", "name": "Alice"},
+			wantErr:    ErrInvalidLabelField,
+		},
+		{
+			name:       "fail_max_labels",
+			maxLabels:  1,
+			properties: map[string]any{"labels": "Writer:Person", "name": "Alice"},
+			wantErr:    ErrTooManyLabels,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			entityType := tt.entityType
+			if entityType == "" {
+				entityType = config.EntityTypeNode
+			}
+
+			writer := New(Params{
+				EntityType:   entityType,
+				EntityLabels: tt.entityLabels,
+				LabelField:   "labels",
+				MaxLabels:    tt.maxLabels,
+			})
+
+			got, err := writer.recordLabels(tt.properties, sdk.Metadata{})
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+			is.Equal(tt.properties, map[string]any{"name": "Alice"}) // the labelField isn't written as a property
+		})
+	}
+}
+
+func TestWriter_deleteLabels(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{EntityType: config.EntityTypeNode, LabelField: "labels"})
+
+	got, err := writer.deleteLabels(sdk.Record{
+		Operation: sdk.OperationDelete,
+		Key:       sdk.RawData(`{"id":1}`),
+		Payload:   sdk.Change{Before: sdk.RawData(`{"id":1,"labels":["Person"]}`)},
+	})
+	is.NoErr(err)
+	is.Equal(got, "`Person`")
+
+	_, err = writer.deleteLabels(sdk.Record{Operation: sdk.OperationDelete, Key: sdk.RawData(`{"id":1}`)})
+	is.True(errors.Is(err, ErrMissingLabelField))
+}
+
+func TestWriter_recordLabels_empty(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// the writer has neither the entityLabels nor the labelField, so the query would have an empty label
+	writer := New(Params{EntityType: config.EntityTypeNode})
+
+	_, err := writer.recordLabels(map[string]any{"name": "Alice"}, sdk.Metadata{})
+	is.True(errors.Is(err, ErrEmptyLabels))
+}
@@ -0,0 +1,67 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+)
+
+// checkLabels checks that an element with the number of labels doesn't exceed the maxLabels.
+func (w *Writer) checkLabels(labels int) error {
+	if w.maxLabels > 0 && labels > w.maxLabels {
+		return fmt.Errorf("%w: %d, the max is %d", ErrTooManyLabels, labels, w.maxLabels)
+	}
+
+	return nil
+}
+
+// checkProperties checks that an element with the number of properties doesn't exceed the maxProperties.
+// The properties are counted as they come in the payload, without the ones added by the writer,
+// e.g. the processedAtProperty.
+func (w *Writer) checkProperties(properties int) error {
+	if w.maxProperties > 0 && properties > w.maxProperties {
+		return fmt.Errorf("%w: %d, the max is %d", ErrTooManyProperties, properties, w.maxProperties)
+	}
+
+	return nil
+}
+
+// checkRelationshipLimits checks that a relationship doesn't exceed the maxProperties,
+// and if the createEndpoints is enabled, its endpoints, which are created along with it,
+// don't exceed the maxLabels and the maxProperties either.
+func (w *Writer) checkRelationshipLimits(properties map[string]any, sourceNode, targetNode *schema.Node) error {
+	if err := w.checkProperties(len(properties)); err != nil {
+		return err
+	}
+
+	if !w.createEndpoints {
+		return nil
+	}
+
+	for _, node := range []*schema.Node{sourceNode, targetNode} {
+		if err := w.checkLabels(len(config.NormalizeLabels(node.Labels))); err != nil {
+			return fmt.Errorf("endpoint: %w", err)
+		}
+
+		if err := w.checkProperties(len(node.Key) + len(node.Properties)); err != nil {
+			return fmt.Errorf("endpoint: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+func TestWriter_resolveLabels_maxLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                  string
+		labelConflictBehavior LabelConflictBehavior
+		metadata              sdk.Metadata
+		wantErr               error
+	}{
+		{
+			name:                  "config_wins",
+			labelConflictBehavior: LabelConflictBehaviorConfigWins,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Writer:Author:Poet"},
+		},
+		{
+			name:                  "metadata_wins",
+			labelConflictBehavior: LabelConflictBehaviorMetadataWins,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Writer:Author"},
+		},
+		{
+			name:                  "metadata_wins_exceeded",
+			labelConflictBehavior: LabelConflictBehaviorMetadataWins,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Writer:Author:Poet"},
+			wantErr:               ErrTooManyLabels,
+		},
+		{
+			name:                  "merge_exceeded",
+			labelConflictBehavior: LabelConflictBehaviorMerge,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Writer:Author"},
+			wantErr:               ErrTooManyLabels,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			writer := New(Params{
+				EntityLabels:          []string{"Person"},
+				LabelConflictBehavior: tt.labelConflictBehavior,
+				MaxLabels:             2,
+			})
+
+			_, err := writer.resolveLabels(tt.metadata)
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+
+				return
+			}
+
+			is.NoErr(err)
+		})
+	}
+}
+
+func TestWriter_checkProperties(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.NoErr(New(Params{}).checkProperties(1000))
+	is.NoErr(New(Params{MaxProperties: 2}).checkProperties(2))
+	is.True(errors.Is(New(Params{MaxProperties: 2}).checkProperties(3), ErrTooManyProperties))
+}
+
+func TestWriter_checkRelationshipLimits(t *testing.T) {
+	t.Parallel()
+
+	endpoint := &schema.Node{
+		Labels:     []string{"Person", "Author"},
+		Key:        map[string]any{"id": 1},
+		Properties: map[string]any{"name": "Alice", "age": 30},
+	}
+
+	tests := []struct {
+		name       string
+		params     Params
+		properties map[string]any
+		wantErr    error
+	}{
+		{
+			name:       "success",
+			params:     Params{MaxLabels: 2, MaxProperties: 3, CreateEndpoints: true},
+			properties: map[string]any{"since": 2020},
+		},
+		{
+			name:       "properties_exceeded",
+			params:     Params{MaxProperties: 1},
+			properties: map[string]any{"since": 2020, "weight": 1},
+			wantErr:    ErrTooManyProperties,
+		},
+		{
+			name:       "endpoint_labels_exceeded",
+			params:     Params{MaxLabels: 1, CreateEndpoints: true},
+			properties: map[string]any{"since": 2020},
+			wantErr:    ErrTooManyLabels,
+		},
+		{
+			name:       "endpoint_properties_exceeded",
+			params:     Params{MaxProperties: 2, CreateEndpoints: true},
+			properties: map[string]any{"since": 2020},
+			wantErr:    ErrTooManyProperties,
+		},
+		{
+			// existing endpoints are only matched, so their labels and properties aren't limited
+			name:       "endpoints_matched",
+			params:     Params{MaxLabels: 1, MaxProperties: 1},
+			properties: map[string]any{"since": 2020},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			err := New(tt.params).checkRelationshipLimits(tt.properties, endpoint, endpoint)
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+
+				return
+			}
+
+			is.NoErr(err)
+		})
+	}
+}
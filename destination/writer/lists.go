@@ -0,0 +1,139 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// convertLists recursively converts lists into slices of their item types, e.g. []string,
+// which Neo4j stores as array properties. Lists of other types, e.g. temporal values, are kept as they are
+// if all of their items have the same type. Lists with null, nested list or map items, or items of different types
+// cannot be stored by Neo4j, so the [ErrUnsupportedList] is returned for them.
+func convertLists(value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for name, item := range v {
+			convertedItem, err := convertLists(item)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", name, err)
+			}
+
+			v[name] = convertedItem
+		}
+
+		return v, nil
+
+	case []any:
+		return convertList(v)
+
+	default:
+		return value, nil
+	}
+}
+
+// convertList converts a list into a slice of its item type.
+//
+// Numbers are decoded into float64, except big integers, which are decoded into int64,
+// so a list mixing them is converted into []int64 if all of its float64 items are integers.
+func convertList(items []any) (any, error) {
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	var floats, integers int
+	for i, item := range items {
+		switch item.(type) {
+		case nil:
+			return nil, fmt.Errorf("%w: item %d is null", ErrUnsupportedList, i)
+
+		case map[string]any, []any:
+			return nil, fmt.Errorf("%w: item %d is a %T", ErrUnsupportedList, i, item)
+
+		case float64:
+			floats++
+
+		case int64:
+			integers++
+		}
+
+		if floats+integers == 0 && reflect.TypeOf(item) != reflect.TypeOf(items[0]) {
+			return nil, fmt.Errorf("%w: item %d is a %T, item 0 is a %T", ErrUnsupportedList, i, item, items[0])
+		}
+	}
+
+	switch {
+	case floats == len(items):
+		return typedList[float64](items), nil
+
+	case integers == len(items):
+		return typedList[int64](items), nil
+
+	case floats+integers == len(items):
+		return integerList(items)
+
+	case floats+integers > 0:
+		return nil, fmt.Errorf("%w: numbers are mixed with items of other types", ErrUnsupportedList)
+	}
+
+	switch items[0].(type) {
+	case string:
+		return typedList[string](items), nil
+
+	case bool:
+		return typedList[bool](items), nil
+
+	default:
+		return items, nil
+	}
+}
+
+// typedList converts a list which items are all of the type T into a slice of T.
+func typedList[T any](items []any) []T {
+	list := make([]T, len(items))
+	for i, item := range items {
+		list[i] = item.(T) //nolint:forcetypeassert // the item types are checked by the caller
+	}
+
+	return list
+}
+
+// integerList converts a list of float64 and int64 numbers into []int64,
+// if all of its float64 items are integers.
+func integerList(items []any) ([]int64, error) {
+	list := make([]int64, len(items))
+	for i, item := range items {
+		switch number := item.(type) {
+		case int64:
+			list[i] = number
+
+		case float64:
+			if number != math.Trunc(number) {
+				return nil, fmt.Errorf("%w: item %d is a fraction in a list of big integers", ErrUnsupportedList, i)
+			}
+
+			list[i] = int64(number)
+		}
+	}
+
+	return list, nil
+}
+
+// isList checks if the value is a list, either the decoded []any or a slice the [convertLists] converts it into.
+func isList(value any) bool {
+	return value != nil && reflect.TypeOf(value).Kind() == reflect.Slice
+}
@@ -0,0 +1,157 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestWriter_structurizeRawData_lists(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rawData sdk.RawData
+		want    map[string]any
+		wantErr error
+	}{
+		{
+			name:    "success_strings",
+			rawData: sdk.RawData(`{"tags":["a","b"]}`),
+			want:    map[string]any{"tags": []string{"a", "b"}},
+		},
+		{
+			name:    "success_bools",
+			rawData: sdk.RawData(`{"flags":[true,false]}`),
+			want:    map[string]any{"flags": []bool{true, false}},
+		},
+		{
+			name:    "success_floats",
+			rawData: sdk.RawData(`{"scores":[1,2.5]}`),
+			want:    map[string]any{"scores": []float64{1, 2.5}},
+		},
+		{
+			name:    "success_big_integers",
+			rawData: sdk.RawData(`{"ids":[1,9007199254740993]}`),
+			want:    map[string]any{"ids": []int64{1, 9007199254740993}},
+		},
+		{
+			name:    "success_dates",
+			rawData: sdk.RawData(`{"days":[{"neo4jType":"date","value":"1990-02-03"}]}`),
+			want:    map[string]any{"days": []any{dbtype.Date(time.Date(1990, 2, 3, 0, 0, 0, 0, time.UTC))}},
+		},
+		{
+			name:    "success_empty",
+			rawData: sdk.RawData(`{"tags":[]}`),
+			want:    map[string]any{"tags": []any{}},
+		},
+		{
+			name:    "success_endpoint_labels",
+			rawData: sdk.RawData(`{"sourceNode":{"labels":["Person"],"key":{"id":1}}}`),
+			want: map[string]any{"sourceNode": map[string]any{
+				"labels": []string{"Person"},
+				"key":    map[string]any{"id": float64(1)},
+			}},
+		},
+		{
+			name:    "fail_mixed_types",
+			rawData: sdk.RawData(`{"tags":["a",1]}`),
+			wantErr: ErrUnsupportedList,
+		},
+		{
+			name:    "fail_fraction_with_big_integers",
+			rawData: sdk.RawData(`{"ids":[1.5,9007199254740993]}`),
+			wantErr: ErrUnsupportedList,
+		},
+		{
+			name:    "fail_null_item",
+			rawData: sdk.RawData(`{"tags":["a",null]}`),
+			wantErr: ErrUnsupportedList,
+		},
+		{
+			name:    "fail_nested_list",
+			rawData: sdk.RawData(`{"matrix":[[1],[2]]}`),
+			wantErr: ErrUnsupportedList,
+		},
+		{
+			name:    "fail_map_item",
+			rawData: sdk.RawData(`{"items":[{"name":"a"}]}`),
+			wantErr: ErrUnsupportedList,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			got, err := New(Params{}).structurizeRawData(tt.rawData)
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestWriter_wrapAppendProperties_typedList(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{AppendProperties: []string{"tags"}})
+
+	properties := map[string]any{"tags": []string{"a", "b"}}
+	writer.wrapAppendProperties(properties)
+
+	is.Equal(properties, map[string]any{"tags": []string{"a", "b"}})
+}
+
+func TestWriter_structurizeRawData_vectors(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{Vectors: schema.Vectors{Properties: []string{"embedding", "features"}, Dimensions: 3}})
+
+	// a vector of integral values with a big integer would be converted into []int64 as a regular list
+	properties, err := writer.structurizeRawData(sdk.RawData(
+		`{"embedding":[1,9007199254740993,0.5],"features":"[0.25,1,2]","tags":["a"]}`,
+	))
+	is.NoErr(err)
+
+	is.NoErr(writer.vectors.Convert(properties))
+	is.Equal(properties, map[string]any{
+		"embedding": []float64{1, 9007199254740993, 0.5},
+		"features":  []float64{0.25, 1, 2},
+		"tags":      []string{"a"},
+	})
+
+	properties, err = writer.structurizeRawData(sdk.RawData(`{"embedding":[1,2]}`))
+	is.NoErr(err)
+	is.True(errors.Is(writer.vectors.Convert(properties), schema.ErrInvalidVector))
+}
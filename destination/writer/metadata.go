@@ -0,0 +1,62 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"strings"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// reservedMetadataPrefixes holds prefixes of the metadata fields that Conduit and the Neo4j connectors
+// set for their own use, e.g. "opencdc.readAt" or "neo4j.entityLabels".
+var reservedMetadataPrefixes = []string{"opencdc.", "conduit.", "neo4j."}
+
+// IsReservedMetadata checks if the metadata field is reserved for internal use,
+// such fields aren't written as properties.
+func IsReservedMetadata(field string) bool {
+	for _, prefix := range reservedMetadataPrefixes {
+		if strings.HasPrefix(field, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// metadataProperties maps the metadata fields to the names of the properties they're written to,
+// which are the fields with the prefix. The reserved fields are skipped.
+func metadataProperties(fields []string, prefix string) map[string]string {
+	properties := make(map[string]string, len(fields))
+	for _, field := range fields {
+		if IsReservedMetadata(field) {
+			continue
+		}
+
+		properties[field] = prefix + field
+	}
+
+	return properties
+}
+
+// setMetadataProperties sets the properties of the metadata fields to their values,
+// the fields the record's metadata doesn't have are skipped.
+func (w *Writer) setMetadataProperties(properties map[string]any, metadata sdk.Metadata) {
+	for field, property := range w.metadataProperties {
+		if value, ok := metadata[field]; ok {
+			properties[property] = value
+		}
+	}
+}
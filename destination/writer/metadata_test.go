@@ -0,0 +1,67 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"testing"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+func TestIsReservedMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		field string
+		want  bool
+	}{
+		{field: "source.region", want: false},
+		{field: "traceId", want: false},
+		{field: "opencdc.readAt", want: true},
+		{field: "conduit.source.connector.id", want: true},
+		{field: "neo4j.entityLabels", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+			is.Equal(IsReservedMetadata(tt.field), tt.want)
+		})
+	}
+}
+
+func TestWriter_setMetadataProperties(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	w := New(Params{
+		MetadataToProperties:   []string{"source.region", "traceId", "opencdc.readAt"},
+		MetadataPropertyPrefix: "metadata_",
+	})
+
+	properties := map[string]any{"name": "Alice"}
+	w.setMetadataProperties(properties, sdk.Metadata{
+		"source.region":  "eu-west-1",
+		"opencdc.readAt": "1700000000000000000",
+		"custom":         "not listed",
+	})
+
+	// the reserved, unlisted and missing fields aren't written
+	is.Equal(properties, map[string]any{"name": "Alice", "metadata_source.region": "eu-west-1"})
+}
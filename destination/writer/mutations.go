@@ -0,0 +1,133 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// MutationCounts holds the numbers of graph mutations Neo4j reported for the written records.
+// Neo4j doesn't count updated elements, so updates are reflected by the PropertiesSet.
+type MutationCounts struct {
+	NodesCreated         int
+	NodesDeleted         int
+	RelationshipsCreated int
+	RelationshipsDeleted int
+	PropertiesSet        int
+	LabelsAdded          int
+	LabelsRemoved        int
+}
+
+// add adds the counters of a query to the counts.
+func (m *MutationCounts) add(counters neo4j.Counters) {
+	m.NodesCreated += counters.NodesCreated()
+	m.NodesDeleted += counters.NodesDeleted()
+	m.RelationshipsCreated += counters.RelationshipsCreated()
+	m.RelationshipsDeleted += counters.RelationshipsDeleted()
+	m.PropertiesSet += counters.PropertiesSet()
+	m.LabelsAdded += counters.LabelsAdded()
+	m.LabelsRemoved += counters.LabelsRemoved()
+}
+
+// merge adds the other counts to the counts.
+func (m *MutationCounts) merge(other MutationCounts) {
+	m.NodesCreated += other.NodesCreated
+	m.NodesDeleted += other.NodesDeleted
+	m.RelationshipsCreated += other.RelationshipsCreated
+	m.RelationshipsDeleted += other.RelationshipsDeleted
+	m.PropertiesSet += other.PropertiesSet
+	m.LabelsAdded += other.LabelsAdded
+	m.LabelsRemoved += other.LabelsRemoved
+}
+
+// mutationCounter aggregates the counters of the queries of a write.
+// The pending counts belong to the transaction in progress, and they're committed along with it,
+// so the counts of rolled back transactions, e.g. the ones retried without a skipped record, are dropped.
+// It's shared by the copies of the [Writer] made for records of the other entity type.
+type mutationCounter struct {
+	committed MutationCounts
+	pending   MutationCounts
+}
+
+// record adds the counters of a query to the pending counts, it does nothing if the counter is nil.
+func (c *mutationCounter) record(counters neo4j.Counters) {
+	if c == nil {
+		return
+	}
+
+	c.pending.add(counters)
+}
+
+// reset drops the counts of the previous write.
+func (c *mutationCounter) reset() {
+	if c == nil {
+		return
+	}
+
+	*c = mutationCounter{}
+}
+
+// begin drops the pending counts at the start of a transaction, which may be an internal retry of the driver.
+func (c *mutationCounter) begin() {
+	if c == nil {
+		return
+	}
+
+	c.pending = MutationCounts{}
+}
+
+// commit adds the pending counts to the committed ones after their transaction is committed.
+func (c *mutationCounter) commit() {
+	if c == nil {
+		return
+	}
+
+	c.committed.merge(c.pending)
+	c.pending = MutationCounts{}
+}
+
+// Mutations returns the numbers of graph mutations made by the transactions committed during the last write,
+// they're zero unless the countMutations is enabled.
+func (w *Writer) Mutations() MutationCounts {
+	if w.mutations == nil {
+		return MutationCounts{}
+	}
+
+	return w.mutations.committed
+}
+
+// logMutations logs the numbers of graph mutations made by the last write along with the number of records
+// of the committed transactions, it does nothing unless the countMutations is enabled.
+func (w *Writer) logMutations(ctx context.Context, records int) {
+	if w.mutations == nil {
+		return
+	}
+
+	mutations := w.mutations.committed
+
+	sdk.Logger(ctx).Info().
+		Int("records", records).
+		Int("nodesCreated", mutations.NodesCreated).
+		Int("nodesDeleted", mutations.NodesDeleted).
+		Int("relationshipsCreated", mutations.RelationshipsCreated).
+		Int("relationshipsDeleted", mutations.RelationshipsDeleted).
+		Int("propertiesSet", mutations.PropertiesSet).
+		Int("labelsAdded", mutations.LabelsAdded).
+		Int("labelsRemoved", mutations.LabelsRemoved).
+		Msg("wrote records")
+}
@@ -0,0 +1,110 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// mutationCounters reports the numbers of graph mutations made by a query.
+type mutationCounters struct {
+	neo4j.Counters
+
+	counts MutationCounts
+}
+
+func (c mutationCounters) NodesCreated() int {
+	return c.counts.NodesCreated
+}
+
+func (c mutationCounters) NodesDeleted() int {
+	return c.counts.NodesDeleted
+}
+
+func (c mutationCounters) RelationshipsCreated() int {
+	return c.counts.RelationshipsCreated
+}
+
+func (c mutationCounters) RelationshipsDeleted() int {
+	return c.counts.RelationshipsDeleted
+}
+
+func (c mutationCounters) PropertiesSet() int {
+	return c.counts.PropertiesSet
+}
+
+func (c mutationCounters) LabelsAdded() int {
+	return c.counts.LabelsAdded
+}
+
+func (c mutationCounters) LabelsRemoved() int {
+	return c.counts.LabelsRemoved
+}
+
+func TestMutationCounter_mixedBatch(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{CountMutations: true})
+
+	// the first transaction creates a node and a relationship, and updates a node
+	writer.mutations.begin()
+	writer.mutations.record(mutationCounters{counts: MutationCounts{NodesCreated: 1, PropertiesSet: 2, LabelsAdded: 1}})
+	writer.mutations.record(mutationCounters{counts: MutationCounts{RelationshipsCreated: 1, PropertiesSet: 1}})
+	writer.mutations.record(mutationCounters{counts: MutationCounts{PropertiesSet: 1}})
+	writer.mutations.commit()
+
+	// the second transaction is rolled back after a delete, so its counts are dropped on the retry
+	writer.mutations.begin()
+	writer.mutations.record(mutationCounters{counts: MutationCounts{NodesDeleted: 1}})
+
+	// the retry detach deletes a node and deletes a relationship
+	writer.mutations.begin()
+	writer.mutations.record(mutationCounters{counts: MutationCounts{NodesDeleted: 1, RelationshipsDeleted: 2}})
+	writer.mutations.record(mutationCounters{counts: MutationCounts{RelationshipsDeleted: 1}})
+	writer.mutations.commit()
+
+	is.Equal(writer.Mutations(), MutationCounts{
+		NodesCreated:         1,
+		NodesDeleted:         1,
+		RelationshipsCreated: 1,
+		RelationshipsDeleted: 3,
+		PropertiesSet:        4,
+		LabelsAdded:          1,
+	})
+
+	// the next write starts from zero
+	writer.mutations.reset()
+	is.Equal(writer.Mutations(), MutationCounts{})
+}
+
+func TestWriter_Mutations_disabled(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{})
+
+	// the nil counter ignores the counters
+	writer.mutations.begin()
+	writer.mutations.record(mutationCounters{counts: MutationCounts{NodesCreated: 1}})
+	writer.mutations.commit()
+
+	is.Equal(writer.Mutations(), MutationCounts{})
+}
@@ -0,0 +1,240 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	// nestedPropertySeparator separates the names of flattened nested properties, e.g. "address.city".
+	nestedPropertySeparator = "."
+	// apocToJSONFunction is a name of the APOC function that serializes values into JSON strings.
+	apocToJSONFunction = "apoc.convert.toJson"
+	// detectFunctionQuery is a query that checks if a function is available on the server.
+	detectFunctionQuery = "SHOW FUNCTIONS YIELD name WHERE name = $name RETURN count(name) > 0 AS available"
+	// apocToJSONQuery is a query that serializes a list of values into JSON strings with the APOC,
+	// the strings are returned in the order of the values.
+	apocToJSONQuery = "UNWIND $values AS value RETURN " + apocToJSONFunction + "(value) AS json"
+)
+
+// NestedObjects defines how nested objects of payloads are written,
+// as Neo4j cannot store maps as property values.
+type NestedObjects string
+
+// The available behaviors for nested objects are listed below.
+const (
+	// NestedObjectsKeep passes nested objects to Neo4j as they are.
+	NestedObjectsKeep NestedObjects = "keep"
+	// NestedObjectsJSON serializes nested objects into JSON string properties.
+	NestedObjectsJSON NestedObjects = "json"
+	// NestedObjectsFlatten writes the properties of nested objects as separate properties,
+	// which names are prefixed with the names of the objects, e.g. "address.city".
+	NestedObjectsFlatten NestedObjects = "flatten"
+	// NestedObjectsAPOC serializes nested objects into JSON string properties server-side
+	// with the apoc.convert.toJson function.
+	NestedObjectsAPOC NestedObjects = "apoc"
+)
+
+// ResolveNestedObjects returns the nestedObjects, or the fallback if the nestedObjects is apoc
+// and the APOC isn't installed on the server.
+func ResolveNestedObjects(
+	ctx context.Context,
+	driver neo4j.DriverWithContext,
+	databaseName string,
+	nestedObjects, fallback NestedObjects,
+) (NestedObjects, error) {
+	if nestedObjects != NestedObjectsAPOC {
+		return nestedObjects, nil
+	}
+
+	available, err := functionAvailable(ctx, driver, databaseName, apocToJSONFunction)
+	if err != nil {
+		return "", fmt.Errorf("detect %s: %w", apocToJSONFunction, err)
+	}
+
+	if !available {
+		sdk.Logger(ctx).Warn().Str("fallback", string(fallback)).
+			Msgf("%s isn't available, nested objects are written with the fallback", apocToJSONFunction)
+
+		return fallback, nil
+	}
+
+	return nestedObjects, nil
+}
+
+// functionAvailable checks if the function is available on the server.
+func functionAvailable(ctx context.Context, driver neo4j.DriverWithContext, databaseName, name string) (bool, error) {
+	result, err := neo4j.ExecuteQuery(ctx, driver, detectFunctionQuery, map[string]any{"name": name},
+		neo4j.EagerResultTransformer,
+		neo4j.ExecuteQueryWithDatabase(databaseName),
+		neo4j.ExecuteQueryWithReadersRouting(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("execute query: %w", err)
+	}
+
+	if len(result.Records) == 0 {
+		return false, nil
+	}
+
+	available, _, err := neo4j.GetRecordValue[bool](result.Records[0], "available")
+	if err != nil {
+		return false, fmt.Errorf("get available: %w", err)
+	}
+
+	return available, nil
+}
+
+// serializesNestedObjects checks if nested objects are serialized into JSON strings,
+// so they can hold values Neo4j cannot store as properties, e.g. lists of objects.
+func (w *Writer) serializesNestedObjects() bool {
+	return w.nestedObjects == NestedObjectsJSON || w.nestedObjects == NestedObjectsAPOC
+}
+
+// convertNestedObjects converts the nested objects of the properties according to the nestedObjects.
+func (w *Writer) convertNestedObjects(
+	ctx context.Context,
+	tx neo4j.ManagedTransaction,
+	properties map[string]any,
+) error {
+	switch w.nestedObjects {
+	case NestedObjectsJSON:
+		return jsonNestedObjects(properties)
+
+	case NestedObjectsFlatten:
+		return flattenNestedObjects(properties)
+
+	case NestedObjectsAPOC:
+		return apocNestedObjects(ctx, tx, properties)
+
+	default:
+		return nil
+	}
+}
+
+// jsonNestedObjects serializes the nested objects of the properties into JSON strings.
+// Temporal and spatial values are serialized as their models, the same way the source emits them.
+func jsonNestedObjects(properties map[string]any) error {
+	for name, value := range properties {
+		object, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		serialized, err := json.Marshal(schema.EncodeValues(object))
+		if err != nil {
+			return fmt.Errorf("marshal %q property: %w", name, err)
+		}
+
+		properties[name] = string(serialized)
+	}
+
+	return nil
+}
+
+// flattenNestedObjects replaces the nested objects of the properties with their properties,
+// which names are prefixed with the names of the objects, e.g. "address.city".
+// If a flattened name is already taken, the [ErrNestedPropertyCollision] is returned.
+func flattenNestedObjects(properties map[string]any) error {
+	for _, name := range sortedNames(properties) {
+		object, ok := properties[name].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		delete(properties, name)
+
+		if err := flattenObject(properties, name, object); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flattenObject adds the properties of the object to the properties recursively.
+func flattenObject(properties map[string]any, prefix string, object map[string]any) error {
+	for name, value := range object {
+		flattenedName := prefix + nestedPropertySeparator + name
+
+		if nested, ok := value.(map[string]any); ok {
+			if err := flattenObject(properties, flattenedName, nested); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if _, ok := properties[flattenedName]; ok {
+			return fmt.Errorf("%w: %q", ErrNestedPropertyCollision, flattenedName)
+		}
+
+		properties[flattenedName] = value
+	}
+
+	return nil
+}
+
+// apocNestedObjects serializes the nested objects of the properties into JSON strings server-side
+// with the APOC, all of them are serialized by a single query.
+func apocNestedObjects(ctx context.Context, tx neo4j.ManagedTransaction, properties map[string]any) error {
+	var (
+		names  []string
+		values []any
+	)
+
+	for _, name := range sortedNames(properties) {
+		if _, ok := properties[name].(map[string]any); ok {
+			names = append(names, name)
+			values = append(values, properties[name])
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	result, err := tx.Run(ctx, apocToJSONQuery, map[string]any{"values": values})
+	if err != nil {
+		return fmt.Errorf("run %s query: %w", apocToJSONFunction, err)
+	}
+
+	records, err := result.Collect(ctx)
+	if err != nil {
+		return fmt.Errorf("collect %s results: %w", apocToJSONFunction, err)
+	}
+
+	if len(records) != len(names) {
+		return fmt.Errorf("%s returned %d values, want %d", apocToJSONFunction, len(records), len(names))
+	}
+
+	for i, record := range records {
+		serialized, _, err := neo4j.GetRecordValue[string](record, "json")
+		if err != nil {
+			return fmt.Errorf("get json of %q property: %w", names[i], err)
+		}
+
+		properties[names[i]] = serialized
+	}
+
+	return nil
+}
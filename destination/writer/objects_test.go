@@ -0,0 +1,116 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+func TestWriter_convertNestedObjects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		nestedObjects NestedObjects
+		rawData       sdk.RawData
+		want          map[string]any
+		wantErr       error
+	}{
+		{
+			name:          "success_keep",
+			nestedObjects: NestedObjectsKeep,
+			rawData:       sdk.RawData(`{"id":1,"address":{"city":"Kyiv"}}`),
+			want:          map[string]any{"id": float64(1), "address": map[string]any{"city": "Kyiv"}},
+		},
+		{
+			name:          "success_json",
+			nestedObjects: NestedObjectsJSON,
+			rawData: sdk.RawData(
+				`{"id":1,"address":{"city":"Kyiv","since":{"neo4jType":"date","value":"1990-02-03"},` +
+					`"phones":[{"number":"1"}]}}`,
+			),
+			want: map[string]any{
+				"id": float64(1),
+				"address": `{"city":"Kyiv","phones":[{"number":"1"}],` +
+					`"since":{"neo4jType":"date","value":"1990-02-03"}}`,
+			},
+		},
+		{
+			name:          "success_flatten",
+			nestedObjects: NestedObjectsFlatten,
+			rawData:       sdk.RawData(`{"id":1,"address":{"city":"Kyiv","geo":{"zip":"01001"}},"tags":["a"]}`),
+			want: map[string]any{
+				"id":              float64(1),
+				"address.city":    "Kyiv",
+				"address.geo.zip": "01001",
+				"tags":            []string{"a"},
+			},
+		},
+		{
+			name:          "fail_flatten_collision",
+			nestedObjects: NestedObjectsFlatten,
+			rawData:       sdk.RawData(`{"address":{"city":"Kyiv"},"address.city":"Lviv"}`),
+			wantErr:       ErrNestedPropertyCollision,
+		},
+		{
+			name:          "fail_flatten_list_of_objects",
+			nestedObjects: NestedObjectsFlatten,
+			rawData:       sdk.RawData(`{"address":{"phones":[{"number":"1"}]}}`),
+			wantErr:       ErrUnsupportedList,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			writer := New(Params{NestedObjects: tt.nestedObjects})
+
+			properties, err := writer.structurizeRawData(tt.rawData)
+			if err == nil {
+				err = writer.convertNestedObjects(context.Background(), nil, properties)
+			}
+
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(properties, tt.want)
+		})
+	}
+}
+
+func TestResolveNestedObjects_notAPOC(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// the server is only queried to detect the APOC, so no driver is needed for the other behaviors
+	nestedObjects, err := ResolveNestedObjects(context.Background(), nil, "neo4j",
+		NestedObjectsFlatten, NestedObjectsJSON,
+	)
+	is.NoErr(err)
+	is.Equal(nestedObjects, NestedObjectsFlatten)
+}
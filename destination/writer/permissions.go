@@ -0,0 +1,108 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	// nodePermissionQueryTemplate is a template of a query that creates a node with the entity labels,
+	// e.g. "CREATE (obj:`Person`) RETURN count(obj)".
+	nodePermissionQueryTemplate = "CREATE (obj%s) RETURN count(obj)"
+	// relationshipPermissionQueryTemplate is a template of a query that creates a relationship of the entity type
+	// between two new nodes, e.g. "CREATE ()-[obj:`KNOWS`]->() RETURN count(obj)".
+	relationshipPermissionQueryTemplate = "CREATE ()-[obj:%s]->() RETURN count(obj)"
+	// forbiddenCode is a code of an error that Neo4j returns when the user lacks a privilege.
+	forbiddenCode = "Neo.ClientError.Security.Forbidden"
+	// readOnlyDatabaseCode is a code of an error that Neo4j returns on writes to a read-only database.
+	readOnlyDatabaseCode = "Neo.ClientError.General.ForbiddenOnReadOnlyDatabase"
+)
+
+// PermissionParams holds parameters of the [CheckWritePermission].
+type PermissionParams struct {
+	Driver       neo4j.DriverWithContext
+	DatabaseName string
+	EntityType   config.EntityType
+	EntityLabels []string
+}
+
+// CheckWritePermission checks that the user can write elements of the entity labels into the database,
+// so an account lacking the privileges fails on open rather than on the first write.
+//
+// It creates an element in a transaction that is always rolled back, so nothing is written.
+// If the user isn't allowed to write, the [ErrPermissionDenied] is returned.
+func CheckWritePermission(ctx context.Context, params PermissionParams) error {
+	session := params.Driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: params.DatabaseName,
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	tx, err := session.BeginTransaction(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", permissionError(err))
+	}
+	defer tx.Close(ctx)
+
+	query := permissionQuery(params.EntityType, params.EntityLabels)
+
+	result, err := tx.Run(ctx, query, nil)
+	if err == nil {
+		_, err = result.Consume(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("run query %q: %w", query, permissionError(err))
+	}
+
+	if err := tx.Rollback(ctx); err != nil {
+		return fmt.Errorf("rollback transaction: %w", err)
+	}
+
+	return nil
+}
+
+// permissionQuery returns a query that creates an element of the entity type with the entity labels.
+// A relationship cannot be created without a type, so nodes are created if there are no labels,
+// e.g. if they're taken from the labelField.
+func permissionQuery(entityType config.EntityType, entityLabels []string) string {
+	if entityType == config.EntityTypeRelationship && len(entityLabels) > 0 {
+		return fmt.Sprintf(relationshipPermissionQueryTemplate, cypherLabels(entityLabels))
+	}
+
+	var labels string
+	if len(entityLabels) > 0 {
+		labels = labelsSeparator + cypherLabels(entityLabels)
+	}
+
+	return fmt.Sprintf(nodePermissionQueryTemplate, labels)
+}
+
+// permissionError wraps the error with the [ErrPermissionDenied] if it's a Neo4j error
+// about a missing privilege or a read-only database, other errors are returned as they are.
+func permissionError(err error) error {
+	var neo4jError *neo4j.Neo4jError
+	if errors.As(err, &neo4jError) && (neo4jError.Code == forbiddenCode || neo4jError.Code == readOnlyDatabaseCode) {
+		return fmt.Errorf("%w: %w", ErrPermissionDenied, err)
+	}
+
+	return err
+}
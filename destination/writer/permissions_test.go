@@ -0,0 +1,138 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/mock"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckWritePermission(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:    "fail_forbidden",
+			err:     &neo4j.Neo4jError{Code: forbiddenCode, Msg: "Create node with labels 'Person' is not allowed"},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name:    "fail_read_only_database",
+			err:     &neo4j.Neo4jError{Code: readOnlyDatabaseCode},
+			wantErr: ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+			ctrl := gomock.NewController(t)
+
+			// the transaction is never committed, only rolled back if the query succeeds
+			tx, txMock := mock.NewExplicitTransaction(ctrl)
+			run := txMock.EXPECT().Run(gomock.Any(), "CREATE (obj:`Person`) RETURN count(obj)", nil)
+			if tt.err != nil {
+				run.Return(nil, tt.err)
+			} else {
+				result, resultMock := mock.NewResult(ctrl)
+				resultMock.EXPECT().Consume(gomock.Any()).Return(nil, nil)
+				run.Return(result, nil)
+				txMock.EXPECT().Rollback(gomock.Any()).Return(nil)
+			}
+			txMock.EXPECT().Close(gomock.Any()).Return(nil)
+
+			session, sessionMock := mock.NewSession(ctrl)
+			sessionMock.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+			sessionMock.EXPECT().Close(gomock.Any()).Return(nil)
+
+			driver := mock.NewMockDriver(ctrl)
+			driver.EXPECT().
+				NewSession(gomock.Any(), neo4j.SessionConfig{DatabaseName: "graph", AccessMode: neo4j.AccessModeWrite}).
+				Return(session)
+
+			err := CheckWritePermission(context.Background(), PermissionParams{
+				Driver:       driver,
+				DatabaseName: "graph",
+				EntityType:   config.EntityTypeNode,
+				EntityLabels: []string{"Person"},
+			})
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+			} else {
+				is.NoErr(err)
+			}
+		})
+	}
+}
+
+func TestPermissionQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		entityType   config.EntityType
+		entityLabels []string
+		want         string
+	}{
+		{
+			name:         "node",
+			entityType:   config.EntityTypeNode,
+			entityLabels: []string{"Writer", "Person"},
+			want:         "CREATE (obj:`Person`:`Writer`) RETURN count(obj)",
+		},
+		{
+			name:       "node_no_labels",
+			entityType: config.EntityTypeNode,
+			want:       "CREATE (obj) RETURN count(obj)",
+		},
+		{
+			name:         "relationship",
+			entityType:   config.EntityTypeRelationship,
+			entityLabels: []string{"KNOWS"},
+			want:         "CREATE ()-[obj:`KNOWS`]->() RETURN count(obj)",
+		},
+		{
+			name:       "relationship_no_type",
+			entityType: config.EntityTypeRelationship,
+			want:       "CREATE (obj) RETURN count(obj)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			is.Equal(permissionQuery(tt.entityType, tt.entityLabels), tt.want)
+		})
+	}
+}
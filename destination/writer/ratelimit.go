@@ -0,0 +1,57 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter limits the rate of written records, so the writes don't overwhelm a shared cluster.
+// It's a token bucket of one second worth of records, which is safe for concurrent use,
+// so writers sharing it are limited in total. The nil RateLimiter doesn't limit the writes.
+type RateLimiter struct {
+	limiter *rate.Limiter
+}
+
+// NewRateLimiter creates a new instance of the [RateLimiter] that permits the recordsPerSecond,
+// which can be fractional, e.g. 0.5 permits a record every two seconds.
+func NewRateLimiter(recordsPerSecond float64) *RateLimiter {
+	burst := max(1, int(math.Ceil(recordsPerSecond)))
+
+	return &RateLimiter{limiter: rate.NewLimiter(rate.Limit(recordsPerSecond), burst)}
+}
+
+// wait blocks until writing the n records is permitted, or the ctx is done.
+// The records beyond the bucket size are waited for in portions of it.
+func (r *RateLimiter) wait(ctx context.Context, n int) error {
+	if r == nil {
+		return nil
+	}
+
+	for n > 0 {
+		tokens := min(n, r.limiter.Burst())
+		if err := r.limiter.WaitN(ctx, tokens); err != nil {
+			return fmt.Errorf("wait for the rate limiter: %w", err)
+		}
+
+		n -= tokens
+	}
+
+	return nil
+}
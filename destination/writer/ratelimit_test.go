@@ -0,0 +1,109 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/mock"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	rateLimiter := NewRateLimiter(100)
+
+	// the bucket starts full with one second worth of records, the rest are spread over the next half a second,
+	// waiting for more records than the bucket holds at once doesn't fail
+	start := time.Now()
+	is.NoErr(rateLimiter.wait(context.Background(), 150))
+	is.True(time.Since(start) >= 490*time.Millisecond)
+}
+
+func TestRateLimiter_fractional(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	rateLimiter := NewRateLimiter(0.5)
+	is.Equal(rateLimiter.limiter.Burst(), 1)
+	is.NoErr(rateLimiter.wait(context.Background(), 1))
+
+	// the next record is permitted in two seconds, which is beyond the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	is.True(rateLimiter.wait(ctx, 1) != nil)
+}
+
+func TestRateLimiter_disabled(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	var rateLimiter *RateLimiter
+
+	start := time.Now()
+	is.NoErr(rateLimiter.wait(context.Background(), 1_000_000))
+	is.True(time.Since(start) < time.Second)
+}
+
+func TestWriter_Write_rateLimiter(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctrl := gomock.NewController(t)
+
+	// the transactions are committed without running them, only the times they start at are recorded
+	var starts []time.Time
+	session, sessionMock := mock.NewSession(ctrl)
+	sessionMock.EXPECT().ExecuteWrite(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(context.Context, neo4j.ManagedTransactionWork, ...func(*neo4j.TransactionConfig)) (any, error) {
+			starts = append(starts, time.Now())
+
+			// the result is asserted to the type of the transaction work result
+			return 0, nil
+		},
+	).Times(15)
+	sessionMock.EXPECT().Close(gomock.Any()).Return(nil).AnyTimes()
+
+	driver := mock.NewMockDriver(ctrl)
+	driver.EXPECT().NewSession(gomock.Any(), gomock.Any()).Return(session).AnyTimes()
+
+	records := make([]sdk.Record, 150)
+	for i := range records {
+		records[i] = sdk.Record{Operation: sdk.OperationCreate, Payload: sdk.Change{After: sdk.StructuredData{"id": i}}}
+	}
+
+	w := New(Params{Driver: driver, TransactionSize: 10, RateLimiter: NewRateLimiter(100)})
+
+	start := time.Now()
+	n, err := w.Write(context.Background(), records)
+	is.NoErr(err)
+	is.Equal(n, len(records))
+
+	// the first 100 records are written right away, the last 50 ones are throttled to 100 records per second
+	is.Equal(len(starts), 15)
+	is.True(starts[9].Sub(start) < 100*time.Millisecond)
+	is.True(starts[14].Sub(start) >= 490*time.Millisecond)
+}
@@ -16,12 +16,18 @@
 package writer
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/querylog"
 	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"github.com/mitchellh/mapstructure"
@@ -31,23 +37,111 @@ import (
 const (
 	// all Cypher queries used by the [Writer] are listed below in the format of Go fmt.
 	createNodeQueryTemplate         = "CREATE (obj:%s {%s})"
-	updateNodeQueryTemplate         = "MATCH (obj:%s {%s}) SET %s"
+	updateNodeQueryTemplate         = "MATCH (obj:%s {%s})%s"
 	deleteNodeQueryTemplate         = "MATCH (obj:%s {%s}) DELETE obj"
-	createRelationshipQueryTemplate = "MATCH (src:%s {%s}) MATCH (trgt:%s {%s}) CREATE (src)-[obj:%s {%s}]->(trgt)"
-	updateRelationshipQueryTemplate = "MATCH ()-[obj:%s {%s}]->() SET %s"
+	detachDeleteNodeQueryTemplate   = "MATCH (obj:%s {%s}) DETACH DELETE obj"
+	createRelationshipQueryTemplate = "%s %s CREATE (src)-[obj:%s {%s}]->(trgt)"
+	updateRelationshipQueryTemplate = "MATCH ()-[obj:%s {%s}]->()%s"
 	deleteRelationshipQueryTemplate = "MATCH ()-[obj:%s {%s}]->() DELETE obj"
 
+	// createRelationshipWithEndpointsQueryTemplate creates a relationship along with its endpoints.
+	createRelationshipWithEndpointsQueryTemplate = "CREATE (src:%s {%s})-[obj:%s {%s}]->(trgt:%s {%s})"
+
+	// relationship MATCH SET and MATCH DELETE queries that match the endpoints by their keys too.
+	updateRelationshipByEndpointsQueryTemplate = "MATCH (src:%s {%s})-[obj:%s {%s}]->(trgt:%s {%s})%s"
+	deleteRelationshipByEndpointsQueryTemplate = "MATCH (src:%s {%s})-[obj:%s {%s}]->(trgt:%s {%s}) DELETE obj"
+
+	// affectedReturnClause is added to updates if the strictCardinality is enabled,
+	// as the counters of a SET clause count properties instead of elements.
+	affectedReturnClause = " RETURN count(obj) AS " + affectedFieldName
+	affectedFieldName    = "affected"
+
+	// skipUnchangedSetClauseTemplate sets the properties of an update only if any of them differs
+	// from the current state, it keeps the matched element in the result, so it's still counted.
+	skipUnchangedSetClauseTemplate = " FOREACH (changed IN CASE WHEN %s THEN [1] ELSE [] END | SET %s)"
+	// changedPropertyTemplate checks if a property differs from its new value,
+	// including the cases where only one of them is null.
+	changedPropertyTemplate = "coalesce(%[1]s <> $%[2]s, %[1]s IS NOT NULL OR $%[2]s IS NOT NULL)"
+
+	// endpoint MATCH clauses that are added to the createRelationshipQueryTemplate.
+	matchEndpointClauseTemplate    = "MATCH (%s:%s {%s})"
+	matchAnyEndpointClauseTemplate = "MATCH (%s:%s) WHERE %s WITH * LIMIT 1"
+
 	// some helper symbols for Cypher queries.
 	setKeyPrefix              = "obj."
 	setAssignSign             = "="
+	appendSetTemplate         = "%[1]s = coalesce(%[1]s, []) + $%[2]s"
 	matchAssignSign           = ":"
 	interpolationSign         = "$"
 	interpolationSourcePrefix = "src_"
 	interpolationTargetPrefix = "trgt_"
+	srcPlaceholder            = "src"
+	trgtPlaceholder           = "trgt"
+	orSign                    = " OR "
+	identifierQuote           = "`"
+	setClausePrefix           = " SET "
+	functionCallSuffix        = "()"
 
 	// relationship payload-specific fields.
 	sourceNodeField = "sourceNode"
 	targetNodeField = "targetNode"
+
+	// maxExactFloatInteger is the max integer that float64 can represent exactly, it's 2^53.
+	maxExactFloatInteger = 1 << 53
+
+	// metadataEntityLabelsField is a name of a metadata field that holds entity labels
+	// joined with ":", the Neo4j source sets it for each record.
+	metadataEntityLabelsField = "neo4j.entityLabels"
+	// metadataEntityTypeField is a name of a metadata field that holds an entity type,
+	// the Neo4j source sets it if it emits both nodes and relationships.
+	metadataEntityTypeField = "neo4j.entityType"
+	// metadataSnapshotCompleteField is a name of a metadata field that marks the record
+	// the Neo4j source emits once its snapshot is complete, such records hold no data.
+	metadataSnapshotCompleteField = "neo4j.snapshotComplete"
+	labelsSeparator               = ":"
+)
+
+// serverFunctions holds names of the Cypher functions without arguments
+// that are allowed to compute property values server-side.
+var serverFunctions = map[string]struct{}{
+	"randomUUID":    {},
+	"timestamp":     {},
+	"datetime":      {},
+	"localdatetime": {},
+	"date":          {},
+	"time":          {},
+	"localtime":     {},
+}
+
+// EndpointsOnNode defines what to do when the entityType is node
+// but a payload contains the relationship-specific sourceNode or targetNode fields.
+type EndpointsOnNode string
+
+// The available behaviors for the endpoints on node are listed below.
+const (
+	EndpointsOnNodeStrip EndpointsOnNode = "strip"
+	EndpointsOnNodeError EndpointsOnNode = "error"
+)
+
+// OnError defines what to do when a record fails with a permanent error.
+type OnError string
+
+// The available behaviors on errors are listed below.
+const (
+	OnErrorStop OnError = "stop"
+	OnErrorSkip OnError = "skip"
+)
+
+// LabelConflictBehavior defines what to do when record metadata contains entity labels
+// that differ from the configured ones.
+type LabelConflictBehavior string
+
+// The available label conflict behaviors are listed below.
+const (
+	LabelConflictBehaviorMetadataWins LabelConflictBehavior = "metadataWins"
+	LabelConflictBehaviorConfigWins   LabelConflictBehavior = "configWins"
+	LabelConflictBehaviorMerge        LabelConflictBehavior = "merge"
+	LabelConflictBehaviorError        LabelConflictBehavior = "error"
 )
 
 // Writer implements a writer logic for the Neo4j Destination.
@@ -56,34 +150,370 @@ type Writer struct {
 	databaseName string
 	entityType   config.EntityType
 	entityLabels string
+	// labels holds the configured entity labels that are compared with labels from record metadata.
+	labels []string
+	// labelField is a name of a payload field which labels are used instead of the configured ones.
+	labelField string
+	// vectors defines the properties which values are written as lists of floats.
+	vectors               schema.Vectors
+	labelConflictBehavior LabelConflictBehavior
+	// processedAtProperty is a name of a property that is set to the current time on each write.
+	processedAtProperty string
+	// metadataProperties maps the metadata fields to the names of the properties they're written to.
+	metadataProperties map[string]string
+	// appendProperties holds names of properties which values
+	// are appended to a list property on updates instead of overwriting it.
+	appendProperties map[string]struct{}
+	endpointsOnNode  EndpointsOnNode
+	// sourceMatchProperties and targetMatchProperties hold names of endpoint key properties
+	// any of which is enough to match the endpoint, if they're empty, the whole key must match.
+	sourceMatchProperties []string
+	targetMatchProperties []string
+	// logRedactProperties holds names of properties which values are redacted in logged queries.
+	logRedactProperties []string
+	// createEndpoints defines if relationship endpoints are created along with relationships
+	// instead of matching existing nodes.
+	createEndpoints bool
+	// detachDelete defines if relationships of a node are deleted along with the node.
+	detachDelete bool
+	// matchByEndpoints defines if relationship updates and deletes match the relationship
+	// by the keys of its endpoints along with its own key.
+	matchByEndpoints bool
+	// strictCardinality defines if updates and deletes fail unless they affect exactly one element.
+	strictCardinality bool
+	// skipUnchanged defines if updates set properties only if any of them differs from the current state.
+	skipUnchanged bool
+	// removeNullProperties defines if null payload properties of updates remove the properties of the element.
+	removeNullProperties bool
+	// onError defines if records failing with permanent errors stop the write or are skipped.
+	onError OnError
+	// entityTypeFromMetadata defines if the entity type of each record is taken from its metadata,
+	// so a single writer writes both nodes and relationships.
+	entityTypeFromMetadata bool
+	// maxLabels and maxProperties are the max numbers of labels and properties of a written element,
+	// records exceeding them are rejected, zero means unlimited.
+	maxLabels     int
+	maxProperties int
+	// keyProperties holds names of properties that are used to derive a key from the payload
+	// of a record that has no key.
+	keyProperties []string
+	// transactionSize is the max number of records written in a single transaction,
+	// if it's zero, all records of a batch are written in a single transaction.
+	transactionSize int
+	// serverComputedProperties maps names of properties to names of the Cypher functions
+	// that compute their values server-side instead of taking them from payloads.
+	serverComputedProperties map[string]string
+	// nestedObjects defines how nested objects of payloads are written.
+	nestedObjects NestedObjects
+	// queryTimeout is the max execution time of a write transaction, zero keeps the server's default.
+	queryTimeout time.Duration
+	// rateLimiter limits the rate of written records, it's nil unless the rate limit is set.
+	rateLimiter *RateLimiter
+	// mutations aggregates the counters of graph mutations made by a write, it's nil if they aren't counted.
+	mutations *mutationCounter
 }
 
 // Params holds incoming params for the [Writer].
 type Params struct {
-	Driver       neo4j.DriverWithContext
-	DatabaseName string
-	EntityType   config.EntityType
-	EntityLabels []string
+	Driver           neo4j.DriverWithContext
+	DatabaseName     string
+	EntityType       config.EntityType
+	EntityLabels     []string
+	AppendProperties []string
+	EndpointsOnNode  EndpointsOnNode
+	// LabelConflictBehavior defines what to do when labels from record metadata
+	// differ from the EntityLabels, the empty value means the EntityLabels are used.
+	LabelConflictBehavior LabelConflictBehavior
+	// LabelField is a name of a payload field which holds the labels of each record's element,
+	// a string of labels separated by ":" or a list of them, the field isn't written as a property.
+	// Records without the field fall back to the EntityLabels, the empty LabelField disables it.
+	LabelField string
+	// Vectors defines the properties which values are written as lists of floats
+	// and checked to have the expected dimensions, the zero Vectors converts none.
+	Vectors schema.Vectors
+	// ProcessedAtProperty is a name of a property that is set to the current time on each write,
+	// the empty ProcessedAtProperty disables it.
+	ProcessedAtProperty string
+	// MetadataToProperties holds the metadata fields which values are written as properties
+	// named by the fields with the MetadataPropertyPrefix, the reserved fields are skipped.
+	MetadataToProperties   []string
+	MetadataPropertyPrefix string
+	// SourceMatchProperties and TargetMatchProperties are names of endpoint key properties
+	// any of which is enough to match the endpoint.
+	SourceMatchProperties []string
+	TargetMatchProperties []string
+	// LogRedactProperties are names of properties which values are redacted in logged queries.
+	LogRedactProperties []string
+	// CreateEndpoints defines if relationship endpoints are created along with relationships
+	// from their labels, key and properties, instead of matching existing nodes.
+	CreateEndpoints bool
+	// DetachDelete defines if relationships of a node are deleted along with the node,
+	// otherwise deleting a node that has relationships fails.
+	DetachDelete bool
+	// MatchRelationshipsByEndpoints defines if relationship updates and deletes match the relationship
+	// by the keys of its sourceNode and targetNode along with its own key, so relationships with the same key
+	// between different endpoints are told apart.
+	MatchRelationshipsByEndpoints bool
+	// StrictCardinality defines if updates and deletes fail with the [ErrCardinalityViolation]
+	// unless they affect exactly one element, so keys that don't identify elements uniquely are surfaced.
+	StrictCardinality bool
+	// SkipUnchanged defines if updates compare the incoming properties with the current state of the element
+	// and set them only if any of them differs, so unchanged updates don't produce writes.
+	SkipUnchanged bool
+	// RemoveNullProperties defines if null payload properties of updates remove the properties of the element,
+	// otherwise, they're ignored and the element keeps its current values.
+	RemoveNullProperties bool
+	// EntityTypeFromMetadata defines if the entity type of each record is taken from the neo4j.entityType metadata,
+	// falling back to the EntityType, records of the other entity type are written with the labels
+	// from the neo4j.entityLabels metadata.
+	EntityTypeFromMetadata bool
+	// MaxLabels and MaxProperties are the max numbers of labels and properties of a written element,
+	// including relationship endpoints that are created along with it, zero means unlimited.
+	MaxLabels     int
+	MaxProperties int
+	// OnError defines what to do when a record fails with a permanent error, if it's skip,
+	// the record is logged and skipped, the empty OnError is treated as the stop one.
+	OnError OnError
+	// KeyProperties are names of properties that are used to derive a key from the payload
+	// of an update or delete record that has no key, if they're empty, such records fail.
+	KeyProperties []string
+	// TransactionSize is the max number of records written in a single transaction,
+	// if it's zero, all records of a batch are written in a single transaction.
+	TransactionSize int
+	// ServerComputedProperties maps names of properties to the Cypher functions that compute their values
+	// on each create and update, e.g. "randomUUID()", they must pass the [ValidateServerComputedProperties].
+	ServerComputedProperties map[string]string
+	// CountMutations defines if the counters of graph mutations reported by Neo4j are aggregated for each write
+	// and logged, they're also returned by the [Writer.Mutations].
+	CountMutations bool
+	// NestedObjects defines how nested objects of payloads are written, the empty NestedObjects
+	// is treated as the keep one. The apoc one must be resolved by the [ResolveNestedObjects] first.
+	NestedObjects NestedObjects
+	// QueryTimeout is the max execution time of a write transaction, after which Neo4j terminates it
+	// and the error is classified as the [ErrTransient], zero keeps the server's default.
+	QueryTimeout time.Duration
+	// RateLimiter limits the rate of written records, it can be shared by writers to limit them in total,
+	// the nil RateLimiter doesn't limit them.
+	RateLimiter *RateLimiter
+}
+
+// ValidateServerComputedProperties checks that the server computed properties
+// use only the allowed functions, so arbitrary Cypher cannot be injected into queries.
+func ValidateServerComputedProperties(properties map[string]string) error {
+	for name, function := range properties {
+		if _, ok := serverFunctions[strings.TrimSuffix(function, functionCallSuffix)]; !ok {
+			return fmt.Errorf("%w: %q of the %q property", ErrUnsupportedServerFunction, function, name)
+		}
+	}
+
+	return nil
 }
 
 // New creates a new instance of the [Writer].
 func New(params Params) *Writer {
+	appendProperties := make(map[string]struct{}, len(params.AppendProperties))
+	for _, property := range params.AppendProperties {
+		appendProperties[property] = struct{}{}
+	}
+
+	// the functions are stored without parentheses, as they're allowed in the config
+	serverComputedProperties := make(map[string]string, len(params.ServerComputedProperties))
+	for name, function := range params.ServerComputedProperties {
+		serverComputedProperties[name] = strings.TrimSuffix(function, functionCallSuffix)
+	}
+
+	var mutations *mutationCounter
+	if params.CountMutations {
+		mutations = &mutationCounter{}
+	}
+
 	return &Writer{
 		driver:       params.Driver,
 		databaseName: params.DatabaseName,
 		entityType:   params.EntityType,
-		// join entity labels here to not do this each time constructing queries
-		entityLabels: strings.Join(params.EntityLabels, ":"),
+		// escape and join entity labels here to not do this each time constructing queries
+		entityLabels:     cypherLabels(params.EntityLabels),
+		appendProperties: appendProperties,
+		endpointsOnNode:  params.EndpointsOnNode,
+
+		labels:                params.EntityLabels,
+		labelField:            params.LabelField,
+		vectors:               params.Vectors,
+		labelConflictBehavior: params.LabelConflictBehavior,
+		processedAtProperty:   params.ProcessedAtProperty,
+		metadataProperties:    metadataProperties(params.MetadataToProperties, params.MetadataPropertyPrefix),
+		sourceMatchProperties: params.SourceMatchProperties,
+		targetMatchProperties: params.TargetMatchProperties,
+		logRedactProperties:   params.LogRedactProperties,
+		createEndpoints:       params.CreateEndpoints,
+		detachDelete:          params.DetachDelete,
+		matchByEndpoints:      params.MatchRelationshipsByEndpoints,
+		strictCardinality:     params.StrictCardinality,
+		skipUnchanged:         params.SkipUnchanged,
+		removeNullProperties:  params.RemoveNullProperties,
+		onError:               params.OnError,
+		keyProperties:         params.KeyProperties,
+		transactionSize:       params.TransactionSize,
+
+		entityTypeFromMetadata:   params.EntityTypeFromMetadata,
+		maxLabels:                params.MaxLabels,
+		maxProperties:            params.MaxProperties,
+		serverComputedProperties: serverComputedProperties,
+		mutations:                mutations,
+		nestedObjects:            params.NestedObjects,
+		queryTimeout:             params.QueryTimeout,
+		rateLimiter:              params.RateLimiter,
+	}
+}
+
+// Write writes records to the destination and returns the number of written records.
+//
+// The records are written using a single session, in transactions of up to the transactionSize records,
+// or in a single transaction if the transactionSize is zero. If a record fails, its transaction is rolled back,
+// so the returned number only includes records of the transactions committed before the failure.
+// The error is classified as the [ErrTransient], the [ErrConstraintViolation] or the [ErrInvalidQuery]
+// if it's caused by one of them.
+//
+// If the onError is skip, a record failing with a permanent error is logged and its transaction
+// is written again without it, so the returned number includes the skipped records.
+//
+// If the countMutations is enabled, the counters of graph mutations made by the committed transactions
+// are aggregated and logged, see the [Writer.Mutations].
+func (w *Writer) Write(ctx context.Context, records []sdk.Record) (int, error) {
+	session := w.driver.NewSession(ctx, neo4j.SessionConfig{
+		AccessMode:   neo4j.AccessModeWrite,
+		DatabaseName: w.databaseName,
+	})
+	defer session.Close(ctx)
+
+	transactionSize := w.transactionSize
+	if transactionSize <= 0 {
+		transactionSize = len(records)
+	}
+
+	w.mutations.reset()
+
+	// skipped holds indexes of the records that failed with permanent errors
+	skipped := make(map[int]struct{})
+
+	for start := 0; start < len(records); start += transactionSize {
+		end := min(start+transactionSize, len(records))
+
+		for {
+			// the rate is waited for outside of the transaction, so it doesn't hold locks while it waits
+			if err := w.rateLimiter.wait(ctx, end-start-skippedIn(skipped, start, end)); err != nil {
+				w.logMutations(ctx, start)
+
+				return start, err
+			}
+
+			_, err := neo4j.ExecuteWrite(ctx, session, func(tx neo4j.ManagedTransaction) (int, error) {
+				w.mutations.begin()
+
+				for i := start; i < end; i++ {
+					if _, ok := skipped[i]; ok {
+						continue
+					}
+
+					if err := w.writeRecord(ctx, tx, records[i]); err != nil {
+						return 0, &recordError{index: i, err: err}
+					}
+				}
+
+				return end - start, nil
+			}, config.TxTimeout(w.queryTimeout))
+			if err == nil {
+				w.mutations.commit()
+
+				break
+			}
+
+			err = classifyError(err)
+
+			index, ok := w.skippable(err)
+			if !ok {
+				w.logMutations(ctx, start)
+
+				return start, fmt.Errorf("execute write: %w", err)
+			}
+
+			sdk.Logger(ctx).Warn().Err(err).Int("index", index).Str("position", string(records[index].Position)).
+				Msg("skipping the record that failed with a permanent error")
+
+			// each attempt skips one more record, so the transaction is written after at most end-start attempts
+			skipped[index] = struct{}{}
+		}
 	}
+
+	w.logMutations(ctx, len(records))
+
+	return len(records), nil
+}
+
+// skippable returns the index of the record that failed with the error
+// if the onError is skip and the error is permanent.
+func (w *Writer) skippable(err error) (int, bool) {
+	var recordErr *recordError
+	if w.onError != OnErrorSkip || !errors.As(err, &recordErr) || errors.Is(err, ErrTransient) {
+		return 0, false
+	}
+
+	return recordErr.index, true
+}
+
+// skippedIn returns the number of the skipped records with indexes from the start up to the end.
+func skippedIn(skipped map[int]struct{}, start, end int) int {
+	var n int
+	for index := range skipped {
+		if index >= start && index < end {
+			n++
+		}
+	}
+
+	return n
+}
+
+// recordError is an error of a record within a batch, it holds the index of the record,
+// so the record can be skipped.
+type recordError struct {
+	index int
+	err   error
+}
+
+func (e *recordError) Error() string {
+	return fmt.Sprintf("write record %d: %s", e.index, e.err)
 }
 
-// Write writes a record to the destination.
-func (w *Writer) Write(ctx context.Context, record sdk.Record) error {
-	err := sdk.Util.Destination.Route(ctx, record,
-		w.handleCreate,
-		w.handleUpdate,
-		w.handleDelete,
-		w.handleCreate,
+func (e *recordError) Unwrap() error {
+	return e.err
+}
+
+// writeRecord routes a record to the handler of its operation within the transaction.
+func (w *Writer) writeRecord(ctx context.Context, tx neo4j.ManagedTransaction, record sdk.Record) error {
+	if record.Metadata[metadataSnapshotCompleteField] == "true" {
+		sdk.Logger(ctx).Debug().Msg("skipping the snapshot completion marker")
+
+		return nil
+	}
+
+	recordWriter, err := w.recordWriter(record.Metadata)
+	if err != nil {
+		return fmt.Errorf("resolve entity type: %w", err)
+	}
+
+	handleCreate := func(ctx context.Context, record sdk.Record) error {
+		return recordWriter.handleCreate(ctx, tx, record)
+	}
+
+	err = sdk.Util.Destination.Route(ctx, record,
+		handleCreate,
+		func(ctx context.Context, record sdk.Record) error {
+			return recordWriter.handleUpdate(ctx, tx, record)
+		},
+		func(ctx context.Context, record sdk.Record) error {
+			return recordWriter.handleDelete(ctx, tx, record)
+		},
+		handleCreate,
 	)
 	if err != nil {
 		return fmt.Errorf("route record: %w", err)
@@ -92,18 +522,49 @@ func (w *Writer) Write(ctx context.Context, record sdk.Record) error {
 	return nil
 }
 
-func (w *Writer) handleCreate(ctx context.Context, record sdk.Record) error {
-	session := w.driver.NewSession(ctx, neo4j.SessionConfig{
-		DatabaseName: w.databaseName,
-	})
-	defer session.Close(ctx)
+// recordWriter returns the writer of the record's entity type. If the entity type is taken from the metadata
+// and differs from the configured one, it returns a copy of the writer with the entity type
+// and the labels from the metadata, as the configured labels belong to the other entity type.
+func (w *Writer) recordWriter(metadata sdk.Metadata) (*Writer, error) {
+	if !w.entityTypeFromMetadata {
+		return w, nil
+	}
 
+	entityType := config.EntityType(metadata[metadataEntityTypeField])
+	switch entityType {
+	case "", w.entityType:
+		return w, nil
+
+	case config.EntityTypeNode, config.EntityTypeRelationship:
+
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEntityType, entityType)
+	}
+
+	labels := config.NormalizeLabels(strings.Split(metadata[metadataEntityLabelsField], labelsSeparator))
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("%w: the record is a %s", ErrMissingEntityLabels, entityType)
+	}
+
+	if err := w.checkLabels(len(labels)); err != nil {
+		return nil, err
+	}
+
+	recordWriter := *w
+	recordWriter.entityType = entityType
+	recordWriter.labels = labels
+	recordWriter.entityLabels = cypherLabels(labels)
+
+	return &recordWriter, nil
+}
+
+func (w *Writer) handleCreate(ctx context.Context, tx neo4j.ManagedTransaction, record sdk.Record) error {
 	switch w.entityType {
 	case config.EntityTypeNode:
-		return w.createNode(ctx, session, record)
+		return w.createNode(ctx, tx, record)
 
 	case config.EntityTypeRelationship:
-		return w.createRelationship(ctx, session, record)
+		return w.createRelationship(ctx, tx, record)
 
 	default:
 		// this shouldn't happen as we validate the config this value comes from
@@ -111,15 +572,10 @@ func (w *Writer) handleCreate(ctx context.Context, record sdk.Record) error {
 	}
 }
 
-func (w *Writer) handleUpdate(ctx context.Context, record sdk.Record) error {
-	session := w.driver.NewSession(ctx, neo4j.SessionConfig{
-		DatabaseName: w.databaseName,
-	})
-	defer session.Close(ctx)
-
-	key, err := w.structurizeRawData(record.Key.Bytes())
+func (w *Writer) handleUpdate(ctx context.Context, tx neo4j.ManagedTransaction, record sdk.Record) error {
+	key, err := w.recordKey(record, ErrMissingKeyForUpdate)
 	if err != nil {
-		return fmt.Errorf("structurize record key: %w", err)
+		return fmt.Errorf("get record key: %w", err)
 	}
 
 	properties, err := w.structurizeRawData(record.Payload.After.Bytes())
@@ -127,13 +583,51 @@ func (w *Writer) handleUpdate(ctx context.Context, record sdk.Record) error {
 		return fmt.Errorf("structurize record payload: %w", err)
 	}
 
+	labels, err := w.recordLabels(properties, record.Metadata)
+	if err != nil {
+		return fmt.Errorf("resolve labels: %w", err)
+	}
+
+	if err := w.vectors.Convert(properties); err != nil {
+		return fmt.Errorf("convert vectors: %w", err)
+	}
+
+	var sourceNode, targetNode *schema.Node
+	if w.matchRelationshipsByEndpoints() {
+		sourceNode, targetNode, err = w.sourceTargetNodesFromProperties(properties)
+		if err != nil {
+			return fmt.Errorf("extract source and target node from properties: %w", err)
+		}
+	}
+
 	// delete reserved sourceNode and targetNode fields
-	// from the properties map, as we don't need them for updates
+	// from the properties map, as we don't need them for updates,
+	// the endpointsOnNode applies only to creates, so updates of nodes strip them silently as well
 	delete(properties, sourceNodeField)
 	delete(properties, targetNodeField)
 
+	if err := w.convertNestedObjects(ctx, tx, properties); err != nil {
+		return fmt.Errorf("convert nested objects: %w", err)
+	}
+
+	w.ignoreNullProperties(properties)
+
+	if err := w.checkProperties(len(properties)); err != nil {
+		return err
+	}
+
+	w.setProcessedAt(properties)
+	w.setMetadataProperties(properties, record.Metadata)
+	w.removeServerComputedProperties(properties)
+
+	for name := range key {
+		if _, ok := w.appendProperties[name]; ok {
+			return fmt.Errorf("%w: %q", ErrAppendKeyProperty, name)
+		}
+	}
+
 	// add keys to the properties map because we need them
-	// for interpolation within the executeWriteQuery method
+	// for interpolation within the runWriteQuery method
 	// and to avoid creating a third map
 	for name, value := range key {
 		if _, ok := properties[name]; !ok {
@@ -152,30 +646,48 @@ func (w *Writer) handleUpdate(ctx context.Context, record sdk.Record) error {
 		return fmt.Errorf("create cypher set properties: %w", err)
 	}
 
-	updateQueryTemplate := updateNodeQueryTemplate
-	if w.entityType == config.EntityTypeRelationship {
-		updateQueryTemplate = updateRelationshipQueryTemplate
+	setClause := w.updateSetClause(properties, key, cypherSetProperties)
+	// there's nothing to set, e.g. all the payload properties were null and ignored,
+	// so the element is only matched if its cardinality is checked
+	if setClause == "" && !w.strictCardinality {
+		return nil
 	}
 
-	query := fmt.Sprintf(updateQueryTemplate, w.entityLabels, cypherMatchProperties, cypherSetProperties)
+	var query string
+	switch {
+	case w.matchRelationshipsByEndpoints():
+		query, err = w.relationshipByEndpointsQuery(
+			updateRelationshipByEndpointsQueryTemplate, labels, cypherMatchProperties,
+			sourceNode, targetNode, properties, setClause,
+		)
+		if err != nil {
+			return fmt.Errorf("create relationship by endpoints query: %w", err)
+		}
+
+	case w.entityType == config.EntityTypeRelationship:
+		query = fmt.Sprintf(updateRelationshipQueryTemplate, labels, cypherMatchProperties, setClause)
+
+	default:
+		query = fmt.Sprintf(updateNodeQueryTemplate, labels, cypherMatchProperties, setClause)
+	}
 
 	// execute the MATCH SET query
-	if err := w.executeWriteQuery(ctx, session, query, properties); err != nil {
-		return fmt.Errorf("execute write query: %w", err)
+	if err := w.runUpdateQuery(ctx, tx, query, properties); err != nil {
+		return fmt.Errorf("run update query: %w", err)
 	}
 
 	return nil
 }
 
-func (w *Writer) handleDelete(ctx context.Context, record sdk.Record) error {
-	session := w.driver.NewSession(ctx, neo4j.SessionConfig{
-		DatabaseName: w.databaseName,
-	})
-	defer session.Close(ctx)
+func (w *Writer) handleDelete(ctx context.Context, tx neo4j.ManagedTransaction, record sdk.Record) error {
+	key, err := w.recordKey(record, ErrMissingKeyForDelete)
+	if err != nil {
+		return fmt.Errorf("get record key: %w", err)
+	}
 
-	key, err := w.structurizeRawData(record.Key.Bytes())
+	labels, err := w.deleteLabels(record)
 	if err != nil {
-		return fmt.Errorf("structurize record key: %w", err)
+		return fmt.Errorf("resolve labels: %w", err)
 	}
 
 	// construct a MATCH DELETE query
@@ -184,92 +696,261 @@ func (w *Writer) handleDelete(ctx context.Context, record sdk.Record) error {
 		return fmt.Errorf("create cypher match properties: %w", err)
 	}
 
-	deleteQueryTemplate := deleteNodeQueryTemplate
-	if w.entityType == config.EntityTypeRelationship {
-		deleteQueryTemplate = deleteRelationshipQueryTemplate
-	}
+	query := fmt.Sprintf(w.deleteQueryTemplate(), labels, cypherMatchProperties)
+	if w.matchRelationshipsByEndpoints() {
+		sourceNode, targetNode, err := w.deleteEndpoints(record)
+		if err != nil {
+			return fmt.Errorf("extract source and target node from payload before: %w", err)
+		}
 
-	query := fmt.Sprintf(deleteQueryTemplate, w.entityLabels, cypherMatchProperties)
+		// the endpoint keys are added to the key map for interpolation, as it's not used after
+		query, err = w.relationshipByEndpointsQuery(
+			deleteRelationshipByEndpointsQueryTemplate, labels, cypherMatchProperties,
+			sourceNode, targetNode, key,
+		)
+		if err != nil {
+			return fmt.Errorf("create relationship by endpoints query: %w", err)
+		}
+	}
 
 	// execute the MATCH DELETE query
-	if err := w.executeWriteQuery(ctx, session, query, key); err != nil {
-		return fmt.Errorf("execute write query: %w", err)
+	if err := w.runDeleteQuery(ctx, tx, query, key); err != nil {
+		return fmt.Errorf("run delete query: %w", err)
 	}
 
 	return nil
 }
 
-func (w *Writer) createNode(ctx context.Context, session neo4j.SessionWithContext, record sdk.Record) error {
+// recordKey returns the key of an update or delete record.
+//
+// If the record has no key, the key is derived from the payload using the keyProperties,
+// the payload after the change is used if it's present, otherwise the payload before it.
+// If the key cannot be derived, the missingKeyErr is returned.
+func (w *Writer) recordKey(record sdk.Record, missingKeyErr error) (map[string]any, error) {
+	if record.Key != nil && len(record.Key.Bytes()) > 0 {
+		key, err := w.structurizeRawData(record.Key.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("structurize record key: %w", err)
+		}
+
+		// an empty key would match all elements, so it's considered missing
+		if len(key) > 0 {
+			return key, nil
+		}
+	}
+
+	if len(w.keyProperties) == 0 {
+		return nil, fmt.Errorf("%w: the record key is empty", missingKeyErr)
+	}
+
+	payload := record.Payload.After
+	if payload == nil || len(payload.Bytes()) == 0 {
+		payload = record.Payload.Before
+	}
+
+	if payload == nil || len(payload.Bytes()) == 0 {
+		return nil, fmt.Errorf("%w: both the record key and the payload are empty", missingKeyErr)
+	}
+
+	properties, err := w.structurizeRawData(payload.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("structurize record payload: %w", err)
+	}
+
+	key := make(map[string]any, len(w.keyProperties))
+	for _, keyProperty := range w.keyProperties {
+		value, ok := properties[keyProperty]
+		if !ok {
+			return nil, fmt.Errorf("%w: the payload doesn't contain the %q key property", missingKeyErr, keyProperty)
+		}
+
+		key[keyProperty] = value
+	}
+
+	return key, nil
+}
+
+// matchRelationshipsByEndpoints reports whether relationship updates and deletes
+// match the endpoints of the relationship by their keys.
+func (w *Writer) matchRelationshipsByEndpoints() bool {
+	return w.matchByEndpoints && w.entityType == config.EntityTypeRelationship
+}
+
+// deleteEndpoints extracts the source and target nodes of a deleted relationship from the payload before the delete,
+// as the payload after it is empty.
+func (w *Writer) deleteEndpoints(record sdk.Record) (*schema.Node, *schema.Node, error) {
+	if record.Payload.Before == nil || len(record.Payload.Before.Bytes()) == 0 {
+		return nil, nil, fmt.Errorf("%w: the payload before the delete is empty", ErrEmptySourceNode)
+	}
+
+	properties, err := w.structurizeRawData(record.Payload.Before.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("structurize record payload: %w", err)
+	}
+
+	return w.sourceTargetNodesFromProperties(properties)
+}
+
+// relationshipByEndpointsQuery constructs a relationship query from the template that matches
+// the relationship along with its endpoints by their keys, e.g.
+// "MATCH (src:`Person` {`id`:$`src_id`})-[obj:KNOWS {`id`:$`id`}]->(trgt:`Person` {`id`:$`trgt_id`})",
+// followed by the rest of the template args, and adds the endpoint keys to the params for interpolation.
+func (w *Writer) relationshipByEndpointsQuery(
+	template, labels, cypherMatchProperties string,
+	sourceNode, targetNode *schema.Node,
+	params map[string]any,
+	args ...any,
+) (string, error) {
+	sourceNodeProperties, err := w.cypherMatchProperties(sourceNode.Key, interpolationSourcePrefix)
+	if err != nil {
+		return "", fmt.Errorf("create cypher match properties for source node: %w", err)
+	}
+
+	targetNodeProperties, err := w.cypherMatchProperties(targetNode.Key, interpolationTargetPrefix)
+	if err != nil {
+		return "", fmt.Errorf("create cypher match properties for target node: %w", err)
+	}
+
+	for name, value := range sourceNode.Key {
+		if _, ok := params[interpolationSourcePrefix+name]; !ok {
+			params[interpolationSourcePrefix+name] = value
+		}
+	}
+
+	for name, value := range targetNode.Key {
+		if _, ok := params[interpolationTargetPrefix+name]; !ok {
+			params[interpolationTargetPrefix+name] = value
+		}
+	}
+
+	return fmt.Sprintf(template, append([]any{
+		cypherLabels(sourceNode.Labels), sourceNodeProperties,
+		labels, cypherMatchProperties,
+		cypherLabels(targetNode.Labels), targetNodeProperties,
+	}, args...)...), nil
+}
+
+// deleteQueryTemplate returns a template of a query that deletes an element of the entityType.
+func (w *Writer) deleteQueryTemplate() string {
+	switch {
+	case w.entityType == config.EntityTypeRelationship:
+		return deleteRelationshipQueryTemplate
+
+	case w.detachDelete:
+		return detachDeleteNodeQueryTemplate
+
+	default:
+		return deleteNodeQueryTemplate
+	}
+}
+
+func (w *Writer) createNode(ctx context.Context, tx neo4j.ManagedTransaction, record sdk.Record) error {
 	properties, err := w.structurizeRawData(record.Payload.After.Bytes())
 	if err != nil {
 		return fmt.Errorf("structurize record payload: %w", err)
 	}
 
+	labels, err := w.recordLabels(properties, record.Metadata)
+	if err != nil {
+		return fmt.Errorf("resolve labels: %w", err)
+	}
+
+	if err := w.vectors.Convert(properties); err != nil {
+		return fmt.Errorf("convert vectors: %w", err)
+	}
+
+	if err := w.resolveEndpointsOnNode(ctx, properties); err != nil {
+		return fmt.Errorf("resolve endpoints on node: %w", err)
+	}
+
+	if err := w.convertNestedObjects(ctx, tx, properties); err != nil {
+		return fmt.Errorf("convert nested objects: %w", err)
+	}
+
+	if err := w.checkProperties(len(properties)); err != nil {
+		return err
+	}
+
+	w.setProcessedAt(properties)
+	w.setMetadataProperties(properties, record.Metadata)
+	w.wrapAppendProperties(properties)
+	w.removeServerComputedProperties(properties)
+
 	// construct a CREATE query
 	cypherMatchProperties, err := w.cypherMatchProperties(properties, "")
 	if err != nil {
 		return fmt.Errorf("create cypher match properties: %w", err)
 	}
 
-	query := fmt.Sprintf(createNodeQueryTemplate, w.entityLabels, cypherMatchProperties)
+	query, err := w.withServerComputedProperties(fmt.Sprintf(createNodeQueryTemplate, labels, cypherMatchProperties))
+	if err != nil {
+		return fmt.Errorf("add server computed properties: %w", err)
+	}
 
 	// execute the CREATE query
-	if err := w.executeWriteQuery(ctx, session, query, properties); err != nil {
-		return fmt.Errorf("execute write query: %w", err)
+	if err := w.runWriteQuery(ctx, tx, query, properties); err != nil {
+		return fmt.Errorf("run write query: %w", err)
 	}
 
 	return nil
 }
 
-func (w *Writer) createRelationship(ctx context.Context, session neo4j.SessionWithContext, record sdk.Record) error {
+func (w *Writer) createRelationship(ctx context.Context, tx neo4j.ManagedTransaction, record sdk.Record) error {
 	properties, err := w.structurizeRawData(record.Payload.After.Bytes())
 	if err != nil {
 		return fmt.Errorf("structurize record payload: %w", err)
 	}
 
+	labels, err := w.recordLabels(properties, record.Metadata)
+	if err != nil {
+		return fmt.Errorf("resolve labels: %w", err)
+	}
+
+	if err := w.vectors.Convert(properties); err != nil {
+		return fmt.Errorf("convert vectors: %w", err)
+	}
+
 	// extract source and target nodes from the properties
 	sourceNode, targetNode, err := w.sourceTargetNodesFromProperties(properties)
 	if err != nil {
 		return fmt.Errorf("extract source and target node from properties: %w", err)
 	}
 
-	// prepare source node
-	sourceNodeLabels := strings.Join(sourceNode.Labels, ":")
-	sourceNodeCypherMatchProperties, err := w.cypherMatchProperties(sourceNode.Key, interpolationSourcePrefix)
-	if err != nil {
-		return fmt.Errorf("create cypher match properties for source node: %w", err)
+	if err := w.convertNestedObjects(ctx, tx, properties); err != nil {
+		return fmt.Errorf("convert nested objects: %w", err)
 	}
 
-	// prepare target node
-	targetNodeLabels := strings.Join(targetNode.Labels, ":")
-	targetNodeCypherMatchProperties, err := w.cypherMatchProperties(targetNode.Key, interpolationTargetPrefix)
-	if err != nil {
-		return fmt.Errorf("create cypher match properties for target node: %w", err)
+	if err := w.checkRelationshipLimits(properties, sourceNode, targetNode); err != nil {
+		return err
 	}
 
+	w.setProcessedAt(properties)
+	w.setMetadataProperties(properties, record.Metadata)
+	w.wrapAppendProperties(properties)
+	w.removeServerComputedProperties(properties)
+
 	// construct a CREATE query
-	relationshipCypherMatchProperties, err := w.cypherMatchProperties(properties, "")
+	query, err := w.createRelationshipQuery(labels, sourceNode, targetNode, properties)
 	if err != nil {
-		return fmt.Errorf("create cypher match properties for relationship: %w", err)
+		return fmt.Errorf("create relationship query: %w", err)
 	}
 
-	query := fmt.Sprintf(createRelationshipQueryTemplate,
-		sourceNodeLabels, sourceNodeCypherMatchProperties,
-		targetNodeLabels, targetNodeCypherMatchProperties,
-		w.entityLabels, relationshipCypherMatchProperties,
-	)
+	query, err = w.withServerComputedProperties(query)
+	if err != nil {
+		return fmt.Errorf("add server computed properties: %w", err)
+	}
 
-	// add sourceNode and targetNode keys to the properties map because we need them
-	// for interpolation within the executeWriteQuery method
+	// add sourceNode and targetNode properties to the properties map because we need them
+	// for interpolation within the runWriteQuery method
 	// and to avoid creating a third map
-	for name, value := range sourceNode.Key {
+	for name, value := range w.endpointProperties(sourceNode) {
 		interpolatedName := interpolationSourcePrefix + name
 		if _, ok := properties[interpolatedName]; !ok {
 			properties[interpolatedName] = value
 		}
 	}
 
-	for name, value := range targetNode.Key {
+	for name, value := range w.endpointProperties(targetNode) {
 		interpolatedName := interpolationTargetPrefix + name
 		if _, ok := properties[interpolatedName]; !ok {
 			properties[interpolatedName] = value
@@ -277,13 +958,87 @@ func (w *Writer) createRelationship(ctx context.Context, session neo4j.SessionWi
 	}
 
 	// execute the CREATE query
-	if err := w.executeWriteQuery(ctx, session, query, properties); err != nil {
-		return fmt.Errorf("execute write query: %w", err)
+	if err := w.runWriteQuery(ctx, tx, query, properties); err != nil {
+		return fmt.Errorf("run write query: %w", err)
 	}
 
 	return nil
 }
 
+// createRelationshipQuery constructs a CREATE query of a relationship.
+//
+// If the createEndpoints is enabled, the endpoints are created along with the relationship,
+// otherwise the relationship is created between matched existing endpoints.
+func (w *Writer) createRelationshipQuery(
+	labels string,
+	sourceNode, targetNode *schema.Node,
+	properties map[string]any,
+) (string, error) {
+	relationshipCypherMatchProperties, err := w.cypherMatchProperties(properties, "")
+	if err != nil {
+		return "", fmt.Errorf("create cypher match properties for relationship: %w", err)
+	}
+
+	if w.createEndpoints {
+		sourceNodeProperties, err := w.cypherMatchProperties(w.endpointProperties(sourceNode), interpolationSourcePrefix)
+		if err != nil {
+			return "", fmt.Errorf("create cypher properties for source node: %w", err)
+		}
+
+		targetNodeProperties, err := w.cypherMatchProperties(w.endpointProperties(targetNode), interpolationTargetPrefix)
+		if err != nil {
+			return "", fmt.Errorf("create cypher properties for target node: %w", err)
+		}
+
+		return fmt.Sprintf(createRelationshipWithEndpointsQueryTemplate,
+			cypherLabels(sourceNode.Labels), sourceNodeProperties,
+			labels, relationshipCypherMatchProperties,
+			cypherLabels(targetNode.Labels), targetNodeProperties,
+		), nil
+	}
+
+	// prepare source node
+	sourceNodeMatchClause, err := w.cypherMatchEndpoint(
+		srcPlaceholder, sourceNode, w.sourceMatchProperties, interpolationSourcePrefix,
+	)
+	if err != nil {
+		return "", fmt.Errorf("create cypher match clause for source node: %w", err)
+	}
+
+	// prepare target node
+	targetNodeMatchClause, err := w.cypherMatchEndpoint(
+		trgtPlaceholder, targetNode, w.targetMatchProperties, interpolationTargetPrefix,
+	)
+	if err != nil {
+		return "", fmt.Errorf("create cypher match clause for target node: %w", err)
+	}
+
+	return fmt.Sprintf(createRelationshipQueryTemplate,
+		sourceNodeMatchClause, targetNodeMatchClause,
+		labels, relationshipCypherMatchProperties,
+	), nil
+}
+
+// endpointProperties returns properties of the endpoint that are interpolated into the query.
+// If the createEndpoints is enabled, they're the endpoint properties along with its key, which takes precedence,
+// otherwise only the key is used to match the endpoint.
+func (w *Writer) endpointProperties(node *schema.Node) map[string]any {
+	if !w.createEndpoints || len(node.Properties) == 0 {
+		return node.Key
+	}
+
+	properties := make(map[string]any, len(node.Properties)+len(node.Key))
+	for name, value := range node.Properties {
+		properties[name] = value
+	}
+
+	for name, value := range node.Key {
+		properties[name] = value
+	}
+
+	return properties
+}
+
 // sourceTargetNodesFromProperties extracts source and target nodes of type [schema.Node] from the properties map.
 //
 // The method also removes sourceNode and targetNode fields from the properties after extracting
@@ -318,6 +1073,136 @@ func (w *Writer) sourceTargetNodesFromProperties(properties map[string]any) (*sc
 	return sourceNode, targetNode, nil
 }
 
+// resolveEndpointsOnNode checks if properties of a created node contain the relationship-specific
+// sourceNode or targetNode fields, and depending on the endpointsOnNode
+// either strips them logging a warning or returns the [ErrEndpointsOnNode].
+func (w *Writer) resolveEndpointsOnNode(ctx context.Context, properties map[string]any) error {
+	for _, field := range []string{sourceNodeField, targetNodeField} {
+		if _, ok := properties[field]; !ok {
+			continue
+		}
+
+		if w.endpointsOnNode != EndpointsOnNodeStrip {
+			return fmt.Errorf("%w: %q", ErrEndpointsOnNode, field)
+		}
+
+		sdk.Logger(ctx).Warn().Str("field", field).
+			Msg("the entityType is node but the payload contains a relationship endpoint field, stripping it")
+
+		delete(properties, field)
+	}
+
+	return nil
+}
+
+// resolveLabels returns entity labels joined with ":" for a record.
+//
+// If the record metadata contains entity labels that differ from the configured ones,
+// they are resolved according to the labelConflictBehavior. A relationship has a single type,
+// so the types cannot be merged and a conflict results in the [ErrLabelConflict].
+func (w *Writer) resolveLabels(metadata sdk.Metadata) (string, error) {
+	if w.labelConflictBehavior == "" || w.labelConflictBehavior == LabelConflictBehaviorConfigWins {
+		return w.entityLabels, nil
+	}
+
+	metadataLabels := config.NormalizeLabels(strings.Split(metadata[metadataEntityLabelsField], labelsSeparator))
+
+	// the labels are compared as sets, so their order doesn't matter
+	if len(metadataLabels) == 0 || sameLabels(metadataLabels, w.labels) {
+		return w.entityLabels, nil
+	}
+
+	switch w.labelConflictBehavior {
+	case LabelConflictBehaviorMetadataWins:
+		if err := w.checkLabels(len(metadataLabels)); err != nil {
+			return "", err
+		}
+
+		return cypherLabels(metadataLabels), nil
+
+	case LabelConflictBehaviorMerge:
+		if w.entityType == config.EntityTypeRelationship {
+			return "", fmt.Errorf("%w: cannot merge relationship types %q and %q",
+				ErrLabelConflict, strings.Join(w.labels, labelsSeparator), metadata[metadataEntityLabelsField])
+		}
+
+		labels := config.NormalizeLabels(append(slices.Clone(w.labels), metadataLabels...))
+
+		if err := w.checkLabels(len(labels)); err != nil {
+			return "", err
+		}
+
+		return cypherLabels(labels), nil
+
+	default:
+		return "", fmt.Errorf("%w: configured %q, metadata %q",
+			ErrLabelConflict, strings.Join(w.labels, labelsSeparator), metadata[metadataEntityLabelsField])
+	}
+}
+
+// setProcessedAt sets the processedAtProperty to the current time,
+// so it reflects when the record was processed rather than when the data was changed.
+func (w *Writer) setProcessedAt(properties map[string]any) {
+	if w.processedAtProperty == "" {
+		return
+	}
+
+	properties[w.processedAtProperty] = time.Now().UTC()
+}
+
+// wrapAppendProperties wraps scalar values of the append properties into lists,
+// so the following updates can append values to them.
+func (w *Writer) wrapAppendProperties(properties map[string]any) {
+	for name := range w.appendProperties {
+		value, ok := properties[name]
+		if !ok || value == nil {
+			continue
+		}
+
+		if !isList(value) {
+			properties[name] = []any{value}
+		}
+	}
+}
+
+// ignoreNullProperties removes the properties with null values from the payload properties
+// unless the removeNullProperties is enabled, so an update doesn't remove them from the element,
+// as SET does for null values.
+func (w *Writer) ignoreNullProperties(properties map[string]any) {
+	if w.removeNullProperties {
+		return
+	}
+
+	for name, value := range properties {
+		if value == nil {
+			delete(properties, name)
+		}
+	}
+}
+
+// removeServerComputedProperties removes the server computed properties from the payload properties,
+// as their values are computed by the functions.
+func (w *Writer) removeServerComputedProperties(properties map[string]any) {
+	for name := range w.serverComputedProperties {
+		delete(properties, name)
+	}
+}
+
+// withServerComputedProperties appends a SET clause of the server computed properties to a CREATE query,
+// e.g.: "CREATE (obj:Person {name: $name}) SET obj.id=randomUUID()".
+func (w *Writer) withServerComputedProperties(query string) (string, error) {
+	if len(w.serverComputedProperties) == 0 {
+		return query, nil
+	}
+
+	cypherSetProperties, err := w.cypherSetProperties(nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("create cypher set properties: %w", err)
+	}
+
+	return query + setClausePrefix + cypherSetProperties, nil
+}
+
 // structurizeRawData tries to unmarshal the [sdk.RawData]
 // and if the process fails or the [sdk.RawData] is empty the method returns an error.
 func (w *Writer) structurizeRawData(rawData sdk.RawData) (map[string]any, error) {
@@ -325,37 +1210,213 @@ func (w *Writer) structurizeRawData(rawData sdk.RawData) (map[string]any, error)
 		return nil, ErrEmptyRawData
 	}
 
+	// use json.Number to not lose precision of big integers
+	decoder := json.NewDecoder(bytes.NewReader(rawData))
+	decoder.UseNumber()
+
 	var structurizedData map[string]any
-	if err := json.Unmarshal(rawData, &structurizedData); err != nil {
+	if err := decoder.Decode(&structurizedData); err != nil {
 		return nil, fmt.Errorf("unmarshal raw data: %w", err)
 	}
 
+	for name, value := range structurizedData {
+		convertedValue, err := convertNumbers(value)
+		if err != nil {
+			return nil, fmt.Errorf("convert %q property: %w", name, err)
+		}
+
+		convertedValue, err = schema.DecodeValues(convertedValue)
+		if err != nil {
+			return nil, fmt.Errorf("decode values of %q property: %w", name, err)
+		}
+
+		// vectors are converted into lists of floats by the writes, whatever numbers they mix
+		if slices.Contains(w.vectors.Properties, name) {
+			structurizedData[name] = convertedValue
+
+			continue
+		}
+
+		// nested objects serialized into JSON strings aren't stored as they are, so they can hold any lists
+		if _, ok := convertedValue.(map[string]any); !ok || !w.serializesNestedObjects() {
+			convertedValue, err = convertLists(convertedValue)
+			if err != nil {
+				return nil, fmt.Errorf("convert lists of %q property: %w", name, err)
+			}
+		}
+
+		structurizedData[name] = convertedValue
+	}
+
 	return structurizedData, nil
 }
 
-// executeWriteQuery is a helper method that wraps the [neo4j.ExecuteWrite] function
-// and the underlying anonymous function.
-func (w *Writer) executeWriteQuery(
+// convertNumbers recursively converts [json.Number] values into types Neo4j can store.
+//
+// Numbers are converted into float64 as the json package does by default,
+// except integers that cannot be represented by float64 exactly, which are converted into int64.
+// Integers exceeding the int64 range cannot be stored by Neo4j, so the [ErrIntegerOverflow] is returned.
+func convertNumbers(value any) (any, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if !strings.ContainsAny(v.String(), ".eE") {
+			integer, err := strconv.ParseInt(v.String(), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrIntegerOverflow, v)
+			}
+
+			if integer > maxExactFloatInteger || integer < -maxExactFloatInteger {
+				return integer, nil
+			}
+		}
+
+		float, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("parse float: %w", err)
+		}
+
+		return float, nil
+
+	case map[string]any:
+		for name, item := range v {
+			convertedItem, err := convertNumbers(item)
+			if err != nil {
+				return nil, err
+			}
+
+			v[name] = convertedItem
+		}
+
+		return v, nil
+
+	case []any:
+		for i, item := range v {
+			convertedItem, err := convertNumbers(item)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = convertedItem
+		}
+
+		return v, nil
+
+	default:
+		return value, nil
+	}
+}
+
+// runWriteQuery is a helper method that runs the query within the transaction
+// and consumes its result.
+func (w *Writer) runWriteQuery(
 	ctx context.Context,
-	session neo4j.SessionWithContext,
+	tx neo4j.ManagedTransaction,
 	query string,
 	properties map[string]any,
 ) error {
-	_, err := neo4j.ExecuteWrite(ctx, session, func(tx neo4j.ManagedTransaction) (neo4j.ResultSummary, error) {
-		result, err := tx.Run(ctx, query, properties)
-		if err != nil {
-			return nil, fmt.Errorf("run tx: %w", err)
-		}
+	querylog.Log(ctx, query, properties, w.logRedactProperties)
 
-		summary, err := result.Consume(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("consume result: %w", err)
-		}
+	result, err := tx.Run(ctx, query, properties)
+	if err != nil {
+		return fmt.Errorf("run tx: %w", err)
+	}
 
-		return summary, nil
-	})
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		return fmt.Errorf("consume result: %w", err)
+	}
+
+	w.mutations.record(summary.Counters())
+
+	return nil
+}
+
+// runUpdateQuery runs the MATCH SET query of an update.
+// If the strictCardinality is enabled, the query returns the number of matched elements,
+// and it fails with the [ErrCardinalityViolation] unless the number is one.
+func (w *Writer) runUpdateQuery(
+	ctx context.Context,
+	tx neo4j.ManagedTransaction,
+	query string,
+	properties map[string]any,
+) error {
+	if !w.strictCardinality {
+		return w.runWriteQuery(ctx, tx, query, properties)
+	}
+
+	query += affectedReturnClause
+
+	querylog.Log(ctx, query, properties, w.logRedactProperties)
+
+	result, err := tx.Run(ctx, query, properties)
+	if err != nil {
+		return fmt.Errorf("run tx: %w", err)
+	}
+
+	record, err := result.Single(ctx)
+	if err != nil {
+		return fmt.Errorf("get single record: %w", err)
+	}
+
+	affected, _, err := neo4j.GetRecordValue[int64](record, affectedFieldName)
+	if err != nil {
+		return fmt.Errorf("get affected elements: %w", err)
+	}
+
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		return fmt.Errorf("consume result: %w", err)
+	}
+
+	w.mutations.record(summary.Counters())
+
+	return checkCardinality(affected)
+}
+
+// runDeleteQuery runs the MATCH DELETE query of a delete.
+// If the strictCardinality is enabled, it fails with the [ErrCardinalityViolation]
+// unless the counters of the query report exactly one deleted element of the entityType.
+func (w *Writer) runDeleteQuery(
+	ctx context.Context,
+	tx neo4j.ManagedTransaction,
+	query string,
+	properties map[string]any,
+) error {
+	querylog.Log(ctx, query, properties, w.logRedactProperties)
+
+	result, err := tx.Run(ctx, query, properties)
 	if err != nil {
-		return fmt.Errorf("execute write: %w", err)
+		return fmt.Errorf("run tx: %w", err)
+	}
+
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		return fmt.Errorf("consume result: %w", err)
+	}
+
+	w.mutations.record(summary.Counters())
+
+	if !w.strictCardinality {
+		return nil
+	}
+
+	return checkCardinality(w.deletedElements(summary.Counters()))
+}
+
+// deletedElements returns the number of deleted elements of the entityType,
+// relationships of the nodes deleted with DETACH DELETE aren't counted.
+func (w *Writer) deletedElements(counters neo4j.Counters) int64 {
+	if w.entityType == config.EntityTypeRelationship {
+		return int64(counters.RelationshipsDeleted())
+	}
+
+	return int64(counters.NodesDeleted())
+}
+
+// checkCardinality returns the [ErrCardinalityViolation] unless exactly one element is affected.
+func checkCardinality(affected int64) error {
+	if affected != 1 {
+		return fmt.Errorf("%w: %d elements are affected", ErrCardinalityViolation, affected)
 	}
 
 	return nil
@@ -363,11 +1424,12 @@ func (w *Writer) executeWriteQuery(
 
 // cypherMatchProperties constructs a set of properties
 // according to the Cypher MATCH syntax, e.g.: "{prop: $prop}".
+// The properties are sorted by their names, so the same properties always produce the same clause.
 func (w *Writer) cypherMatchProperties(properties map[string]any, interpolationPrefix string) (string, error) {
 	var sb strings.Builder
-	for propertyName := range properties {
-		_, err := sb.WriteString(
-			propertyName + matchAssignSign + interpolationSign + interpolationPrefix + propertyName + ", ",
+	for _, propertyName := range sortedNames(properties) {
+		_, err := sb.WriteString(escapeIdentifier(propertyName) + matchAssignSign +
+			interpolationSign + escapeIdentifier(interpolationPrefix+propertyName) + ", ",
 		)
 		if err != nil {
 			return "", fmt.Errorf("write string: %w", err)
@@ -377,17 +1439,74 @@ func (w *Writer) cypherMatchProperties(properties map[string]any, interpolationP
 	return strings.TrimRight(sb.String(), ", "), nil
 }
 
+// cypherMatchEndpoint constructs a MATCH clause for a relationship endpoint.
+//
+// If the endpoint key contains any of the match properties, the endpoint is matched
+// if any of them is equal, e.g.: "MATCH (src:Person) WHERE src.email=$src_email OR src.username=$src_username",
+// and only the first found node is used, otherwise the whole key must match.
+func (w *Writer) cypherMatchEndpoint(
+	placeholder string,
+	node *schema.Node,
+	matchProperties []string,
+	interpolationPrefix string,
+) (string, error) {
+	labels := cypherLabels(node.Labels)
+
+	var conditions []string
+	for _, propertyName := range matchProperties {
+		if _, ok := node.Key[propertyName]; !ok {
+			continue
+		}
+
+		conditions = append(conditions, placeholder+"."+escapeIdentifier(propertyName)+
+			setAssignSign+interpolationSign+escapeIdentifier(interpolationPrefix+propertyName),
+		)
+	}
+
+	if len(conditions) > 0 {
+		return fmt.Sprintf(matchAnyEndpointClauseTemplate, placeholder, labels, strings.Join(conditions, orSign)), nil
+	}
+
+	cypherMatchProperties, err := w.cypherMatchProperties(node.Key, interpolationPrefix)
+	if err != nil {
+		return "", fmt.Errorf("create cypher match properties: %w", err)
+	}
+
+	return fmt.Sprintf(matchEndpointClauseTemplate, placeholder, labels, cypherMatchProperties), nil
+}
+
 // cypherSetProperties constructs a set of properties
 // according to the Cypher SET syntax, e.g.: "prefix.prop = $prop".
+// The append properties are set as "prefix.prop = coalesce(prefix.prop, []) + $prop",
+// and the server computed properties that aren't a part of the key are set as "prefix.prop = function()".
+// Both are sorted by their names, so the same properties always produce the same clause.
 func (w *Writer) cypherSetProperties(properties map[string]any, key map[string]any) (string, error) {
 	var sb strings.Builder
-	for propertyName := range properties {
+	for _, propertyName := range sortedNames(properties) {
+		if _, ok := key[propertyName]; ok {
+			continue
+		}
+
+		escapedName := escapeIdentifier(propertyName)
+
+		setProperty := setKeyPrefix + escapedName + setAssignSign + interpolationSign + escapedName
+		if _, ok := w.appendProperties[propertyName]; ok {
+			setProperty = fmt.Sprintf(appendSetTemplate, setKeyPrefix+escapedName, escapedName)
+		}
+
+		_, err := sb.WriteString(setProperty + ", ")
+		if err != nil {
+			return "", fmt.Errorf("write string: %w", err)
+		}
+	}
+
+	for _, propertyName := range sortedNames(w.serverComputedProperties) {
 		if _, ok := key[propertyName]; ok {
 			continue
 		}
 
-		_, err := sb.WriteString(
-			setKeyPrefix + propertyName + setAssignSign + interpolationSign + propertyName + ", ",
+		_, err := sb.WriteString(setKeyPrefix + escapeIdentifier(propertyName) + setAssignSign +
+			w.serverComputedProperties[propertyName] + functionCallSuffix + ", ",
 		)
 		if err != nil {
 			return "", fmt.Errorf("write string: %w", err)
@@ -396,3 +1515,93 @@ func (w *Writer) cypherSetProperties(properties map[string]any, key map[string]a
 
 	return strings.TrimRight(sb.String(), ", "), nil
 }
+
+// updateSetClause returns the SET clause of an update, e.g.: " SET obj.`name` = $`name`".
+// If the skipUnchanged is enabled, the properties are set only if any of the payload properties
+// differs from the current state of the element. The key, the append properties, which always change it,
+// the processedAtProperty and the server computed properties are not compared.
+// If there are no properties to set, the clause is empty.
+func (w *Writer) updateSetClause(properties, key map[string]any, cypherSetProperties string) string {
+	if cypherSetProperties == "" {
+		return ""
+	}
+
+	setClause := setClausePrefix + cypherSetProperties
+	if !w.skipUnchanged {
+		return setClause
+	}
+
+	changedConditions := make([]string, 0, len(properties))
+	for propertyName := range properties {
+		if _, ok := w.appendProperties[propertyName]; ok {
+			return setClause
+		}
+
+		if _, ok := key[propertyName]; ok || propertyName == w.processedAtProperty {
+			continue
+		}
+
+		escapedName := escapeIdentifier(propertyName)
+		changedConditions = append(changedConditions,
+			fmt.Sprintf(changedPropertyTemplate, setKeyPrefix+escapedName, escapedName),
+		)
+	}
+
+	// there's nothing to compare, so the properties are set as usual
+	if len(changedConditions) == 0 {
+		return setClause
+	}
+
+	// sort the conditions, so the query is deterministic
+	slices.Sort(changedConditions)
+
+	return fmt.Sprintf(skipUnchangedSetClauseTemplate, strings.Join(changedConditions, orSign), cypherSetProperties)
+}
+
+// sortedNames returns the names of the properties in the ascending order,
+// so the clauses built from them, and thus the queries, are the same for the same properties.
+func sortedNames[V any](properties map[string]V) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// sameLabels checks if both lists contain the same set of labels.
+func sameLabels(a, b []string) bool {
+	for _, label := range a {
+		if !slices.Contains(b, label) {
+			return false
+		}
+	}
+
+	for _, label := range b {
+		if !slices.Contains(a, label) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// escapeIdentifier wraps a label or property name in backticks, doubling the embedded ones,
+// so it's interpolated into a query as a single identifier, whatever characters it contains.
+func escapeIdentifier(name string) string {
+	return identifierQuote + strings.ReplaceAll(name, identifierQuote, identifierQuote+identifierQuote) + identifierQuote
+}
+
+// cypherLabels normalizes labels with the [config.NormalizeLabels], escapes them and joins them with ":".
+func cypherLabels(labels []string) string {
+	labels = config.NormalizeLabels(labels)
+
+	escapedLabels := make([]string, len(labels))
+	for i, label := range labels {
+		escapedLabels[i] = escapeIdentifier(label)
+	}
+
+	return strings.Join(escapedLabels, labelsSeparator)
+}
@@ -15,9 +15,895 @@
 package writer
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/mock"
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"go.uber.org/mock/gomock"
 )
 
+func TestWriter_cypherSetProperties_append(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{AppendProperties: []string{"events"}})
+
+	got, err := writer.cypherSetProperties(map[string]any{"id": 1, "events": "login"}, map[string]any{"id": 1})
+	is.NoErr(err)
+	is.Equal(got, "obj.`events` = coalesce(obj.`events`, []) + $`events`")
+}
+
+func TestWriter_cypherProperties_sorted(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{ServerComputedProperties: map[string]string{"uuid": "randomUUID", "createdAt": "datetime"}})
+
+	properties := map[string]any{"name": "Alex", "id": 1, "age": 30, "email": "alex@example.com", "city": "Kyiv"}
+
+	// maps are iterated in a random order, so the clauses are built several times
+	for range 10 {
+		got, err := writer.cypherMatchProperties(properties, interpolationSourcePrefix)
+		is.NoErr(err)
+		is.Equal(got, "`age`:$`src_age`, `city`:$`src_city`, `email`:$`src_email`, `id`:$`src_id`, `name`:$`src_name`")
+
+		got, err = writer.cypherSetProperties(properties, map[string]any{"id": 1})
+		is.NoErr(err)
+		is.Equal(got, "obj.`age`=$`age`, obj.`city`=$`city`, obj.`email`=$`email`, obj.`name`=$`name`, "+
+			"obj.`createdAt`=datetime(), obj.`uuid`=randomUUID()")
+	}
+}
+
+func TestWriter_cypherSetProperties_serverComputed(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{ServerComputedProperties: map[string]string{"uuid": "randomUUID()", "id": "randomUUID"}})
+	is.Equal(writer.serverComputedProperties, map[string]string{"uuid": "randomUUID", "id": "randomUUID"})
+
+	// the key properties are not recomputed
+	got, err := writer.cypherSetProperties(nil, map[string]any{"id": 1})
+	is.NoErr(err)
+	is.Equal(got, "obj.`uuid`=randomUUID()")
+
+	query, err := writer.withServerComputedProperties("CREATE (obj:`Person` {`id`:$`id`})")
+	is.NoErr(err)
+	is.True(strings.HasPrefix(query, "CREATE (obj:`Person` {`id`:$`id`}) SET "))
+
+	properties := map[string]any{"uuid": "client", "name": "Alex"}
+	writer.removeServerComputedProperties(properties)
+	is.Equal(properties, map[string]any{"name": "Alex"})
+}
+
+func TestValidateServerComputedProperties(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		properties map[string]string
+		wantErr    error
+	}{
+		{
+			name:       "success",
+			properties: map[string]string{"id": "randomUUID()", "createdAt": "datetime"},
+		},
+		{
+			name:       "fail_unsupported",
+			properties: map[string]string{"id": "apoc.create.uuid()"},
+			wantErr:    ErrUnsupportedServerFunction,
+		},
+		{
+			name:       "fail_injection",
+			properties: map[string]string{"id": "randomUUID() DETACH DELETE obj"},
+			wantErr:    ErrUnsupportedServerFunction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := ValidateServerComputedProperties(tt.properties); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateServerComputedProperties() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriter_wrapAppendProperties(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{AppendProperties: []string{"events", "tags"}})
+
+	properties := map[string]any{"name": "Alex", "events": "login", "tags": []any{"a"}}
+	writer.wrapAppendProperties(properties)
+
+	is.Equal(properties, map[string]any{"name": "Alex", "events": []any{"login"}, "tags": []any{"a"}})
+}
+
+func TestWriter_cypherMatchEndpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		node            *schema.Node
+		matchProperties []string
+		want            string
+	}{
+		{
+			name: "success_key",
+			node: &schema.Node{Labels: []string{"Person"}, Key: map[string]any{"id": 1}},
+			want: "MATCH (src:`Person` {`id`:$`src_id`})",
+		},
+		{
+			name:            "success_any_property",
+			node:            &schema.Node{Labels: []string{"Person"}, Key: map[string]any{"email": "a", "username": "b"}},
+			matchProperties: []string{"email", "username"},
+			want:            "MATCH (src:`Person`) WHERE src.`email`=$`src_email` OR src.`username`=$`src_username` WITH * LIMIT 1",
+		},
+		{
+			name:            "success_missing_property",
+			node:            &schema.Node{Labels: []string{"Person"}, Key: map[string]any{"username": "b"}},
+			matchProperties: []string{"email", "username"},
+			want:            "MATCH (src:`Person`) WHERE src.`username`=$`src_username` WITH * LIMIT 1",
+		},
+		{
+			name:            "success_no_match_properties_in_key",
+			node:            &schema.Node{Labels: []string{"Person"}, Key: map[string]any{"id": 1}},
+			matchProperties: []string{"email"},
+			want:            "MATCH (src:`Person` {`id`:$`src_id`})",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			writer := New(Params{})
+
+			got, err := writer.cypherMatchEndpoint(
+				srcPlaceholder, tt.node, tt.matchProperties, interpolationSourcePrefix,
+			)
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestWriter_createRelationshipQuery_createEndpoints(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{CreateEndpoints: true})
+
+	sourceNode := &schema.Node{Labels: []string{"Person"}, Key: map[string]any{"id": 1}}
+	targetNode := &schema.Node{Labels: []string{"Person", "Writer"}, Key: map[string]any{"id": 2}}
+
+	got, err := writer.createRelationshipQuery("KNOWS", sourceNode, targetNode, map[string]any{"since": 2020})
+	is.NoErr(err)
+	is.Equal(got, "CREATE (src:`Person` {`id`:$`src_id`})-[obj:KNOWS {`since`:$`since`}]->(trgt:`Person`:`Writer` {`id`:$`trgt_id`})")
+
+	// the key takes precedence over the properties with the same name
+	is.Equal(writer.endpointProperties(&schema.Node{
+		Key:        map[string]any{"id": 1},
+		Properties: map[string]any{"id": 2, "name": "Alice"},
+	}), map[string]any{"id": 1, "name": "Alice"})
+
+	// the properties are ignored if the endpoints are matched
+	is.Equal(New(Params{}).endpointProperties(&schema.Node{
+		Key:        map[string]any{"id": 1},
+		Properties: map[string]any{"name": "Alice"},
+	}), map[string]any{"id": 1})
+}
+
+func TestWriter_relationshipByEndpointsQuery(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{EntityType: config.EntityTypeRelationship, MatchRelationshipsByEndpoints: true})
+	is.True(writer.matchRelationshipsByEndpoints())
+
+	// two relationships with identical properties are told apart by their endpoints
+	first := map[string]any{"since": 2020}
+	got, err := writer.relationshipByEndpointsQuery(
+		updateRelationshipByEndpointsQueryTemplate, "KNOWS", "`since`:$`since`",
+		&schema.Node{Labels: []string{"Person"}, Key: map[string]any{"id": 1}},
+		&schema.Node{Labels: []string{"Person"}, Key: map[string]any{"id": 2}},
+		first, " SET obj.`note` = $`note`",
+	)
+	is.NoErr(err)
+	is.Equal(got, "MATCH (src:`Person` {`id`:$`src_id`})-[obj:KNOWS {`since`:$`since`}]->"+
+		"(trgt:`Person` {`id`:$`trgt_id`}) SET obj.`note` = $`note`")
+	is.Equal(first, map[string]any{"since": 2020, "src_id": 1, "trgt_id": 2})
+
+	second := map[string]any{"since": 2020}
+	got, err = writer.relationshipByEndpointsQuery(
+		deleteRelationshipByEndpointsQueryTemplate, "KNOWS", "`since`:$`since`",
+		&schema.Node{Labels: []string{"Person"}, Key: map[string]any{"id": 1}},
+		&schema.Node{Labels: []string{"Person"}, Key: map[string]any{"id": 3}},
+		second,
+	)
+	is.NoErr(err)
+	is.Equal(got, "MATCH (src:`Person` {`id`:$`src_id`})-[obj:KNOWS {`since`:$`since`}]->"+
+		"(trgt:`Person` {`id`:$`trgt_id`}) DELETE obj")
+	is.Equal(second, map[string]any{"since": 2020, "src_id": 1, "trgt_id": 3})
+
+	// nodes are always matched by their own key
+	is.True(!New(Params{EntityType: config.EntityTypeNode, MatchRelationshipsByEndpoints: true}).
+		matchRelationshipsByEndpoints())
+}
+
+func TestWriter_deleteEndpoints(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{EntityType: config.EntityTypeRelationship, MatchRelationshipsByEndpoints: true})
+
+	sourceNode, targetNode, err := writer.deleteEndpoints(sdk.Record{
+		Operation: sdk.OperationDelete,
+		Payload: sdk.Change{Before: sdk.StructuredData{
+			"since":      2020,
+			"sourceNode": map[string]any{"labels": []string{"Person"}, "key": map[string]any{"id": 1}},
+			"targetNode": map[string]any{"labels": []string{"Person"}, "key": map[string]any{"id": 2}},
+		}},
+	})
+	is.NoErr(err)
+	is.Equal(sourceNode.Labels, []string{"Person"})
+	is.Equal(targetNode.Labels, []string{"Person"})
+
+	// the payload before a delete is empty unless the source includes the deleted state
+	_, _, err = writer.deleteEndpoints(sdk.Record{Operation: sdk.OperationDelete})
+	is.True(errors.Is(err, ErrEmptySourceNode))
+}
+
+// deleteCounters reports the numbers of deleted nodes and relationships.
+type deleteCounters struct {
+	neo4j.Counters
+
+	nodesDeleted         int
+	relationshipsDeleted int
+}
+
+func (c deleteCounters) NodesDeleted() int {
+	return c.nodesDeleted
+}
+
+func (c deleteCounters) RelationshipsDeleted() int {
+	return c.relationshipsDeleted
+}
+
+func TestWriter_deletedElements(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		entityType config.EntityType
+		counters   deleteCounters
+		wantErr    error
+	}{
+		{
+			name:       "node",
+			entityType: config.EntityTypeNode,
+			counters:   deleteCounters{nodesDeleted: 1},
+		},
+		{
+			name:       "node_detach",
+			entityType: config.EntityTypeNode,
+			counters:   deleteCounters{nodesDeleted: 1, relationshipsDeleted: 2},
+		},
+		{
+			name:       "fail_node_none",
+			entityType: config.EntityTypeNode,
+			counters:   deleteCounters{},
+			wantErr:    ErrCardinalityViolation,
+		},
+		{
+			name:       "fail_node_many",
+			entityType: config.EntityTypeNode,
+			counters:   deleteCounters{nodesDeleted: 2},
+			wantErr:    ErrCardinalityViolation,
+		},
+		{
+			name:       "relationship",
+			entityType: config.EntityTypeRelationship,
+			counters:   deleteCounters{relationshipsDeleted: 1},
+		},
+		{
+			name:       "fail_relationship_many",
+			entityType: config.EntityTypeRelationship,
+			counters:   deleteCounters{relationshipsDeleted: 3},
+			wantErr:    ErrCardinalityViolation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			writer := New(Params{EntityType: tt.entityType, StrictCardinality: true})
+
+			err := checkCardinality(writer.deletedElements(tt.counters))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkCardinality() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriter_deleteQueryTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		entityType   config.EntityType
+		detachDelete bool
+		want         string
+	}{
+		{
+			name:       "node",
+			entityType: config.EntityTypeNode,
+			want:       deleteNodeQueryTemplate,
+		},
+		{
+			name:         "node_detach",
+			entityType:   config.EntityTypeNode,
+			detachDelete: true,
+			want:         detachDeleteNodeQueryTemplate,
+		},
+		{
+			name:         "relationship",
+			entityType:   config.EntityTypeRelationship,
+			detachDelete: true,
+			want:         deleteRelationshipQueryTemplate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			writer := New(Params{EntityType: tt.entityType, DetachDelete: tt.detachDelete})
+			if got := writer.deleteQueryTemplate(); got != tt.want {
+				t.Errorf("deleteQueryTemplate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_recordKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		keyProperties []string
+		record        sdk.Record
+		missingKeyErr error
+		want          map[string]any
+		wantErr       error
+	}{
+		{
+			name:          "success_record_key",
+			keyProperties: []string{"email"},
+			record: sdk.Record{
+				Key:     sdk.StructuredData{"id": 1},
+				Payload: sdk.Change{After: sdk.StructuredData{"id": 1, "email": "alice@example.com"}},
+			},
+			missingKeyErr: ErrMissingKeyForUpdate,
+			want:          map[string]any{"id": float64(1)},
+		},
+		{
+			name:          "success_fallback_payload_after",
+			keyProperties: []string{"email"},
+			record: sdk.Record{
+				Payload: sdk.Change{After: sdk.StructuredData{"id": 1, "email": "alice@example.com"}},
+			},
+			missingKeyErr: ErrMissingKeyForUpdate,
+			want:          map[string]any{"email": "alice@example.com"},
+		},
+		{
+			name:          "success_fallback_payload_before",
+			keyProperties: []string{"id"},
+			record: sdk.Record{
+				Key:     sdk.StructuredData{},
+				Payload: sdk.Change{Before: sdk.StructuredData{"id": 1}},
+			},
+			missingKeyErr: ErrMissingKeyForDelete,
+			want:          map[string]any{"id": float64(1)},
+		},
+		{
+			name:          "fail_update_no_key",
+			record:        sdk.Record{Payload: sdk.Change{After: sdk.StructuredData{"id": 1}}},
+			missingKeyErr: ErrMissingKeyForUpdate,
+			wantErr:       ErrMissingKeyForUpdate,
+		},
+		{
+			name:          "fail_delete_no_key",
+			record:        sdk.Record{},
+			missingKeyErr: ErrMissingKeyForDelete,
+			wantErr:       ErrMissingKeyForDelete,
+		},
+		{
+			name:          "fail_delete_empty_payload",
+			keyProperties: []string{"id"},
+			record:        sdk.Record{},
+			missingKeyErr: ErrMissingKeyForDelete,
+			wantErr:       ErrMissingKeyForDelete,
+		},
+		{
+			name:          "fail_missing_key_property",
+			keyProperties: []string{"email"},
+			record:        sdk.Record{Payload: sdk.Change{After: sdk.StructuredData{"id": 1}}},
+			missingKeyErr: ErrMissingKeyForUpdate,
+			wantErr:       ErrMissingKeyForUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			writer := New(Params{KeyProperties: tt.keyProperties})
+
+			got, err := writer.recordKey(tt.record, tt.missingKeyErr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("recordKey() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recordKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_resolveLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                  string
+		entityType            config.EntityType
+		labelConflictBehavior LabelConflictBehavior
+		metadata              sdk.Metadata
+		want                  string
+		wantErr               error
+	}{
+		{
+			name:                  "config_wins",
+			labelConflictBehavior: LabelConflictBehaviorConfigWins,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Writer"},
+			want:                  "`Author`:`Person`",
+		},
+		{
+			name:                  "metadata_wins",
+			labelConflictBehavior: LabelConflictBehaviorMetadataWins,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Writer"},
+			want:                  "`Writer`",
+		},
+		{
+			name:                  "merge",
+			labelConflictBehavior: LabelConflictBehaviorMerge,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Writer:Person"},
+			want:                  "`Author`:`Person`:`Writer`",
+		},
+		{
+			name:                  "merge_relationship",
+			entityType:            config.EntityTypeRelationship,
+			labelConflictBehavior: LabelConflictBehaviorMerge,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Writer"},
+			wantErr:               ErrLabelConflict,
+		},
+		{
+			name:                  "error",
+			labelConflictBehavior: LabelConflictBehaviorError,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Writer"},
+			wantErr:               ErrLabelConflict,
+		},
+		{
+			name:                  "error_same_labels",
+			labelConflictBehavior: LabelConflictBehaviorError,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Author:Person"},
+			want:                  "`Author`:`Person`",
+		},
+		{
+			name:                  "metadata_wins_unsorted_duplicated",
+			labelConflictBehavior: LabelConflictBehaviorMetadataWins,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Writer: Editor:Writer"},
+			want:                  "`Editor`:`Writer`",
+		},
+		{
+			name:                  "merge_unsorted_duplicated",
+			labelConflictBehavior: LabelConflictBehaviorMerge,
+			metadata:              sdk.Metadata{metadataEntityLabelsField: "Writer:Author:Writer"},
+			want:                  "`Author`:`Person`:`Writer`",
+		},
+		{
+			name:                  "error_no_metadata",
+			labelConflictBehavior: LabelConflictBehaviorError,
+			want:                  "`Author`:`Person`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			writer := New(Params{
+				EntityType:            tt.entityType,
+				EntityLabels:          []string{"Person", "Author"},
+				LabelConflictBehavior: tt.labelConflictBehavior,
+			})
+
+			got, err := writer.resolveLabels(tt.metadata)
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestWriter_recordWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                   string
+		entityTypeFromMetadata bool
+		metadata               sdk.Metadata
+		wantEntityType         config.EntityType
+		wantEntityLabels       string
+		wantErr                error
+	}{
+		{
+			name: "disabled",
+			metadata: sdk.Metadata{
+				metadataEntityTypeField:   string(config.EntityTypeRelationship),
+				metadataEntityLabelsField: "KNOWS",
+			},
+			wantEntityType:   config.EntityTypeNode,
+			wantEntityLabels: "`Person`",
+		},
+		{
+			name:                   "no_metadata",
+			entityTypeFromMetadata: true,
+			wantEntityType:         config.EntityTypeNode,
+			wantEntityLabels:       "`Person`",
+		},
+		{
+			name:                   "same_entity_type",
+			entityTypeFromMetadata: true,
+			metadata:               sdk.Metadata{metadataEntityTypeField: string(config.EntityTypeNode)},
+			wantEntityType:         config.EntityTypeNode,
+			wantEntityLabels:       "`Person`",
+		},
+		{
+			name:                   "other_entity_type",
+			entityTypeFromMetadata: true,
+			metadata: sdk.Metadata{
+				metadataEntityTypeField:   string(config.EntityTypeRelationship),
+				metadataEntityLabelsField: "KNOWS",
+			},
+			wantEntityType:   config.EntityTypeRelationship,
+			wantEntityLabels: "`KNOWS`",
+		},
+		{
+			name:                   "missing_labels",
+			entityTypeFromMetadata: true,
+			metadata:               sdk.Metadata{metadataEntityTypeField: string(config.EntityTypeRelationship)},
+			wantErr:                ErrMissingEntityLabels,
+		},
+		{
+			name:                   "unsupported_entity_type",
+			entityTypeFromMetadata: true,
+			metadata:               sdk.Metadata{metadataEntityTypeField: "path"},
+			wantErr:                ErrUnsupportedEntityType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			writer := New(Params{
+				EntityType:             config.EntityTypeNode,
+				EntityLabels:           []string{"Person"},
+				EntityTypeFromMetadata: tt.entityTypeFromMetadata,
+			})
+
+			got, err := writer.recordWriter(tt.metadata)
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(got.entityType, tt.wantEntityType)
+			is.Equal(got.entityLabels, tt.wantEntityLabels)
+			// the configured writer must stay untouched
+			is.Equal(writer.entityType, config.EntityTypeNode)
+		})
+	}
+}
+
+func TestWriter_setProcessedAt(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{ProcessedAtProperty: "processedAt"})
+
+	before := time.Now()
+	properties := map[string]any{"name": "Alex"}
+	writer.setProcessedAt(properties)
+
+	processedAt, ok := properties["processedAt"].(time.Time)
+	is.True(ok)
+	is.True(!processedAt.Before(before.Truncate(time.Second)))
+	is.Equal(properties["name"], "Alex")
+}
+
+func TestWriter_setProcessedAt_disabled(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	properties := map[string]any{"name": "Alex"}
+	New(Params{}).setProcessedAt(properties)
+
+	is.Equal(properties, map[string]any{"name": "Alex"})
+}
+
+func TestWriter_updateSetClause(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		params     Params
+		properties map[string]any
+		want       string
+	}{
+		{
+			name:       "success_disabled",
+			params:     Params{},
+			properties: map[string]any{"id": 1, "name": "Alex"},
+			want:       " SET obj.`name` = $`name`",
+		},
+		{
+			name:       "success_skipUnchanged",
+			params:     Params{SkipUnchanged: true, ProcessedAtProperty: "processedAt"},
+			properties: map[string]any{"id": 1, "name": "Alex", "processedAt": time.Now()},
+			want: " FOREACH (changed IN CASE WHEN " +
+				"coalesce(obj.`name` <> $`name`, obj.`name` IS NOT NULL OR $`name` IS NOT NULL) " +
+				"THEN [1] ELSE [] END | SET obj.`name` = $`name`)",
+		},
+		{
+			name:       "success_skipUnchanged_append",
+			params:     Params{SkipUnchanged: true, AppendProperties: []string{"events"}},
+			properties: map[string]any{"id": 1, "events": []any{"updated"}},
+			want:       " SET obj.`name` = $`name`",
+		},
+		{
+			name:       "success_skipUnchanged_key_only",
+			params:     Params{SkipUnchanged: true},
+			properties: map[string]any{"id": 1},
+			want:       " SET obj.`name` = $`name`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			// the SET properties are passed as is, so only the comparison is checked
+			got := New(tt.params).updateSetClause(tt.properties, map[string]any{"id": 1}, "obj.`name` = $`name`")
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestWriter_updateSetClause_empty(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	got := New(Params{SkipUnchanged: true}).updateSetClause(map[string]any{"id": 1}, map[string]any{"id": 1}, "")
+	is.Equal(got, "")
+}
+
+func TestWriter_ignoreNullProperties(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                 string
+		removeNullProperties bool
+		want                 map[string]any
+	}{
+		{
+			name:                 "success_ignored",
+			removeNullProperties: false,
+			want:                 map[string]any{"id": 1, "tags": []string{"a"}},
+		},
+		{
+			name:                 "success_removed",
+			removeNullProperties: true,
+			want:                 map[string]any{"id": 1, "name": nil, "tags": []string{"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			properties := map[string]any{"id": 1, "name": nil, "tags": []string{"a"}}
+			New(Params{RemoveNullProperties: tt.removeNullProperties}).ignoreNullProperties(properties)
+			is.Equal(properties, tt.want)
+		})
+	}
+}
+
+func TestWriter_resolveEndpointsOnNode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		endpointsOnNode EndpointsOnNode
+		properties      map[string]any
+		want            map[string]any
+		wantErr         error
+	}{
+		{
+			name:            "success_no_endpoints",
+			endpointsOnNode: EndpointsOnNodeError,
+			properties:      map[string]any{"name": "Alex"},
+			want:            map[string]any{"name": "Alex"},
+		},
+		{
+			name:            "success_strip",
+			endpointsOnNode: EndpointsOnNodeStrip,
+			properties: map[string]any{
+				"name":       "Alex",
+				"sourceNode": map[string]any{"labels": []any{"Person"}},
+				"targetNode": map[string]any{"labels": []any{"Person"}},
+			},
+			want: map[string]any{"name": "Alex"},
+		},
+		{
+			name:            "fail_error",
+			endpointsOnNode: EndpointsOnNodeError,
+			properties: map[string]any{
+				"name":       "Alex",
+				"sourceNode": map[string]any{"labels": []any{"Person"}},
+			},
+			wantErr: ErrEndpointsOnNode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			writer := New(Params{EndpointsOnNode: tt.endpointsOnNode})
+
+			err := writer.resolveEndpointsOnNode(context.Background(), tt.properties)
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(tt.properties, tt.want)
+		})
+	}
+}
+
+func TestWriter_structurizeRawData_numbers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rawData sdk.RawData
+		want    map[string]any
+		wantErr error
+	}{
+		{
+			name:    "success_small_numbers",
+			rawData: sdk.RawData(`{"int":1,"float":1.5,"nested":{"list":[2]}}`),
+			want:    map[string]any{"int": float64(1), "float": 1.5, "nested": map[string]any{"list": []float64{2}}},
+		},
+		{
+			name:    "success_big_integer",
+			rawData: sdk.RawData(`{"id":9007199254740993}`),
+			want:    map[string]any{"id": int64(9007199254740993)},
+		},
+		{
+			name:    "fail_integer_overflow",
+			rawData: sdk.RawData(`{"id":9223372036854775808}`),
+			wantErr: ErrIntegerOverflow,
+		},
+		{
+			name:    "fail_nested_integer_overflow",
+			rawData: sdk.RawData(`{"sourceNode":{"key":{"id":-9223372036854775809}}}`),
+			wantErr: ErrIntegerOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			got, err := New(Params{}).structurizeRawData(tt.rawData)
+			if tt.wantErr != nil {
+				is.True(errors.Is(err, tt.wantErr))
+
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestWriter_structurizeRawData_values(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	got, err := New(Params{}).structurizeRawData(sdk.RawData(
+		`{"born":{"neo4jType":"date","value":"1990-02-03"},"home":{"srid":4326,"x":30.52,"y":50.45},` +
+			`"sourceNode":{"key":{"since":{"neo4jType":"duration","value":"P1M0DT0S"}}}}`,
+	))
+	is.NoErr(err)
+	is.Equal(got, map[string]any{
+		"born":       dbtype.Date(time.Date(1990, 2, 3, 0, 0, 0, 0, time.UTC)),
+		"home":       dbtype.Point2D{X: 30.52, Y: 50.45, SpatialRefId: 4326},
+		"sourceNode": map[string]any{"key": map[string]any{"since": dbtype.Duration{Months: 1}}},
+	})
+
+	_, err = New(Params{}).structurizeRawData(sdk.RawData(`{"born":{"neo4jType":"date","value":"1990"}}`))
+	is.True(errors.Is(err, schema.ErrInvalidTemporalValue))
+}
+
 func BenchmarkWriter_cypherMatchProperties(b *testing.B) {
 	var (
 		writer     = New(Params{})
@@ -46,3 +932,226 @@ func BenchmarkWriter_cypherSetProperties(b *testing.B) {
 		}
 	}
 }
+
+func TestEscapeIdentifier(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		identifier string
+		want       string
+	}{
+		{
+			name:       "plain",
+			identifier: "Person",
+			want:       "`Person`",
+		},
+		{
+			name:       "space",
+			identifier: "Per son",
+			want:       "`Per son`",
+		},
+		{
+			name:       "dot",
+			identifier: "address.city",
+			want:       "`address.city`",
+		},
+		{
+			name:       "backtick",
+			identifier: "Person`) DETACH DELETE (n",
+			want:       "`Person``) DETACH DELETE (n`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := escapeIdentifier(tt.identifier); got != tt.want {
+				t.Errorf("escapeIdentifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_cypherQuery_escaped(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	writer := New(Params{EntityLabels: []string{"Per son", "Author"}})
+
+	labels, err := writer.resolveLabels(nil)
+	is.NoErr(err)
+	is.Equal(labels, "`Author`:`Per son`")
+
+	got, err := writer.cypherMatchProperties(map[string]any{"address.city": "Kyiv"}, interpolationSourcePrefix)
+	is.NoErr(err)
+	is.Equal(got, "`address.city`:$`src_address.city`")
+
+	got, err = writer.cypherSetProperties(map[string]any{"first name": "Alice"}, nil)
+	is.NoErr(err)
+	is.Equal(got, "obj.`first name`=$`first name`")
+}
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{
+			name:    "deadlock",
+			err:     &neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected"},
+			wantErr: ErrTransient,
+		},
+		{
+			name:    "cluster",
+			err:     &neo4j.Neo4jError{Code: "Neo.ClientError.Cluster.NotALeader"},
+			wantErr: ErrTransient,
+		},
+		{
+			name:    "connectivity",
+			err:     &neo4j.ConnectivityError{},
+			wantErr: ErrTransient,
+		},
+		{
+			name:    "timeout",
+			err:     &neo4j.Neo4jError{Code: "Neo.ClientError.Transaction.TransactionTimedOutClientConfiguration"},
+			wantErr: ErrTransient,
+		},
+		{
+			name:    "constraint",
+			err:     &neo4j.Neo4jError{Code: "Neo.ClientError.Schema.ConstraintValidationFailed"},
+			wantErr: ErrConstraintViolation,
+		},
+		{
+			name:    "syntax",
+			err:     &neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError"},
+			wantErr: ErrInvalidQuery,
+		},
+		{
+			name:    "not_classified",
+			err:     &neo4j.Neo4jError{Code: "Neo.ClientError.Security.Forbidden"},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			err := classifyError(fmt.Errorf("write record 0: %w", tt.err))
+
+			// the underlying error is still available
+			is.True(errors.Is(err, tt.err))
+
+			for _, class := range []error{ErrTransient, ErrConstraintViolation, ErrInvalidQuery} {
+				is.Equal(errors.Is(err, class), class == tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriter_skippable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		onError   OnError
+		err       error
+		wantIndex int
+		wantOK    bool
+	}{
+		{
+			name:      "skip_permanent",
+			onError:   OnErrorSkip,
+			err:       fmt.Errorf("%w: duplicate", ErrConstraintViolation),
+			wantIndex: 2,
+			wantOK:    true,
+		},
+		{
+			name:      "skip_malformed",
+			onError:   OnErrorSkip,
+			err:       ErrMissingKeyForUpdate,
+			wantIndex: 2,
+			wantOK:    true,
+		},
+		{
+			name:    "skip_transient",
+			onError: OnErrorSkip,
+			err:     fmt.Errorf("%w: deadlock", ErrTransient),
+		},
+		{
+			name:    "stop",
+			onError: OnErrorStop,
+			err:     ErrMissingKeyForUpdate,
+		},
+		{
+			name: "default",
+			err:  ErrMissingKeyForUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			writer := New(Params{OnError: tt.onError})
+
+			// the record error is wrapped the same way the Write wraps it
+			err := fmt.Errorf("execute write: %w", &recordError{index: 2, err: tt.err})
+
+			index, ok := writer.skippable(err)
+			is.Equal(ok, tt.wantOK)
+			is.Equal(index, tt.wantIndex)
+		})
+	}
+
+	// errors that aren't caused by a record, e.g. a failed commit, cannot be skipped
+	_, ok := New(Params{OnError: OnErrorSkip}).skippable(errors.New("commit: connection reset"))
+	is.New(t).True(!ok)
+}
+
+var errTestSession = errors.New("test session")
+
+func TestWriter_Write_accessMode(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctrl := gomock.NewController(t)
+
+	session, sessionMock := mock.NewSession(ctrl)
+	sessionMock.EXPECT().ExecuteWrite(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errTestSession)
+	sessionMock.EXPECT().Close(gomock.Any()).Return(nil)
+
+	// the destination only writes, so routing drivers must send its queries to the leader
+	driver := mock.NewMockDriver(ctrl)
+	driver.EXPECT().
+		NewSession(gomock.Any(), neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: "neo4j"}).
+		Return(session)
+
+	_, err := New(Params{Driver: driver, DatabaseName: "neo4j"}).Write(context.Background(), []sdk.Record{
+		{Operation: sdk.OperationCreate, Payload: sdk.Change{After: sdk.StructuredData{"id": 1}}},
+	})
+	is.True(errors.Is(err, errTestSession))
+}
+
+func TestWriter_writeRecord_snapshotCompleteMarker(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// the marker holds no data, so it's skipped without touching the transaction
+	err := New(Params{DatabaseName: "neo4j"}).writeRecord(context.Background(), nil, sdk.Record{
+		Operation: sdk.OperationSnapshot,
+		Metadata:  sdk.Metadata{metadataSnapshotCompleteField: "true", metadataEntityLabelsField: "Person"},
+	})
+	is.NoErr(err)
+}
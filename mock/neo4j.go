@@ -0,0 +1,158 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/mock/gomock"
+)
+
+// The interfaces below hold the exported methods of the Neo4j driver interfaces the mocks are generated for.
+// The session, transaction and result interfaces of the driver have unexported methods, so they can't be
+// implemented outside of the driver, and their mocks are wrapped to implement them, see the [NewSession].
+
+// Driver is the [neo4j.DriverWithContext].
+type Driver interface {
+	ExecuteQueryBookmarkManager() neo4j.BookmarkManager
+	Target() url.URL
+	NewSession(ctx context.Context, config neo4j.SessionConfig) neo4j.SessionWithContext
+	VerifyConnectivity(ctx context.Context) error
+	VerifyAuthentication(ctx context.Context, auth *neo4j.AuthToken) error
+	Close(ctx context.Context) error
+	IsEncrypted() bool
+	GetServerInfo(ctx context.Context) (neo4j.ServerInfo, error)
+}
+
+// Session is the exported part of the [neo4j.SessionWithContext].
+type Session interface {
+	LastBookmarks() neo4j.Bookmarks
+	BeginTransaction(ctx context.Context, configurers ...func(*neo4j.TransactionConfig)) (
+		neo4j.ExplicitTransaction, error,
+	)
+	ExecuteRead(ctx context.Context, work neo4j.ManagedTransactionWork,
+		configurers ...func(*neo4j.TransactionConfig),
+	) (any, error)
+	ExecuteWrite(ctx context.Context, work neo4j.ManagedTransactionWork,
+		configurers ...func(*neo4j.TransactionConfig),
+	) (any, error)
+	Run(ctx context.Context, cypher string, params map[string]any, configurers ...func(*neo4j.TransactionConfig)) (
+		neo4j.ResultWithContext, error,
+	)
+	Close(ctx context.Context) error
+}
+
+// ExplicitTransaction is the exported part of the [neo4j.ExplicitTransaction].
+type ExplicitTransaction interface {
+	Run(ctx context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error)
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// ManagedTransaction is the exported part of the [neo4j.ManagedTransaction].
+type ManagedTransaction interface {
+	Run(ctx context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error)
+}
+
+// Result is the exported part of the [neo4j.ResultWithContext].
+type Result interface {
+	Keys() ([]string, error)
+	NextRecord(ctx context.Context, record **neo4j.Record) bool
+	Next(ctx context.Context) bool
+	PeekRecord(ctx context.Context, record **neo4j.Record) bool
+	Peek(ctx context.Context) bool
+	Err() error
+	Record() *neo4j.Record
+	Collect(ctx context.Context) ([]*neo4j.Record, error)
+	Single(ctx context.Context) (*neo4j.Record, error)
+	Consume(ctx context.Context) (neo4j.ResultSummary, error)
+	IsOpen() bool
+}
+
+// session implements the [neo4j.SessionWithContext] with the MockSession. The driver interface is embedded
+// one level deeper than the mock, so only its unexported methods are promoted, and calling them panics.
+type session struct {
+	*MockSession
+	unexportedSession
+}
+
+type unexportedSession struct {
+	neo4j.SessionWithContext
+}
+
+// NewSession returns a [neo4j.SessionWithContext] which calls are served by the returned MockSession.
+func NewSession(ctrl *gomock.Controller) (neo4j.SessionWithContext, *MockSession) {
+	mock := NewMockSession(ctrl)
+
+	return session{MockSession: mock}, mock
+}
+
+// explicitTransaction implements the [neo4j.ExplicitTransaction] with the MockExplicitTransaction,
+// see the [session].
+type explicitTransaction struct {
+	*MockExplicitTransaction
+	unexportedExplicitTransaction
+}
+
+type unexportedExplicitTransaction struct {
+	neo4j.ExplicitTransaction
+}
+
+// NewExplicitTransaction returns a [neo4j.ExplicitTransaction]
+// which calls are served by the returned MockExplicitTransaction.
+func NewExplicitTransaction(ctrl *gomock.Controller) (neo4j.ExplicitTransaction, *MockExplicitTransaction) {
+	mock := NewMockExplicitTransaction(ctrl)
+
+	return explicitTransaction{MockExplicitTransaction: mock}, mock
+}
+
+// managedTransaction implements the [neo4j.ManagedTransaction] with the MockManagedTransaction,
+// see the [session].
+type managedTransaction struct {
+	*MockManagedTransaction
+	unexportedManagedTransaction
+}
+
+type unexportedManagedTransaction struct {
+	neo4j.ManagedTransaction
+}
+
+// NewManagedTransaction returns a [neo4j.ManagedTransaction]
+// which calls are served by the returned MockManagedTransaction.
+func NewManagedTransaction(ctrl *gomock.Controller) (neo4j.ManagedTransaction, *MockManagedTransaction) {
+	mock := NewMockManagedTransaction(ctrl)
+
+	return managedTransaction{MockManagedTransaction: mock}, mock
+}
+
+// result implements the [neo4j.ResultWithContext] with the MockResult, see the [session].
+type result struct {
+	*MockResult
+	unexportedResult
+}
+
+type unexportedResult struct {
+	neo4j.ResultWithContext
+}
+
+// NewResult returns a [neo4j.ResultWithContext] which calls are served by the returned MockResult.
+func NewResult(ctrl *gomock.Controller) (neo4j.ResultWithContext, *MockResult) {
+	mock := NewMockResult(ctrl)
+
+	return result{MockResult: mock}, mock
+}
@@ -0,0 +1,590 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: mock/neo4j.go
+//
+// Generated by this command:
+//
+//	mockgen -package mock -self_package github.com/conduitio-labs/conduit-connector-neo4j/mock -source mock/neo4j.go -destination mock/neo4j_mock.go
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	url "net/url"
+	reflect "reflect"
+
+	neo4j "github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDriver is a mock of Driver interface.
+type MockDriver struct {
+	ctrl     *gomock.Controller
+	recorder *MockDriverMockRecorder
+	isgomock struct{}
+}
+
+// MockDriverMockRecorder is the mock recorder for MockDriver.
+type MockDriverMockRecorder struct {
+	mock *MockDriver
+}
+
+// NewMockDriver creates a new mock instance.
+func NewMockDriver(ctrl *gomock.Controller) *MockDriver {
+	mock := &MockDriver{ctrl: ctrl}
+	mock.recorder = &MockDriverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDriver) EXPECT() *MockDriverMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockDriver) Close(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockDriverMockRecorder) Close(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDriver)(nil).Close), ctx)
+}
+
+// ExecuteQueryBookmarkManager mocks base method.
+func (m *MockDriver) ExecuteQueryBookmarkManager() neo4j.BookmarkManager {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteQueryBookmarkManager")
+	ret0, _ := ret[0].(neo4j.BookmarkManager)
+	return ret0
+}
+
+// ExecuteQueryBookmarkManager indicates an expected call of ExecuteQueryBookmarkManager.
+func (mr *MockDriverMockRecorder) ExecuteQueryBookmarkManager() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteQueryBookmarkManager", reflect.TypeOf((*MockDriver)(nil).ExecuteQueryBookmarkManager))
+}
+
+// GetServerInfo mocks base method.
+func (m *MockDriver) GetServerInfo(ctx context.Context) (neo4j.ServerInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServerInfo", ctx)
+	ret0, _ := ret[0].(neo4j.ServerInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServerInfo indicates an expected call of GetServerInfo.
+func (mr *MockDriverMockRecorder) GetServerInfo(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerInfo", reflect.TypeOf((*MockDriver)(nil).GetServerInfo), ctx)
+}
+
+// IsEncrypted mocks base method.
+func (m *MockDriver) IsEncrypted() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEncrypted")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsEncrypted indicates an expected call of IsEncrypted.
+func (mr *MockDriverMockRecorder) IsEncrypted() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEncrypted", reflect.TypeOf((*MockDriver)(nil).IsEncrypted))
+}
+
+// NewSession mocks base method.
+func (m *MockDriver) NewSession(ctx context.Context, config neo4j.SessionConfig) neo4j.SessionWithContext {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewSession", ctx, config)
+	ret0, _ := ret[0].(neo4j.SessionWithContext)
+	return ret0
+}
+
+// NewSession indicates an expected call of NewSession.
+func (mr *MockDriverMockRecorder) NewSession(ctx, config any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSession", reflect.TypeOf((*MockDriver)(nil).NewSession), ctx, config)
+}
+
+// Target mocks base method.
+func (m *MockDriver) Target() url.URL {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Target")
+	ret0, _ := ret[0].(url.URL)
+	return ret0
+}
+
+// Target indicates an expected call of Target.
+func (mr *MockDriverMockRecorder) Target() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Target", reflect.TypeOf((*MockDriver)(nil).Target))
+}
+
+// VerifyAuthentication mocks base method.
+func (m *MockDriver) VerifyAuthentication(ctx context.Context, auth *neo4j.AuthToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAuthentication", ctx, auth)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyAuthentication indicates an expected call of VerifyAuthentication.
+func (mr *MockDriverMockRecorder) VerifyAuthentication(ctx, auth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAuthentication", reflect.TypeOf((*MockDriver)(nil).VerifyAuthentication), ctx, auth)
+}
+
+// VerifyConnectivity mocks base method.
+func (m *MockDriver) VerifyConnectivity(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyConnectivity", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyConnectivity indicates an expected call of VerifyConnectivity.
+func (mr *MockDriverMockRecorder) VerifyConnectivity(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyConnectivity", reflect.TypeOf((*MockDriver)(nil).VerifyConnectivity), ctx)
+}
+
+// MockSession is a mock of Session interface.
+type MockSession struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionMockRecorder
+	isgomock struct{}
+}
+
+// MockSessionMockRecorder is the mock recorder for MockSession.
+type MockSessionMockRecorder struct {
+	mock *MockSession
+}
+
+// NewMockSession creates a new mock instance.
+func NewMockSession(ctrl *gomock.Controller) *MockSession {
+	mock := &MockSession{ctrl: ctrl}
+	mock.recorder = &MockSessionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSession) EXPECT() *MockSessionMockRecorder {
+	return m.recorder
+}
+
+// BeginTransaction mocks base method.
+func (m *MockSession) BeginTransaction(ctx context.Context, configurers ...func(*neo4j.TransactionConfig)) (neo4j.ExplicitTransaction, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range configurers {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BeginTransaction", varargs...)
+	ret0, _ := ret[0].(neo4j.ExplicitTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockSessionMockRecorder) BeginTransaction(ctx any, configurers ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, configurers...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockSession)(nil).BeginTransaction), varargs...)
+}
+
+// Close mocks base method.
+func (m *MockSession) Close(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockSessionMockRecorder) Close(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSession)(nil).Close), ctx)
+}
+
+// ExecuteRead mocks base method.
+func (m *MockSession) ExecuteRead(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (any, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, work}
+	for _, a := range configurers {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExecuteRead", varargs...)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteRead indicates an expected call of ExecuteRead.
+func (mr *MockSessionMockRecorder) ExecuteRead(ctx, work any, configurers ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, work}, configurers...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteRead", reflect.TypeOf((*MockSession)(nil).ExecuteRead), varargs...)
+}
+
+// ExecuteWrite mocks base method.
+func (m *MockSession) ExecuteWrite(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (any, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, work}
+	for _, a := range configurers {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExecuteWrite", varargs...)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteWrite indicates an expected call of ExecuteWrite.
+func (mr *MockSessionMockRecorder) ExecuteWrite(ctx, work any, configurers ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, work}, configurers...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteWrite", reflect.TypeOf((*MockSession)(nil).ExecuteWrite), varargs...)
+}
+
+// LastBookmarks mocks base method.
+func (m *MockSession) LastBookmarks() neo4j.Bookmarks {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastBookmarks")
+	ret0, _ := ret[0].(neo4j.Bookmarks)
+	return ret0
+}
+
+// LastBookmarks indicates an expected call of LastBookmarks.
+func (mr *MockSessionMockRecorder) LastBookmarks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastBookmarks", reflect.TypeOf((*MockSession)(nil).LastBookmarks))
+}
+
+// Run mocks base method.
+func (m *MockSession) Run(ctx context.Context, cypher string, params map[string]any, configurers ...func(*neo4j.TransactionConfig)) (neo4j.ResultWithContext, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, cypher, params}
+	for _, a := range configurers {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Run", varargs...)
+	ret0, _ := ret[0].(neo4j.ResultWithContext)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockSessionMockRecorder) Run(ctx, cypher, params any, configurers ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, cypher, params}, configurers...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockSession)(nil).Run), varargs...)
+}
+
+// MockExplicitTransaction is a mock of ExplicitTransaction interface.
+type MockExplicitTransaction struct {
+	ctrl     *gomock.Controller
+	recorder *MockExplicitTransactionMockRecorder
+	isgomock struct{}
+}
+
+// MockExplicitTransactionMockRecorder is the mock recorder for MockExplicitTransaction.
+type MockExplicitTransactionMockRecorder struct {
+	mock *MockExplicitTransaction
+}
+
+// NewMockExplicitTransaction creates a new mock instance.
+func NewMockExplicitTransaction(ctrl *gomock.Controller) *MockExplicitTransaction {
+	mock := &MockExplicitTransaction{ctrl: ctrl}
+	mock.recorder = &MockExplicitTransactionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExplicitTransaction) EXPECT() *MockExplicitTransactionMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockExplicitTransaction) Close(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockExplicitTransactionMockRecorder) Close(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockExplicitTransaction)(nil).Close), ctx)
+}
+
+// Commit mocks base method.
+func (m *MockExplicitTransaction) Commit(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockExplicitTransactionMockRecorder) Commit(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockExplicitTransaction)(nil).Commit), ctx)
+}
+
+// Rollback mocks base method.
+func (m *MockExplicitTransaction) Rollback(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockExplicitTransactionMockRecorder) Rollback(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockExplicitTransaction)(nil).Rollback), ctx)
+}
+
+// Run mocks base method.
+func (m *MockExplicitTransaction) Run(ctx context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx, cypher, params)
+	ret0, _ := ret[0].(neo4j.ResultWithContext)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockExplicitTransactionMockRecorder) Run(ctx, cypher, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockExplicitTransaction)(nil).Run), ctx, cypher, params)
+}
+
+// MockManagedTransaction is a mock of ManagedTransaction interface.
+type MockManagedTransaction struct {
+	ctrl     *gomock.Controller
+	recorder *MockManagedTransactionMockRecorder
+	isgomock struct{}
+}
+
+// MockManagedTransactionMockRecorder is the mock recorder for MockManagedTransaction.
+type MockManagedTransactionMockRecorder struct {
+	mock *MockManagedTransaction
+}
+
+// NewMockManagedTransaction creates a new mock instance.
+func NewMockManagedTransaction(ctrl *gomock.Controller) *MockManagedTransaction {
+	mock := &MockManagedTransaction{ctrl: ctrl}
+	mock.recorder = &MockManagedTransactionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockManagedTransaction) EXPECT() *MockManagedTransactionMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockManagedTransaction) Run(ctx context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx, cypher, params)
+	ret0, _ := ret[0].(neo4j.ResultWithContext)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockManagedTransactionMockRecorder) Run(ctx, cypher, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockManagedTransaction)(nil).Run), ctx, cypher, params)
+}
+
+// MockResult is a mock of Result interface.
+type MockResult struct {
+	ctrl     *gomock.Controller
+	recorder *MockResultMockRecorder
+	isgomock struct{}
+}
+
+// MockResultMockRecorder is the mock recorder for MockResult.
+type MockResultMockRecorder struct {
+	mock *MockResult
+}
+
+// NewMockResult creates a new mock instance.
+func NewMockResult(ctrl *gomock.Controller) *MockResult {
+	mock := &MockResult{ctrl: ctrl}
+	mock.recorder = &MockResultMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResult) EXPECT() *MockResultMockRecorder {
+	return m.recorder
+}
+
+// Collect mocks base method.
+func (m *MockResult) Collect(ctx context.Context) ([]*neo4j.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Collect", ctx)
+	ret0, _ := ret[0].([]*neo4j.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Collect indicates an expected call of Collect.
+func (mr *MockResultMockRecorder) Collect(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collect", reflect.TypeOf((*MockResult)(nil).Collect), ctx)
+}
+
+// Consume mocks base method.
+func (m *MockResult) Consume(ctx context.Context) (neo4j.ResultSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx)
+	ret0, _ := ret[0].(neo4j.ResultSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockResultMockRecorder) Consume(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockResult)(nil).Consume), ctx)
+}
+
+// Err mocks base method.
+func (m *MockResult) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockResultMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockResult)(nil).Err))
+}
+
+// IsOpen mocks base method.
+func (m *MockResult) IsOpen() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOpen")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsOpen indicates an expected call of IsOpen.
+func (mr *MockResultMockRecorder) IsOpen() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOpen", reflect.TypeOf((*MockResult)(nil).IsOpen))
+}
+
+// Keys mocks base method.
+func (m *MockResult) Keys() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockResultMockRecorder) Keys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockResult)(nil).Keys))
+}
+
+// Next mocks base method.
+func (m *MockResult) Next(ctx context.Context) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next", ctx)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockResultMockRecorder) Next(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockResult)(nil).Next), ctx)
+}
+
+// NextRecord mocks base method.
+func (m *MockResult) NextRecord(ctx context.Context, record **neo4j.Record) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextRecord", ctx, record)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NextRecord indicates an expected call of NextRecord.
+func (mr *MockResultMockRecorder) NextRecord(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextRecord", reflect.TypeOf((*MockResult)(nil).NextRecord), ctx, record)
+}
+
+// Peek mocks base method.
+func (m *MockResult) Peek(ctx context.Context) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Peek", ctx)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Peek indicates an expected call of Peek.
+func (mr *MockResultMockRecorder) Peek(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Peek", reflect.TypeOf((*MockResult)(nil).Peek), ctx)
+}
+
+// PeekRecord mocks base method.
+func (m *MockResult) PeekRecord(ctx context.Context, record **neo4j.Record) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PeekRecord", ctx, record)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// PeekRecord indicates an expected call of PeekRecord.
+func (mr *MockResultMockRecorder) PeekRecord(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeekRecord", reflect.TypeOf((*MockResult)(nil).PeekRecord), ctx, record)
+}
+
+// Record mocks base method.
+func (m *MockResult) Record() *neo4j.Record {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record")
+	ret0, _ := ret[0].(*neo4j.Record)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockResultMockRecorder) Record() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockResult)(nil).Record))
+}
+
+// Single mocks base method.
+func (m *MockResult) Single(ctx context.Context) (*neo4j.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Single", ctx)
+	ret0, _ := ret[0].(*neo4j.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Single indicates an expected call of Single.
+func (mr *MockResultMockRecorder) Single(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Single", reflect.TypeOf((*MockResult)(nil).Single), ctx)
+}
@@ -0,0 +1,78 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package querylog implements logging of Cypher queries
+// with redaction of sensitive parameter values shared between the source and the destination.
+package querylog
+
+import (
+	"context"
+	"strings"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// Mask is a value that replaces redacted parameter values.
+const Mask = "***"
+
+// Log logs the query and its parameters at the debug level,
+// values of parameters that refer to the redacted properties are replaced with the [Mask].
+func Log(ctx context.Context, query string, params map[string]any, redactProperties []string) {
+	logger := sdk.Logger(ctx)
+	if logger.Debug().Enabled() {
+		logger.Debug().
+			Str("query", strings.TrimSpace(query)).
+			Interface("params", Redact(params, redactProperties)).
+			Msg("executing cypher query")
+	}
+}
+
+// Redact returns a copy of the parameters with values of the redacted properties replaced with the [Mask].
+//
+// A parameter refers to a property if its name is equal to the property name
+// or ends with "_" followed by the property name, as endpoint parameters are prefixed, e.g. "src_email".
+// Nested maps are redacted as well.
+func Redact(params map[string]any, redactProperties []string) map[string]any {
+	if len(redactProperties) == 0 {
+		return params
+	}
+
+	redacted := make(map[string]any, len(params))
+	for name, value := range params {
+		switch {
+		case isRedacted(name, redactProperties):
+			redacted[name] = Mask
+
+		default:
+			if nested, ok := value.(map[string]any); ok {
+				value = Redact(nested, redactProperties)
+			}
+
+			redacted[name] = value
+		}
+	}
+
+	return redacted
+}
+
+// isRedacted checks if the parameter name refers to any of the redacted properties.
+func isRedacted(name string, redactProperties []string) bool {
+	for _, property := range redactProperties {
+		if name == property || strings.HasSuffix(name, "_"+property) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,70 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querylog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rs/zerolog"
+)
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	params := map[string]any{
+		"name":      "Alex",
+		"email":     "alex@example.com",
+		"src_email": "bob@example.com",
+		"nested":    map[string]any{"email": "john@example.com", "age": 30},
+	}
+
+	got := Redact(params, []string{"email"})
+	is.Equal(got, map[string]any{
+		"name":      "Alex",
+		"email":     Mask,
+		"src_email": Mask,
+		"nested":    map[string]any{"email": Mask, "age": 30},
+	})
+
+	// the original parameters are not modified, as they're used to run the query
+	is.Equal(params["email"], "alex@example.com")
+}
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Level(zerolog.DebugLevel)
+	ctx := logger.WithContext(context.Background())
+
+	Log(ctx, "CREATE (obj:Person {name:$name, email:$email})",
+		map[string]any{"name": "Alex", "email": "alex@example.com"},
+		[]string{"email"},
+	)
+
+	output := buf.String()
+	is.True(strings.Contains(output, "CREATE (obj:Person {name:$name, email:$email})"))
+	is.True(strings.Contains(output, `"name":"Alex"`))
+	is.True(strings.Contains(output, `"email":"***"`))
+	is.True(!strings.Contains(output, "alex@example.com"))
+}
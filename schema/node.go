@@ -20,4 +20,7 @@ package schema
 type Node struct {
 	Labels []string       `json:"labels"`
 	Key    map[string]any `json:"key"`
+	// Properties holds additional properties of the node,
+	// they're used only if the node is created along with a relationship.
+	Properties map[string]any `json:"properties,omitempty"`
 }
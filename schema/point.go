@@ -0,0 +1,105 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// ErrInvalidPointSRID occurs when the srid of a point is not a valid coordinate reference system id.
+var ErrInvalidPointSRID = errors.New("invalid point srid")
+
+// Names of the point fields.
+const (
+	pointSRIDField = "srid"
+	pointXField    = "x"
+	pointYField    = "y"
+	pointZField    = "z"
+)
+
+// Point defines a model of a Neo4j spatial value, the Z is set only for three-dimensional points.
+type Point struct {
+	SRID uint32   `json:"srid"`
+	X    float64  `json:"x"`
+	Y    float64  `json:"y"`
+	Z    *float64 `json:"z,omitempty"`
+}
+
+// encodePoint returns the [Point] of the value if it's a spatial one.
+func encodePoint(value any) (Point, bool) {
+	switch v := value.(type) {
+	case dbtype.Point2D:
+		return Point{SRID: v.SpatialRefId, X: v.X, Y: v.Y}, true
+
+	case dbtype.Point3D:
+		z := v.Z
+
+		return Point{SRID: v.SpatialRefId, X: v.X, Y: v.Y, Z: &z}, true
+
+	default:
+		return Point{}, false
+	}
+}
+
+// decodePoint converts the map into the driver point type if it consists of the [Point] fields only
+// and all of them are numbers, the bool result reports whether the map is a point.
+func decodePoint(value map[string]any) (any, bool, error) {
+	if len(value) != 3 && len(value) != 4 {
+		return nil, false, nil
+	}
+
+	srid, sridOK := number(value[pointSRIDField])
+	x, xOK := number(value[pointXField])
+	y, yOK := number(value[pointYField])
+	if !sridOK || !xOK || !yOK {
+		return nil, false, nil
+	}
+
+	var z *float64
+	if len(value) == 4 {
+		zValue, zOK := number(value[pointZField])
+		if !zOK {
+			return nil, false, nil
+		}
+
+		z = &zValue
+	}
+
+	if srid != math.Trunc(srid) || srid < 0 || srid > math.MaxUint32 {
+		return nil, true, fmt.Errorf("%w: %v is not an unsigned 32-bit integer", ErrInvalidPointSRID, srid)
+	}
+
+	if z != nil {
+		return dbtype.Point3D{X: x, Y: y, Z: *z, SpatialRefId: uint32(srid)}, true, nil
+	}
+
+	return dbtype.Point2D{X: x, Y: y, SpatialRefId: uint32(srid)}, true, nil
+}
+
+// number returns the value as float64 if it's a number decoded from JSON.
+func number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestPoints_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{
+			name:  "2d",
+			value: dbtype.Point2D{X: 30.52, Y: 50.45, SpatialRefId: 4326},
+			want:  `{"value":{"srid":4326,"x":30.52,"y":50.45}}`,
+		},
+		{
+			name:  "3d",
+			value: dbtype.Point3D{X: 1, Y: 2, Z: 3, SpatialRefId: 9157},
+			want:  `{"value":{"srid":9157,"x":1,"y":2,"z":3}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			payload, err := json.Marshal(EncodeValues(map[string]any{"value": tt.value}))
+			if err != nil {
+				t.Fatalf("marshal json error = %v", err)
+			}
+
+			if string(payload) != tt.want {
+				t.Fatalf("EncodeValues() = %s, want %s", payload, tt.want)
+			}
+
+			var unmarshaled map[string]any
+			if err = json.Unmarshal(payload, &unmarshaled); err != nil {
+				t.Fatalf("unmarshal json error = %v", err)
+			}
+
+			decoded, err := DecodeValues(unmarshaled)
+			if err != nil {
+				t.Fatalf("DecodeValues() error = %v", err)
+			}
+
+			want := map[string]any{"value": tt.value}
+			if !reflect.DeepEqual(decoded, want) {
+				t.Errorf("DecodeValues() = %v, want %v", decoded, want)
+			}
+		})
+	}
+}
+
+func TestDecodeValues_points(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   map[string]any
+		want    any
+		wantErr error
+	}{
+		{
+			name:  "success_not_a_point",
+			value: map[string]any{"srid": "4326", "x": 1.0, "y": 2.0},
+			want:  map[string]any{"srid": "4326", "x": 1.0, "y": 2.0},
+		},
+		{
+			name:  "success_extra_field",
+			value: map[string]any{"srid": 4326.0, "x": 1.0, "y": 2.0, "name": "home"},
+			want:  map[string]any{"srid": 4326.0, "x": 1.0, "y": 2.0, "name": "home"},
+		},
+		{
+			name:    "fail_fractional_srid",
+			value:   map[string]any{"srid": 4326.5, "x": 1.0, "y": 2.0},
+			wantErr: ErrInvalidPointSRID,
+		},
+		{
+			name:    "fail_negative_srid",
+			value:   map[string]any{"srid": int64(-1), "x": 1.0, "y": 2.0, "z": 3.0},
+			wantErr: ErrInvalidPointSRID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := DecodeValues(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
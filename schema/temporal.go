@@ -0,0 +1,218 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// ErrInvalidTemporalValue occurs when a value of a tagged temporal cannot be parsed.
+var ErrInvalidTemporalValue = errors.New("invalid temporal value")
+
+// TemporalType defines a Neo4j temporal type a tagged temporal value holds.
+type TemporalType string
+
+// The available temporal types are listed below.
+const (
+	TemporalTypeDate          TemporalType = "date"
+	TemporalTypeTime          TemporalType = "time"
+	TemporalTypeLocalTime     TemporalType = "localTime"
+	TemporalTypeDateTime      TemporalType = "dateTime"
+	TemporalTypeLocalDateTime TemporalType = "localDateTime"
+	TemporalTypeDuration      TemporalType = "duration"
+)
+
+// Layouts of the temporal values, the Duration is represented in the ISO-8601 form instead.
+const (
+	dateLayout          = "2006-01-02"
+	timeLayout          = "15:04:05.999999999Z07:00"
+	localTimeLayout     = "15:04:05.999999999"
+	dateTimeLayout      = time.RFC3339Nano
+	localDateTimeLayout = "2006-01-02T15:04:05.999999999"
+)
+
+// durationNanosDigits is a number of digits the fraction of the Duration seconds is formatted with.
+const durationNanosDigits = 9
+
+// Names of the tagged temporal fields.
+const (
+	temporalTypeField  = "neo4jType"
+	temporalValueField = "value"
+)
+
+// Temporal defines a model of a Neo4j temporal value tagged with its type,
+// so the value can be converted back into the same type instead of a plain string.
+type Temporal struct {
+	Type  TemporalType `json:"neo4jType"`
+	Value string       `json:"value"`
+}
+
+// encodeTemporal returns the tagged [Temporal] of the value if it's a temporal one.
+func encodeTemporal(value any) (Temporal, bool) {
+	switch v := value.(type) {
+	case dbtype.Date:
+		return Temporal{Type: TemporalTypeDate, Value: time.Time(v).Format(dateLayout)}, true
+
+	case dbtype.Time:
+		return Temporal{Type: TemporalTypeTime, Value: time.Time(v).Format(timeLayout)}, true
+
+	case dbtype.LocalTime:
+		return Temporal{Type: TemporalTypeLocalTime, Value: time.Time(v).Format(localTimeLayout)}, true
+
+	case time.Time:
+		return Temporal{Type: TemporalTypeDateTime, Value: v.Format(dateTimeLayout)}, true
+
+	case dbtype.LocalDateTime:
+		return Temporal{Type: TemporalTypeLocalDateTime, Value: time.Time(v).Format(localDateTimeLayout)}, true
+
+	case dbtype.Duration:
+		return Temporal{Type: TemporalTypeDuration, Value: v.String()}, true
+
+	default:
+		return Temporal{}, false
+	}
+}
+
+// taggedTemporal returns the [Temporal] if the map consists of its fields only and the type is a known one.
+func taggedTemporal(value map[string]any) (Temporal, bool) {
+	if len(value) != 2 {
+		return Temporal{}, false
+	}
+
+	temporalType, typeOK := value[temporalTypeField].(string)
+	temporalValue, valueOK := value[temporalValueField].(string)
+	if !typeOK || !valueOK {
+		return Temporal{}, false
+	}
+
+	switch TemporalType(temporalType) {
+	case TemporalTypeDate, TemporalTypeTime, TemporalTypeLocalTime,
+		TemporalTypeDateTime, TemporalTypeLocalDateTime, TemporalTypeDuration:
+		return Temporal{Type: TemporalType(temporalType), Value: temporalValue}, true
+
+	default:
+		return Temporal{}, false
+	}
+}
+
+// decode parses the value of the temporal into the driver type of it.
+func (t Temporal) decode() (any, error) {
+	var layout string
+	switch t.Type {
+	case TemporalTypeDate:
+		layout = dateLayout
+	case TemporalTypeTime:
+		layout = timeLayout
+	case TemporalTypeLocalTime:
+		layout = localTimeLayout
+	case TemporalTypeDateTime:
+		layout = dateTimeLayout
+	case TemporalTypeLocalDateTime:
+		layout = localDateTimeLayout
+	case TemporalTypeDuration:
+		return parseDuration(t.Value)
+	}
+
+	parsed, err := time.Parse(layout, t.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: parse %s %q: %w", ErrInvalidTemporalValue, t.Type, t.Value, err)
+	}
+
+	switch t.Type {
+	case TemporalTypeDate:
+		return dbtype.Date(parsed), nil
+	case TemporalTypeTime:
+		return dbtype.Time(parsed), nil
+	case TemporalTypeLocalTime:
+		return dbtype.LocalTime(parsed), nil
+	case TemporalTypeLocalDateTime:
+		return dbtype.LocalDateTime(parsed), nil
+	default:
+		return parsed, nil
+	}
+}
+
+// parseDuration parses a duration in the form of the [dbtype.Duration.String], which is PnMnDTn.nnnnnnnnnS.
+//
+// The form keeps the sign of the seconds part along with the fraction,
+// so negative seconds with nanoseconds are shifted back the same way they were shifted by the String.
+func parseDuration(value string) (dbtype.Duration, error) {
+	invalidErr := fmt.Errorf("%w: parse duration %q", ErrInvalidTemporalValue, value)
+
+	rest, ok := strings.CutPrefix(value, "P")
+	if !ok {
+		return dbtype.Duration{}, invalidErr
+	}
+
+	months, rest, ok := strings.Cut(rest, "M")
+	if !ok {
+		return dbtype.Duration{}, invalidErr
+	}
+
+	days, rest, ok := strings.Cut(rest, "DT")
+	if !ok {
+		return dbtype.Duration{}, invalidErr
+	}
+
+	seconds, ok := strings.CutSuffix(rest, "S")
+	if !ok {
+		return dbtype.Duration{}, invalidErr
+	}
+
+	var (
+		duration dbtype.Duration
+		err      error
+	)
+
+	if duration.Months, err = strconv.ParseInt(months, 10, 64); err != nil {
+		return dbtype.Duration{}, invalidErr
+	}
+
+	if duration.Days, err = strconv.ParseInt(days, 10, 64); err != nil {
+		return dbtype.Duration{}, invalidErr
+	}
+
+	negative := strings.HasPrefix(seconds, "-")
+
+	seconds, nanos, hasNanos := strings.Cut(seconds, ".")
+	if duration.Seconds, err = strconv.ParseInt(seconds, 10, 64); err != nil {
+		return dbtype.Duration{}, invalidErr
+	}
+
+	if !hasNanos {
+		return duration, nil
+	}
+
+	if len(nanos) != durationNanosDigits {
+		return dbtype.Duration{}, invalidErr
+	}
+
+	if duration.Nanos, err = strconv.Atoi(nanos); err != nil || duration.Nanos < 0 {
+		return dbtype.Duration{}, invalidErr
+	}
+
+	if negative && duration.Nanos > 0 {
+		duration.Seconds--
+		duration.Nanos = int(time.Second) - duration.Nanos
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,178 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestTemporals_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	zone := time.FixedZone("", 2*60*60)
+
+	tests := []struct {
+		name  string
+		value any
+		want  Temporal
+	}{
+		{
+			name:  "date",
+			value: dbtype.Date(time.Date(2023, 5, 17, 0, 0, 0, 0, time.UTC)),
+			want:  Temporal{Type: TemporalTypeDate, Value: "2023-05-17"},
+		},
+		{
+			name:  "time",
+			value: dbtype.Time(time.Date(0, 1, 1, 10, 30, 0, 500, zone)),
+			want:  Temporal{Type: TemporalTypeTime, Value: "10:30:00.0000005+02:00"},
+		},
+		{
+			name:  "localTime",
+			value: dbtype.LocalTime(time.Date(0, 1, 1, 10, 30, 15, 0, time.UTC)),
+			want:  Temporal{Type: TemporalTypeLocalTime, Value: "10:30:15"},
+		},
+		{
+			name:  "dateTime",
+			value: time.Date(2023, 5, 17, 10, 30, 15, 123000000, zone),
+			want:  Temporal{Type: TemporalTypeDateTime, Value: "2023-05-17T10:30:15.123+02:00"},
+		},
+		{
+			name:  "localDateTime",
+			value: dbtype.LocalDateTime(time.Date(2023, 5, 17, 10, 30, 15, 0, time.UTC)),
+			want:  Temporal{Type: TemporalTypeLocalDateTime, Value: "2023-05-17T10:30:15"},
+		},
+		{
+			name:  "duration",
+			value: dbtype.Duration{Months: 14, Days: 3, Seconds: 3600, Nanos: 5},
+			want:  Temporal{Type: TemporalTypeDuration, Value: "P14M3DT3600.000000005S"},
+		},
+		{
+			name:  "duration_negative_seconds",
+			value: dbtype.Duration{Months: -1, Seconds: -4, Nanos: 250000000},
+			want:  Temporal{Type: TemporalTypeDuration, Value: "P-1M0DT-3.750000000S"},
+		},
+		{
+			name:  "duration_negative_fraction",
+			value: dbtype.Duration{Seconds: -1, Nanos: 5},
+			want:  Temporal{Type: TemporalTypeDuration, Value: "P0M0DT-0.999999995S"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded := EncodeValues(map[string]any{"value": tt.value, "list": []any{tt.value}})
+			if !reflect.DeepEqual(encoded["value"], tt.want) {
+				t.Fatalf("EncodeValues() = %v, want %v", encoded["value"], tt.want)
+			}
+
+			payload, err := json.Marshal(encoded)
+			if err != nil {
+				t.Fatalf("marshal json error = %v", err)
+			}
+
+			var unmarshaled map[string]any
+			if err = json.Unmarshal(payload, &unmarshaled); err != nil {
+				t.Fatalf("unmarshal json error = %v", err)
+			}
+
+			decoded, err := DecodeValues(unmarshaled)
+			if err != nil {
+				t.Fatalf("DecodeValues() error = %v", err)
+			}
+
+			// time values are compared by their encoded form, as parsed locations differ from the original ones
+			reencoded := EncodeValues(decoded.(map[string]any))
+			if !reflect.DeepEqual(reencoded, encoded) {
+				t.Errorf("DecodeValues() round trip = %v, want %v", reencoded, encoded)
+			}
+		})
+	}
+}
+
+func TestEncodeValues_node(t *testing.T) {
+	t.Parallel()
+
+	date := dbtype.Date(time.Date(2023, 5, 17, 0, 0, 0, 0, time.UTC))
+
+	got := EncodeValues(map[string]any{
+		"sourceNode": Node{Labels: []string{"Person"}, Key: map[string]any{"born": date}},
+	})
+
+	want := map[string]any{
+		"sourceNode": Node{
+			Labels: []string{"Person"},
+			Key:    map[string]any{"born": Temporal{Type: TemporalTypeDate, Value: "2023-05-17"}},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeValues() = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeValues_temporals(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   any
+		want    any
+		wantErr error
+	}{
+		{
+			name:  "success_unknown_type",
+			value: map[string]any{"neo4jType": "point", "value": "1,2"},
+			want:  map[string]any{"neo4jType": "point", "value": "1,2"},
+		},
+		{
+			name:  "success_extra_field",
+			value: map[string]any{"neo4jType": "date", "value": "2023-05-17", "note": "x"},
+			want:  map[string]any{"neo4jType": "date", "value": "2023-05-17", "note": "x"},
+		},
+		{
+			name:    "fail_invalid_date",
+			value:   map[string]any{"neo4jType": "date", "value": "17.05.2023"},
+			wantErr: ErrInvalidTemporalValue,
+		},
+		{
+			name:    "fail_invalid_duration",
+			value:   []any{map[string]any{"neo4jType": "duration", "value": "PT1H"}},
+			wantErr: ErrInvalidTemporalValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := DecodeValues(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
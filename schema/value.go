@@ -0,0 +1,103 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+// EncodeValues returns a copy of the properties with values that cannot be serialized as they are
+// replaced by their models: temporal values by the tagged [Temporal] and spatial ones by the [Point].
+// Lists, maps and [Node] models are processed recursively.
+func EncodeValues(props map[string]any) map[string]any {
+	if props == nil {
+		return nil
+	}
+
+	encoded := make(map[string]any, len(props))
+	for name, value := range props {
+		encoded[name] = encodeValue(value)
+	}
+
+	return encoded
+}
+
+// encodeValue returns the model of the value if it has one, or the value itself otherwise.
+func encodeValue(value any) any {
+	if temporal, ok := encodeTemporal(value); ok {
+		return temporal
+	}
+
+	if point, ok := encodePoint(value); ok {
+		return point
+	}
+
+	switch v := value.(type) {
+	case []any:
+		encoded := make([]any, len(v))
+		for i, item := range v {
+			encoded[i] = encodeValue(item)
+		}
+
+		return encoded
+
+	case map[string]any:
+		return EncodeValues(v)
+
+	case Node:
+		return Node{Labels: v.Labels, Key: EncodeValues(v.Key), Properties: EncodeValues(v.Properties)}
+
+	default:
+		return value
+	}
+}
+
+// DecodeValues recursively replaces the [Temporal] and [Point] models, decoded from JSON as maps,
+// with the driver types, so they're stored as temporal and spatial values instead of maps.
+// Maps that don't match any model exactly are left as they are.
+func DecodeValues(value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		if temporal, ok := taggedTemporal(v); ok {
+			return temporal.decode()
+		}
+
+		if point, ok, err := decodePoint(v); ok {
+			return point, err
+		}
+
+		for name, item := range v {
+			decodedItem, err := DecodeValues(item)
+			if err != nil {
+				return nil, err
+			}
+
+			v[name] = decodedItem
+		}
+
+		return v, nil
+
+	case []any:
+		for i, item := range v {
+			decodedItem, err := DecodeValues(item)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = decodedItem
+		}
+
+		return v, nil
+
+	default:
+		return value, nil
+	}
+}
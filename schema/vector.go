@@ -0,0 +1,95 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidVector occurs when a value of a vector property is not a list of numbers
+// or doesn't have the expected number of dimensions.
+var ErrInvalidVector = errors.New("invalid vector")
+
+// Vectors defines the properties which values are vectors, e.g. node embeddings.
+type Vectors struct {
+	// Properties are names of the vector properties.
+	Properties []string
+	// Dimensions is the number of dimensions each vector must have, zero disables the check.
+	Dimensions int
+}
+
+// Convert replaces the values of the vector properties with lists of floats, so they're serialized
+// as numeric arrays and stored as float lists, whether they come as lists of any numbers
+// or as JSON strings of them. Missing and null vector properties are skipped.
+func (v Vectors) Convert(props map[string]any) error {
+	for _, name := range v.Properties {
+		value, ok := props[name]
+		if !ok || value == nil {
+			continue
+		}
+
+		vector, err := v.vector(value)
+		if err != nil {
+			return fmt.Errorf("%w %q: %w", ErrInvalidVector, name, err)
+		}
+
+		props[name] = vector
+	}
+
+	return nil
+}
+
+// vector converts the value into a list of floats and checks its dimensions.
+func (v Vectors) vector(value any) ([]float64, error) {
+	var vector []float64
+
+	switch val := value.(type) {
+	case []float64:
+		vector = val
+
+	case []int64:
+		vector = make([]float64, len(val))
+		for i, item := range val {
+			vector[i] = float64(item)
+		}
+
+	case []any:
+		vector = make([]float64, len(val))
+		for i, item := range val {
+			number, ok := number(item)
+			if !ok {
+				return nil, fmt.Errorf("item %d is a %T, not a number", i, item)
+			}
+
+			vector[i] = number
+		}
+
+	case string:
+		if err := json.Unmarshal([]byte(val), &vector); err != nil {
+			return nil, fmt.Errorf("the string is not a JSON list of numbers: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("the value is a %T, not a list of numbers", value)
+	}
+
+	if v.Dimensions > 0 && len(vector) != v.Dimensions {
+		return nil, fmt.Errorf("it has %d dimensions, expected %d", len(vector), v.Dimensions)
+	}
+
+	return vector, nil
+}
@@ -0,0 +1,134 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVectors_Convert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		props   map[string]any
+		want    map[string]any
+		wantErr error
+	}{
+		{
+			name:  "list",
+			props: map[string]any{"embedding": []any{0.5, int64(1), -2.25}, "name": "Alice"},
+			want:  map[string]any{"embedding": []float64{0.5, 1, -2.25}, "name": "Alice"},
+		},
+		{
+			name:  "typed_list",
+			props: map[string]any{"embedding": []int64{1, 2, 3}},
+			want:  map[string]any{"embedding": []float64{1, 2, 3}},
+		},
+		{
+			name:  "json_string",
+			props: map[string]any{"embedding": "[0.5, 1, -2.25]"},
+			want:  map[string]any{"embedding": []float64{0.5, 1, -2.25}},
+		},
+		{
+			name:  "missing_and_null",
+			props: map[string]any{"name": "Alice", "features": nil},
+			want:  map[string]any{"name": "Alice", "features": nil},
+		},
+		{
+			name:    "fail_dimensions",
+			props:   map[string]any{"embedding": []any{0.5, 1.5}},
+			wantErr: ErrInvalidVector,
+		},
+		{
+			name:    "fail_item",
+			props:   map[string]any{"embedding": []any{0.5, "1", 2.5}},
+			wantErr: ErrInvalidVector,
+		},
+		{
+			name:    "fail_string",
+			props:   map[string]any{"embedding": "0.5, 1, -2.25"},
+			wantErr: ErrInvalidVector,
+		},
+		{
+			name:    "fail_scalar",
+			props:   map[string]any{"embedding": 0.5},
+			wantErr: ErrInvalidVector,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vectors := Vectors{Properties: []string{"embedding", "features"}, Dimensions: 3}
+
+			err := vectors.Convert(tt.props)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(tt.props, tt.want) {
+				t.Errorf("Convert() = %v, want %v", tt.props, tt.want)
+			}
+		})
+	}
+}
+
+func TestVectors_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	vectors := Vectors{Properties: []string{"embedding"}, Dimensions: 384}
+
+	// the driver returns float lists as lists of any, integral floats are serialized without fractions
+	embedding := make([]any, 384)
+	want := make([]float64, 384)
+	for i := range embedding {
+		want[i] = float64(i%7) / 4
+
+		embedding[i] = want[i]
+	}
+
+	props := map[string]any{"embedding": embedding}
+	if err := vectors.Convert(props); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	payload, err := json.Marshal(EncodeValues(props))
+	if err != nil {
+		t.Fatalf("marshal json error = %v", err)
+	}
+
+	if !strings.HasPrefix(string(payload), `{"embedding":[0,0.25,0.5,`) {
+		t.Fatalf("the vector is not serialized as a numeric array: %.40s", payload)
+	}
+
+	var unmarshaled map[string]any
+	if err = json.Unmarshal(payload, &unmarshaled); err != nil {
+		t.Fatalf("unmarshal json error = %v", err)
+	}
+
+	if err := vectors.Convert(unmarshaled); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(unmarshaled["embedding"], want) {
+		t.Errorf("Convert() = %v, want %v", unmarshaled["embedding"], want)
+	}
+}
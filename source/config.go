@@ -14,17 +14,266 @@
 
 package source
 
-import "github.com/conduitio-labs/conduit-connector-neo4j/config"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/source/iterator"
+)
 
 const (
 	// ConfigKeyOrderingProperty is a config name for a orderingProperty field.
 	ConfigKeyOrderingProperty = "orderingProperty"
 	// ConfigKeyKeyProperties is a config name for a keyProperties field.
 	ConfigKeyKeyProperties = "keyProperties"
+	// ConfigKeyExcludeOrderingPropertyFromKey is a config name for an excludeOrderingPropertyFromKey field.
+	ConfigKeyExcludeOrderingPropertyFromKey = "excludeOrderingPropertyFromKey"
 	// ConfigKeyBatchSize is a config name for a batch size.
 	ConfigKeyBatchSize = "batchSize"
 	// ConfigKeySnapshot is a config name for a snapshot field.
 	ConfigKeySnapshot = "snapshot"
+	// ConfigKeyRelationshipFieldCollision is a config name for a relationshipFieldCollision field.
+	ConfigKeyRelationshipFieldCollision = "relationshipFieldCollision"
+	// ConfigKeySkipOrderingCheck is a config name for a skipOrderingCheck field.
+	ConfigKeySkipOrderingCheck = "skipOrderingCheck"
+	// ConfigKeyIncludeRelationshipCounts is a config name for an includeRelationshipCounts field.
+	ConfigKeyIncludeRelationshipCounts = "includeRelationshipCounts"
+	// ConfigKeyRelationshipCountsDepth is a config name for a relationshipCountsDepth field.
+	ConfigKeyRelationshipCountsDepth = "relationshipCountsDepth"
+	// ConfigKeySoftDeleteField is a config name for a softDeleteField field.
+	ConfigKeySoftDeleteField = "softDeleteField"
+	// ConfigKeySoftDeleteValue is a config name for a softDeleteValue field.
+	ConfigKeySoftDeleteValue = "softDeleteValue"
+	// ConfigKeyIncludeDeletedState is a config name for an includeDeletedState field.
+	ConfigKeyIncludeDeletedState = "includeDeletedState"
+	// ConfigKeyShardCount is a config name for a shardCount field.
+	ConfigKeyShardCount = "shardCount"
+	// ConfigKeyShardIndex is a config name for a shardIndex field.
+	ConfigKeyShardIndex = "shardIndex"
+	// ConfigKeyChangedWithin is a config name for a changedWithin field.
+	ConfigKeyChangedWithin = "changedWithin"
+	// ConfigKeySeedNodeMatch is a config name for a seedNodeMatch field.
+	ConfigKeySeedNodeMatch = "seedNodeMatch"
+	// ConfigKeyMaxHops is a config name for a maxHops field.
+	ConfigKeyMaxHops = "maxHops"
+	// ConfigKeyPayloadFormat is a config name for a payloadFormat field.
+	ConfigKeyPayloadFormat = "payloadFormat"
+	// ConfigKeyHistoryProperty is a config name for a historyProperty field.
+	ConfigKeyHistoryProperty = "historyProperty"
+	// ConfigKeyHistoryDecodeJSON is a config name for a historyDecodeJSON field.
+	ConfigKeyHistoryDecodeJSON = "historyDecodeJSON"
+	// ConfigKeyEmitEndpointsAsRecords is a config name for an emitEndpointsAsRecords field.
+	ConfigKeyEmitEndpointsAsRecords = "emitEndpointsAsRecords"
+	// ConfigKeyAlignPositionsToBatches is a config name for an alignPositionsToBatches field.
+	ConfigKeyAlignPositionsToBatches = "alignPositionsToBatches"
+	// ConfigKeyCDCEnabled is a config name for a cdcEnabled field.
+	ConfigKeyCDCEnabled = "cdcEnabled"
+	// ConfigKeySnapshotByElementID is a config name for a snapshotByElementId field.
+	ConfigKeySnapshotByElementID = "snapshotByElementId"
+	// ConfigKeySnapshotSort is a config name for a snapshotSort field.
+	ConfigKeySnapshotSort = "snapshotSort"
+	// ConfigKeyDeterministicOrder is a config name for a deterministicOrder field.
+	ConfigKeyDeterministicOrder = "deterministicOrder"
+	// ConfigKeyPositionMismatch is a config name for a positionMismatch field.
+	ConfigKeyPositionMismatch = "positionMismatch"
+	// ConfigKeyOrderingPropertyType is a config name for an orderingPropertyType field.
+	ConfigKeyOrderingPropertyType = "orderingPropertyType"
+	// ConfigKeyKeyByEndpoints is a config name for a keyByEndpoints field.
+	ConfigKeyKeyByEndpoints = "keyByEndpoints"
+	// ConfigKeyExistingEndpointsOnly is a config name for an existingEndpointsOnly field.
+	ConfigKeyExistingEndpointsOnly = "existingEndpointsOnly"
+	// ConfigKeyOrderingDirection is a config name for an orderingDirection field.
+	ConfigKeyOrderingDirection = "orderingDirection"
+	// ConfigKeyFilter is a config name for a filter field.
+	ConfigKeyFilter = "filter"
+	// ConfigKeyFilterParams is a config name for a filterParams field.
+	ConfigKeyFilterParams = "filterParams"
+	// ConfigKeyDegreeFilter is a config name for a degreeFilter field.
+	ConfigKeyDegreeFilter = "degreeFilter"
+	// ConfigKeySubgraphRelationshipTypes is a config name for a subgraphRelationshipTypes field.
+	ConfigKeySubgraphRelationshipTypes = "subgraphRelationshipTypes"
+	// ConfigKeyQuery is a config name for a query field.
+	ConfigKeyQuery = "query"
+	// ConfigKeyIndexProperty is a config name for an indexProperty field.
+	ConfigKeyIndexProperty = "indexProperty"
+	// ConfigKeyDetectDeletes is a config name for a detectDeletes field.
+	ConfigKeyDetectDeletes = "detectDeletes"
+	// ConfigKeyReconcileInterval is a config name for a reconcileInterval field.
+	ConfigKeyReconcileInterval = "reconcileInterval"
+	// ConfigKeyReconcileMaxKeys is a config name for a reconcileMaxKeys field.
+	ConfigKeyReconcileMaxKeys = "reconcileMaxKeys"
+	// ConfigKeyLabelMatch is a config name for a labelMatch field.
+	ConfigKeyLabelMatch = "labelMatch"
+	// ConfigKeyEmitOrder is a config name for an emitOrder field.
+	ConfigKeyEmitOrder = "emitOrder"
+	// ConfigKeySnapshotOperation is a config name for a snapshotOperation field.
+	ConfigKeySnapshotOperation = "snapshotOperation"
+	// ConfigKeyEndpointLabelsSource is a config name for an endpointLabels.source field.
+	ConfigKeyEndpointLabelsSource = "endpointLabels.source"
+	// ConfigKeyEndpointLabelsTarget is a config name for an endpointLabels.target field.
+	ConfigKeyEndpointLabelsTarget = "endpointLabels.target"
+	// ConfigKeyProjections is a config name for a projections field.
+	ConfigKeyProjections = "projections"
+	// ConfigKeyResumeGrace is a config name for a resumeGrace field.
+	ConfigKeyResumeGrace = "resumeGrace"
+	// ConfigKeyIncludeElementID is a config name for an includeElementId field.
+	ConfigKeyIncludeElementID = "includeElementId"
+	// ConfigKeyElementIDField is a config name for an elementIdField field.
+	ConfigKeyElementIDField = "elementIdField"
+	// ConfigKeyIncludeMetadata is a config name for an includeMetadata field.
+	ConfigKeyIncludeMetadata = "includeMetadata"
+	// ConfigKeyRelationshipDirection is a config name for a relationshipDirection field.
+	ConfigKeyRelationshipDirection = "relationshipDirection"
+	// ConfigKeyMaxBatchSize is a config name for a maxBatchSize field.
+	ConfigKeyMaxBatchSize = "maxBatchSize"
+	// ConfigKeySnapshotOnly is a config name for a snapshotOnly field.
+	ConfigKeySnapshotOnly = "snapshotOnly"
+	// ConfigKeyCausalConsistency is a config name for a causalConsistency field.
+	ConfigKeyCausalConsistency = "causalConsistency"
+	// ConfigKeyReadRateLimit is a config name for a readRateLimit field.
+	ConfigKeyReadRateLimit = "readRateLimit"
+	// ConfigKeySnapshotCompleteMarker is a config name for a snapshotCompleteMarker field.
+	ConfigKeySnapshotCompleteMarker = "snapshotCompleteMarker"
+	// ConfigKeyEntities is a config name for an entities field.
+	ConfigKeyEntities = "entities"
+
+	// DefaultMaxBatchSize is the default upper bound of the batchSize, it's the default value of the maxBatchSize.
+	DefaultMaxBatchSize = 100000
+
+	// defaultEntityName is the name of the entity of the top-level config values in positions of multiple entities,
+	// it's reserved, so it cannot be used as a name of the entities.
+	defaultEntityName = "default"
+
+	// maxHopsLimit is the maximum allowed value of the maxHops,
+	// as the number of traversed paths grows exponentially with the number of hops.
+	maxHopsLimit = 5
+)
+
+var (
+	// errInvalidShardIndex occurs when the shardIndex is out of the [0, shardCount) range.
+	errInvalidShardIndex = errors.New("shardIndex must be less than shardCount")
+	// errBatchSizeTooLarge occurs when the batchSize exceeds the maxBatchSize.
+	errBatchSizeTooLarge = errors.New("batchSize exceeds maxBatchSize")
+	// errInvalidMaxHops occurs when the seedNodeMatch is set and the maxHops is out of the [1, maxHopsLimit] range.
+	errInvalidMaxHops = errors.New("maxHops is out of range")
+	// errSeedNodeMatchRelationship occurs when the seedNodeMatch is set and the entityType is relationship.
+	errSeedNodeMatchRelationship = errors.New("seedNodeMatch is supported only if the entityType is node")
+	// errChangedWithinByElementID occurs when both the changedWithin and the snapshotByElementId are set,
+	// as the window is applied to the orderingProperty.
+	errChangedWithinByElementID = errors.New("changedWithin cannot be used with snapshotByElementId")
+	// errChangedWithinDescending occurs when the changedWithin is set and the orderingDirection is desc,
+	// as the window expects the ordering property to grow with time.
+	errChangedWithinDescending = errors.New("changedWithin cannot be used with the desc orderingDirection")
+	// errSubgraphEntityType occurs when the subgraphRelationshipTypes are set and the entityType is not node.
+	errSubgraphEntityType = errors.New("subgraphRelationshipTypes is supported only if the entityType is node")
+	// errSubgraphNoSnapshot occurs when the subgraphRelationshipTypes are set and the snapshot is disabled.
+	errSubgraphNoSnapshot = errors.New("subgraphRelationshipTypes requires the snapshot")
+	// errSnapshotCompleteMarkerNoSnapshot occurs when the snapshotCompleteMarker is set and the snapshot is disabled.
+	errSnapshotCompleteMarkerNoSnapshot = errors.New("snapshotCompleteMarker requires the snapshot")
+	// errSnapshotOnlyConflict occurs when the snapshotOnly is set along with an option
+	// that either disables the snapshot or captures changes after it.
+	errSnapshotOnlyConflict = errors.New(
+		"snapshotOnly requires the snapshot and cannot be used with cdcEnabled or detectDeletes",
+	)
+	// errSubgraphNarrowed occurs when the subgraphRelationshipTypes are set along with an option
+	// that narrows the snapshot of nodes, as relationships to the skipped nodes would be dangling.
+	errSubgraphNarrowed = errors.New(
+		"subgraphRelationshipTypes cannot be used with shardCount, seedNodeMatch, filter, degreeFilter, " +
+			"softDeleteField or changedWithin",
+	)
+	// errNoKeyProperties occurs when the orderingProperty is excluded from the key and the keyProperties is empty.
+	errNoKeyProperties = errors.New("excludeOrderingPropertyFromKey requires keyProperties")
+	// errOrderingPropertyInKey occurs when the orderingProperty is excluded from the key
+	// and the keyProperties contains it.
+	errOrderingPropertyInKey = errors.New("keyProperties contains the orderingProperty excluded from the key")
+	// errIndexPropertyConflict occurs when the indexProperty is set along with an option
+	// that doesn't page elements by a property of the entity labels.
+	errIndexPropertyConflict = errors.New(
+		"indexProperty cannot be used with query, seedNodeMatch, snapshotByElementId or subgraphRelationshipTypes",
+	)
+	// errQueryConflict occurs when the query is set along with an option that relies on the entityLabels.
+	errQueryConflict = errors.New(
+		"query cannot be used with seedNodeMatch, changedWithin, cdcEnabled or subgraphRelationshipTypes",
+	)
+	// errSnapshotSortConflict occurs when the snapshotSort is set along with an option
+	// that paginates the snapshot by the orderingProperty or by element ids.
+	errSnapshotSortConflict = errors.New(
+		"snapshotSort cannot be used with snapshotByElementId, indexProperty or changedWithin",
+	)
+	// errDeterministicOrderConflict occurs when the deterministicOrder is set along with an option
+	// that defines the order of the snapshot on its own.
+	errDeterministicOrderConflict = errors.New(
+		"deterministicOrder cannot be used with snapshotSort, snapshotByElementId, indexProperty or changedWithin",
+	)
+	// errDeterministicOrderNoKeyProperties occurs when the deterministicOrder is set but the key has no properties
+	// to sort the snapshot by, i.e. the orderingProperty is excluded from the key of relationships keyed by endpoints.
+	errDeterministicOrderNoKeyProperties = errors.New("deterministicOrder requires keyProperties")
+	// errDetectDeletesConflict occurs when the detectDeletes is set along with the cdcEnabled,
+	// which captures deletes on its own.
+	errDetectDeletesConflict = errors.New("detectDeletes cannot be used with cdcEnabled")
+	// errLabelMatchAnyConflict occurs when the labelMatch is any along with an option
+	// that relies on all captured elements having the same labels.
+	errLabelMatchAnyConflict = errors.New(
+		"the any labelMatch cannot be used with indexProperty or subgraphRelationshipTypes",
+	)
+	// errEmitOrderKeyEndpoints occurs when the emitOrder is key and the emitEndpointsAsRecords is enabled,
+	// as the endpoint records must precede their relationship records.
+	errEmitOrderKeyEndpoints = errors.New("the key emitOrder cannot be used with emitEndpointsAsRecords")
+	// errEndpointLabelsConflict occurs when the endpointLabels are set but the captured elements
+	// either aren't relationships or are matched by the custom query.
+	errEndpointLabelsConflict = errors.New(
+		"endpointLabels is supported only if the entityType is relationship and the query is empty",
+	)
+	// errEndpointLabelsUnknownType occurs when the endpointLabels are set for a type that isn't captured.
+	errEndpointLabelsUnknownType = errors.New("endpointLabels contains a relationship type that is not in entityLabels")
+	// errEndpointLabelsEmptyLabel occurs when the endpointLabels of a type contain an empty label.
+	errEndpointLabelsEmptyLabel = errors.New("endpointLabels contains an empty label")
+	// errDegreeFilterRelationship occurs when the degreeFilter narrows the captured nodes
+	// but the entityType is relationship.
+	errDegreeFilterRelationship = errors.New("degreeFilter is supported only if the entityType is node")
+	// errProjectionsCDC occurs when the projections are set along with the cdcEnabled,
+	// as CDC records are built of change events that the projections cannot be computed for.
+	errProjectionsCDC = errors.New("projections cannot be used with cdcEnabled")
+	// errElementIDFieldWithoutInclude occurs when the elementIdField is set but the includeElementId is disabled.
+	errElementIDFieldWithoutInclude = errors.New("elementIdField requires includeElementId")
+	// errRelationshipDirectionConflict occurs when the relationshipDirection isn't outgoing
+	// but the captured elements either aren't relationships, are matched by the custom query
+	// or are captured by the CDC, which selects relationships by their start and end nodes.
+	errRelationshipDirectionConflict = errors.New(
+		"relationshipDirection is supported only if the entityType is relationship, the query is empty " +
+			"and cdcEnabled is false",
+	)
+	// errResumeGraceConflict occurs when the resumeGrace is set along with an option
+	// that either doesn't poll or relies on positions following the order of the emitted records.
+	errResumeGraceConflict = errors.New(
+		"resumeGrace cannot be used with cdcEnabled, the desc orderingDirection, alignPositionsToBatches, " +
+			"the key emitOrder or emitEndpointsAsRecords",
+	)
+	// errEntitiesConflict occurs when the entities are set along with an option
+	// that relies on the entityLabels of a single entity.
+	errEntitiesConflict = errors.New(
+		"entities cannot be used with query, indexProperty, subgraphRelationshipTypes or endpointLabels",
+	)
+	// errReservedEntityName occurs when one of the entities is named as the entity of the top-level config values.
+	errReservedEntityName = errors.New("the entity name is reserved")
+	// errEntityNoLabels occurs when one of the entities doesn't set the entityLabels,
+	// so it would capture the same elements as the top-level config values do.
+	errEntityNoLabels = errors.New("the entity requires entityLabels")
+	// errNegativeReadRateLimit occurs when the readRateLimit is negative.
+	errNegativeReadRateLimit = errors.New("readRateLimit must not be negative")
+)
+
+// PositionMismatch defines what to do when the position to resume from doesn't match the config.
+type PositionMismatch string
+
+// The available position mismatch behaviors are listed below.
+const (
+	PositionMismatchError   PositionMismatch = "error"
+	PositionMismatchRestart PositionMismatch = "restart"
 )
 
 // Config holds configurable values specific to source.
@@ -35,10 +284,583 @@ type Config struct {
 	// nodes or relationships when capturing a snapshot.
 	OrderingProperty string `json:"orderingProperty" validate:"required"`
 	// The list of property names that are used for constructing a record key.
+	// If it's empty, the orderingProperty is used, unless the excludeOrderingPropertyFromKey is enabled.
 	KeyProperties []string `json:"keyProperties"`
+	// Determines whether or not the orderingProperty is kept out of the record key.
+	// If it's true, the keyProperties must be set and must not contain the orderingProperty.
+	ExcludeOrderingPropertyFromKey bool `json:"excludeOrderingPropertyFromKey" default:"false"`
 	// The size of an element batch.
-	BatchSize int `json:"batchSize" validate:"gt=0,lt=100001" default:"1000"`
+	BatchSize int `json:"batchSize" validate:"gt=0" default:"1000"`
+	// The upper bound of the batchSize, which guards against batches too big to be held in memory.
+	// If it's 0, the batchSize is unlimited.
+	MaxBatchSize int `json:"maxBatchSize" validate:"gt=-1" default:"100000"`
 	// Determines whether or not the connector will take a snapshot
 	// of all nodes or relationships before starting polling mode.
 	Snapshot bool `json:"snapshot" default:"true"`
+	// Determines whether or not the connector will stop reading after the snapshot instead of starting polling mode,
+	// so it can run as a one-shot export. It requires the snapshot.
+	SnapshotOnly bool `json:"snapshotOnly" default:"false"`
+	// Determines whether or not the connector will emit a marker record once the snapshot is complete,
+	// so consumers know the capture of changes begins. The marker has neither a key nor a payload,
+	// and its "neo4j.snapshotComplete" metadata field is "true". It requires the snapshot.
+	SnapshotCompleteMarker bool `json:"snapshotCompleteMarker" default:"false"`
+	// Determines what to do if a relationship property collides with the reserved sourceNode or targetNode fields.
+	// If it's "prefix", the property is renamed by adding the "_" prefix, if it's "error", the connector fails.
+	RelationshipFieldCollision iterator.FieldCollision `json:"relationshipFieldCollision" validate:"inclusion=prefix|error" default:"prefix"` //nolint:lll // the tag is long
+	// Determines whether or not the connector will skip sampling the ordering property on start.
+	// The sampling detects duplicate and non-monotonic values that can lead to missed elements.
+	SkipOrderingCheck bool `json:"skipOrderingCheck" default:"false"`
+	// Determines whether or not the connector will attach counts of node relationships to record metadata.
+	// It's supported only if the entityType is node.
+	IncludeRelationshipCounts bool `json:"includeRelationshipCounts" default:"false"`
+	// The max depth of the relationship counts. If it's 1, only the number of relationships is attached,
+	// if it's 2, the number of two-relationship paths is attached as well.
+	RelationshipCountsDepth int `json:"relationshipCountsDepth" validate:"inclusion=1|2" default:"1"`
+	// The name of a property that marks an element as soft-deleted.
+	// If it's set, elements which property value is equal to the softDeleteValue are emitted as deletes.
+	SoftDeleteField string `json:"softDeleteField"`
+	// The value of the softDeleteField that marks an element as soft-deleted.
+	SoftDeleteValue string `json:"softDeleteValue" default:"true"`
+	// Determines whether or not the connector will put the last known state of a deleted element
+	// into the payload before of delete records, of soft-deleted elements, of CDC deletes and of detected deletes,
+	// the detection keeps the states of the captured elements in memory then.
+	IncludeDeletedState bool `json:"includeDeletedState" default:"false"`
+	// The number of shards the capture is split into, so multiple connector instances
+	// can capture disjoint slices of elements in parallel. Elements are assigned to shards by a hash
+	// of their element ids, which no index can serve, so each instance scans all elements on each query.
+	ShardCount int `json:"shardCount" validate:"gt=0" default:"1"`
+	// The index of the shard this connector instance captures, it must be less than the shardCount.
+	ShardIndex int `json:"shardIndex" validate:"gt=-1" default:"0"`
+	// The window of the capture, e.g. "24h". If it's set and there's no position to resume from,
+	// only elements which ordering property is later than the current time minus the window are captured.
+	// The ordering property must be a date or a date-time.
+	ChangedWithin time.Duration `json:"changedWithin"`
+	// The Cypher node pattern of a seed node, e.g. ":Person {email: 'alice@example.com'}".
+	// If it's set, only nodes reachable from the seed node within the maxHops are captured.
+	// It's supported only if the entityType is node.
+	SeedNodeMatch string `json:"seedNodeMatch"`
+	// The max number of relationships between the seed node and a captured node.
+	MaxHops int `json:"maxHops" validate:"gt=0,lt=6" default:"1"`
+	// The format which element properties are serialized into a record payload with.
+	// The Neo4j destination can consume only the "json" and "jsonPretty" formats.
+	PayloadFormat iterator.PayloadFormat `json:"payloadFormat" validate:"inclusion=json|jsonPretty|msgpack" default:"json"` //nolint:lll // the tag is long
+	// The name of a property with a change history maintained by APOC triggers.
+	// If it's set, the property is normalized into a list of history entries in the payload.
+	HistoryProperty string `json:"historyProperty"`
+	// Determines whether or not the connector will decode JSON string entries of the historyProperty.
+	HistoryDecodeJSON bool `json:"historyDecodeJSON" default:"true"`
+	// Determines whether or not the connector will emit endpoint nodes of a relationship
+	// as separate records before the relationship record. It's supported only if the entityType is relationship.
+	EmitEndpointsAsRecords bool `json:"emitEndpointsAsRecords" default:"false"`
+	// Determines whether or not the connector will align record positions to boundaries of batches,
+	// so a resumed capture restarts at the beginning of the last batch that wasn't fully emitted.
+	AlignPositionsToBatches bool `json:"alignPositionsToBatches" default:"false"`
+	// Determines whether or not the connector will capture changes using the Neo4j native CDC
+	// after the snapshot instead of polling. It requires Neo4j 5.13+ with CDC enabled.
+	CDCEnabled bool `json:"cdcEnabled" default:"false"`
+	// Determines whether or not the connector will paginate the snapshot by immutable element ids
+	// instead of the orderingProperty, so changes of the property don't make the snapshot skip or re-read elements.
+	// The polling still uses the orderingProperty.
+	SnapshotByElementID bool `json:"snapshotByElementId" default:"false"`
+	// Comma-separated properties with their directions the snapshot is sorted and paginated by
+	// instead of the orderingProperty, e.g. "priority:desc,createdAt:asc". A property without a direction
+	// is sorted in the ascending one, and elements with equal values are sorted by their element ids.
+	// Elements without any of the properties aren't captured by the snapshot. The polling still uses
+	// the orderingProperty.
+	SnapshotSort string `json:"snapshotSort"`
+	// Determines whether or not the snapshot is sorted and paginated by the keyProperties in the ascending order
+	// instead of the orderingProperty, and elements with equal keys are sorted by their element ids,
+	// so every run of the snapshot over the same data emits records in the same order.
+	// Elements without any of the keyProperties aren't captured by the snapshot.
+	DeterministicOrder bool `json:"deterministicOrder" default:"false"`
+	// Determines what to do if the position to resume from was created for a different orderingProperty
+	// or entityLabels. If it's "error", the connector fails, if it's "restart", the capture starts from scratch.
+	PositionMismatch PositionMismatch `json:"positionMismatch" validate:"inclusion=error|restart" default:"error"`
+	// The type of the orderingProperty values, which positions are coerced to before the capture resumes,
+	// as positions are stored as JSON, which loses the types, e.g. of datetimes or integers beyond 2^53.
+	// It's one of "int", "float", "string" or "datetime". If it's empty, the values are kept as they're parsed.
+	// A position that cannot be coerced is treated as a position mismatch.
+	OrderingPropertyType iterator.OrderingPropertyType `json:"orderingPropertyType" validate:"inclusion=int|float|string|datetime"` //nolint:lll // the tag is long
+	// Determines whether or not the connector will compose keys of relationship records of the keys
+	// of their source and target nodes prefixed with "source_" and "target_" instead of the keyProperties.
+	// It's supported only if the entityType is relationship.
+	KeyByEndpoints bool `json:"keyByEndpoints" default:"false"`
+	// Determines whether or not the connector will capture only relationships between nodes that existed
+	// before them, i.e. which source and target nodes have the orderingProperty less than the relationship's one.
+	// It's supported only if the entityType is relationship.
+	ExistingEndpointsOnly bool `json:"existingEndpointsOnly" default:"false"`
+	// The direction in which values of the orderingProperty grow for new elements.
+	// If it's "desc", the snapshot and the polling capture elements in the descending order,
+	// so the polling captures elements which values are less than the ones captured before.
+	OrderingDirection iterator.OrderingDirection `json:"orderingDirection" validate:"inclusion=asc|desc" default:"asc"`
+	// The Cypher predicate referencing the captured element as "obj", e.g. "obj.active = true".
+	// If it's set, only elements matching it are captured by the snapshot and the polling.
+	Filter string `json:"filter"`
+	// The query parameters referenced by the filter, e.g. "filterParams.status" for "$status".
+	// Their values are passed as strings, the $opv, $opmv, $shardCount and $shardIndex parameters are reserved.
+	FilterParams map[string]string `json:"filterParams"`
+	// Determines which nodes are captured by the snapshot and the polling depending on their relationships.
+	// If it's "rootsOnly", only nodes without incoming relationships are captured, if it's "leavesOnly",
+	// only nodes without outgoing relationships, and if it's "isolatedOnly", only nodes without any relationships.
+	// It's supported only if the entityType is node.
+	DegreeFilter iterator.DegreeFilter `json:"degreeFilter" validate:"inclusion=all|rootsOnly|leavesOnly|isolatedOnly" default:"all"` //nolint:lll // the tag is long
+	// The list of relationship types that are captured between the captured nodes after the snapshot of nodes,
+	// so the snapshot is a subgraph without relationships referencing nodes that weren't captured.
+	// It's supported only if the entityType is node and the snapshot is enabled.
+	SubgraphRelationshipTypes []string `json:"subgraphRelationshipTypes"`
+	// The custom Cypher query that matches the captured elements instead of the entityLabels, so they're optional.
+	// It must return the captured element as "obj", and if the entityType is relationship,
+	// its source and target nodes as "src" and "trgt", e.g. "MATCH (src:Person)-[obj:KNOWS]->(trgt) RETURN *".
+	// The query is wrapped into a subquery, which results are paginated by the orderingProperty of the "obj".
+	Query string `json:"query"`
+	// The name of an index-backed property the snapshot and the polling page elements by
+	// instead of the orderingProperty, with a hint that makes Neo4j use its index.
+	// A range index of the property must exist for the first of the entityLabels.
+	// The orderingProperty is still used as the record key if the keyProperties is empty.
+	IndexProperty string `json:"indexProperty"`
+	// Determines whether or not the polling will emit deletes of elements that disappeared.
+	// The polling periodically reads keys of all captured elements and compares them with the previous ones,
+	// which are held in memory, so it takes memory proportional to the number of captured elements.
+	// Elements deleted while the connector is stopped are not detected. Relationships keyed by the keyByEndpoints
+	// are reconciled by the properties of their endpoints.
+	DetectDeletes bool `json:"detectDeletes" default:"false"`
+	// The interval between the reconciliations of keys that detect deleted elements.
+	ReconcileInterval time.Duration `json:"reconcileInterval" default:"1m"`
+	// The max number of keys held in memory for the delete detection.
+	// If there are more captured elements, the reconciliation is skipped with a warning.
+	ReconcileMaxKeys int `json:"reconcileMaxKeys" validate:"gt=0" default:"100000"`
+	// Determines whether the captured elements must have all of the entityLabels or any of them.
+	// If it's "any", nodes having at least one of the labels and relationships of any of the types are captured.
+	LabelMatch iterator.LabelMatch `json:"labelMatch" validate:"inclusion=all|any" default:"all"`
+	// The order in which records of a batch are emitted. If it's "key", the records of each batch
+	// are sorted by their keys and positions are aligned to batches, but the key order isn't kept across batches.
+	EmitOrder iterator.EmitOrder `json:"emitOrder" validate:"inclusion=ordering|key" default:"ordering"`
+	// The operation of records emitted by the snapshot. If it's "create", the snapshot records
+	// are emitted as creates, so sinks can't tell them apart from the polling ones.
+	SnapshotOperation iterator.SnapshotOperation `json:"snapshotOperation" validate:"inclusion=snapshot|create" default:"snapshot"` //nolint:lll // the tag is long
+	// EndpointLabels holds labels the endpoints of the captured relationships must have per relationship type.
+	EndpointLabels EndpointLabelsConfig `json:"endpointLabels"`
+	// The Cypher expressions computed by Neo4j for each captured element and merged into the payload by their names,
+	// e.g. "projections.name_upper" set to "toUpper(obj.name)". They reference the captured element as "obj",
+	// and its source and target nodes as "src" and "trgt" if the entityType is relationship.
+	Projections map[string]string `json:"projections"`
+	// The window behind the last processed value the polling re-reads on each poll, e.g. "5s",
+	// so elements committed with ordering property values preceding the captured ones, e.g. due to a clock skew,
+	// are captured too. The elements emitted before are skipped. The ordering property must be a date or a date-time.
+	ResumeGrace time.Duration `json:"resumeGrace"`
+	// Determines whether or not the connector will put the Neo4j element id of the captured element
+	// into the "neo4j.elementId" metadata field of its record.
+	IncludeElementID bool `json:"includeElementId" default:"false"`
+	// The name of a payload field the element id is put into if the includeElementId is enabled.
+	// If it's empty, the element id is put only into the metadata.
+	ElementIDField string `json:"elementIdField"`
+	// The list of optional fields put into the metadata of records, so records of multiple sources
+	// can be routed and debugged. The supported fields are "database", "uri" without the password, "entityType"
+	// and "orderingValue" of snapshot and polling records, they're put into "neo4j.<field>" metadata fields.
+	IncludeMetadata []string `json:"includeMetadata"`
+	// The direction relationships are matched in relative to their source node, which the endpointLabels.source
+	// constrain. If it's "incoming" or "both", the actual direction is put into the "neo4j.relationshipDirection"
+	// metadata field, and the sourceNode and targetNode are always the start and end nodes of the relationship.
+	RelationshipDirection iterator.RelationshipDirection `json:"relationshipDirection" validate:"inclusion=outgoing|incoming|both" default:"outgoing"` //nolint:lll // the tag is long
+	// Determines whether or not the connector will pass the Neo4j bookmarks of each read to the next one
+	// and store them in positions, so reads routed to different cluster members never observe an older state
+	// than the previous reads did, including the reads after a restart.
+	CausalConsistency bool `json:"causalConsistency" default:"false"`
+	// The max number of queries per second that load batches of elements or changes, which can be fractional,
+	// e.g. 0.5 permits a batch every two seconds, so the capture keeps the load of the database bounded.
+	// The limit is shared by all entities. If it's 0, the queries are not limited.
+	ReadRateLimit float64 `json:"readRateLimit" default:"0"`
+	// Entities holds the configs of the entities captured along with the entity of the top-level config values,
+	// keyed by their names, e.g. "entities.knows.entityLabels" set to "KNOWS". Their records are read in turns.
+	Entities map[string]EntityConfig `json:"entities"`
+}
+
+// EntityConfig holds the config values of an entity captured along with the entity of the top-level config values.
+// The values that aren't set are inherited from the top-level ones, and the other top-level values,
+// e.g. the batchSize, are shared by all entities.
+type EntityConfig struct {
+	// Defines an entity type of the entity.
+	EntityType config.EntityType `json:"entityType" validate:"inclusion=node|relationship"`
+	// Holds a list of labels belonging to the entity.
+	EntityLabels []string `json:"entityLabels"`
+	// The name of a property that is used for ordering elements of the entity.
+	OrderingProperty string `json:"orderingProperty"`
+	// The list of property names that are used for constructing a record key of the entity.
+	KeyProperties []string `json:"keyProperties"`
+}
+
+// EndpointLabelsConfig holds comma-separated labels the endpoints of relationships must have,
+// keyed by the relationship type. Relationships of the types that aren't listed are captured
+// regardless of their endpoints.
+type EndpointLabelsConfig struct {
+	// The labels the source node must have per relationship type, e.g. "endpointLabels.source.WORKS_AT"
+	// set to "Person". If a node must have multiple labels, they're separated by commas.
+	Source map[string]string `json:"source"`
+	// The labels the target node must have per relationship type, e.g. "endpointLabels.target.WORKS_AT"
+	// set to "Company". If a node must have multiple labels, they're separated by commas.
+	Target map[string]string `json:"target"`
+}
+
+// Validate checks the values that cannot be validated by the tags.
+func (c Config) Validate() error {
+	if c.MaxBatchSize > 0 && c.BatchSize > c.MaxBatchSize {
+		return fmt.Errorf("%w: %d > %d, raise the maxBatchSize or set it to 0 to remove the limit",
+			errBatchSizeTooLarge, c.BatchSize, c.MaxBatchSize,
+		)
+	}
+
+	if c.ReadRateLimit < 0 {
+		return fmt.Errorf("%w: %v", errNegativeReadRateLimit, c.ReadRateLimit)
+	}
+
+	if c.ShardCount > 0 && c.ShardIndex >= c.ShardCount {
+		return fmt.Errorf("%w: %d >= %d", errInvalidShardIndex, c.ShardIndex, c.ShardCount)
+	}
+
+	if c.SeedNodeMatch != "" {
+		if c.EntityType == config.EntityTypeRelationship {
+			return errSeedNodeMatchRelationship
+		}
+
+		if c.MaxHops < 1 || c.MaxHops > maxHopsLimit {
+			return fmt.Errorf("%w: %d, it must be between 1 and %d", errInvalidMaxHops, c.MaxHops, maxHopsLimit)
+		}
+	}
+
+	if c.ChangedWithin > 0 && c.SnapshotByElementID {
+		return errChangedWithinByElementID
+	}
+
+	if c.ChangedWithin > 0 && c.OrderingDirection == iterator.OrderingDirectionDesc {
+		return errChangedWithinDescending
+	}
+
+	if err := iterator.ValidateFilter(c.Filter, c.FilterParams); err != nil {
+		return fmt.Errorf("validate filter: %w", err)
+	}
+
+	if err := c.validateKey(); err != nil {
+		return err
+	}
+
+	if c.IndexProperty != "" && (c.Query != "" || c.SeedNodeMatch != "" || c.SnapshotByElementID ||
+		len(c.SubgraphRelationshipTypes) > 0) {
+		return errIndexPropertyConflict
+	}
+
+	if c.Query != "" &&
+		(c.SeedNodeMatch != "" || c.ChangedWithin > 0 || c.CDCEnabled || len(c.SubgraphRelationshipTypes) > 0) {
+		return errQueryConflict
+	}
+
+	if err := c.validateSnapshotSort(); err != nil {
+		return err
+	}
+
+	if err := c.validateDeterministicOrder(); err != nil {
+		return err
+	}
+
+	if c.DetectDeletes && c.CDCEnabled {
+		return errDetectDeletesConflict
+	}
+
+	if c.SnapshotOnly && (!c.Snapshot || c.CDCEnabled || c.DetectDeletes) {
+		return errSnapshotOnlyConflict
+	}
+
+	if c.SnapshotCompleteMarker && !c.Snapshot {
+		return errSnapshotCompleteMarkerNoSnapshot
+	}
+
+	if c.LabelMatch == iterator.LabelMatchAny && (c.IndexProperty != "" || len(c.SubgraphRelationshipTypes) > 0) {
+		return errLabelMatchAnyConflict
+	}
+
+	if c.EmitOrder == iterator.EmitOrderKey && c.EmitEndpointsAsRecords {
+		return errEmitOrderKeyEndpoints
+	}
+
+	if _, err := c.endpointLabels(); err != nil {
+		return err
+	}
+
+	if len(c.Projections) > 0 && c.CDCEnabled {
+		return errProjectionsCDC
+	}
+
+	if err := iterator.ValidateProjections(c.Projections); err != nil {
+		return fmt.Errorf("validate projections: %w", err)
+	}
+
+	if err := c.validateResumeGrace(); err != nil {
+		return err
+	}
+
+	if err := c.validateRelationshipDirection(); err != nil {
+		return err
+	}
+
+	if c.filtersByDegree() && c.EntityType != config.EntityTypeNode {
+		return errDegreeFilterRelationship
+	}
+
+	if c.ElementIDField != "" && !c.IncludeElementID {
+		return errElementIDFieldWithoutInclude
+	}
+
+	if err := iterator.ValidateElementIDField(c.ElementIDField); err != nil {
+		return fmt.Errorf("validate elementIdField: %w", err)
+	}
+
+	if err := iterator.ValidateMetadataFields(c.includeMetadata()); err != nil {
+		return fmt.Errorf("validate includeMetadata: %w", err)
+	}
+
+	return c.validateSubgraph()
+}
+
+// includeMetadata returns the includeMetadata as the [iterator.MetadataField] list.
+func (c Config) includeMetadata() []iterator.MetadataField {
+	fields := make([]iterator.MetadataField, len(c.IncludeMetadata))
+	for i, field := range c.IncludeMetadata {
+		fields[i] = iterator.MetadataField(field)
+	}
+
+	return fields
+}
+
+// pagingProperty returns the property the snapshot and the polling page elements by,
+// which is the indexProperty if it's set, or the orderingProperty otherwise.
+func (c Config) pagingProperty() string {
+	if c.IndexProperty != "" {
+		return c.IndexProperty
+	}
+
+	return c.OrderingProperty
+}
+
+// validateKey checks that the keyProperties don't contain the orderingProperty if it's excluded from the key.
+func (c Config) validateKey() error {
+	if !c.ExcludeOrderingPropertyFromKey {
+		return nil
+	}
+
+	// relationship keys are composed of the endpoint keys, so the keyProperties are not needed
+	if len(c.KeyProperties) == 0 && !c.keyedByEndpoints() {
+		return errNoKeyProperties
+	}
+
+	if slices.Contains(c.KeyProperties, c.OrderingProperty) {
+		return fmt.Errorf("%w: %q", errOrderingPropertyInKey, c.OrderingProperty)
+	}
+
+	return nil
+}
+
+// keyedByEndpoints checks if record keys are composed of the endpoint keys instead of the keyProperties.
+func (c Config) keyedByEndpoints() bool {
+	return c.KeyByEndpoints && c.EntityType == config.EntityTypeRelationship
+}
+
+// endpointLabels parses the endpointLabels into the labels of the relationship types,
+// it returns nil if there are no endpoint labels.
+func (c Config) endpointLabels() (map[string]iterator.EndpointLabels, error) {
+	if len(c.EndpointLabels.Source) == 0 && len(c.EndpointLabels.Target) == 0 {
+		return nil, nil
+	}
+
+	if c.EntityType != config.EntityTypeRelationship || c.Query != "" {
+		return nil, errEndpointLabelsConflict
+	}
+
+	sourceLabels, err := c.parseEndpointLabels(c.EndpointLabels.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	targetLabels, err := c.parseEndpointLabels(c.EndpointLabels.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointLabels := make(map[string]iterator.EndpointLabels, len(sourceLabels)+len(targetLabels))
+	for relationshipType, labels := range sourceLabels {
+		endpointLabels[relationshipType] = iterator.EndpointLabels{Source: labels}
+	}
+
+	for relationshipType, labels := range targetLabels {
+		endpoints := endpointLabels[relationshipType]
+		endpoints.Target = labels
+		endpointLabels[relationshipType] = endpoints
+	}
+
+	return endpointLabels, nil
+}
+
+// parseEndpointLabels splits the comma-separated labels of an endpoint per relationship type,
+// and checks that the types are captured and the labels aren't empty.
+func (c Config) parseEndpointLabels(rawLabels map[string]string) (map[string][]string, error) {
+	endpointLabels := make(map[string][]string, len(rawLabels))
+	for relationshipType, raw := range rawLabels {
+		if !slices.Contains(c.EntityLabels, relationshipType) {
+			return nil, fmt.Errorf("%w: %q", errEndpointLabelsUnknownType, relationshipType)
+		}
+
+		labels := strings.Split(raw, ",")
+		for i, label := range labels {
+			if labels[i] = strings.TrimSpace(label); labels[i] == "" {
+				return nil, fmt.Errorf("%w: %q of the %q type", errEndpointLabelsEmptyLabel, raw, relationshipType)
+			}
+		}
+
+		endpointLabels[relationshipType] = labels
+	}
+
+	return endpointLabels, nil
+}
+
+// validateResumeGrace checks that the polling can re-read the grace window,
+// i.e. it's not replaced by the CDC, and positions don't have to follow the order of the emitted records,
+// as the elements captured within the window precede the last processed value.
+func (c Config) validateResumeGrace() error {
+	if c.ResumeGrace <= 0 {
+		return nil
+	}
+
+	if c.CDCEnabled || c.OrderingDirection == iterator.OrderingDirectionDesc || c.AlignPositionsToBatches ||
+		c.EmitOrder == iterator.EmitOrderKey || c.EmitEndpointsAsRecords {
+		return errResumeGraceConflict
+	}
+
+	return nil
+}
+
+// validateSnapshotSort checks that the snapshotSort is well-formed and that the snapshot can be paginated by it,
+// i.e. neither by element ids, nor by the indexProperty, nor from the start of the changedWithin window.
+func (c Config) validateSnapshotSort() error {
+	sort, err := iterator.ParseSort(c.SnapshotSort)
+	if err != nil {
+		return fmt.Errorf("parse snapshotSort: %w", err)
+	}
+
+	if len(sort) > 0 && (c.SnapshotByElementID || c.IndexProperty != "" || c.ChangedWithin > 0) {
+		return errSnapshotSortConflict
+	}
+
+	return nil
+}
+
+// validateDeterministicOrder checks that the snapshot can be sorted by the keyProperties,
+// i.e. it's not sorted by the snapshotSort or paginated by element ids, by the indexProperty,
+// or from the start of the changedWithin window.
+func (c Config) validateDeterministicOrder() error {
+	if !c.DeterministicOrder {
+		return nil
+	}
+
+	if strings.TrimSpace(c.SnapshotSort) != "" || c.SnapshotByElementID || c.IndexProperty != "" || c.ChangedWithin > 0 {
+		return errDeterministicOrderConflict
+	}
+
+	if len(c.KeyProperties) == 0 {
+		return errDeterministicOrderNoKeyProperties
+	}
+
+	return nil
+}
+
+// validateEntities checks that the entities are named and labeled,
+// and that the top-level config values don't rely on a single entity.
+func (c Config) validateEntities() error {
+	if c.Query != "" || c.IndexProperty != "" || len(c.SubgraphRelationshipTypes) > 0 ||
+		len(c.EndpointLabels.Source) > 0 || len(c.EndpointLabels.Target) > 0 {
+		return errEntitiesConflict
+	}
+
+	for name, entity := range c.Entities {
+		if name == defaultEntityName {
+			return fmt.Errorf("%w: %q", errReservedEntityName, name)
+		}
+
+		if len(entity.EntityLabels) == 0 {
+			return fmt.Errorf("%w: %q", errEntityNoLabels, name)
+		}
+	}
+
+	return nil
+}
+
+// entityConfig returns the config of the entity with the given name, which values that aren't set
+// are inherited from the top-level ones. The config of the defaultEntityName holds the top-level values.
+func (c Config) entityConfig(name string) Config {
+	entityConfig := c
+	entityConfig.Entities = nil
+	entityConfig.EntityLabels = slices.Clone(c.EntityLabels)
+	entityConfig.KeyProperties = slices.Clone(c.KeyProperties)
+
+	entity, ok := c.Entities[name]
+	if !ok {
+		return entityConfig
+	}
+
+	if entity.EntityType != "" {
+		entityConfig.EntityType = entity.EntityType
+	}
+
+	if len(entity.EntityLabels) > 0 {
+		entityConfig.EntityLabels = slices.Clone(entity.EntityLabels)
+	}
+
+	if entity.OrderingProperty != "" {
+		entityConfig.OrderingProperty = entity.OrderingProperty
+	}
+
+	if len(entity.KeyProperties) > 0 {
+		entityConfig.KeyProperties = slices.Clone(entity.KeyProperties)
+	}
+
+	return entityConfig
+}
+
+// filtersByDegree checks if the degreeFilter narrows the captured nodes.
+func (c Config) filtersByDegree() bool {
+	return c.DegreeFilter != "" && c.DegreeFilter != iterator.DegreeFilterAll
+}
+
+// validateRelationshipDirection checks that the relationshipDirection other than outgoing applies to
+// the relationships matched by the snapshot and the polling, as the custom query defines the direction on its own,
+// and the CDC selects relationships by their start and end nodes.
+func (c Config) validateRelationshipDirection() error {
+	if c.RelationshipDirection == "" || c.RelationshipDirection == iterator.RelationshipDirectionOutgoing {
+		return nil
+	}
+
+	if c.EntityType != config.EntityTypeRelationship || c.Query != "" || c.CDCEnabled {
+		return errRelationshipDirectionConflict
+	}
+
+	return nil
+}
+
+// validateSubgraph checks that the subgraph snapshot can capture all endpoints of the captured relationships.
+func (c Config) validateSubgraph() error {
+	if len(c.SubgraphRelationshipTypes) == 0 {
+		return nil
+	}
+
+	if c.EntityType != config.EntityTypeNode {
+		return errSubgraphEntityType
+	}
+
+	if !c.Snapshot {
+		return errSubgraphNoSnapshot
+	}
+
+	if c.ShardCount > 1 || c.SeedNodeMatch != "" || c.Filter != "" || c.filtersByDegree() ||
+		c.SoftDeleteField != "" || c.ChangedWithin > 0 {
+		return errSubgraphNarrowed
+	}
+
+	return nil
 }
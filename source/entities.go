@@ -0,0 +1,150 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/source/iterator"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// entity is one of the multiple entities the [Source] captures.
+type entity struct {
+	name   string
+	source *Source
+	// position is the position of the last record of the entity,
+	// it's nil until the entity either reads a record or is resumed from a position.
+	position sdk.Position
+}
+
+// configureEntities configures a source for each of the entities, the first of them captures the entity
+// of the top-level config values. It returns nil if the entities are not set.
+func (s *Source) configureEntities(ctx context.Context) ([]*entity, error) {
+	if len(s.config.Entities) == 0 {
+		return nil, nil
+	}
+
+	if err := s.config.validateEntities(); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(s.config.Entities))
+	for name := range s.config.Entities {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	names = append([]string{defaultEntityName}, names...)
+
+	entities := make([]*entity, 0, len(names))
+	for _, name := range names {
+		source := &Source{config: s.config.entityConfig(name)}
+		if err := source.configure(entityContext(ctx, name)); err != nil {
+			return nil, fmt.Errorf("configure entity %q: %w", name, err)
+		}
+
+		entities = append(entities, &entity{name: name, source: source})
+	}
+
+	return entities, nil
+}
+
+// openEntities opens the sources of the entities from their positions,
+// the entities without positions are captured from scratch.
+func (s *Source) openEntities(ctx context.Context, positions map[string]*iterator.Position) error {
+	for _, entity := range s.entities {
+		entity.position = nil
+
+		position := positions[entity.name]
+		if position != nil {
+			sdkPosition, err := position.MarshalSDKPosition()
+			if err != nil {
+				return fmt.Errorf("marshal position of entity %q: %w", entity.name, err)
+			}
+
+			entity.position = sdkPosition
+		}
+
+		entity.source.driver = s.driver
+		entity.source.rateLimiter = s.rateLimiter
+
+		if err := entity.source.open(entityContext(ctx, entity.name), position); err != nil {
+			return fmt.Errorf("open entity %q: %w", entity.name, err)
+		}
+	}
+
+	return nil
+}
+
+// readEntities reads a record from the entities in turns, an entity without a record to read is skipped,
+// so the others are not blocked by it. The position of the record holds the positions of all entities.
+func (s *Source) readEntities(ctx context.Context) (sdk.Record, error) {
+	for range s.entities {
+		entity := s.entities[s.nextEntity]
+		s.nextEntity = (s.nextEntity + 1) % len(s.entities)
+
+		record, err := entity.source.Read(ctx)
+		if err != nil {
+			if errors.Is(err, sdk.ErrBackoffRetry) {
+				continue
+			}
+
+			return sdk.Record{}, fmt.Errorf("read entity %q: %w", entity.name, err)
+		}
+
+		entity.position = record.Position
+
+		record.Position, err = s.entitiesPosition()
+		if err != nil {
+			return sdk.Record{}, fmt.Errorf("get entities position: %w", err)
+		}
+
+		return record, nil
+	}
+
+	return sdk.Record{}, sdk.ErrBackoffRetry
+}
+
+// entitiesPosition returns a position that holds the positions of the entities.
+func (s *Source) entitiesPosition() (sdk.Position, error) {
+	position := &iterator.Position{
+		Version:  iterator.PositionVersion,
+		Mode:     iterator.ModeEntities,
+		Entities: make(map[string]json.RawMessage, len(s.entities)),
+	}
+
+	for _, entity := range s.entities {
+		if entity.position != nil {
+			position.Entities[entity.name] = json.RawMessage(entity.position)
+		}
+	}
+
+	sdkPosition, err := position.MarshalSDKPosition()
+	if err != nil {
+		return nil, fmt.Errorf("marshal position: %w", err)
+	}
+
+	return sdkPosition, nil
+}
+
+// entityContext returns a context which logger tags the logs with the entity name.
+func entityContext(ctx context.Context, name string) context.Context {
+	return sdk.Logger(ctx).With().Str("entity", name).Logger().WithContext(ctx)
+}
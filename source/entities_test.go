@@ -0,0 +1,250 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/source/iterator"
+	"github.com/conduitio-labs/conduit-connector-neo4j/source/mock"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+	"go.uber.org/mock/gomock"
+)
+
+// The sdk.Util.ParseConfig has problems with concurrent access, so the t.Parallel isn't placed inside the loop.
+//
+//nolint:paralleltest,tparallel,nolintlint
+func TestSource_Configure_entities(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		raw     map[string]string
+		want    map[string]Config
+		wantErr error
+	}{
+		{
+			name: "success_inherited",
+			raw: map[string]string{
+				ConfigKeyEntities + ".knows.entityType":        string(config.EntityTypeRelationship),
+				ConfigKeyEntities + ".knows.entityLabels":      "KNOWS",
+				ConfigKeyEntities + ".companies.entityLabels":  "Company",
+				ConfigKeyEntities + ".companies.keyProperties": "name",
+			},
+			want: map[string]Config{
+				defaultEntityName: {
+					Config:           config.Config{EntityType: config.EntityTypeNode, EntityLabels: []string{"Person"}},
+					OrderingProperty: "id",
+					KeyProperties:    []string{"id"},
+				},
+				"companies": {
+					Config:           config.Config{EntityType: config.EntityTypeNode, EntityLabels: []string{"Company"}},
+					OrderingProperty: "id",
+					KeyProperties:    []string{"name"},
+				},
+				"knows": {
+					Config: config.Config{
+						EntityType: config.EntityTypeRelationship, EntityLabels: []string{"KNOWS"},
+					},
+					OrderingProperty: "id",
+					KeyProperties:    []string{"id"},
+				},
+			},
+		},
+		{
+			name: "success_overridden",
+			raw: map[string]string{
+				ConfigKeyKeyProperties:                         "email",
+				ConfigKeyEntities + ".events.entityLabels":     "Event",
+				ConfigKeyEntities + ".events.orderingProperty": "createdAt",
+				ConfigKeyEntities + ".events.keyProperties":    "uuid",
+			},
+			want: map[string]Config{
+				defaultEntityName: {
+					Config:           config.Config{EntityType: config.EntityTypeNode, EntityLabels: []string{"Person"}},
+					OrderingProperty: "id",
+					KeyProperties:    []string{"email"},
+				},
+				"events": {
+					Config:           config.Config{EntityType: config.EntityTypeNode, EntityLabels: []string{"Event"}},
+					OrderingProperty: "createdAt",
+					KeyProperties:    []string{"uuid"},
+				},
+			},
+		},
+		{
+			name: "success_empty",
+			raw:  map[string]string{},
+		},
+		{
+			name: "fail_query",
+			raw: map[string]string{
+				ConfigKeyQuery: "MATCH (obj:Person) RETURN obj",
+				ConfigKeyEntities + ".knows.entityLabels": "KNOWS",
+			},
+			wantErr: errEntitiesConflict,
+		},
+		{
+			name:    "fail_reserved_name",
+			raw:     map[string]string{ConfigKeyEntities + "." + defaultEntityName + ".entityLabels": "Company"},
+			wantErr: errReservedEntityName,
+		},
+		{
+			name:    "fail_no_entityLabels",
+			raw:     map[string]string{ConfigKeyEntities + ".knows.orderingProperty": "createdAt"},
+			wantErr: errEntityNoLabels,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			raw := map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      string(config.EntityTypeNode),
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "id",
+			}
+			for key, value := range tt.raw {
+				raw[key] = value
+			}
+
+			s := Source{}
+
+			err := s.Configure(context.Background(), raw)
+			is.True(errors.Is(err, tt.wantErr))
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			is.Equal(len(s.entities), len(tt.want))
+
+			for i, entity := range s.entities {
+				// the entity of the top-level config values goes first
+				if i == 0 {
+					is.Equal(entity.name, defaultEntityName)
+				}
+
+				want, ok := tt.want[entity.name]
+				is.True(ok)
+				is.Equal(entity.source.config.EntityType, want.EntityType)
+				is.Equal(entity.source.config.EntityLabels, want.EntityLabels)
+				is.Equal(entity.source.config.OrderingProperty, want.OrderingProperty)
+				is.Equal(entity.source.config.KeyProperties, want.KeyProperties)
+				is.Equal(entity.source.config.Entities, nil)
+			}
+		})
+	}
+}
+
+func TestSource_Read_entities(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	person := sdk.Record{
+		Position: sdk.Position(`{"version":1,"mode":"snapshot","lastProcessedValue":1}`),
+		Key:      sdk.StructuredData{"id": 1},
+	}
+	company := sdk.Record{
+		Position: sdk.Position(`{"version":1,"mode":"snapshot","lastProcessedValue":"acme"}`),
+		Key:      sdk.StructuredData{"name": "acme"},
+	}
+
+	personIt := mock.NewMockIterator(ctrl)
+	gomock.InOrder(
+		personIt.EXPECT().HasNext(ctx).Return(true, nil),
+		personIt.EXPECT().Next(ctx).Return(person, nil),
+		personIt.EXPECT().HasNext(ctx).Return(false, nil),
+	)
+
+	companyIt := mock.NewMockIterator(ctrl)
+	companyIt.EXPECT().HasNext(ctx).Return(true, nil).Times(2)
+	companyIt.EXPECT().Next(ctx).Return(company, nil).Times(2)
+
+	s := Source{entities: []*entity{
+		{name: defaultEntityName, source: &Source{pollingSnapshot: personIt}},
+		{name: "companies", source: &Source{pollingSnapshot: companyIt}},
+	}}
+
+	// the entities are read in turns
+	r, err := s.Read(ctx)
+	is.NoErr(err)
+	is.Equal(r.Key, person.Key)
+	is.Equal(entityPositions(t, r.Position), map[string]*iterator.Position{
+		defaultEntityName: {Version: iterator.PositionVersion, Mode: iterator.ModeSnapshot, LastProcessedValue: 1.0},
+	})
+
+	r, err = s.Read(ctx)
+	is.NoErr(err)
+	is.Equal(r.Key, company.Key)
+
+	// the entity without records is skipped, and the positions of both entities are kept
+	r, err = s.Read(ctx)
+	is.NoErr(err)
+	is.Equal(r.Key, company.Key)
+	is.Equal(entityPositions(t, r.Position), map[string]*iterator.Position{
+		defaultEntityName: {Version: iterator.PositionVersion, Mode: iterator.ModeSnapshot, LastProcessedValue: 1.0},
+		"companies":       {Version: iterator.PositionVersion, Mode: iterator.ModeSnapshot, LastProcessedValue: "acme"},
+	})
+}
+
+func TestSource_Read_entitiesBackoff(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	personIt := mock.NewMockIterator(ctrl)
+	personIt.EXPECT().HasNext(ctx).Return(false, nil)
+
+	companyIt := mock.NewMockIterator(ctrl)
+	companyIt.EXPECT().HasNext(ctx).Return(false, nil)
+
+	s := Source{entities: []*entity{
+		{name: defaultEntityName, source: &Source{pollingSnapshot: personIt}},
+		{name: "companies", source: &Source{pollingSnapshot: companyIt}},
+	}}
+
+	_, err := s.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+// entityPositions parses the positions of the entities held by the position.
+func entityPositions(t *testing.T, sdkPosition sdk.Position) map[string]*iterator.Position {
+	t.Helper()
+
+	is := is.New(t)
+
+	position, err := iterator.ParsePosition(sdkPosition)
+	is.NoErr(err)
+	is.Equal(position.Mode, iterator.ModeEntities)
+
+	positions, err := position.EntityPositions(defaultEntityName)
+	is.NoErr(err)
+
+	return positions
+}
@@ -0,0 +1,70 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"slices"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Bookmarks holds the Neo4j bookmarks of the last read session of the iterators,
+// which are passed to the next session, so it observes at least the state the previous one observed,
+// even if a cluster routes it to another member. It's shared by the iterators of a source,
+// so the capture that follows the snapshot sees the snapshot's state.
+// The nil Bookmarks disables the causal consistency.
+type Bookmarks struct {
+	values neo4j.Bookmarks
+}
+
+// NewBookmarks creates a new instance of the [Bookmarks] starting with the values,
+// e.g. the ones stored in a position, the empty values start with no bookmarks.
+func NewBookmarks(values []string) *Bookmarks {
+	return &Bookmarks{values: slices.Clone(values)}
+}
+
+// sessionConfig returns a config of a read session on the database that waits for the bookmarks.
+func (b *Bookmarks) sessionConfig(database string) neo4j.SessionConfig {
+	config := neo4j.SessionConfig{
+		AccessMode:   neo4j.AccessModeRead,
+		DatabaseName: database,
+	}
+
+	if b != nil {
+		config.Bookmarks = b.values
+	}
+
+	return config
+}
+
+// update replaces the bookmarks with the last bookmarks of the session after it has read successfully.
+func (b *Bookmarks) update(session neo4j.SessionWithContext) {
+	if b == nil {
+		return
+	}
+
+	if values := session.LastBookmarks(); len(values) > 0 {
+		b.values = values
+	}
+}
+
+// list returns a copy of the bookmarks that is stored in positions, it's nil if the bookmarks are nil.
+func (b *Bookmarks) list() []string {
+	if b == nil {
+		return nil
+	}
+
+	return slices.Clone(b.values)
+}
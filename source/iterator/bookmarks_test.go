@@ -0,0 +1,94 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/mock"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/mock/gomock"
+)
+
+// bookmarksSession returns a session that reports the bookmarks as its last ones.
+func bookmarksSession(ctrl *gomock.Controller, bookmarks neo4j.Bookmarks) neo4j.SessionWithContext {
+	session, sessionMock := mock.NewSession(ctrl)
+	sessionMock.EXPECT().LastBookmarks().Return(bookmarks)
+
+	return session
+}
+
+func TestBookmarks(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctrl := gomock.NewController(t)
+
+	bookmarks := NewBookmarks([]string{"bm:1"})
+	is.Equal(bookmarks.sessionConfig("neo4j"), neo4j.SessionConfig{
+		AccessMode:   neo4j.AccessModeRead,
+		DatabaseName: "neo4j",
+		Bookmarks:    neo4j.Bookmarks{"bm:1"},
+	})
+
+	// the bookmarks of the next session replace the previous ones
+	bookmarks.update(bookmarksSession(ctrl, neo4j.Bookmarks{"bm:2"}))
+	is.Equal(bookmarks.sessionConfig("neo4j").Bookmarks, neo4j.Bookmarks{"bm:2"})
+	is.Equal(bookmarks.list(), []string{"bm:2"})
+
+	// a session without bookmarks keeps the previous ones
+	bookmarks.update(bookmarksSession(ctrl, nil))
+	is.Equal(bookmarks.list(), []string{"bm:2"})
+}
+
+func TestBookmarks_disabled(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctrl := gomock.NewController(t)
+
+	var bookmarks *Bookmarks
+
+	// the session isn't asked for its bookmarks
+	session, _ := mock.NewSession(ctrl)
+	bookmarks.update(session)
+	is.Equal(bookmarks.sessionConfig("neo4j"), neo4j.SessionConfig{
+		AccessMode:   neo4j.AccessModeRead,
+		DatabaseName: "neo4j",
+	})
+	is.Equal(bookmarks.list(), nil)
+}
+
+func TestSnapshot_loadBatch_bookmarks(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctrl := gomock.NewController(t)
+
+	var sessionConfig neo4j.SessionConfig
+	driver := newFailingDriver(ctrl, &sessionConfig)
+
+	s := &Snapshot{driver: driver, orderingProperty: "id", bookmarks: NewBookmarks([]string{"bm:1"})}
+
+	err := s.loadBatch(context.Background())
+	is.True(errors.Is(err, errTestSession))
+	is.Equal(sessionConfig.Bookmarks, neo4j.Bookmarks{"bm:1"})
+
+	// the bookmarks are stored in positions, so a resumed capture waits for them
+	is.Equal(s.newPosition(int64(1)).Bookmarks, []string{"bm:1"})
+}
@@ -0,0 +1,553 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/querylog"
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/mitchellh/mapstructure"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+)
+
+const (
+	// all Cypher queries used by the [CDC] are listed below.
+	getCurrentChangeIDQuery = "CALL db.cdc.current() YIELD id RETURN id"
+	getChangesQuery         = `
+	CALL db.cdc.query($from, $selectors) YIELD id, event
+	RETURN id, event LIMIT $limit`
+
+	// some helpers for CDC queries.
+	changeIDPlaceholder    = "id"
+	changeEventPlaceholder = "event"
+	fromFieldName          = "from"
+	selectorsFieldName     = "selectors"
+	limitFieldName         = "limit"
+
+	// CDC selector fields and values.
+	selectorSelectField = "select"
+	selectorLabelsField = "labels"
+	selectorTypeField   = "type"
+	selectNodes         = "n"
+	selectRelationships = "r"
+
+	// CDC event operations.
+	operationCreate = "c"
+	operationUpdate = "u"
+	operationDelete = "d"
+)
+
+// errUnsupportedChangeOperation occurs when a CDC event has an unknown operation.
+var errUnsupportedChangeOperation = errors.New("unsupported change operation")
+
+// changeEvent is a CDC event of a node or a relationship.
+type changeEvent struct {
+	ElementID string `mapstructure:"elementId"`
+	Operation string `mapstructure:"operation"`
+	// Start and End are the endpoints of a relationship, they're empty for nodes.
+	Start changeEndpoint `mapstructure:"start"`
+	End   changeEndpoint `mapstructure:"end"`
+	State struct {
+		Before *changeState `mapstructure:"before"`
+		After  *changeState `mapstructure:"after"`
+	} `mapstructure:"state"`
+}
+
+// changeEndpoint is an endpoint node of a changed relationship.
+type changeEndpoint struct {
+	Labels []string `mapstructure:"labels"`
+	// Keys holds node key constraint values, grouped by labels.
+	Keys map[string][]map[string]any `mapstructure:"keys"`
+}
+
+// changeState is a state of an element before or after a change.
+type changeState struct {
+	Properties map[string]any `mapstructure:"properties"`
+}
+
+// CDC implements a change data capture logic for the connector based on the Neo4j native CDC.
+type CDC struct {
+	driver         neo4j.DriverWithContext
+	databaseName   string
+	entityType     config.EntityType
+	entityLabels   string
+	keyProperties  []string
+	batchSize      int
+	fieldCollision FieldCollision
+	payloadFormat  PayloadFormat
+	// vectors defines the properties which values are converted into lists of floats.
+	vectors schema.Vectors
+	// selectors are CDC selectors that limit the changes to the ones of the entity labels.
+	selectors []any
+	// labels holds the entity labels that are stored in positions.
+	labels []string
+	// keyByEndpoints defines if keys of relationship records are composed of their endpoint keys.
+	keyByEndpoints bool
+	// includeDeletedState defines if the payload before of delete records holds the state before the delete.
+	includeDeletedState bool
+	// includeElementID defines if element ids are put into the metadata of records,
+	// and into their payloads by the elementIDField if it's not empty.
+	includeElementID bool
+	elementIDField   string
+	// logRedactProperties holds names of properties which values are redacted in logged queries.
+	logRedactProperties []string
+	// changeID is an identifier of the last loaded change, the next batch is loaded after it.
+	changeID string
+	// metadataFields holds the optional fields put into the metadata of records.
+	metadataFields metadataFields
+	// records stores records built from the loaded changes,
+	// this channel works as a queue from which the Next method takes records.
+	records chan sdk.Record
+	// bookmarks are passed to the read sessions and updated after them, they're nil unless the causal consistency
+	// is enabled, see the [Bookmarks].
+	bookmarks *Bookmarks
+	// rateLimiter limits the rate of the queries that load batches, it's nil unless the rate limit is set.
+	rateLimiter *RateLimiter
+	// queryTimeout is the max execution time of the read transactions, zero keeps the server's default.
+	queryTimeout time.Duration
+}
+
+// CDCParams is incoming params for the [NewCDC] function.
+type CDCParams struct {
+	Driver         neo4j.DriverWithContext
+	DatabaseName   string
+	EntityType     config.EntityType
+	EntityLabels   []string
+	KeyProperties  []string
+	BatchSize      int
+	FieldCollision FieldCollision
+	PayloadFormat  PayloadFormat
+	// Vectors defines the properties which values are converted into lists of floats
+	// and checked to have the expected dimensions, the zero Vectors converts none.
+	Vectors schema.Vectors
+	// LabelMatch defines whether the captured elements must have all of the EntityLabels or any of them.
+	LabelMatch LabelMatch
+	// EndpointLabels maps relationship types of the EntityLabels to the labels their endpoints must have.
+	EndpointLabels map[string]EndpointLabels
+	// KeyByEndpoints defines if keys of relationship records are composed of the keys
+	// of their source and target nodes instead of the KeyProperties.
+	KeyByEndpoints bool
+	// IncludeDeletedState defines if the payload before of delete records holds the state before the delete.
+	IncludeDeletedState bool
+	// IncludeElementID defines if the Neo4j element ids are put into the metadata of records,
+	// and into their payloads by the ElementIDField if it's not empty.
+	IncludeElementID bool
+	ElementIDField   string
+	// LogRedactProperties are names of properties which values are redacted in logged queries.
+	LogRedactProperties []string
+	// ChangeID is an identifier of a change the capture starts after,
+	// if it's empty, the capture starts after the current change.
+	ChangeID string
+	// IncludeMetadata holds the optional fields put into the metadata of records,
+	// they must pass the [ValidateMetadataFields], the ordering value isn't put into CDC records.
+	// The URI is put into the metadata without the password.
+	IncludeMetadata []MetadataField
+	URI             string
+	// Bookmarks are shared by the iterators of a source to read with the causal consistency,
+	// the nil Bookmarks disable it.
+	Bookmarks *Bookmarks
+	// RateLimiter is shared by the iterators of a source to limit the rate of the queries that load batches,
+	// the nil RateLimiter doesn't limit them.
+	RateLimiter *RateLimiter
+	// QueryTimeout is the max execution time of the read transactions, zero keeps the server's default.
+	QueryTimeout time.Duration
+}
+
+// NewCDC creates a new instance of the [CDC].
+func NewCDC(ctx context.Context, params CDCParams) (*CDC, error) {
+	changeID := params.ChangeID
+	if changeID == "" {
+		var err error
+
+		changeID, err = CurrentChangeID(ctx, params.Driver, params.DatabaseName)
+		if err != nil {
+			return nil, fmt.Errorf("get current change id: %w", err)
+		}
+	}
+
+	return &CDC{
+		driver:              params.Driver,
+		databaseName:        params.DatabaseName,
+		entityType:          params.EntityType,
+		entityLabels:        strings.Join(params.EntityLabels, ":"),
+		labels:              params.EntityLabels,
+		keyProperties:       params.KeyProperties,
+		keyByEndpoints:      params.KeyByEndpoints,
+		includeDeletedState: params.IncludeDeletedState,
+		includeElementID:    params.IncludeElementID,
+		elementIDField:      params.ElementIDField,
+		batchSize:           params.BatchSize,
+		fieldCollision:      params.FieldCollision,
+		payloadFormat:       params.PayloadFormat,
+		vectors:             params.Vectors,
+		selectors: withEndpointLabels(
+			changeSelectors(params.EntityType, params.EntityLabels, params.LabelMatch), params.EndpointLabels,
+		),
+		logRedactProperties: params.LogRedactProperties,
+		changeID:            changeID,
+		records:             make(chan sdk.Record, min(params.BatchSize, maxRecordsCapacity)),
+		bookmarks:           params.Bookmarks,
+		rateLimiter:         params.RateLimiter,
+		queryTimeout:        params.QueryTimeout,
+		metadataFields: newMetadataFields(
+			params.IncludeMetadata, params.DatabaseName, params.URI, params.EntityType,
+		),
+	}, nil
+}
+
+// HasNext checks whether the CDC iterator has records to return or not.
+func (c *CDC) HasNext(ctx context.Context) (bool, error) {
+	if len(c.records) > 0 {
+		return true, nil
+	}
+
+	if err := c.loadBatch(ctx); err != nil {
+		return false, fmt.Errorf("load batch: %w", err)
+	}
+
+	return len(c.records) > 0, nil
+}
+
+// Next returns the next available record.
+func (c *CDC) Next(ctx context.Context) (sdk.Record, error) {
+	select {
+	case <-ctx.Done():
+		return sdk.Record{}, ctx.Err() //nolint:wrapcheck // there's no much to wrap here
+
+	case record := <-c.records:
+		return record, nil
+	}
+}
+
+// loadBatch loads a batch of changes that happened after the last loaded change.
+func (c *CDC) loadBatch(ctx context.Context) error {
+	if err := c.rateLimiter.wait(ctx); err != nil {
+		return err
+	}
+
+	session := c.driver.NewSession(ctx, c.bookmarks.sessionConfig(c.databaseName))
+	defer session.Close(ctx)
+
+	params := map[string]any{
+		fromFieldName:      c.changeID,
+		selectorsFieldName: c.selectors,
+		limitFieldName:     c.batchSize,
+	}
+
+	querylog.Log(ctx, getChangesQuery, params, c.logRedactProperties)
+
+	// lastChangeID is set within the transaction function,
+	// it's overwritten if the function is retried, as the same changes are loaded again
+	var lastChangeID string
+
+	records, err := neo4j.ExecuteRead(ctx, session, func(tx neo4j.ManagedTransaction) ([]sdk.Record, error) {
+		result, err := tx.Run(ctx, getChangesQuery, params)
+		if err != nil {
+			return nil, fmt.Errorf("run tx: %w", err)
+		}
+
+		var (
+			records []sdk.Record
+			record  *db.Record
+		)
+
+		for result.NextRecord(ctx, &record) {
+			changeID, changeRecord, err := c.changeRecord(record)
+			if err != nil {
+				return nil, fmt.Errorf("build change record: %w", err)
+			}
+
+			lastChangeID = changeID
+			records = append(records, changeRecord)
+		}
+
+		if err = result.Err(); err != nil {
+			return nil, fmt.Errorf("iterate result: %w", err)
+		}
+
+		return records, nil
+	}, config.TxTimeout(c.queryTimeout))
+	if err != nil {
+		return fmt.Errorf("execute read: %w", err)
+	}
+
+	c.bookmarks.update(session)
+
+	// send the records only after the transaction has completed,
+	// so a retried transaction doesn't send the same records twice
+	// the records channel is empty here, and it's grown only if the loaded batch doesn't fit into it
+	if len(records) > cap(c.records) {
+		c.records = make(chan sdk.Record, len(records))
+	}
+
+	for _, record := range records {
+		c.records <- record
+	}
+
+	if len(records) > 0 {
+		c.changeID = lastChangeID
+	}
+
+	return nil
+}
+
+// changeRecord builds an [sdk.Record] from a CDC result record and returns it along with the change identifier.
+func (c *CDC) changeRecord(record *db.Record) (string, sdk.Record, error) {
+	changeIDRaw, ok := record.Get(changeIDPlaceholder)
+	if !ok {
+		return "", sdk.Record{}, fmt.Errorf("record doesn't contain %q key", changeIDPlaceholder)
+	}
+
+	changeEventRaw, ok := record.Get(changeEventPlaceholder)
+	if !ok {
+		return "", sdk.Record{}, fmt.Errorf("record doesn't contain %q key", changeEventPlaceholder)
+	}
+
+	var event changeEvent
+	if err := mapstructure.Decode(changeEventRaw, &event); err != nil {
+		return "", sdk.Record{}, fmt.Errorf("decode change event: %w", err)
+	}
+
+	changeID := fmt.Sprint(changeIDRaw)
+
+	eventRecord, err := c.eventRecord(changeID, event)
+	if err != nil {
+		return "", sdk.Record{}, err
+	}
+
+	return changeID, eventRecord, nil
+}
+
+// eventRecord builds an [sdk.Record] of the change event with the given change identifier.
+func (c *CDC) eventRecord(changeID string, event changeEvent) (sdk.Record, error) {
+	position := &Position{
+		Version:      PositionVersion,
+		Mode:         ModeCDC,
+		ChangeID:     changeID,
+		EntityLabels: c.labels,
+		Bookmarks:    c.bookmarks.list(),
+	}
+
+	sdkPosition, err := position.MarshalSDKPosition()
+	if err != nil {
+		return sdk.Record{}, fmt.Errorf("marshal sdk position: %w", err)
+	}
+
+	key, err := c.recordKey(changeID, event)
+	if err != nil {
+		return sdk.Record{}, fmt.Errorf("construct key: %w", err)
+	}
+
+	metadata := sdk.Metadata{metadataEntityLabelsField: c.entityLabels}
+	c.metadataFields.set(metadata)
+	metadata.SetCreatedAt(time.Now())
+
+	if c.includeElementID {
+		setElementID(metadata, nil, event.ElementID, "")
+	}
+
+	switch event.Operation {
+	case operationCreate:
+		after, err := c.payload(event, event.State.After)
+		if err != nil {
+			return sdk.Record{}, fmt.Errorf("prepare payload after: %w", err)
+		}
+
+		return sdk.Util.Source.NewRecordCreate(sdkPosition, metadata, key, after), nil
+
+	case operationUpdate:
+		before, err := c.payload(event, event.State.Before)
+		if err != nil {
+			return sdk.Record{}, fmt.Errorf("prepare payload before: %w", err)
+		}
+
+		after, err := c.payload(event, event.State.After)
+		if err != nil {
+			return sdk.Record{}, fmt.Errorf("prepare payload after: %w", err)
+		}
+
+		return sdk.Util.Source.NewRecordUpdate(sdkPosition, metadata, key, before, after), nil
+
+	case operationDelete:
+		record := sdk.Util.Source.NewRecordDelete(sdkPosition, metadata, key)
+		if !c.includeDeletedState {
+			return record, nil
+		}
+
+		record.Payload.Before, err = c.payload(event, event.State.Before)
+		if err != nil {
+			return sdk.Record{}, fmt.Errorf("prepare payload before: %w", err)
+		}
+
+		return record, nil
+
+	default:
+		return sdk.Record{}, fmt.Errorf("%w: %q", errUnsupportedChangeOperation, event.Operation)
+	}
+}
+
+// recordKey constructs a key of the change event record from the keyProperties,
+// or from the endpoint keys if it's a relationship change and the keyByEndpoints is enabled.
+func (c *CDC) recordKey(changeID string, event changeEvent) (sdk.StructuredData, error) {
+	if c.keyByEndpoints && c.entityType == config.EntityTypeRelationship {
+		return schema.EncodeValues(endpointsKey(event.Start.key(), event.End.key())), nil
+	}
+
+	// the state after the change is missing for deletes, so the key is taken from the state before it
+	keyState := event.State.After
+	if keyState == nil {
+		keyState = event.State.Before
+	}
+
+	if keyState == nil {
+		return nil, fmt.Errorf("change %q doesn't contain a state", changeID)
+	}
+
+	key := make(sdk.StructuredData)
+	for _, keyProperty := range c.keyProperties {
+		keyPropertyValue, ok := keyState.Properties[keyProperty]
+		if !ok {
+			return nil, fmt.Errorf("payload doesn't contain %q property", keyProperty)
+		}
+
+		key[keyProperty] = keyPropertyValue
+	}
+
+	return schema.EncodeValues(key), nil
+}
+
+// payload marshals the element state into a record payload,
+// relationship payloads get the sourceNode and targetNode fields, as the snapshot ones.
+func (c *CDC) payload(event changeEvent, state *changeState) (sdk.Data, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	props := make(map[string]any, len(state.Properties))
+	for name, value := range state.Properties {
+		props[name] = value
+	}
+
+	if c.includeElementID && c.elementIDField != "" {
+		props[c.elementIDField] = event.ElementID
+	}
+
+	if c.entityType == config.EntityTypeRelationship {
+		if err := resolveReservedFields(props, c.fieldCollision); err != nil {
+			return nil, fmt.Errorf("resolve reserved fields: %w", err)
+		}
+
+		props[sourceNodeField] = schema.Node{Labels: event.Start.Labels, Key: event.Start.key()}
+		props[targetNodeField] = schema.Node{Labels: event.End.Labels, Key: event.End.key()}
+	}
+
+	if err := c.vectors.Convert(props); err != nil {
+		return nil, fmt.Errorf("convert vectors: %w", err)
+	}
+
+	payload, err := marshalPayload(props, c.payloadFormat)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	return sdk.RawData(payload), nil
+}
+
+// key merges the node key constraint values of the endpoint into a single key.
+func (e changeEndpoint) key() map[string]any {
+	key := make(map[string]any)
+	for _, labelKeys := range e.Keys {
+		for _, labelKey := range labelKeys {
+			for name, value := range labelKey {
+				key[name] = value
+			}
+		}
+	}
+
+	return key
+}
+
+// CurrentChangeID returns an identifier of the current change of the database,
+// a CDC capture started after it captures changes that happen from now on.
+func CurrentChangeID(ctx context.Context, driver neo4j.DriverWithContext, database string) (string, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		AccessMode:   neo4j.AccessModeRead,
+		DatabaseName: database,
+	})
+	defer session.Close(ctx)
+
+	changeID, err := neo4j.ExecuteRead(ctx, session, func(tx neo4j.ManagedTransaction) (string, error) {
+		result, err := tx.Run(ctx, getCurrentChangeIDQuery, nil)
+		if err != nil {
+			return "", fmt.Errorf("run tx: %w", err)
+		}
+
+		record, err := result.Single(ctx)
+		if err != nil {
+			return "", fmt.Errorf("extract single from result: %w", err)
+		}
+
+		changeID, ok := record.Get(changeIDPlaceholder)
+		if !ok {
+			return "", fmt.Errorf("record doesn't contain %q key", changeIDPlaceholder)
+		}
+
+		return fmt.Sprint(changeID), nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("execute read: %w", err)
+	}
+
+	return changeID, nil
+}
+
+// changeSelectors returns CDC selectors that limit the changes to the ones of the entity labels.
+// Changes matching any of the selectors are returned, so if the elements must have any of the labels,
+// there's a selector per label.
+func changeSelectors(entityType config.EntityType, entityLabels []string, labelMatch LabelMatch) []any {
+	if labelMatch == LabelMatchAny && len(entityLabels) > 1 {
+		selectors := make([]any, 0, len(entityLabels))
+		for _, label := range entityLabels {
+			selectors = append(selectors, changeSelectors(entityType, []string{label}, LabelMatchAll)...)
+		}
+
+		return selectors
+	}
+
+	if entityType == config.EntityTypeRelationship {
+		return []any{map[string]any{
+			selectorSelectField: selectRelationships,
+			selectorTypeField:   strings.Join(entityLabels, ":"),
+		}}
+	}
+
+	labels := make([]any, len(entityLabels))
+	for i, label := range entityLabels {
+		labels[i] = label
+	}
+
+	return []any{map[string]any{
+		selectorSelectField: selectNodes,
+		selectorLabelsField: labels,
+	}}
+}
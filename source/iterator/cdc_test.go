@@ -0,0 +1,280 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+)
+
+func TestCDC_changeRecord_node(t *testing.T) {
+	t.Parallel()
+
+	c := &CDC{
+		entityType:    config.EntityTypeNode,
+		entityLabels:  "Person",
+		keyProperties: []string{"id"},
+	}
+
+	tests := []struct {
+		name      string
+		event     map[string]any
+		operation sdk.Operation
+		before    sdk.Data
+		after     sdk.Data
+	}{
+		{
+			name: "create",
+			event: map[string]any{
+				"operation": "c",
+				"state": map[string]any{
+					"before": nil,
+					"after":  map[string]any{"labels": []any{"Person"}, "properties": map[string]any{"id": int64(1)}},
+				},
+			},
+			operation: sdk.OperationCreate,
+			after:     sdk.RawData(`{"id":1}`),
+		},
+		{
+			name: "update",
+			event: map[string]any{
+				"operation": "u",
+				"state": map[string]any{
+					"before": map[string]any{"properties": map[string]any{"id": int64(1), "name": "Alex"}},
+					"after":  map[string]any{"properties": map[string]any{"id": int64(1), "name": "Alice"}},
+				},
+			},
+			operation: sdk.OperationUpdate,
+			before:    sdk.RawData(`{"id":1,"name":"Alex"}`),
+			after:     sdk.RawData(`{"id":1,"name":"Alice"}`),
+		},
+		{
+			name: "delete",
+			event: map[string]any{
+				"operation": "d",
+				"state": map[string]any{
+					"before": map[string]any{"properties": map[string]any{"id": int64(1)}},
+					"after":  nil,
+				},
+			},
+			operation: sdk.OperationDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			changeID, record, err := c.changeRecord(&db.Record{
+				Keys:   []string{changeIDPlaceholder, changeEventPlaceholder},
+				Values: []any{"A1", tt.event},
+			})
+			is.NoErr(err)
+			is.Equal(changeID, "A1")
+			is.Equal(record.Operation, tt.operation)
+			is.Equal(record.Key, sdk.StructuredData{"id": int64(1)})
+			is.Equal(record.Metadata[metadataEntityLabelsField], "Person")
+			is.Equal(record.Payload.Before, tt.before)
+			is.Equal(record.Payload.After, tt.after)
+
+			position, err := ParsePosition(record.Position)
+			is.NoErr(err)
+			is.Equal(position.Mode, ModeCDC)
+			is.Equal(position.ChangeID, "A1")
+		})
+	}
+}
+
+func TestCDC_changeRecord_includeElementID(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	c := &CDC{
+		entityType:       config.EntityTypeNode,
+		entityLabels:     "Person",
+		keyProperties:    []string{"id"},
+		includeElementID: true,
+		elementIDField:   "_elementId",
+	}
+
+	_, record, err := c.changeRecord(&db.Record{
+		Keys: []string{changeIDPlaceholder, changeEventPlaceholder},
+		Values: []any{"A1", map[string]any{
+			"elementId": "4:abc:1",
+			"operation": "c",
+			"state": map[string]any{
+				"after": map[string]any{"properties": map[string]any{"id": int64(1)}},
+			},
+		}},
+	})
+	is.NoErr(err)
+	is.Equal(record.Metadata[metadataElementIDField], "4:abc:1")
+	is.Equal(record.Payload.After, sdk.RawData(`{"_elementId":"4:abc:1","id":1}`))
+}
+
+func TestCDC_eventRecord_relationship(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	c := &CDC{
+		entityType:     config.EntityTypeRelationship,
+		entityLabels:   "KNOWS",
+		keyProperties:  []string{"id"},
+		fieldCollision: FieldCollisionPrefix,
+	}
+
+	event := changeEvent{
+		Operation: operationCreate,
+		Start: changeEndpoint{
+			Labels: []string{"Person"},
+			Keys:   map[string][]map[string]any{"Person": {{"email": "alice@example.com"}}},
+		},
+		End: changeEndpoint{
+			Labels: []string{"Person"},
+			Keys:   map[string][]map[string]any{"Person": {{"email": "bob@example.com"}}},
+		},
+	}
+	event.State.After = &changeState{Properties: map[string]any{"id": int64(1), "since": int64(2020)}}
+
+	record, err := c.eventRecord("A1", event)
+	is.NoErr(err)
+
+	var payload map[string]any
+	is.NoErr(json.Unmarshal(record.Payload.After.Bytes(), &payload))
+	is.Equal(payload, map[string]any{
+		"id":         float64(1),
+		"since":      float64(2020),
+		"sourceNode": map[string]any{"labels": []any{"Person"}, "key": map[string]any{"email": "alice@example.com"}},
+		"targetNode": map[string]any{"labels": []any{"Person"}, "key": map[string]any{"email": "bob@example.com"}},
+	})
+
+	// the state of the change is not modified by the reserved fields
+	is.Equal(len(event.State.After.Properties), 2)
+}
+
+func TestCDC_eventRecord_keyByEndpoints(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	c := &CDC{
+		entityType:     config.EntityTypeRelationship,
+		entityLabels:   "KNOWS",
+		keyProperties:  []string{"id"},
+		keyByEndpoints: true,
+	}
+
+	// the key is composed of the endpoint keys even for deletes that have no state after the change
+	event := changeEvent{
+		Operation: operationDelete,
+		Start: changeEndpoint{
+			Labels: []string{"Person"},
+			Keys:   map[string][]map[string]any{"Person": {{"email": "alice@example.com"}}},
+		},
+		End: changeEndpoint{
+			Labels: []string{"Person"},
+			Keys:   map[string][]map[string]any{"Person": {{"email": "bob@example.com"}}},
+		},
+	}
+	event.State.Before = &changeState{Properties: map[string]any{"id": int64(1)}}
+
+	record, err := c.eventRecord("A1", event)
+	is.NoErr(err)
+	is.Equal(record.Key, sdk.StructuredData{
+		"source_email": "alice@example.com",
+		"target_email": "bob@example.com",
+	})
+}
+
+func TestCDC_eventRecord_includeDeletedState(t *testing.T) {
+	t.Parallel()
+
+	event := changeEvent{Operation: operationDelete}
+	event.State.Before = &changeState{Properties: map[string]any{"id": int64(1), "name": "Alice"}}
+
+	tests := []struct {
+		name                string
+		includeDeletedState bool
+		want                sdk.Data
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:                "enabled",
+			includeDeletedState: true,
+			want:                sdk.RawData(`{"id":1,"name":"Alice"}`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			c := &CDC{
+				entityType:          config.EntityTypeNode,
+				entityLabels:        "Person",
+				keyProperties:       []string{"id"},
+				includeDeletedState: tt.includeDeletedState,
+			}
+
+			record, err := c.eventRecord("A1", event)
+			is.NoErr(err)
+			is.Equal(record.Operation, sdk.OperationDelete)
+			is.Equal(record.Key, sdk.StructuredData{"id": int64(1)})
+			is.Equal(record.Payload.Before, tt.want)
+		})
+	}
+}
+
+func TestCDC_eventRecord_failUnsupportedOperation(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	event := changeEvent{Operation: "x"}
+	event.State.After = &changeState{Properties: map[string]any{"id": int64(1)}}
+
+	_, err := (&CDC{keyProperties: []string{"id"}}).eventRecord("A1", event)
+	is.True(errors.Is(err, errUnsupportedChangeOperation))
+}
+
+func TestChangeSelectors(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.Equal(changeSelectors(config.EntityTypeNode, []string{"Person", "Writer"}, LabelMatchAll),
+		[]any{map[string]any{"select": "n", "labels": []any{"Person", "Writer"}}})
+	is.Equal(changeSelectors(config.EntityTypeRelationship, []string{"KNOWS"}, LabelMatchAll),
+		[]any{map[string]any{"select": "r", "type": "KNOWS"}})
+	is.Equal(changeSelectors(config.EntityTypeNode, []string{"Person", "Writer"}, LabelMatchAny),
+		[]any{
+			map[string]any{"select": "n", "labels": []any{"Person"}},
+			map[string]any{"select": "n", "labels": []any{"Writer"}},
+		})
+}
@@ -0,0 +1,115 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import "fmt"
+
+// PositionStrategy defines how the snapshot resumes capturing elements from a position,
+// i.e. which value of an element is stored in positions of its records,
+// and how elements following the last processed value are selected.
+// The elements are still paginated in the order of the ordering property,
+// or of their element ids, so the strategy must select them following that order.
+type PositionStrategy interface {
+	// NextPosition returns the value stored in the position of a record of an element
+	// by its properties and element id, the value must be JSON-serializable.
+	NextPosition(props map[string]any, elementID string) any
+	// WhereClause returns a Cypher condition referencing the obj that matches elements
+	// following the last processed value, which is passed as the query parameters returned by the Params.
+	WhereClause() string
+	// Params returns the query parameters the WhereClause references by the last processed value,
+	// their names must not collide with the other parameters of snapshot queries, so they should be prefixed.
+	Params(lastProcessedValue any) map[string]any
+}
+
+// orderingPositionStrategy is the default [PositionStrategy],
+// it stores the ordering property values of elements, or their element ids, in positions.
+type orderingPositionStrategy struct {
+	orderingProperty  string
+	orderingDirection OrderingDirection
+	byElementID       bool
+}
+
+// expression returns a Cypher expression the elements are paginated by.
+func (o orderingPositionStrategy) expression() string {
+	if o.byElementID {
+		return elementIDOrderingExpression
+	}
+
+	return objPlaceholder + "." + escapeIdentifier(o.orderingProperty)
+}
+
+// NextPosition returns the element id if elements are paginated by element ids,
+// or the ordering property value otherwise.
+func (o orderingPositionStrategy) NextPosition(props map[string]any, elementID string) any {
+	if o.byElementID {
+		return elementID
+	}
+
+	return props[o.orderingProperty]
+}
+
+// WhereClause matches elements which values follow the last processed value in the ordering direction.
+func (o orderingPositionStrategy) WhereClause() string {
+	return fmt.Sprintf(opvWhereClause, o.expression(), o.orderingDirection.after())
+}
+
+// Params returns the last processed value as the opv parameter.
+func (o orderingPositionStrategy) Params(lastProcessedValue any) map[string]any {
+	return map[string]any{orderingPropertyValueFieldName: lastProcessedValue}
+}
+
+// positionStrategy returns the configured [PositionStrategy] of the snapshot,
+// or the one of the sort keys if they're set, or the default one.
+func (s *Snapshot) positionStrategy() PositionStrategy {
+	switch {
+	case s.customPositionStrategy != nil:
+		return s.customPositionStrategy
+
+	case len(s.sort) > 0:
+		return sortPositionStrategy{keys: s.sort}
+
+	default:
+		return s.orderingPositionStrategy()
+	}
+}
+
+// orderingPositionStrategy returns the default [PositionStrategy] of the snapshot.
+func (s *Snapshot) orderingPositionStrategy() orderingPositionStrategy {
+	return orderingPositionStrategy{
+		orderingProperty:  s.orderingProperty,
+		orderingDirection: s.orderingDirection,
+		byElementID:       s.byElementID,
+	}
+}
+
+// cursorCondition returns a condition that matches elements following the last processed value,
+// and puts the parameters it references into the params, the condition is empty if there's no last processed value.
+func (s *Snapshot) cursorCondition(params map[string]any) (string, error) {
+	lastProcessedValue, err := s.lastProcessedValue()
+	if err != nil {
+		return "", fmt.Errorf("get last processed value: %w", err)
+	}
+
+	if lastProcessedValue == nil {
+		return "", nil
+	}
+
+	strategy := s.positionStrategy()
+	for name, value := range strategy.Params(lastProcessedValue) {
+		params[name] = value
+	}
+
+	return strategy.WhereClause(), nil
+}
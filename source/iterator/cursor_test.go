@@ -0,0 +1,129 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+// compositePositionStrategy is a custom [PositionStrategy] that resumes the capture by a pair
+// of the updatedAt property and the element id, so elements with equal updatedAt values aren't skipped.
+type compositePositionStrategy struct{}
+
+func (compositePositionStrategy) NextPosition(props map[string]any, elementID string) any {
+	return []any{props["updatedAt"], elementID}
+}
+
+func (compositePositionStrategy) WhereClause() string {
+	return "(obj.updatedAt > $cursorUpdatedAt OR " +
+		"(obj.updatedAt = $cursorUpdatedAt AND elementId(obj) > $cursorElementID))"
+}
+
+func (compositePositionStrategy) Params(lastProcessedValue any) map[string]any {
+	values, _ := lastProcessedValue.([]any)
+	if len(values) != 2 {
+		return nil
+	}
+
+	return map[string]any{"cursorUpdatedAt": values[0], "cursorElementID": values[1]}
+}
+
+func TestSnapshot_cursorCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		strategy           PositionStrategy
+		orderingDirection  OrderingDirection
+		lastProcessedValue any
+		wantCondition      string
+		wantParams         map[string]any
+	}{
+		{
+			name:               "success_default",
+			lastProcessedValue: int64(2),
+			wantCondition:      "obj.`updatedAt` > $opv",
+			wantParams:         map[string]any{orderingPropertyValueFieldName: int64(2)},
+		},
+		{
+			name:               "success_default_desc",
+			orderingDirection:  OrderingDirectionDesc,
+			lastProcessedValue: int64(2),
+			wantCondition:      "obj.`updatedAt` < $opv",
+			wantParams:         map[string]any{orderingPropertyValueFieldName: int64(2)},
+		},
+		{
+			name:               "success_custom",
+			strategy:           compositePositionStrategy{},
+			lastProcessedValue: []any{int64(2), "4:abc:1"},
+			wantCondition: "(obj.updatedAt > $cursorUpdatedAt OR " +
+				"(obj.updatedAt = $cursorUpdatedAt AND elementId(obj) > $cursorElementID))",
+			wantParams: map[string]any{"cursorUpdatedAt": int64(2), "cursorElementID": "4:abc:1"},
+		},
+		{
+			name:       "success_no_last_processed_value",
+			strategy:   compositePositionStrategy{},
+			wantParams: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			s := &Snapshot{
+				orderingProperty:       "updatedAt",
+				orderingDirection:      tt.orderingDirection,
+				customPositionStrategy: tt.strategy,
+				position:               &Position{LastProcessedValue: tt.lastProcessedValue},
+			}
+
+			params := make(map[string]any)
+
+			condition, err := s.cursorCondition(params)
+			is.NoErr(err)
+			is.Equal(condition, tt.wantCondition)
+			is.Equal(params, tt.wantParams)
+		})
+	}
+}
+
+func TestSnapshot_Next_customPositionStrategy(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	s := &Snapshot{
+		orderingProperty:       "updatedAt",
+		keyProperties:          []string{"id"},
+		customPositionStrategy: compositePositionStrategy{},
+		records:                make(chan element, 1),
+	}
+
+	s.records <- element{props: map[string]any{"id": int64(1), "updatedAt": int64(5)}, elementID: "4:abc:1"}
+
+	record, err := s.Next(context.Background())
+	is.NoErr(err)
+
+	// the position holds the value returned by the strategy, which is passed back to it on resume
+	position, err := ParsePosition(record.Position)
+	is.NoErr(err)
+	is.Equal(position.LastProcessedValue, []any{float64(5), "4:abc:1"})
+}
@@ -0,0 +1,52 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+// conditions of the degree filters, they're added to the scope conditions of the snapshot of nodes.
+const (
+	rootsOnlyWhereClause    = "NOT EXISTS { (obj)<--() }"
+	leavesOnlyWhereClause   = "NOT EXISTS { (obj)-->() }"
+	isolatedOnlyWhereClause = "NOT EXISTS { (obj)--() }"
+)
+
+// DegreeFilter defines which nodes are captured depending on their relationships.
+type DegreeFilter string
+
+// The available degree filters are listed below.
+const (
+	// DegreeFilterAll captures nodes regardless of their relationships.
+	DegreeFilterAll DegreeFilter = "all"
+	// DegreeFilterRootsOnly captures nodes without incoming relationships.
+	DegreeFilterRootsOnly DegreeFilter = "rootsOnly"
+	// DegreeFilterLeavesOnly captures nodes without outgoing relationships.
+	DegreeFilterLeavesOnly DegreeFilter = "leavesOnly"
+	// DegreeFilterIsolatedOnly captures nodes without any relationships.
+	DegreeFilterIsolatedOnly DegreeFilter = "isolatedOnly"
+)
+
+// condition returns a condition that matches the nodes of the degree filter,
+// it's empty if the nodes aren't filtered.
+func (f DegreeFilter) condition() string {
+	switch f {
+	case DegreeFilterRootsOnly:
+		return rootsOnlyWhereClause
+	case DegreeFilterLeavesOnly:
+		return leavesOnlyWhereClause
+	case DegreeFilterIsolatedOnly:
+		return isolatedOnlyWhereClause
+	default:
+		return ""
+	}
+}
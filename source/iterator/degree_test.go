@@ -0,0 +1,33 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestDegreeFilter_condition(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.Equal(DegreeFilter("").condition(), "")
+	is.Equal(DegreeFilterAll.condition(), "")
+	is.Equal(DegreeFilterRootsOnly.condition(), "NOT EXISTS { (obj)<--() }")
+	is.Equal(DegreeFilterLeavesOnly.condition(), "NOT EXISTS { (obj)-->() }")
+	is.Equal(DegreeFilterIsolatedOnly.condition(), "NOT EXISTS { (obj)--() }")
+}
@@ -0,0 +1,67 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// metadataRelationshipDirectionField is a name of a metadata field that holds the direction
+// of a relationship relative to the node matched as its source, it's set only if the direction isn't outgoing.
+const metadataRelationshipDirectionField = "neo4j.relationshipDirection"
+
+// RelationshipDirection defines the direction in which relationships are matched relative to the src node,
+// which the endpointLabels of the source and filters referencing it constrain.
+type RelationshipDirection string
+
+// The available relationship directions are listed below.
+const (
+	RelationshipDirectionOutgoing RelationshipDirection = "outgoing"
+	RelationshipDirectionIncoming RelationshipDirection = "incoming"
+	RelationshipDirectionBoth     RelationshipDirection = "both"
+)
+
+// arrows returns the left and the right parts of a relationship pattern of the direction,
+// the empty direction is treated as the outgoing one.
+func (d RelationshipDirection) arrows() (string, string) {
+	switch d {
+	case RelationshipDirectionIncoming:
+		return "<-", "-"
+	case RelationshipDirectionBoth:
+		return "-", "-"
+	default:
+		return "-", "->"
+	}
+}
+
+// orientEndpoints returns the start and the end nodes of a relationship matched between the src and trgt nodes,
+// which are swapped if the relationship points to the src, and puts the actual direction into the metadata
+// if the direction isn't outgoing.
+func (d RelationshipDirection) orientEndpoints(
+	relationship dbtype.Relationship, src, trgt dbtype.Node, metadata sdk.Metadata,
+) (dbtype.Node, dbtype.Node) {
+	direction := RelationshipDirectionOutgoing
+	if relationship.StartElementId != src.ElementId {
+		direction = RelationshipDirectionIncoming
+		src, trgt = trgt, src
+	}
+
+	if d == RelationshipDirectionIncoming || d == RelationshipDirectionBoth {
+		metadata[metadataRelationshipDirectionField] = string(direction)
+	}
+
+	return src, trgt
+}
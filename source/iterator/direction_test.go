@@ -0,0 +1,82 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestRelationshipDirection_orientEndpoints(t *testing.T) {
+	t.Parallel()
+
+	alice := dbtype.Node{ElementId: "4:alice", Props: map[string]any{"name": "Alice"}}
+	bob := dbtype.Node{ElementId: "4:bob", Props: map[string]any{"name": "Bob"}}
+	// the relationship points from Bob to Alice
+	relationship := dbtype.Relationship{ElementId: "5:knows", StartElementId: bob.ElementId, EndElementId: alice.ElementId}
+
+	tests := []struct {
+		name          string
+		direction     RelationshipDirection
+		src           dbtype.Node
+		trgt          dbtype.Node
+		wantDirection string
+	}{
+		{
+			name:      "outgoing",
+			direction: RelationshipDirectionOutgoing,
+			src:       bob,
+			trgt:      alice,
+		},
+		{
+			name:          "incoming",
+			direction:     RelationshipDirectionIncoming,
+			src:           alice,
+			trgt:          bob,
+			wantDirection: string(RelationshipDirectionIncoming),
+		},
+		{
+			name:          "both_outgoing",
+			direction:     RelationshipDirectionBoth,
+			src:           bob,
+			trgt:          alice,
+			wantDirection: string(RelationshipDirectionOutgoing),
+		},
+		{
+			name:          "both_incoming",
+			direction:     RelationshipDirectionBoth,
+			src:           alice,
+			trgt:          bob,
+			wantDirection: string(RelationshipDirectionIncoming),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			metadata := make(sdk.Metadata)
+			start, end := tt.direction.orientEndpoints(relationship, tt.src, tt.trgt, metadata)
+			is.Equal(start.ElementId, bob.ElementId)
+			is.Equal(end.ElementId, alice.ElementId)
+			is.Equal(metadata[metadataRelationshipDirectionField], tt.wantDirection)
+		})
+	}
+}
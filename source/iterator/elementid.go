@@ -0,0 +1,45 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// metadataElementIDField is a name of a metadata field that holds the Neo4j element id,
+// it's set only if the element ids are included.
+const metadataElementIDField = "neo4j.elementId"
+
+// ValidateElementIDField checks that the payload field of element ids
+// doesn't collide with the reserved relationship payload-specific fields.
+func ValidateElementIDField(field string) error {
+	if field == sourceNodeField || field == targetNodeField {
+		return fmt.Errorf("%w: %q", ErrReservedPayloadField, field)
+	}
+
+	return nil
+}
+
+// setElementID puts the element id into the metadata, and into the props by the field if it's not empty,
+// where it takes precedence over the property with the same name.
+func setElementID(metadata sdk.Metadata, props map[string]any, elementID, field string) {
+	metadata[metadataElementIDField] = elementID
+
+	if field != "" {
+		props[field] = elementID
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// EmitOrder defines the order in which records of a batch are emitted.
+type EmitOrder string
+
+// The available emit orders are listed below.
+const (
+	EmitOrderOrdering EmitOrder = "ordering"
+	EmitOrderKey      EmitOrder = "key"
+)
+
+// sortByKey sorts the elements of a batch by their record keys.
+// Values of the key fields are compared one by one in the order of the keyProperties,
+// or in the order of the field names if the keys are composed of the endpoint keys.
+func (s *Snapshot) sortByKey(elements []element) error {
+	keys := make([]sdk.StructuredData, len(elements))
+	for i, elem := range elements {
+		key, err := s.recordKey(elem.props)
+		if err != nil {
+			return fmt.Errorf("construct key: %w", err)
+		}
+
+		keys[i] = key
+	}
+
+	indexes := make([]int, len(elements))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	// the sort is stable, so elements with equal keys keep the order of the ordering property
+	slices.SortStableFunc(indexes, func(a, b int) int {
+		for _, field := range s.keyFields(keys[a], keys[b]) {
+			if c := compareKeyValues(keys[a][field], keys[b][field]); c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+
+	sorted := make([]element, len(elements))
+	for i, index := range indexes {
+		sorted[i] = elements[index]
+	}
+
+	copy(elements, sorted)
+
+	return nil
+}
+
+// keyFields returns names of the key fields in the order they're compared in.
+func (s *Snapshot) keyFields(a, b sdk.StructuredData) []string {
+	if !s.keyByEndpoints || s.entityType != config.EntityTypeRelationship {
+		return s.keyProperties
+	}
+
+	fields := make([]string, 0, len(a)+len(b))
+	for field := range a {
+		fields = append(fields, field)
+	}
+
+	for field := range b {
+		if _, ok := a[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+
+	slices.Sort(fields)
+
+	return fields
+}
+
+// compareKeyValues compares values of a key field, numbers are compared numerically,
+// missing values precede present ones, and values of other types are compared by their string forms.
+func compareKeyValues(a, b any) int {
+	if a == nil || b == nil {
+		return cmp.Compare(boolRank(a != nil), boolRank(b != nil))
+	}
+
+	if numberA, ok := keyNumber(a); ok {
+		if numberB, ok := keyNumber(b); ok {
+			return cmp.Compare(numberA, numberB)
+		}
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// keyNumber returns the value as a float64 if it's a number.
+func keyNumber(value any) (float64, bool) {
+	switch value := value.(type) {
+	case int64:
+		return float64(value), true
+
+	case float64:
+		return value, true
+
+	default:
+		return 0, false
+	}
+}
+
+// boolRank returns 1 for true and 0 for false, so booleans can be compared.
+func boolRank(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
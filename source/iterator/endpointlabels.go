@@ -0,0 +1,110 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// relationshipTypeWhereClause matches relationships of a type, it's joined with the endpoint label conditions
+	// with AND, and the conditions of all types are joined with OR.
+	relationshipTypeWhereClause = "type(obj) = $%s"
+	sourceLabelsWhereClause     = "src:%s"
+	targetLabelsWhereClause     = "trgt:%s"
+	// endpointLabelsTypeFieldName is a prefix of the query parameters that hold the relationship types.
+	endpointLabelsTypeFieldName = "endpointLabelsType"
+
+	// CDC relationship selector fields of the endpoints.
+	selectorStartField = "start"
+	selectorEndField   = "end"
+)
+
+// EndpointLabels holds the labels the source and target nodes of relationships of a type must have,
+// the empty Source or Target doesn't constrain the endpoint.
+type EndpointLabels struct {
+	Source []string
+	Target []string
+}
+
+// endpointLabelsCondition returns a condition that matches relationships which endpoints have the labels
+// of their type, e.g. "((type(obj) = $endpointLabelsType0 AND src:`Person` AND trgt:`Company`) OR ...)",
+// along with the parameters that hold the types. Relationships of the types without endpoint labels
+// match regardless of their endpoints, and the condition is empty if none of the types has them.
+func endpointLabelsCondition(types []string, endpointLabels map[string]EndpointLabels) (string, map[string]any) {
+	if len(endpointLabels) == 0 {
+		return "", nil
+	}
+
+	conditions := make([]string, len(types))
+	params := make(map[string]any, len(types))
+	for i, relationshipType := range types {
+		name := fmt.Sprintf("%s%d", endpointLabelsTypeFieldName, i)
+		params[name] = relationshipType
+
+		typeConditions := []string{fmt.Sprintf(relationshipTypeWhereClause, name)}
+
+		labels := endpointLabels[relationshipType]
+		if len(labels.Source) > 0 {
+			typeConditions = append(typeConditions, fmt.Sprintf(sourceLabelsWhereClause, cypherLabels(labels.Source)))
+		}
+
+		if len(labels.Target) > 0 {
+			typeConditions = append(typeConditions, fmt.Sprintf(targetLabelsWhereClause, cypherLabels(labels.Target)))
+		}
+
+		conditions[i] = "(" + strings.Join(typeConditions, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", params
+}
+
+// withEndpointLabels adds the endpoint labels of the relationship type to the CDC relationship selectors,
+// e.g. {"select": "r", "type": "WORKS_AT", "start": {"labels": ["Person"]}, "end": {"labels": ["Company"]}}.
+func withEndpointLabels(selectors []any, endpointLabels map[string]EndpointLabels) []any {
+	for _, selector := range selectors {
+		fields, ok := selector.(map[string]any)
+		if !ok || fields[selectorSelectField] != selectRelationships {
+			continue
+		}
+
+		relationshipType, _ := fields[selectorTypeField].(string)
+		labels, ok := endpointLabels[relationshipType]
+		if !ok {
+			continue
+		}
+
+		if len(labels.Source) > 0 {
+			fields[selectorStartField] = map[string]any{selectorLabelsField: anySlice(labels.Source)}
+		}
+
+		if len(labels.Target) > 0 {
+			fields[selectorEndField] = map[string]any{selectorLabelsField: anySlice(labels.Target)}
+		}
+	}
+
+	return selectors
+}
+
+// anySlice converts the strings into a slice of any, as the CDC selectors are passed as query parameters.
+func anySlice(values []string) []any {
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+
+	return result
+}
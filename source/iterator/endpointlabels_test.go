@@ -0,0 +1,71 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/matryer/is"
+)
+
+func TestEndpointLabelsCondition(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// the types have different endpoint labels, and the LIKES type isn't constrained
+	condition, params := endpointLabelsCondition([]string{"WORKS_AT", "KNOWS", "LIKES"}, map[string]EndpointLabels{
+		"WORKS_AT": {Source: []string{"Person"}, Target: []string{"Company"}},
+		"KNOWS":    {Target: []string{"Person", "Writer"}},
+	})
+	is.Equal(condition, "((type(obj) = $endpointLabelsType0 AND src:`Person` AND trgt:`Company`) OR "+
+		"(type(obj) = $endpointLabelsType1 AND trgt:`Person`:`Writer`) OR "+
+		"(type(obj) = $endpointLabelsType2))")
+	is.Equal(params, map[string]any{
+		"endpointLabelsType0": "WORKS_AT",
+		"endpointLabelsType1": "KNOWS",
+		"endpointLabelsType2": "LIKES",
+	})
+
+	condition, params = endpointLabelsCondition([]string{"KNOWS"}, nil)
+	is.Equal(condition, "")
+	is.Equal(len(params), 0)
+}
+
+func TestWithEndpointLabels(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	selectors := withEndpointLabels(
+		changeSelectors(config.EntityTypeRelationship, []string{"WORKS_AT", "KNOWS"}, LabelMatchAny),
+		map[string]EndpointLabels{
+			"WORKS_AT": {Source: []string{"Person"}, Target: []string{"Company"}},
+		},
+	)
+	is.Equal(selectors, []any{
+		map[string]any{
+			selectorSelectField: selectRelationships,
+			selectorTypeField:   "WORKS_AT",
+			selectorStartField:  map[string]any{selectorLabelsField: []any{"Person"}},
+			selectorEndField:    map[string]any{selectorLabelsField: []any{"Company"}},
+		},
+		map[string]any{
+			selectorSelectField: selectRelationships,
+			selectorTypeField:   "KNOWS",
+		},
+	})
+}
@@ -21,6 +21,38 @@ var (
 	// It's just a sentinel error for the [parsePosition] function.
 	ErrNilSDKPosition = errors.New("nil sdk position")
 
+	// ErrUnsupportedPositionVersion occurs when trying to parse a position
+	// which version is newer than the connector supports.
+	ErrUnsupportedPositionVersion = errors.New("unsupported position version")
+
+	// ErrPositionMismatch occurs when a position was created
+	// for a different ordering property or entity labels than the configured ones.
+	ErrPositionMismatch = errors.New("position doesn't match the config")
+
+	// ErrReservedFilterParam occurs when a filter or its params use
+	// a query parameter that is reserved by the snapshot.
+	ErrReservedFilterParam = errors.New("filter uses a reserved query parameter")
+
+	// ErrIndexNotFound occurs when the snapshot is hinted to use an index of the ordering property
+	// but there's no range index of it.
+	ErrIndexNotFound = errors.New("range index not found")
+
+	// ErrInvalidProjection occurs when a projection has no name or expression,
+	// uses a reserved field name, or Neo4j cannot plan its expression.
+	ErrInvalidProjection = errors.New("invalid projection")
+
+	// ErrReservedPayloadField occurs when the payload field of element ids
+	// is one of the reserved relationship payload-specific fields.
+	ErrReservedPayloadField = errors.New("payload field is reserved")
+
+	// ErrInvalidSort occurs when a sort spec has a key without a property, with an unsupported direction,
+	// or with a repeated property.
+	ErrInvalidSort = errors.New("invalid sort")
+
+	// ErrUnsupportedMetadataField occurs when an optional metadata field isn't supported,
+	// see the [ValidateMetadataFields].
+	ErrUnsupportedMetadataField = errors.New("unsupported metadata field")
+
 	// errNoElements occurs when trying to read elements
 	// but Neo4j returns nothing.
 	errNoElements = errors.New("no elements")
@@ -33,6 +65,14 @@ var (
 	// and this process fails.
 	errConvertRawRelationship = errors.New("cannot convert raw element to dbtype relationship")
 
+	// errReservedFieldCollision occurs when a relationship property collides
+	// with the reserved sourceNode or targetNode fields and the field collision behavior is error.
+	errReservedFieldCollision = errors.New("relationship property collides with a reserved field")
+
+	// errUnsupportedWindowType occurs when the changed within window is used
+	// with an ordering property which type is not a date or a date-time.
+	errUnsupportedWindowType = errors.New("ordering property type is not supported by the window")
+
 	// neo4jNoMoreRecordsErrorMessage is a message
 	// that Neo4j returns when it cannot find records.
 	neo4jNoMoreRecordsErrorMessage = "Result contains no more records"
@@ -0,0 +1,51 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// reservedParams holds names of the query parameters that are set by the [Snapshot],
+// so a filter cannot reference or override them.
+var reservedParams = []string{
+	orderingPropertyMaxValueFieldName,
+	orderingPropertyValueFieldName,
+	shardCountFieldName,
+	shardIndexFieldName,
+}
+
+// reservedParamReference matches references to the reserved parameters in a filter,
+// including the ones escaped with backticks.
+var reservedParamReference = regexp.MustCompile(fmt.Sprintf(
+	"\\$`?(%s|%s|%s|%s)\\b", orderingPropertyMaxValueFieldName, orderingPropertyValueFieldName,
+	shardCountFieldName, shardIndexFieldName,
+))
+
+// ValidateFilter checks that the filter and its params don't use the reserved query parameters.
+func ValidateFilter(filter string, params map[string]string) error {
+	if match := reservedParamReference.FindStringSubmatch(filter); match != nil {
+		return fmt.Errorf("%w: the filter references $%s", ErrReservedFilterParam, match[1])
+	}
+
+	for _, name := range reservedParams {
+		if _, ok := params[name]; ok {
+			return fmt.Errorf("%w: the filter params contain %q", ErrReservedFilterParam, name)
+		}
+	}
+
+	return nil
+}
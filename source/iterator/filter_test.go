@@ -0,0 +1,68 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		filter  string
+		params  map[string]string
+		wantErr error
+	}{
+		{
+			name:   "success",
+			filter: "obj.status = $status OR obj.active = true",
+			params: map[string]string{"status": "active"},
+		},
+		{
+			name:   "success_similar_param",
+			filter: "obj.score > $opvMin",
+			params: map[string]string{"opvMin": "1"},
+		},
+		{
+			name:    "fail_reference",
+			filter:  "obj.id > $opv",
+			wantErr: ErrReservedFilterParam,
+		},
+		{
+			name:    "fail_escaped_reference",
+			filter:  "obj.id <> $`opmv`",
+			wantErr: ErrReservedFilterParam,
+		},
+		{
+			name:    "fail_params",
+			filter:  "obj.active = true",
+			params:  map[string]string{"shardIndex": "1"},
+			wantErr: ErrReservedFilterParam,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := ValidateFilter(tt.filter, tt.params); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
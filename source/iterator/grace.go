@@ -0,0 +1,174 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// graceKeySeparator separates an element id from its ordering property value in keys of the seen elements.
+const graceKeySeparator = "@"
+
+// lastProcessedValue returns the value the snapshot loads elements after.
+// If the resumeGrace is set, the polling pages from the start of the grace window behind the last processed value,
+// so elements committed with ordering property values preceding the ones captured before are captured too.
+func (s *Snapshot) lastProcessedValue() (any, error) {
+	if s.resumeGrace > 0 && s.graceCursor != nil {
+		return s.graceCursor, nil
+	}
+
+	if s.position == nil || s.position.LastProcessedValue == nil {
+		return nil, nil
+	}
+
+	if s.resumeGrace == 0 {
+		return s.position.LastProcessedValue, nil
+	}
+
+	lastTime, err := temporalTime(s.position.LastProcessedValue)
+	if err != nil {
+		return nil, fmt.Errorf("convert last processed value: %w", err)
+	}
+
+	start, err := graceStart(s.position.LastProcessedValue, s.resumeGrace)
+	if err != nil {
+		return nil, fmt.Errorf("get grace window start: %w", err)
+	}
+
+	startTime, err := temporalTime(start)
+	if err != nil {
+		return nil, fmt.Errorf("convert grace window start: %w", err)
+	}
+
+	// the elements preceding the window won't be read again, so there's no need to remember them
+	for key, seenTime := range s.graceSeen {
+		if seenTime.Before(startTime) {
+			delete(s.graceSeen, key)
+		}
+	}
+
+	if !s.graceSeeded {
+		s.graceBaseline = &lastTime
+	}
+
+	s.graceCursor = start
+
+	return start, nil
+}
+
+// skipSeen moves the grace cursor to the end of the loaded elements and returns the ones that weren't emitted
+// before with the same ordering property value. An empty batch ends the poll, so the next one starts
+// from the grace window again.
+func (s *Snapshot) skipSeen(elements []element) ([]element, error) {
+	if len(elements) == 0 {
+		s.graceCursor = nil
+		s.graceBaseline = nil
+		s.graceSeeded = true
+
+		return elements, nil
+	}
+
+	s.graceCursor = elements[len(elements)-1].props[s.orderingProperty]
+
+	unseen := make([]element, 0, len(elements))
+	for _, elem := range elements {
+		valueTime, err := temporalTime(elem.props[s.orderingProperty])
+		if err != nil {
+			return nil, fmt.Errorf("convert ordering property value: %w", err)
+		}
+
+		// the element changed since it was emitted if its ordering property value differs
+		key := elem.elementID + graceKeySeparator + strconv.FormatInt(valueTime.UnixNano(), 10)
+		if _, ok := s.graceSeen[key]; ok {
+			continue
+		}
+
+		s.graceSeen[key] = valueTime
+
+		// the first poll only remembers the elements up to the value it started with
+		if s.graceBaseline != nil && !valueTime.After(*s.graceBaseline) {
+			continue
+		}
+
+		unseen = append(unseen, elem)
+	}
+
+	return unseen, nil
+}
+
+// latestValue returns the value of the element unless it precedes the last processed value,
+// which happens to elements captured within the grace window, so positions never move backwards.
+func (s *Snapshot) latestValue(value any) any {
+	if s.position == nil || s.position.LastProcessedValue == nil {
+		return value
+	}
+
+	valueTime, err := temporalTime(value)
+	if err != nil {
+		return value
+	}
+
+	lastTime, err := temporalTime(s.position.LastProcessedValue)
+	if err != nil || !valueTime.Before(lastTime) {
+		return value
+	}
+
+	return s.position.LastProcessedValue
+}
+
+// graceStart returns the start of the grace window that ends at the value,
+// converted to the type of the value. Values restored from positions are RFC 3339 strings,
+// so they're converted to date-times.
+func graceStart(value any, grace time.Duration) (any, error) {
+	valueTime, err := temporalTime(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := value.(string); ok {
+		value = valueTime
+	}
+
+	return windowStart(value, valueTime, grace)
+}
+
+// temporalTime converts a temporal ordering property value into a [time.Time].
+func temporalTime(value any) (time.Time, error) {
+	switch value := value.(type) {
+	case time.Time:
+		return value, nil
+
+	case dbtype.LocalDateTime:
+		return time.Time(value), nil
+
+	case dbtype.Date:
+		return time.Time(value), nil
+
+	case string:
+		valueTime, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %q", errUnsupportedWindowType, value)
+		}
+
+		return valueTime, nil
+
+	default:
+		return time.Time{}, fmt.Errorf("%w: %T", errUnsupportedWindowType, value)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestGraceStart(t *testing.T) {
+	t.Parallel()
+
+	var (
+		grace  = 10 * time.Second
+		berlin = time.FixedZone("Europe/Berlin", 60*60)
+	)
+
+	tests := []struct {
+		name    string
+		value   any
+		want    any
+		wantErr error
+	}{
+		{
+			name:  "success_date_time",
+			value: time.Date(2024, 3, 10, 10, 0, 0, 0, berlin),
+			want:  time.Date(2024, 3, 10, 9, 59, 50, 0, berlin),
+		},
+		{
+			name:  "success_position_string",
+			value: "2024-03-10T10:00:00Z",
+			want:  time.Date(2024, 3, 10, 9, 59, 50, 0, time.UTC),
+		},
+		{
+			name:    "fail_integer",
+			value:   int64(1),
+			wantErr: errUnsupportedWindowType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			got, err := graceStart(tt.value, grace)
+			is.True(errors.Is(err, tt.wantErr))
+
+			if tt.wantErr == nil {
+				is.True(got.(time.Time).Equal(tt.want.(time.Time)))
+			}
+		})
+	}
+}
+
+func TestSnapshot_skipSeen(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	var (
+		base = time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+		late = base.Add(-5 * time.Second)
+	)
+
+	snapshot := &Snapshot{
+		orderingProperty: "updatedAt",
+		resumeGrace:      time.Minute,
+		graceSeen:        make(map[string]time.Time),
+		position:         &Position{LastProcessedValue: base},
+	}
+
+	// the first poll starts at the grace window and only remembers the elements up to the last processed value
+	cursor, err := snapshot.lastProcessedValue()
+	is.NoErr(err)
+	is.Equal(cursor, base.Add(-time.Minute))
+
+	elements, err := snapshot.skipSeen([]element{
+		{elementID: "1", props: map[string]any{"updatedAt": base}},
+		{elementID: "2", props: map[string]any{"updatedAt": base.Add(time.Second)}},
+	})
+	is.NoErr(err)
+	is.Equal(len(elements), 1)
+	is.Equal(elements[0].elementID, "2")
+	is.Equal(snapshot.graceCursor, base.Add(time.Second))
+
+	elements, err = snapshot.skipSeen(nil)
+	is.NoErr(err)
+	is.Equal(len(elements), 0)
+	is.Equal(snapshot.graceCursor, nil)
+
+	// the next poll skips the elements emitted before, but captures the late one and the changed one
+	snapshot.position = &Position{LastProcessedValue: base.Add(time.Second)}
+
+	_, err = snapshot.lastProcessedValue()
+	is.NoErr(err)
+
+	elements, err = snapshot.skipSeen([]element{
+		{elementID: "3", props: map[string]any{"updatedAt": late}},
+		{elementID: "1", props: map[string]any{"updatedAt": base}},
+		{elementID: "2", props: map[string]any{"updatedAt": base.Add(time.Second)}},
+		{elementID: "1", props: map[string]any{"updatedAt": base.Add(2 * time.Second)}},
+	})
+	is.NoErr(err)
+	is.Equal(len(elements), 2)
+	is.Equal(elements[0].elementID, "3")
+	is.Equal(elements[1].elementID, "1")
+
+	// the position doesn't move backwards to the late element
+	is.Equal(snapshot.latestValue(late), base.Add(time.Second))
+	is.Equal(snapshot.latestValue(base.Add(2*time.Second)), base.Add(2*time.Second))
+}
@@ -0,0 +1,64 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"encoding/json"
+)
+
+// decodeHistory normalizes a history property maintained by APOC triggers into a list of entries.
+//
+// Neo4j cannot store maps as property values, so triggers usually store history entries as JSON strings.
+// If the historyDecodeJSON is true, such entries are decoded, entries that are not valid JSON are kept as is.
+// A single value is wrapped into a list, so the history property is always a list in the payload.
+func (s *Snapshot) decodeHistory(props map[string]any) {
+	if s.historyProperty == "" {
+		return
+	}
+
+	value, ok := props[s.historyProperty]
+	if !ok || value == nil {
+		return
+	}
+
+	entries, ok := value.([]any)
+	if !ok {
+		entries = []any{value}
+	}
+
+	if s.historyDecodeJSON {
+		for i, entry := range entries {
+			entries[i] = decodeHistoryEntry(entry)
+		}
+	}
+
+	props[s.historyProperty] = entries
+}
+
+// decodeHistoryEntry decodes a JSON string history entry,
+// if the entry is not a string or not valid JSON, it's returned as is.
+func decodeHistoryEntry(entry any) any {
+	raw, ok := entry.(string)
+	if !ok {
+		return entry
+	}
+
+	var decoded any
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return entry
+	}
+
+	return decoded
+}
@@ -0,0 +1,102 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSnapshot_decodeHistory(t *testing.T) {
+	t.Parallel()
+
+	// simulate a history property maintained by an APOC trigger
+	history := func() []any {
+		return []any{
+			`{"property":"name","old":null,"new":"Alex","changedAt":1700000000000}`,
+			`{"property":"name","old":"Alex","new":"Alexander","changedAt":1700000001000}`,
+		}
+	}
+
+	tests := []struct {
+		name              string
+		historyProperty   string
+		historyDecodeJSON bool
+		props             map[string]any
+		want              map[string]any
+	}{
+		{
+			name:              "disabled",
+			historyDecodeJSON: true,
+			props:             map[string]any{"_history": history()},
+			want:              map[string]any{"_history": history()},
+		},
+		{
+			name:              "decode_json",
+			historyProperty:   "_history",
+			historyDecodeJSON: true,
+			props:             map[string]any{"name": "Alexander", "_history": history()},
+			want: map[string]any{
+				"name": "Alexander",
+				"_history": []any{
+					map[string]any{"property": "name", "old": nil, "new": "Alex", "changedAt": float64(1700000000000)},
+					map[string]any{
+						"property": "name", "old": "Alex", "new": "Alexander", "changedAt": float64(1700000001000),
+					},
+				},
+			},
+		},
+		{
+			name:            "keep_raw",
+			historyProperty: "_history",
+			props:           map[string]any{"_history": history()},
+			want:            map[string]any{"_history": history()},
+		},
+		{
+			name:              "wrap_single_value",
+			historyProperty:   "_history",
+			historyDecodeJSON: true,
+			props:             map[string]any{"_history": `{"property":"name"}`},
+			want:              map[string]any{"_history": []any{map[string]any{"property": "name"}}},
+		},
+		{
+			name:              "keep_invalid_json",
+			historyProperty:   "_history",
+			historyDecodeJSON: true,
+			props:             map[string]any{"_history": []any{"created", int64(1)}},
+			want:              map[string]any{"_history": []any{"created", int64(1)}},
+		},
+		{
+			name:              "missing_property",
+			historyProperty:   "_history",
+			historyDecodeJSON: true,
+			props:             map[string]any{"name": "Alex"},
+			want:              map[string]any{"name": "Alex"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Snapshot{historyProperty: tt.historyProperty, historyDecodeJSON: tt.historyDecodeJSON}
+			s.decodeHistory(tt.props)
+
+			if !reflect.DeepEqual(tt.props, tt.want) {
+				t.Errorf("decodeHistory() = %v, want %v", tt.props, tt.want)
+			}
+		})
+	}
+}
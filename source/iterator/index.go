@@ -0,0 +1,78 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	// rangeIndexQuery returns range indexes of a single property of nodes with a label or relationships with a type.
+	rangeIndexQuery = `
+	SHOW RANGE INDEXES YIELD entityType, labelsOrTypes, properties, state
+	WHERE entityType = $entityType AND labelsOrTypes = [$label] AND properties = [$property]
+	RETURN state`
+	// indexHintClause is a hint that makes the planner use the index of the ordering property,
+	// it's appended to the match clause of the snapshot queries.
+	indexHintClause = " USING INDEX obj:%s(%s)"
+
+	// index entity types returned by the rangeIndexQuery.
+	nodeIndexEntityType         = "NODE"
+	relationshipIndexEntityType = "RELATIONSHIP"
+)
+
+// VerifyIndex checks that a range index of the property exists for the label,
+// which is the first entity label of nodes or the relationship type,
+// so the snapshot can page elements by the property using the index hint.
+func VerifyIndex(
+	ctx context.Context,
+	driver neo4j.DriverWithContext,
+	database, label, property string,
+	entityType config.EntityType,
+) error {
+	indexEntityType := nodeIndexEntityType
+	if entityType == config.EntityTypeRelationship {
+		indexEntityType = relationshipIndexEntityType
+	}
+
+	result, err := neo4j.ExecuteQuery(ctx, driver, rangeIndexQuery,
+		map[string]any{"entityType": indexEntityType, "label": label, "property": property},
+		neo4j.EagerResultTransformer,
+		neo4j.ExecuteQueryWithDatabase(database),
+		neo4j.ExecuteQueryWithReadersRouting(),
+	)
+	if err != nil {
+		return fmt.Errorf("execute query: %w", err)
+	}
+
+	if len(result.Records) == 0 {
+		return fmt.Errorf("%w: %s(%s)", ErrIndexNotFound, label, property)
+	}
+
+	return nil
+}
+
+// indexHint returns the index hint of the ordering property for the first entity label.
+func indexHint(labels []string, property string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(indexHintClause, escapeIdentifier(labels[0]), escapeIdentifier(property))
+}
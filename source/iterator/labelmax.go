@@ -0,0 +1,128 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxLabelQueries is the max number of the per-label max value queries running at once,
+// so elements with many labels don't exhaust the connection pool.
+const maxLabelQueries = 4
+
+// anyLabelMaxPropertyValue returns the last ordering property value in the direction among the elements
+// having any of the entity labels. Instead of a single query matching elements by a disjunction of the labels,
+// which can't use the indexes of the labels, it runs a query per label, up to the maxLabelQueries at once,
+// and returns the last of their values. It returns the errNoElements if none of the labels has elements.
+func anyLabelMaxPropertyValue(ctx context.Context, params SnapshotParams) (any, error) {
+	labels := config.NormalizeLabels(params.EntityLabels)
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxLabelQueries)
+
+	var (
+		mu     sync.Mutex
+		values []any
+	)
+
+	for _, label := range labels {
+		group.Go(func() error {
+			// the bookmarks aren't safe for concurrent use, so each query waits for a copy of them
+			var bookmarks *Bookmarks
+			if params.Bookmarks != nil {
+				bookmarks = NewBookmarks(params.Bookmarks.list())
+			}
+
+			value, err := getMaxPropertyValue(groupCtx, params.Driver,
+				params.DatabaseName, []string{label}, LabelMatchAll, params.OrderingProperty,
+				params.EntityType, params.OrderingDirection, bookmarks, params.QueryTimeout,
+			)
+			if err != nil {
+				if errors.Is(err, errNoElements) {
+					return nil
+				}
+
+				return fmt.Errorf("get max value of label %q: %w", label, err)
+			}
+
+			mu.Lock()
+			values = append(values, value)
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, fmt.Errorf("wait for label queries: %w", err)
+	}
+
+	if len(values) == 0 {
+		return nil, errNoElements
+	}
+
+	last := values[0]
+	for _, value := range values[1:] {
+		comparison := compareOrderingValues(value, last)
+		if params.OrderingDirection == OrderingDirectionDesc {
+			comparison = -comparison
+		}
+
+		if comparison > 0 {
+			last = value
+		}
+	}
+
+	return last, nil
+}
+
+// compareOrderingValues compares values of the ordering property, numbers are compared numerically,
+// strings lexicographically, temporal values chronologically,
+// and values of other types are compared by their string forms.
+func compareOrderingValues(a, b any) int {
+	if stringA, ok := a.(string); ok {
+		if stringB, ok := b.(string); ok {
+			return strings.Compare(stringA, stringB)
+		}
+	}
+
+	if timeA, ok := orderingTime(a); ok {
+		if timeB, ok := orderingTime(b); ok {
+			return timeA.Compare(timeB)
+		}
+	}
+
+	return compareKeyValues(a, b)
+}
+
+// orderingTime converts a temporal ordering property value into a [time.Time],
+// strings aren't converted, as Neo4j compares them lexicographically.
+func orderingTime(value any) (time.Time, bool) {
+	if _, ok := value.(string); ok {
+		return time.Time{}, false
+	}
+
+	valueTime, err := temporalTime(value)
+
+	return valueTime, err == nil
+}
@@ -0,0 +1,182 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/mock"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/mock/gomock"
+)
+
+// labelMaxQueries records the max value queries and the max number of them running at once.
+type labelMaxQueries struct {
+	mu       sync.Mutex
+	queries  []string
+	running  atomic.Int32
+	inFlight atomic.Int32
+}
+
+// newLabelMaxDriver returns a driver that answers the max value queries with the values of the labels they match,
+// and records them in the queries.
+func newLabelMaxDriver(ctrl *gomock.Controller, values map[string]any, queries *labelMaxQueries) *mock.MockDriver {
+	tx, txMock := mock.NewManagedTransaction(ctrl)
+	txMock.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, query string, _ map[string]any) (neo4j.ResultWithContext, error) {
+			queries.mu.Lock()
+			queries.queries = append(queries.queries, query)
+			queries.mu.Unlock()
+
+			result, resultMock := mock.NewResult(ctrl)
+			for label, value := range values {
+				if strings.Contains(query, "(obj:`"+label+"`)") {
+					resultMock.EXPECT().Single(gomock.Any()).
+						Return(&neo4j.Record{Keys: []string{"createdAt"}, Values: []any{value}}, nil)
+
+					return result, nil
+				}
+			}
+
+			resultMock.EXPECT().Single(gomock.Any()).
+				Return(nil, &neo4j.UsageError{Message: neo4jNoMoreRecordsErrorMessage})
+
+			return result, nil
+		},
+	).AnyTimes()
+
+	session, sessionMock := mock.NewSession(ctrl)
+	sessionMock.EXPECT().ExecuteRead(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, work neo4j.ManagedTransactionWork, _ ...func(*neo4j.TransactionConfig)) (any, error) {
+			running := queries.running.Add(1)
+			defer queries.running.Add(-1)
+
+			for {
+				inFlight := queries.inFlight.Load()
+				if running <= inFlight || queries.inFlight.CompareAndSwap(inFlight, running) {
+					break
+				}
+			}
+
+			// the queries overlap, so the limit of the ones running at once is checked
+			time.Sleep(10 * time.Millisecond)
+
+			return work(tx)
+		},
+	).AnyTimes()
+	sessionMock.EXPECT().Close(gomock.Any()).Return(nil).AnyTimes()
+
+	driver := mock.NewMockDriver(ctrl)
+	driver.EXPECT().NewSession(gomock.Any(), gomock.Any()).Return(session).AnyTimes()
+
+	return driver
+}
+
+func TestMaxPropertyValue_anyLabel(t *testing.T) {
+	t.Parallel()
+
+	labels := []string{"Person", "Company", "Country", "City", "Street", "Empty"}
+	values := map[string]any{
+		"Person":  int64(7),
+		"Company": int64(42),
+		"Country": int64(3),
+		"City":    int64(15),
+		"Street":  int64(41),
+	}
+
+	tests := []struct {
+		name      string
+		direction OrderingDirection
+		want      any
+	}{
+		{
+			name:      "asc",
+			direction: OrderingDirectionAsc,
+			want:      int64(42),
+		},
+		{
+			name:      "desc",
+			direction: OrderingDirectionDesc,
+			want:      int64(3),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+			ctrl := gomock.NewController(t)
+
+			queries := &labelMaxQueries{}
+
+			got, err := maxPropertyValue(context.Background(), SnapshotParams{
+				Driver:            newLabelMaxDriver(ctrl, values, queries),
+				OrderingProperty:  "createdAt",
+				OrderingDirection: tt.direction,
+				EntityType:        config.EntityTypeNode,
+				EntityLabels:      labels,
+				LabelMatch:        LabelMatchAny,
+			})
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+
+			// each label is queried on its own, and no more than the limit of queries run at once
+			is.Equal(len(queries.queries), len(labels))
+			for _, query := range queries.queries {
+				is.True(!strings.Contains(query, " OR "))
+			}
+
+			is.True(queries.inFlight.Load() <= maxLabelQueries)
+		})
+	}
+}
+
+func TestMaxPropertyValue_anyLabelNoElements(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctrl := gomock.NewController(t)
+
+	_, err := maxPropertyValue(context.Background(), SnapshotParams{
+		Driver:           newLabelMaxDriver(ctrl, nil, &labelMaxQueries{}),
+		OrderingProperty: "createdAt",
+		EntityType:       config.EntityTypeNode,
+		EntityLabels:     []string{"Person", "Company"},
+		LabelMatch:       LabelMatchAny,
+	})
+	is.True(errors.Is(err, errNoElements))
+}
+
+func TestCompareOrderingValues(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	now := time.Now()
+
+	is.Equal(compareOrderingValues(int64(2), 1.5), 1)
+	is.Equal(compareOrderingValues("b", "a"), 1)
+	is.Equal(compareOrderingValues(now, now.Add(time.Hour)), -1)
+	is.Equal(compareOrderingValues(now.In(time.FixedZone("UTC+5", 5*60*60)), now), 0)
+}
@@ -0,0 +1,74 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// metadataSnapshotCompleteField is a name of a metadata field that marks the record emitted once the snapshot
+// is complete, see the [SnapshotCompleteRecord].
+const metadataSnapshotCompleteField = "neo4j.snapshotComplete"
+
+// SnapshotCompleteRecord returns a marker record that signals the snapshot is complete and the capture of changes
+// begins. It's a snapshot record without a key and a payload, with the metadataSnapshotCompleteField set to "true".
+func SnapshotCompleteRecord(position sdk.Position, entityLabels []string) sdk.Record {
+	metadata := sdk.Metadata{
+		metadataSnapshotCompleteField: "true",
+		metadataEntityLabelsField:     strings.Join(entityLabels, ":"),
+	}
+	metadata.SetCreatedAt(time.Now())
+
+	return sdk.Util.Source.NewRecordSnapshot(position, metadata, nil, nil)
+}
+
+// CurrentPosition returns the position of the last emitted record,
+// or the position the iterator starts from if no records were emitted.
+func (s *Snapshot) CurrentPosition() (sdk.Position, error) {
+	var lastProcessedValue any
+	if s.position != nil {
+		lastProcessedValue = s.position.LastProcessedValue
+	}
+
+	sdkPosition, err := s.newPosition(lastProcessedValue).MarshalSDKPosition()
+	if err != nil {
+		return nil, fmt.Errorf("marshal sdk position: %w", err)
+	}
+
+	return sdkPosition, nil
+}
+
+// CurrentPosition returns the position after the last loaded change, or the position the capture starts from
+// if no changes were loaded. It's the position of the last emitted record while no loaded records are pending.
+func (c *CDC) CurrentPosition() (sdk.Position, error) {
+	position := &Position{
+		Version:      PositionVersion,
+		Mode:         ModeCDC,
+		ChangeID:     c.changeID,
+		EntityLabels: c.labels,
+		Bookmarks:    c.bookmarks.list(),
+	}
+
+	sdkPosition, err := position.MarshalSDKPosition()
+	if err != nil {
+		return nil, fmt.Errorf("marshal sdk position: %w", err)
+	}
+
+	return sdkPosition, nil
+}
@@ -0,0 +1,59 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+func TestSnapshotCompleteRecord(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	position := sdk.Position(`{"version":1,"mode":"cdc","changeId":"A1"}`)
+
+	record := SnapshotCompleteRecord(position, []string{"Person", "Writer"})
+	is.Equal(record.Operation, sdk.OperationSnapshot)
+	is.Equal(record.Position, position)
+	is.Equal(record.Metadata[metadataSnapshotCompleteField], "true")
+	is.Equal(record.Metadata[metadataEntityLabelsField], "Person:Writer")
+	is.Equal(record.Key, nil)
+	is.Equal(record.Payload.After, nil)
+}
+
+func TestCDC_CurrentPosition(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	c := &CDC{changeID: "A1", labels: []string{"Person"}, bookmarks: NewBookmarks([]string{"FB:1"})}
+
+	sdkPosition, err := c.CurrentPosition()
+	is.NoErr(err)
+
+	position, err := ParsePosition(sdkPosition)
+	is.NoErr(err)
+	is.Equal(position, &Position{
+		Version:      PositionVersion,
+		Mode:         ModeCDC,
+		ChangeID:     "A1",
+		EntityLabels: []string{"Person"},
+		Bookmarks:    []string{"FB:1"},
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+const (
+	// metadataDatabaseField is a name of a metadata field that holds the name of the database.
+	metadataDatabaseField = "neo4j.database"
+	// metadataURIField is a name of a metadata field that holds the connection URI without the password.
+	metadataURIField = "neo4j.uri"
+	// metadataOrderingValueField is a name of a metadata field that holds the ordering property value.
+	metadataOrderingValueField = "neo4j.orderingValue"
+)
+
+// MetadataField is a name of an optional field of record metadata.
+type MetadataField string
+
+// The available optional metadata fields are listed below.
+const (
+	// MetadataFieldDatabase is the name of the database, it's put into the neo4j.database field.
+	MetadataFieldDatabase MetadataField = "database"
+	// MetadataFieldURI is the connection URI without the password, it's put into the neo4j.uri field.
+	MetadataFieldURI MetadataField = "uri"
+	// MetadataFieldEntityType is the entity type, it's put into the neo4j.entityType field.
+	MetadataFieldEntityType MetadataField = "entityType"
+	// MetadataFieldOrderingValue is the ordering property value of a snapshot or polling record,
+	// it's put into the neo4j.orderingValue field, strings as they are and other values as JSON.
+	MetadataFieldOrderingValue MetadataField = "orderingValue"
+)
+
+// ValidateMetadataFields checks that the metadata fields are supported.
+func ValidateMetadataFields(fields []MetadataField) error {
+	for _, field := range fields {
+		switch field {
+		case MetadataFieldDatabase, MetadataFieldURI, MetadataFieldEntityType, MetadataFieldOrderingValue:
+
+		default:
+			return fmt.Errorf("%w: %q", ErrUnsupportedMetadataField, field)
+		}
+	}
+
+	return nil
+}
+
+// metadataFields holds the optional fields of record metadata.
+type metadataFields struct {
+	// static holds the fields which values are the same for all records.
+	static sdk.Metadata
+	// orderingValue defines if the ordering property value of an element is put into the metadata.
+	orderingValue bool
+}
+
+// newMetadataFields returns the optional metadata fields of the included fields.
+func newMetadataFields(
+	fields []MetadataField, databaseName, uri string, entityType config.EntityType,
+) metadataFields {
+	metadataFields := metadataFields{static: make(sdk.Metadata)}
+
+	for _, field := range fields {
+		switch field {
+		case MetadataFieldDatabase:
+			metadataFields.static[metadataDatabaseField] = databaseName
+
+		case MetadataFieldURI:
+			metadataFields.static[metadataURIField] = sanitizeURI(uri)
+
+		case MetadataFieldEntityType:
+			metadataFields.static[metadataEntityTypeField] = string(entityType)
+
+		case MetadataFieldOrderingValue:
+			metadataFields.orderingValue = true
+		}
+	}
+
+	return metadataFields
+}
+
+// set puts the static fields into the metadata.
+func (f metadataFields) set(metadata sdk.Metadata) {
+	for name, value := range f.static {
+		metadata[name] = value
+	}
+}
+
+// sanitizeURI removes the password from the URI, so it can be exposed,
+// a URI that cannot be parsed is replaced with an empty string.
+func sanitizeURI(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+
+	if parsed.User != nil {
+		parsed.User = url.User(parsed.User.Username())
+	}
+
+	return parsed.String()
+}
+
+// metadataValue returns the value as a metadata value, strings are kept as they are,
+// and other values are serialized into JSON, temporal and spatial values as their models.
+func metadataValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	encoded, err := json.Marshal(schema.EncodeValues(map[string]any{"value": value})["value"])
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
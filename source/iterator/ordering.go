@@ -0,0 +1,163 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	// all Cypher queries used by the [SampleOrderingProperty] are listed below in the format of Go fmt.
+	// The queries take the most recently created elements and compare their ordering property values
+	// in the creation order, the inversions field holds the number of elements
+	// which ordering property value follows the value of an element created after them in the ordering direction.
+	// The creation order is the order of the element id sequence numbers, see the [elementSequenceExpression].
+	sampleNodeOrderingPropertyQueryTemplate = `
+	MATCH (obj%s) WHERE obj.%s IS NOT NULL%s
+	WITH obj ORDER BY %s DESC LIMIT %d
+	WITH collect(obj.%s) AS vals, count(DISTINCT obj.%s) AS distinctTotal
+	RETURN size(vals) AS total, distinctTotal,
+		size([i IN range(1, size(vals) - 1) WHERE vals[i] %s vals[i - 1]]) AS inversions`
+
+	sampleRelationshipOrderingPropertyQueryTemplate = `
+	MATCH ()-[obj%s]->() WHERE obj.%s IS NOT NULL%s
+	WITH obj ORDER BY %s DESC LIMIT %d
+	WITH collect(obj.%s) AS vals, count(DISTINCT obj.%s) AS distinctTotal
+	RETURN size(vals) AS total, distinctTotal,
+		size([i IN range(1, size(vals) - 1) WHERE vals[i] %s vals[i - 1]]) AS inversions`
+
+	// elementSequenceExpression extracts the sequence number that ends the element id of an obj element,
+	// e.g. 42 of "4:5f1c0e6b-...:42", it grows in the creation order, so the deprecated id() isn't needed.
+	elementSequenceExpression = "toInteger(last(split(elementId(obj), ':')))"
+
+	// orderingSampleSize is the number of the most recently created elements
+	// that are sampled to check the ordering property.
+	orderingSampleSize = 1000
+	// maxInversionsRatio is the maximum ratio of inversions among the sampled elements
+	// after which the ordering property is considered non-monotonic.
+	// It's not zero because Neo4j can reuse the element ids of deleted elements.
+	maxInversionsRatio = 0.1
+)
+
+// OrderingStats holds the results of sampling an ordering property.
+type OrderingStats struct {
+	// Total is the number of sampled elements.
+	Total int64
+	// Distinct is the number of distinct ordering property values among the sampled elements.
+	Distinct int64
+	// Inversions is the number of sampled elements which ordering property value
+	// follows the value of an element created after them in the ordering direction.
+	Inversions int64
+}
+
+// Warnings returns a list of human-readable warnings about the sampled ordering property.
+func (s OrderingStats) Warnings(property string) []string {
+	var warnings []string
+
+	if duplicates := s.Total - s.Distinct; duplicates > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"%d out of %d sampled elements have duplicate values of the ordering property %q, "+
+				"elements with equal values can be skipped when the capture is resumed; "+
+				"consider using a property with unique values, or ordering by a compound key "+
+				"with keyProperties and either snapshotSort or deterministicOrder",
+			duplicates, s.Total, property,
+		))
+	}
+
+	if s.Total > 1 && float64(s.Inversions)/float64(s.Total-1) > maxInversionsRatio {
+		warnings = append(warnings, fmt.Sprintf(
+			"%d out of %d sampled elements have a value of the ordering property %q "+
+				"that follows values of elements created after them in the ordering direction, "+
+				"elements created with such values are never captured by polling; "+
+				"consider using a monotonic property, e.g. a sequence number or a creation timestamp, "+
+				"or ordering by a compound key with keyProperties and either snapshotSort or deterministicOrder",
+			s.Inversions, s.Total, property,
+		))
+	}
+
+	return warnings
+}
+
+// SampleOrderingProperty samples the most recently created elements
+// and collects [OrderingStats] of their ordering property.
+// The query is terminated if it runs longer than the non-zero queryTimeout.
+func SampleOrderingProperty(
+	ctx context.Context,
+	driver neo4j.DriverWithContext,
+	database string,
+	labels []string,
+	labelMatch LabelMatch,
+	property string,
+	entityType config.EntityType,
+	direction OrderingDirection,
+	queryTimeout time.Duration,
+) (OrderingStats, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		AccessMode:   neo4j.AccessModeRead,
+		DatabaseName: database,
+	})
+	defer session.Close(ctx)
+
+	queryTemplate := sampleNodeOrderingPropertyQueryTemplate
+	if entityType == config.EntityTypeRelationship {
+		queryTemplate = sampleRelationshipOrderingPropertyQueryTemplate
+	}
+
+	escapedProperty := escapeIdentifier(property)
+
+	query := fmt.Sprintf(queryTemplate,
+		labelMatch.pattern(labels, entityType), escapedProperty, andCondition(labelMatch.condition(labels, entityType)),
+		elementSequenceExpression, orderingSampleSize, escapedProperty, escapedProperty, direction.after(),
+	)
+
+	stats, err := neo4j.ExecuteRead(ctx, session, func(tx neo4j.ManagedTransaction) (OrderingStats, error) {
+		result, err := tx.Run(ctx, query, nil)
+		if err != nil {
+			return OrderingStats{}, fmt.Errorf("run tx: %w", err)
+		}
+
+		record, err := result.Single(ctx)
+		if err != nil {
+			return OrderingStats{}, fmt.Errorf("extract single from result: %w", err)
+		}
+
+		total, _, err := neo4j.GetRecordValue[int64](record, "total")
+		if err != nil {
+			return OrderingStats{}, fmt.Errorf("get total: %w", err)
+		}
+
+		distinct, _, err := neo4j.GetRecordValue[int64](record, "distinctTotal")
+		if err != nil {
+			return OrderingStats{}, fmt.Errorf("get distinct total: %w", err)
+		}
+
+		inversions, _, err := neo4j.GetRecordValue[int64](record, "inversions")
+		if err != nil {
+			return OrderingStats{}, fmt.Errorf("get inversions: %w", err)
+		}
+
+		return OrderingStats{Total: total, Distinct: distinct, Inversions: inversions}, nil
+	}, config.TxTimeout(queryTimeout))
+	if err != nil {
+		return OrderingStats{}, fmt.Errorf("execute read: %w", err)
+	}
+
+	return stats, nil
+}
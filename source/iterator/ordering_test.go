@@ -0,0 +1,74 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOrderingStats_Warnings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		stats        OrderingStats
+		wantWarnings int
+	}{
+		{
+			name:         "good_ordering_property",
+			stats:        OrderingStats{Total: 100, Distinct: 100, Inversions: 1},
+			wantWarnings: 0,
+		},
+		{
+			name:         "duplicates",
+			stats:        OrderingStats{Total: 100, Distinct: 50},
+			wantWarnings: 1,
+		},
+		{
+			name:         "non_monotonic",
+			stats:        OrderingStats{Total: 100, Distinct: 100, Inversions: 50},
+			wantWarnings: 1,
+		},
+		{
+			name:         "duplicates_and_non_monotonic",
+			stats:        OrderingStats{Total: 4, Distinct: 3, Inversions: 2},
+			wantWarnings: 2,
+		},
+		{
+			name:         "empty",
+			stats:        OrderingStats{},
+			wantWarnings: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.stats.Warnings("id")
+			if len(got) != tt.wantWarnings {
+				t.Errorf("Warnings() = %v, want %d warnings", got, tt.wantWarnings)
+			}
+
+			// each warning suggests the compound key, as it's a fix for both problems
+			for _, warning := range got {
+				if !strings.Contains(warning, "keyProperties") || !strings.Contains(warning, "deterministicOrder") {
+					t.Errorf("Warnings() = %q, want a compound key suggestion", warning)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// jsonPrettyIndent is an indent used by the [PayloadFormatJSONPretty].
+const jsonPrettyIndent = "  "
+
+// PayloadFormat defines a format which element properties are serialized into a record payload with.
+type PayloadFormat string
+
+// The available payload formats are listed below.
+const (
+	PayloadFormatJSON       PayloadFormat = "json"
+	PayloadFormatJSONPretty PayloadFormat = "jsonPretty"
+	PayloadFormatMsgpack    PayloadFormat = "msgpack"
+)
+
+// marshalPayload serializes element properties into a record payload using the format.
+// An empty format falls back to the [PayloadFormatJSON].
+// Temporal values are tagged with their types, so they can be converted back by the destination.
+func marshalPayload(props map[string]any, format PayloadFormat) ([]byte, error) {
+	props = schema.EncodeValues(props)
+
+	switch format {
+	case PayloadFormatJSONPretty:
+		payload, err := json.MarshalIndent(props, "", jsonPrettyIndent)
+		if err != nil {
+			return nil, fmt.Errorf("marshal indent json: %w", err)
+		}
+
+		return payload, nil
+
+	case PayloadFormatMsgpack:
+		var buf bytes.Buffer
+
+		// use json tags, so structs like the schema.Node have the same field names as in JSON
+		encoder := msgpack.NewEncoder(&buf)
+		encoder.SetCustomStructTag("json")
+
+		if err := encoder.Encode(props); err != nil {
+			return nil, fmt.Errorf("encode msgpack: %w", err)
+		}
+
+		return buf.Bytes(), nil
+
+	default:
+		payload, err := json.Marshal(props)
+		if err != nil {
+			return nil, fmt.Errorf("marshal json: %w", err)
+		}
+
+		return payload, nil
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestMarshalPayload(t *testing.T) {
+	t.Parallel()
+
+	props := map[string]any{
+		"name": "Alex",
+		"age":  int64(30),
+		"sourceNode": schema.Node{
+			Labels: []string{"Person"},
+			Key:    map[string]any{"id": int64(1)},
+		},
+	}
+
+	unmarshalJSON := func(payload []byte) (map[string]any, error) {
+		var got map[string]any
+
+		return got, json.Unmarshal(payload, &got)
+	}
+
+	wantJSON := map[string]any{
+		"name": "Alex",
+		"age":  float64(30),
+		"sourceNode": map[string]any{
+			"labels": []any{"Person"},
+			"key":    map[string]any{"id": float64(1)},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		format    PayloadFormat
+		unmarshal func([]byte) (map[string]any, error)
+		want      map[string]any
+	}{
+		{
+			name:      "default",
+			unmarshal: unmarshalJSON,
+			want:      wantJSON,
+		},
+		{
+			name:      "json",
+			format:    PayloadFormatJSON,
+			unmarshal: unmarshalJSON,
+			want:      wantJSON,
+		},
+		{
+			name:      "jsonPretty",
+			format:    PayloadFormatJSONPretty,
+			unmarshal: unmarshalJSON,
+			want:      wantJSON,
+		},
+		{
+			name:   "msgpack",
+			format: PayloadFormatMsgpack,
+			unmarshal: func(payload []byte) (map[string]any, error) {
+				decoder := msgpack.NewDecoder(bytes.NewReader(payload))
+				decoder.UseLooseInterfaceDecoding(true)
+
+				var got map[string]any
+
+				return got, decoder.Decode(&got)
+			},
+			want: map[string]any{
+				"name": "Alex",
+				"age":  int64(30),
+				"sourceNode": map[string]any{
+					"labels": []any{"Person"},
+					"key":    map[string]any{"id": int64(1)},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			payload, err := marshalPayload(props, tt.format)
+			if err != nil {
+				t.Fatalf("marshalPayload() error = %v", err)
+			}
+
+			got, err := tt.unmarshal(payload)
+			if err != nil {
+				t.Fatalf("unmarshal payload error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("marshalPayload() round trip = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalPayload_temporal(t *testing.T) {
+	t.Parallel()
+
+	props := map[string]any{
+		"born": dbtype.Date(time.Date(1990, 2, 3, 0, 0, 0, 0, time.UTC)),
+	}
+
+	payload, err := marshalPayload(props, PayloadFormatJSON)
+	if err != nil {
+		t.Fatalf("marshalPayload() error = %v", err)
+	}
+
+	want := `{"born":{"neo4jType":"date","value":"1990-02-03"}}`
+	if string(payload) != want {
+		t.Errorf("marshalPayload() = %s, want %s", payload, want)
+	}
+
+	// the properties are kept intact, as their raw values are used in positions
+	if _, ok := props["born"].(dbtype.Date); !ok {
+		t.Errorf("marshalPayload() changed the properties: %v", props)
+	}
+}
@@ -15,12 +15,39 @@
 package iterator
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	sdk "github.com/conduitio/conduit-connector-sdk"
 )
 
+// PositionVersion is the current version of the [Position] format.
+//
+// It must be incremented each time a field or a mode is added to the [Position], or the meaning of one changes,
+// and [Position.migrate] must upgrade positions of the previous version. So a connector resumes any position
+// an older connector created, and rejects positions of a newer format with the [ErrUnsupportedPositionVersion]
+// instead of silently dropping the state it doesn't know about.
+//
+// The version 2 added the ChangeID, OrderingProperty, EntityLabels, Bookmarks and Entities fields,
+// and the cdc, subgraph_snapshot and entities modes. The version 3 added the Reconcile field.
+const PositionVersion = 3
+
+const (
+	// legacyPositionVersion is a version of positions that were created before the versioning was introduced.
+	legacyPositionVersion = 0
+	// firstPositionVersion is a version of positions that hold only the snapshot and polling state.
+	firstPositionVersion = 1
+	// secondPositionVersion is a version of positions that don't hold the state of the delete detection.
+	secondPositionVersion = 2
+)
+
+// maxExactFloatInteger is the max integer that float64 can represent exactly, it's 2^53.
+const maxExactFloatInteger = 1 << 53
+
 // PositionMode defines the [position] mode.
 type PositionMode string
 
@@ -28,17 +55,50 @@ type PositionMode string
 const (
 	ModeSnapshot        PositionMode = "snapshot"
 	ModeSnapshotPolling PositionMode = "snapshot_polling"
+	ModeCDC             PositionMode = "cdc"
+	// ModeSubgraphSnapshot is a mode of positions of relationships captured by a subgraph snapshot
+	// after the snapshot of nodes.
+	ModeSubgraphSnapshot PositionMode = "subgraph_snapshot"
+	// ModeEntities is a mode of positions of a source capturing multiple entities,
+	// which hold the positions of each of them.
+	ModeEntities PositionMode = "entities"
 )
 
+// snapshot checks if the mode is a mode of the snapshot that precedes the polling.
+func (m PositionMode) snapshot() bool {
+	return m == ModeSnapshot || m == ModeSubgraphSnapshot
+}
+
 // Position is an iterator position.
 type Position struct {
-	Mode PositionMode `json:"mode"`
+	// Version is a version of the position format.
+	// Positions created before the versioning was introduced don't have it, so it's zero.
+	Version int          `json:"version"`
+	Mode    PositionMode `json:"mode"`
 	// LastProcessedValue is a value of the last processed element by the snapshot capture.
 	// This value is used if the mode is snapshot.
 	LastProcessedValue any `json:"lastProcessedValue"`
 	// MaxElement is a max value of an ordering property at the start of a snapshot.
 	// This value is used if the mode is snapshot.
 	MaxElement any `json:"maxElement,omitempty"`
+	// ChangeID is an identifier of the last processed Neo4j CDC change.
+	// This value is used if the mode is cdc, snapshot positions hold a change the CDC capture starts after.
+	ChangeID string `json:"changeId,omitempty"`
+	// OrderingProperty and EntityLabels are the config values the position was created for,
+	// they're used to detect a position that doesn't match the config anymore.
+	OrderingProperty string   `json:"orderingProperty,omitempty"`
+	EntityLabels     []string `json:"entityLabels,omitempty"`
+	// Bookmarks are the Neo4j bookmarks of the read the record was captured by,
+	// a resumed capture waits for them, so it doesn't observe an older state. They're set if the causal consistency
+	// is enabled.
+	Bookmarks []string `json:"bookmarks,omitempty"`
+	// Entities holds the positions of the entities keyed by their names.
+	// This value is used if the mode is entities, a record of any of the entities carries the positions
+	// of all of them, so a resumed capture resumes each of them.
+	Entities map[string]json.RawMessage `json:"entities,omitempty"`
+	// Reconcile is the state of the delete detection, it's set if the detectDeletes is enabled
+	// and the polling has reconciled the keys at least once.
+	Reconcile *ReconcileState `json:"reconcile,omitempty"`
 }
 
 // MarshalSDKPosition marshals the underlying [position] into a [sdk.Position] as JSON bytes.
@@ -57,10 +117,145 @@ func ParsePosition(sdkPosition sdk.Position) (*Position, error) {
 		return nil, ErrNilSDKPosition
 	}
 
+	// use json.Number to not lose precision of big integers
+	decoder := json.NewDecoder(bytes.NewReader(sdkPosition))
+	decoder.UseNumber()
+
 	position := new(Position)
-	if err := json.Unmarshal(sdkPosition, position); err != nil {
+	if err := decoder.Decode(position); err != nil {
 		return nil, fmt.Errorf("unmarshal sdk.Position into position: %w", err)
 	}
 
+	position.LastProcessedValue = positionNumbers(position.LastProcessedValue)
+	position.MaxElement = positionNumbers(position.MaxElement)
+
+	if err := position.migrate(); err != nil {
+		return nil, fmt.Errorf("migrate position: %w", err)
+	}
+
 	return position, nil
 }
+
+// positionNumbers recursively converts [json.Number] values parsed from a position into float64,
+// as the json package does by default, except integers that cannot be represented by float64 exactly,
+// which are converted into int64, so the capture resumes exactly after them.
+func positionNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if !strings.ContainsAny(v.String(), ".eE") {
+			integer, err := strconv.ParseInt(v.String(), 10, 64)
+			if err == nil && (integer > maxExactFloatInteger || integer < -maxExactFloatInteger) {
+				return integer
+			}
+		}
+
+		// the number was validated by the decoder, so it can only be out of the float64 range
+		float, _ := v.Float64()
+
+		return float
+
+	case []any:
+		for i, item := range v {
+			v[i] = positionNumbers(item)
+		}
+
+		return v
+
+	case map[string]any:
+		for key, item := range v {
+			v[key] = positionNumbers(item)
+		}
+
+		return v
+
+	default:
+		return value
+	}
+}
+
+// EntityPositions returns the positions of the entities the position holds keyed by their names.
+// A position of another mode is a position of a single entity, so it's returned for the defaultName.
+func (p *Position) EntityPositions(defaultName string) (map[string]*Position, error) {
+	if p.Mode != ModeEntities {
+		return map[string]*Position{defaultName: p}, nil
+	}
+
+	positions := make(map[string]*Position, len(p.Entities))
+	for name, raw := range p.Entities {
+		position, err := ParsePosition(sdk.Position(raw))
+		if err != nil {
+			return nil, fmt.Errorf("parse position of the entity %q: %w", name, err)
+		}
+
+		positions[name] = position
+	}
+
+	return positions, nil
+}
+
+// Validate checks that the position was created for the given ordering property and entity labels,
+// and returns the [ErrPositionMismatch] otherwise. The order of the labels doesn't matter.
+// Values that are not stored in the position, e.g. in positions created by older versions, are not checked.
+func (p *Position) Validate(orderingProperty string, entityLabels []string) error {
+	if p.OrderingProperty != "" && p.OrderingProperty != orderingProperty {
+		return fmt.Errorf("%w: the position is for the orderingProperty %q, but it's %q",
+			ErrPositionMismatch, p.OrderingProperty, orderingProperty)
+	}
+
+	if len(p.EntityLabels) > 0 && !slices.Equal(sortedLabels(p.EntityLabels), sortedLabels(entityLabels)) {
+		return fmt.Errorf("%w: the position is for the entityLabels %q, but they're %q",
+			ErrPositionMismatch, p.EntityLabels, entityLabels)
+	}
+
+	return nil
+}
+
+// ValidateSort checks that the last processed value of a snapshot position can be resumed
+// by the sort keys, i.e. it's a list of their values followed by the element id if the keys are set,
+// or it's a single value otherwise, and returns the [ErrPositionMismatch] if it can't.
+// The positions of the other modes aren't checked, as the sort is used only by the snapshot of elements.
+func (p *Position) ValidateSort(keys []SortKey) error {
+	if p.Mode != ModeSnapshot || p.LastProcessedValue == nil {
+		return nil
+	}
+
+	values, isList := p.LastProcessedValue.([]any)
+
+	switch {
+	case len(keys) == 0 && isList:
+		return fmt.Errorf("%w: the position is for the snapshotSort, but it's empty", ErrPositionMismatch)
+
+	case len(keys) > 0 && (!isList || len(values) != len(keys)+1):
+		return fmt.Errorf("%w: the position isn't for the snapshotSort of %d properties", ErrPositionMismatch, len(keys))
+
+	default:
+		return nil
+	}
+}
+
+// sortedLabels returns a sorted copy of the labels.
+func sortedLabels(labels []string) []string {
+	sorted := slices.Clone(labels)
+	slices.Sort(sorted)
+
+	return sorted
+}
+
+// migrate upgrades the position to the current [PositionVersion].
+func (p *Position) migrate() error {
+	switch p.Version {
+	case PositionVersion:
+		return nil
+
+	case legacyPositionVersion, firstPositionVersion, secondPositionVersion:
+		// legacy positions have the same set of fields as the first version, and the next ones only added
+		// optional fields and modes, which older positions don't have, so it's enough to just stamp the version.
+		// Positions without the Reconcile state resume the delete detection with a fresh baseline
+		p.Version = PositionVersion
+
+		return nil
+
+	default:
+		return fmt.Errorf("%w: %d", ErrUnsupportedPositionVersion, p.Version)
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+func TestParsePosition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		sdkPosition sdk.Position
+		want        *Position
+		wantErr     error
+	}{
+		{
+			name:        "success_legacy",
+			sdkPosition: sdk.Position(`{"mode":"snapshot","lastProcessedValue":2,"maxElement":10}`),
+			want: &Position{
+				Version:            PositionVersion,
+				Mode:               ModeSnapshot,
+				LastProcessedValue: float64(2),
+				MaxElement:         float64(10),
+			},
+		},
+		{
+			name:        "success_versioned",
+			sdkPosition: sdk.Position(`{"version":1,"mode":"snapshot_polling","lastProcessedValue":2}`),
+			want: &Position{
+				Version:            PositionVersion,
+				Mode:               ModeSnapshotPolling,
+				LastProcessedValue: float64(2),
+			},
+		},
+		{
+			name:        "success_big_integer",
+			sdkPosition: sdk.Position(`{"version":1,"mode":"snapshot_polling","lastProcessedValue":9007199254740993}`),
+			want: &Position{
+				Version:            PositionVersion,
+				Mode:               ModeSnapshotPolling,
+				LastProcessedValue: int64(9007199254740993),
+			},
+		},
+		{
+			name:        "success_sort_values",
+			sdkPosition: sdk.Position(`{"version":1,"mode":"snapshot","lastProcessedValue":[2.5,9007199254740993,"4:abc:1"]}`),
+			want: &Position{
+				Version:            PositionVersion,
+				Mode:               ModeSnapshot,
+				LastProcessedValue: []any{2.5, int64(9007199254740993), "4:abc:1"},
+			},
+		},
+		{
+			name:        "success_current_version",
+			sdkPosition: sdk.Position(`{"version":2,"mode":"cdc","changeId":"abc","bookmarks":["b1"]}`),
+			want: &Position{
+				Version:   PositionVersion,
+				Mode:      ModeCDC,
+				ChangeID:  "abc",
+				Bookmarks: []string{"b1"},
+			},
+		},
+		{
+			name: "success_reconcile_state",
+			sdkPosition: sdk.Position(
+				`{"version":3,"mode":"snapshot_polling","lastProcessedValue":2,` +
+					`"reconcile":{"keys":2,"digest":"00000000000000ff"}}`,
+			),
+			want: &Position{
+				Version:            PositionVersion,
+				Mode:               ModeSnapshotPolling,
+				LastProcessedValue: float64(2),
+				Reconcile:          &ReconcileState{Keys: 2, Digest: "00000000000000ff"},
+			},
+		},
+		{
+			name:        "fail_unsupported_version",
+			sdkPosition: sdk.Position(`{"version":100,"mode":"snapshot"}`),
+			wantErr:     ErrUnsupportedPositionVersion,
+		},
+		{
+			name:    "fail_nil",
+			wantErr: ErrNilSDKPosition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePosition(tt.sdkPosition)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParsePosition() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePosition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPosition_MarshalSDKPosition(t *testing.T) {
+	t.Parallel()
+
+	position := &Position{
+		Version:            PositionVersion,
+		Mode:               ModeSnapshot,
+		LastProcessedValue: float64(1),
+	}
+
+	sdkPosition, err := position.MarshalSDKPosition()
+	if err != nil {
+		t.Fatalf("MarshalSDKPosition() error = %v", err)
+	}
+
+	got, err := ParsePosition(sdkPosition)
+	if err != nil {
+		t.Fatalf("ParsePosition() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, position) {
+		t.Errorf("ParsePosition() = %v, want %v", got, position)
+	}
+}
+
+func TestPosition_ValidateSort(t *testing.T) {
+	t.Parallel()
+
+	sort := []SortKey{{Property: "priority", Direction: OrderingDirectionDesc}, {Property: "createdAt"}}
+
+	tests := []struct {
+		name     string
+		position *Position
+		sort     []SortKey
+		wantErr  error
+	}{
+		{
+			name:     "success_sort",
+			position: &Position{Mode: ModeSnapshot, LastProcessedValue: []any{float64(2), float64(1), "4:abc:1"}},
+			sort:     sort,
+		},
+		{
+			name:     "success_no_sort",
+			position: &Position{Mode: ModeSnapshot, LastProcessedValue: float64(1)},
+		},
+		{
+			name:     "success_polling",
+			position: &Position{Mode: ModeSnapshotPolling, LastProcessedValue: float64(1)},
+			sort:     sort,
+		},
+		{
+			name:     "success_snapshot_start",
+			position: &Position{Mode: ModeSnapshot, MaxElement: float64(10)},
+			sort:     sort,
+		},
+		{
+			name:     "fail_single_value",
+			position: &Position{Mode: ModeSnapshot, LastProcessedValue: float64(1)},
+			sort:     sort,
+			wantErr:  ErrPositionMismatch,
+		},
+		{
+			name:     "fail_other_sort",
+			position: &Position{Mode: ModeSnapshot, LastProcessedValue: []any{float64(2), "4:abc:1"}},
+			sort:     sort,
+			wantErr:  ErrPositionMismatch,
+		},
+		{
+			name:     "fail_sort_removed",
+			position: &Position{Mode: ModeSnapshot, LastProcessedValue: []any{float64(2), float64(1), "4:abc:1"}},
+			wantErr:  ErrPositionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.position.ValidateSort(tt.sort)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateSort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPosition_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		position *Position
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			position: &Position{OrderingProperty: "id", EntityLabels: []string{"Person", "Writer"}},
+		},
+		{
+			name:     "success_labels_order",
+			position: &Position{OrderingProperty: "id", EntityLabels: []string{"Writer", "Person"}},
+		},
+		{
+			name:     "success_legacy",
+			position: &Position{Mode: ModeSnapshot, LastProcessedValue: float64(1)},
+		},
+		{
+			name:     "fail_orderingProperty",
+			position: &Position{OrderingProperty: "createdAt", EntityLabels: []string{"Person", "Writer"}},
+			wantErr:  ErrPositionMismatch,
+		},
+		{
+			name:     "fail_entityLabels",
+			position: &Position{OrderingProperty: "id", EntityLabels: []string{"Person"}},
+			wantErr:  ErrPositionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.position.Validate("id", []string{"Person", "Writer"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPosition_EntityPositions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		position *Position
+		want     map[string]*Position
+		wantErr  error
+	}{
+		{
+			name: "success_entities",
+			position: &Position{
+				Version: PositionVersion,
+				Mode:    ModeEntities,
+				Entities: map[string]json.RawMessage{
+					"default":   json.RawMessage(`{"mode":"snapshot","lastProcessedValue":2}`),
+					"companies": json.RawMessage(`{"version":1,"mode":"snapshot_polling","lastProcessedValue":"acme"}`),
+				},
+			},
+			want: map[string]*Position{
+				"default":   {Version: PositionVersion, Mode: ModeSnapshot, LastProcessedValue: float64(2)},
+				"companies": {Version: PositionVersion, Mode: ModeSnapshotPolling, LastProcessedValue: "acme"},
+			},
+		},
+		{
+			name:     "success_single_entity",
+			position: &Position{Version: PositionVersion, Mode: ModeSnapshot, LastProcessedValue: float64(1)},
+			want: map[string]*Position{
+				"default": {Version: PositionVersion, Mode: ModeSnapshot, LastProcessedValue: float64(1)},
+			},
+		},
+		{
+			name: "fail_unsupported_version",
+			position: &Position{
+				Version:  PositionVersion,
+				Mode:     ModeEntities,
+				Entities: map[string]json.RawMessage{"default": json.RawMessage(`{"version":100}`)},
+			},
+			wantErr: ErrUnsupportedPositionVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.position.EntityPositions("default")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("EntityPositions() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EntityPositions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
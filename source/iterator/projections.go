@@ -0,0 +1,128 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+)
+
+const (
+	// projectionReturnClause is a return clause of a single projection that is added to the snapshot queries,
+	// it takes the expression and the escaped column alias.
+	projectionReturnClause = ", %s AS %s"
+	// projectionAliasPrefix is a prefix of projection column aliases,
+	// so they don't collide with the obj, src, trgt and relationship counts columns.
+	projectionAliasPrefix = "projection_"
+
+	// explainNodesQueryTemplate and explainRelationshipsQueryTemplate plan the projections without running them,
+	// they take the projections return clause.
+	explainNodesQueryTemplate         = "EXPLAIN MATCH (obj) RETURN obj%s"
+	explainRelationshipsQueryTemplate = "EXPLAIN MATCH (src)-[obj]->(trgt) RETURN obj, src, trgt%s"
+)
+
+// ValidateProjections checks that the projections have names and expressions,
+// and that their names don't collide with the reserved relationship payload-specific fields.
+func ValidateProjections(projections map[string]string) error {
+	for name, expression := range projections {
+		if name == "" || expression == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidProjection, name)
+		}
+
+		if name == sourceNodeField || name == targetNodeField {
+			return fmt.Errorf("%w: %q is a reserved field", ErrInvalidProjection, name)
+		}
+	}
+
+	return nil
+}
+
+// VerifyProjections checks that Neo4j can plan the projection expressions with EXPLAIN,
+// so invalid expressions are reported on start instead of failing the first batch.
+func VerifyProjections(
+	ctx context.Context,
+	driver neo4j.DriverWithContext,
+	database string,
+	entityType config.EntityType,
+	projections map[string]string,
+) error {
+	if len(projections) == 0 {
+		return nil
+	}
+
+	queryTemplate := explainNodesQueryTemplate
+	if entityType == config.EntityTypeRelationship {
+		queryTemplate = explainRelationshipsQueryTemplate
+	}
+
+	_, err := neo4j.ExecuteQuery(ctx, driver, fmt.Sprintf(queryTemplate, projectionsReturnClause(projections)), nil,
+		neo4j.EagerResultTransformer,
+		neo4j.ExecuteQueryWithDatabase(database),
+		neo4j.ExecuteQueryWithReadersRouting(),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProjection, err)
+	}
+
+	return nil
+}
+
+// projectionsReturnClause returns the return clause of the projections sorted by their names,
+// so the query text is stable.
+func projectionsReturnClause(projections map[string]string) string {
+	var returnClause string
+	for _, name := range sortedProjectionNames(projections) {
+		returnClause += fmt.Sprintf(projectionReturnClause, projections[name], projectionAlias(name))
+	}
+
+	return returnClause
+}
+
+// setProjections puts the projected values of the record into the props by the projection names,
+// they take precedence over the element properties with the same names.
+func setProjections(record *db.Record, props map[string]any, projections map[string]string) error {
+	for name := range projections {
+		value, ok := record.Get(projectionAliasPrefix + name)
+		if !ok {
+			return fmt.Errorf("record doesn't contain %q key", projectionAliasPrefix+name)
+		}
+
+		props[name] = value
+	}
+
+	return nil
+}
+
+// projectionAlias returns the escaped column alias of the projection.
+func projectionAlias(name string) string {
+	return escapeIdentifier(projectionAliasPrefix + name)
+}
+
+// sortedProjectionNames returns the projection names in the ascending order.
+func sortedProjectionNames(projections map[string]string) []string {
+	names := make([]string, 0, len(projections))
+	for name := range projections {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
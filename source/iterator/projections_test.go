@@ -0,0 +1,94 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+)
+
+func TestProjectionsReturnClause(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	returnClause := projectionsReturnClause(map[string]string{
+		"name_upper": "toUpper(obj.name)",
+		"age_next":   "obj.age + 1",
+	})
+	is.Equal(returnClause, ", obj.age + 1 AS `projection_age_next`, toUpper(obj.name) AS `projection_name_upper`")
+
+	is.Equal(projectionsReturnClause(nil), "")
+}
+
+func TestSetProjections(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	record := &db.Record{
+		Keys:   []string{objPlaceholder, "projection_name_upper"},
+		Values: []any{nil, "ALICE"},
+	}
+
+	// the projection takes precedence over the property with the same name
+	props := map[string]any{"name": "Alice", "name_upper": "alice"}
+
+	err := setProjections(record, props, map[string]string{"name_upper": "toUpper(obj.name)"})
+	is.NoErr(err)
+	is.Equal(props, map[string]any{"name": "Alice", "name_upper": "ALICE"})
+
+	err = setProjections(record, props, map[string]string{"age_next": "obj.age + 1"})
+	is.True(err != nil)
+}
+
+func TestValidateProjections(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		projections map[string]string
+		wantErr     error
+	}{
+		{
+			name:        "success",
+			projections: map[string]string{"name_upper": "toUpper(obj.name)"},
+		},
+		{
+			name:        "fail_empty_expression",
+			projections: map[string]string{"name_upper": ""},
+			wantErr:     ErrInvalidProjection,
+		},
+		{
+			name:        "fail_reserved_field",
+			projections: map[string]string{targetNodeField: "trgt.name"},
+			wantErr:     ErrInvalidProjection,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateProjections(tt.projections)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateProjections() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// OrderingPropertyType defines the type of the ordering property values. Values stored in positions
+// lose their types, as they're serialized into JSON, e.g. integers are parsed as floats and datetimes as strings,
+// so they're coerced back to the type before they're compared with the ordering property.
+type OrderingPropertyType string
+
+// The available ordering property types are listed below, the empty type keeps the parsed values as they are.
+const (
+	OrderingPropertyTypeInt      OrderingPropertyType = "int"
+	OrderingPropertyTypeFloat    OrderingPropertyType = "float"
+	OrderingPropertyTypeString   OrderingPropertyType = "string"
+	OrderingPropertyTypeDatetime OrderingPropertyType = "datetime"
+)
+
+// coercePosition returns a copy of the position with the ordering property values coerced to the type.
+// The last processed value is coerced only if the lastProcessedValue is true,
+// as it isn't an ordering property value if elements are paginated by element ids or sort keys.
+// A value that cannot be coerced means the position was created for another ordering property,
+// so the [ErrPositionMismatch] is returned.
+func (t OrderingPropertyType) coercePosition(position *Position, lastProcessedValue bool) (*Position, error) {
+	if t == "" || position == nil {
+		return position, nil
+	}
+
+	coerced := *position
+
+	var err error
+	if lastProcessedValue && coerced.LastProcessedValue != nil {
+		coerced.LastProcessedValue, err = t.coerce(coerced.LastProcessedValue)
+		if err != nil {
+			return nil, fmt.Errorf("coerce last processed value: %w", err)
+		}
+	}
+
+	if coerced.MaxElement != nil {
+		coerced.MaxElement, err = t.coerce(coerced.MaxElement)
+		if err != nil {
+			return nil, fmt.Errorf("coerce max element: %w", err)
+		}
+	}
+
+	return &coerced, nil
+}
+
+// ValidatePosition checks that the ordering property values of the Position
+// can be coerced to the OrderingPropertyType, it returns the [ErrPositionMismatch] otherwise.
+func (p SnapshotParams) ValidatePosition() error {
+	_, err := p.coercePosition()
+
+	return err
+}
+
+// coercePosition coerces the Position to the OrderingPropertyType of the params,
+// the last processed value holds an ordering property value only if elements are paginated by the ordering property.
+func (p SnapshotParams) coercePosition() (*Position, error) {
+	var lastProcessedValue bool
+	if p.Position != nil && p.PositionStrategy == nil {
+		switch p.Position.Mode {
+		case ModeSnapshot:
+			lastProcessedValue = !p.SnapshotByElementID && len(p.Sort) == 0
+		case ModeSnapshotPolling:
+			lastProcessedValue = true
+		}
+	}
+
+	return p.OrderingPropertyType.coercePosition(p.Position, lastProcessedValue)
+}
+
+// coerce converts the value parsed from a position to the type.
+func (t OrderingPropertyType) coerce(value any) (any, error) {
+	switch t {
+	case OrderingPropertyTypeInt:
+		return coerceInt(value)
+
+	case OrderingPropertyTypeFloat:
+		return coerceFloat(value)
+
+	case OrderingPropertyTypeString:
+		if _, ok := value.(string); !ok {
+			return nil, fmt.Errorf("%w: %v is a %T, not a string", ErrPositionMismatch, value, value)
+		}
+
+		return value, nil
+
+	case OrderingPropertyTypeDatetime:
+		return coerceDatetime(value)
+
+	default:
+		return value, nil
+	}
+}
+
+// coerceInt converts the value to int64, floats must have no fractional part.
+func coerceInt(value any) (any, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return nil, fmt.Errorf("%w: %v is not an integer", ErrPositionMismatch, v)
+		}
+
+		return int64(v), nil
+
+	case string:
+		integer, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not an integer", ErrPositionMismatch, v)
+		}
+
+		return integer, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %v is a %T, not an integer", ErrPositionMismatch, value, value)
+	}
+}
+
+// coerceFloat converts the value to float64.
+func coerceFloat(value any) (any, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+
+	case int64:
+		return float64(v), nil
+
+	case string:
+		float, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a float", ErrPositionMismatch, v)
+		}
+
+		return float, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %v is a %T, not a float", ErrPositionMismatch, value, value)
+	}
+}
+
+// coerceDatetime converts the value to [time.Time], which the driver binds as a datetime,
+// strings must be in the RFC 3339 format, which datetimes are serialized into.
+func coerceDatetime(value any) (any, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+
+	case string:
+		datetime, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a datetime", ErrPositionMismatch, v)
+		}
+
+		return datetime, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %v is a %T, not a datetime", ErrPositionMismatch, value, value)
+	}
+}
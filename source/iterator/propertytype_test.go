@@ -0,0 +1,214 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+func TestOrderingPropertyType_coerce(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2023, time.May, 4, 10, 30, 15, 500, time.UTC)
+
+	tests := []struct {
+		name         string
+		propertyType OrderingPropertyType
+		value        any
+		want         any
+		wantErr      error
+	}{
+		{
+			name:         "success_int_float",
+			propertyType: OrderingPropertyTypeInt,
+			value:        float64(2),
+			want:         int64(2),
+		},
+		{
+			name:         "success_int_big",
+			propertyType: OrderingPropertyTypeInt,
+			value:        int64(9007199254740993),
+			want:         int64(9007199254740993),
+		},
+		{
+			name:         "success_int_string",
+			propertyType: OrderingPropertyTypeInt,
+			value:        "42",
+			want:         int64(42),
+		},
+		{
+			name:         "success_float_int",
+			propertyType: OrderingPropertyTypeFloat,
+			value:        int64(3),
+			want:         float64(3),
+		},
+		{
+			name:         "success_string",
+			propertyType: OrderingPropertyTypeString,
+			value:        "acme",
+			want:         "acme",
+		},
+		{
+			name:         "success_datetime",
+			propertyType: OrderingPropertyTypeDatetime,
+			value:        createdAt.Format(time.RFC3339Nano),
+			want:         createdAt,
+		},
+		{
+			name:  "success_empty_type",
+			value: "2023-05-04T10:30:15Z",
+			want:  "2023-05-04T10:30:15Z",
+		},
+		{
+			name:         "fail_int_fraction",
+			propertyType: OrderingPropertyTypeInt,
+			value:        2.5,
+			wantErr:      ErrPositionMismatch,
+		},
+		{
+			name:         "fail_string_number",
+			propertyType: OrderingPropertyTypeString,
+			value:        float64(1),
+			wantErr:      ErrPositionMismatch,
+		},
+		{
+			name:         "fail_datetime_format",
+			propertyType: OrderingPropertyTypeDatetime,
+			value:        "04.05.2023",
+			wantErr:      ErrPositionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.propertyType.coerce(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("coerce() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("coerce() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotParams_coercePosition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		params  SnapshotParams
+		want    *Position
+		wantErr error
+	}{
+		{
+			name: "success_snapshot",
+			params: SnapshotParams{
+				OrderingPropertyType: OrderingPropertyTypeInt,
+				Position:             &Position{Mode: ModeSnapshot, LastProcessedValue: float64(2), MaxElement: float64(10)},
+			},
+			want: &Position{Mode: ModeSnapshot, LastProcessedValue: int64(2), MaxElement: int64(10)},
+		},
+		{
+			name: "success_polling",
+			params: SnapshotParams{
+				OrderingPropertyType: OrderingPropertyTypeInt,
+				Position:             &Position{Mode: ModeSnapshotPolling, LastProcessedValue: float64(2)},
+			},
+			want: &Position{Mode: ModeSnapshotPolling, LastProcessedValue: int64(2)},
+		},
+		{
+			name: "success_snapshot_sort",
+			params: SnapshotParams{
+				OrderingPropertyType: OrderingPropertyTypeInt,
+				Sort:                 []SortKey{{Property: "priority"}},
+				Position: &Position{
+					Mode: ModeSnapshot, LastProcessedValue: []any{float64(2), "4:abc:1"}, MaxElement: float64(10),
+				},
+			},
+			want: &Position{Mode: ModeSnapshot, LastProcessedValue: []any{float64(2), "4:abc:1"}, MaxElement: int64(10)},
+		},
+		{
+			name: "success_snapshot_by_element_id",
+			params: SnapshotParams{
+				OrderingPropertyType: OrderingPropertyTypeInt,
+				SnapshotByElementID:  true,
+				Position:             &Position{Mode: ModeSnapshot, LastProcessedValue: "4:abc:1", MaxElement: float64(10)},
+			},
+			want: &Position{Mode: ModeSnapshot, LastProcessedValue: "4:abc:1", MaxElement: int64(10)},
+		},
+		{
+			name: "success_nil_position",
+			params: SnapshotParams{
+				OrderingPropertyType: OrderingPropertyTypeInt,
+			},
+		},
+		{
+			name: "fail_mismatch",
+			params: SnapshotParams{
+				OrderingPropertyType: OrderingPropertyTypeDatetime,
+				Position:             &Position{Mode: ModeSnapshotPolling, LastProcessedValue: float64(2)},
+			},
+			wantErr: ErrPositionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.params.coercePosition()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("coercePosition() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("coercePosition() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestOrderingPropertyType_coercePosition_bigInt checks that integer ids beyond 2^53 survive the position round trip,
+// a float64 would round 9007199254740993 down to 9007199254740992, so the element after it would be re-read.
+func TestOrderingPropertyType_coercePosition_bigInt(t *testing.T) {
+	t.Parallel()
+
+	position, err := ParsePosition(sdk.Position(`{"version":1,"mode":"snapshot_polling","lastProcessedValue":9007199254740993}`))
+	if err != nil {
+		t.Fatalf("ParsePosition() error = %v", err)
+	}
+
+	got, err := OrderingPropertyTypeInt.coercePosition(position, true)
+	if err != nil {
+		t.Fatalf("coercePosition() error = %v", err)
+	}
+
+	if got.LastProcessedValue != int64(9007199254740993) {
+		t.Errorf("coercePosition() last processed value = %#v, want %#v", got.LastProcessedValue, int64(9007199254740993))
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter limits the rate of the queries that load batches of elements or changes,
+// so a capture keeps the load of a production database bounded. It's a token bucket shared by the iterators
+// of a source, so the limit applies to all of their queries in total.
+// The nil RateLimiter doesn't limit the queries.
+type RateLimiter struct {
+	limiter *rate.Limiter
+}
+
+// NewRateLimiter creates a new instance of the [RateLimiter] that permits the queriesPerSecond,
+// which can be fractional, e.g. 0.5 permits a query every two seconds.
+func NewRateLimiter(queriesPerSecond float64) *RateLimiter {
+	return &RateLimiter{limiter: rate.NewLimiter(rate.Limit(queriesPerSecond), 1)}
+}
+
+// wait blocks until the next query is permitted, or the ctx is done.
+func (r *RateLimiter) wait(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	if err := r.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("wait for the rate limiter: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	const (
+		queriesPerSecond = 20
+		queries          = 11
+	)
+
+	rateLimiter := NewRateLimiter(queriesPerSecond)
+
+	start := time.Now()
+	for range queries {
+		is.NoErr(rateLimiter.wait(context.Background()))
+	}
+
+	// the first query is permitted right away, the others are spread evenly over the window
+	window := time.Duration(queries-1) * time.Second / queriesPerSecond
+	is.True(time.Since(start) >= window-10*time.Millisecond)
+}
+
+func TestRateLimiter_disabled(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	var rateLimiter *RateLimiter
+
+	start := time.Now()
+	for range 1000 {
+		is.NoErr(rateLimiter.wait(context.Background()))
+	}
+
+	is.True(time.Since(start) < time.Second)
+}
+
+func TestRateLimiter_canceled(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	rateLimiter := NewRateLimiter(0.01)
+	is.NoErr(rateLimiter.wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	is.True(errors.Is(rateLimiter.wait(ctx), context.Canceled))
+}
+
+func TestSnapshot_loadBatch_rateLimiter(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctrl := gomock.NewController(t)
+
+	// the driver creates a single session, the second batch doesn't get to it
+	driver := newFailingDriver(ctrl, &neo4j.SessionConfig{})
+
+	s := &Snapshot{driver: driver, orderingProperty: "id", rateLimiter: NewRateLimiter(0.01)}
+
+	err := s.loadBatch(context.Background())
+	is.True(errors.Is(err, errTestSession))
+
+	// the next query isn't permitted for 100 seconds, so the batch isn't loaded before the ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = s.loadBatch(ctx)
+	is.True(err != nil && !errors.Is(err, errTestSession))
+}
@@ -0,0 +1,483 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/querylog"
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+const (
+	// reconcileKeysQueryTemplate returns keys of all elements the polling captures.
+	reconcileKeysQueryTemplate = `
+	%s WHERE %s%s
+	RETURN DISTINCT [%s] AS key%s`
+	reconcileKeyPlaceholder = "key"
+	// reconcile state return clauses are added to the reconcileKeysQueryTemplate if the states are kept,
+	// the endpoints of a relationship are its start and end nodes regardless of the relationshipDirection.
+	reconcileNodeStateReturnClause         = ", properties(obj) AS state"
+	reconcileRelationshipStateReturnClause = ", properties(obj) AS state, startNode(obj) AS source, endNode(obj) AS target"
+	reconcileStatePlaceholder              = "state"
+	reconcileSourcePlaceholder             = "source"
+	reconcileTargetPlaceholder             = "target"
+	// reconcileEndpointsKeyExpressions return properties of the endpoints of a relationship keyed by them,
+	// the start and end nodes are the source and target ones regardless of the relationshipDirection.
+	reconcileEndpointsKeyExpressions = "properties(startNode(obj)), properties(endNode(obj))"
+)
+
+// errTooManyKeys occurs when the number of the captured elements exceeds the max number of reconciled keys.
+var errTooManyKeys = errors.New("too many keys to reconcile")
+
+// ReconcileState is the state of the delete detection stored in positions.
+// It holds a digest of the keys of the elements known to exist instead of the keys themselves,
+// so positions stay small, and a restarted capture can tell whether the elements changed while it was stopped.
+type ReconcileState struct {
+	// Keys is the number of the known keys.
+	Keys int `json:"keys"`
+	// Digest is an order-independent digest of the known keys, it's a hex-encoded sum of hashes
+	// of their canonical forms, so it's updated by adding the hash of each observed key.
+	Digest string `json:"digest"`
+}
+
+// reconciler detects deleted elements by comparing keys of the captured elements between reconciliations.
+type reconciler struct {
+	interval time.Duration
+	maxKeys  int
+	lastRun  time.Time
+	// keys holds keys of the elements known to exist by their canonical JSON form,
+	// it's nil until the first reconciliation takes the baseline.
+	keys map[string]sdk.StructuredData
+	// digest is the sum of hashes of the known keys, see the [ReconcileState].
+	digest uint64
+	// deletedDigest is the sum of hashes of the deleted keys that haven't been emitted yet,
+	// they're still known to the positions, so a restart before they're emitted is detected.
+	deletedDigest uint64
+	// restored is the state stored in the position the capture was resumed from,
+	// it's compared with the keys of the first reconciliation and dropped then.
+	restored *ReconcileState
+	// deleted holds keys of the elements which disappeared since the previous reconciliation
+	// and haven't been emitted yet.
+	deleted []sdk.StructuredData
+	// states holds the last known states of the elements by the canonical JSON forms of their keys,
+	// including the ones of the deleted elements until they're emitted.
+	// It's nil unless the states are kept for the payload before of delete records.
+	states map[string]sdk.RawData
+}
+
+// newReconciler creates a new instance of the [reconciler],
+// it keeps the states of the elements if the keepStates is true.
+func newReconciler(interval time.Duration, maxKeys int, keepStates bool) *reconciler {
+	r := &reconciler{interval: interval, maxKeys: maxKeys}
+	if keepStates {
+		r.states = make(map[string]sdk.RawData)
+	}
+
+	return r
+}
+
+// due checks if it's time for the next reconciliation.
+func (r *reconciler) due(now time.Time) bool {
+	return r.lastRun.IsZero() || now.Sub(r.lastRun) >= r.interval
+}
+
+// observe adds a key of an emitted element to the known keys, and its state to the known states if they're kept,
+// so its deletion is detected even if it's created and deleted between reconciliations.
+func (r *reconciler) observe(key sdk.StructuredData, state sdk.RawData) {
+	if r.keys == nil || len(r.keys) >= r.maxKeys {
+		return
+	}
+
+	if canonical, err := canonicalKey(key); err == nil {
+		if _, ok := r.keys[canonical]; !ok {
+			r.digest += keyHash(canonical)
+		}
+
+		r.keys[canonical] = key
+
+		if r.states != nil {
+			r.states[canonical] = state
+		}
+	}
+}
+
+// update replaces the known keys and states with the current ones and queues the keys that disappeared as deleted,
+// the states of the deleted elements are kept until they're emitted.
+// The first update only takes the baseline, as there's nothing to compare it with.
+func (r *reconciler) update(current map[string]sdk.StructuredData, states map[string]sdk.RawData) {
+	if r.keys != nil {
+		var vanished []string
+		for canonical := range r.keys {
+			if _, ok := current[canonical]; !ok {
+				vanished = append(vanished, canonical)
+			}
+		}
+
+		// sort the keys, so deletes are emitted in a stable order
+		slices.Sort(vanished)
+
+		for _, canonical := range vanished {
+			r.deleted = append(r.deleted, r.keys[canonical])
+			r.deletedDigest += keyHash(canonical)
+
+			if state, ok := r.states[canonical]; ok && states != nil {
+				states[canonical] = state
+			}
+		}
+	}
+
+	r.keys = current
+	r.digest = keysDigest(current)
+
+	if r.states != nil {
+		r.states = states
+	}
+}
+
+// state returns the state of the known keys that is stored in positions. Until the first reconciliation
+// after a restart takes the baseline, it's the restored state, so it isn't lost if the connector restarts again.
+// It's nil if the detection is disabled or there's no baseline.
+func (r *reconciler) state() *ReconcileState {
+	switch {
+	case r == nil:
+		return nil
+
+	case r.keys == nil:
+		return r.restored
+
+	default:
+		return &ReconcileState{
+			Keys:   len(r.keys) + len(r.deleted),
+			Digest: formatDigest(r.digest + r.deletedDigest),
+		}
+	}
+}
+
+// popDeleted removes the next deleted key from the queue and from the state stored in positions.
+func (r *reconciler) popDeleted() sdk.StructuredData {
+	key := r.deleted[0]
+	r.deleted = r.deleted[1:]
+
+	if canonical, err := canonicalKey(key); err == nil {
+		r.deletedDigest -= keyHash(canonical)
+	}
+
+	return key
+}
+
+// verifyRestored compares the keys loaded by the first reconciliation after a restart with the restored state
+// and drops it. It returns false if they differ, i.e. elements were created or deleted while the connector
+// was stopped, it returns true if they're equal or there's nothing to compare.
+func (r *reconciler) verifyRestored(current map[string]sdk.StructuredData) bool {
+	restored := r.restored
+	if restored == nil || r.keys != nil {
+		return true
+	}
+
+	r.restored = nil
+
+	return restored.Keys == len(current) && restored.Digest == formatDigest(keysDigest(current))
+}
+
+// deletedState returns the last known state of a deleted element and forgets it,
+// it returns nil if the state is unknown or the states aren't kept.
+func (r *reconciler) deletedState(key sdk.StructuredData) sdk.RawData {
+	canonical, err := canonicalKey(key)
+	if err != nil {
+		return nil
+	}
+
+	state := r.states[canonical]
+	delete(r.states, canonical)
+
+	return state
+}
+
+// reconcile loads the keys of the captured elements and queues deletes of the ones that disappeared.
+// If there are more elements than the max number of keys, the reconciliation is skipped
+// and the baseline is dropped, so no deletes are emitted based on an incomplete set of keys.
+func (s *Snapshot) reconcile(ctx context.Context) error {
+	s.reconciler.lastRun = time.Now()
+
+	keys, states, err := s.loadKeys(ctx)
+	if err != nil {
+		if !errors.Is(err, errTooManyKeys) {
+			return fmt.Errorf("load keys: %w", err)
+		}
+
+		sdk.Logger(ctx).Warn().Int("maxKeys", s.reconciler.maxKeys).
+			Msg("skipping the reconciliation, as there are more elements than the reconcileMaxKeys")
+
+		s.reconciler.keys = nil
+		s.reconciler.digest = 0
+		if s.reconciler.states != nil {
+			s.reconciler.states = make(map[string]sdk.RawData)
+		}
+
+		return nil
+	}
+
+	if restored := s.reconciler.restored; !s.reconciler.verifyRestored(keys) {
+		sdk.Logger(ctx).Warn().Int("storedKeys", restored.Keys).Int("currentKeys", len(keys)).
+			Msg("the captured elements changed while the connector was stopped, deletes of elements " +
+				"that disappeared meanwhile aren't emitted, as the position holds only a digest of their keys")
+	}
+
+	s.reconciler.update(keys, states)
+
+	return nil
+}
+
+// loadKeys returns keys of all elements the polling captures by their canonical JSON form,
+// and their states if the reconciler keeps them, or nil otherwise.
+func (s *Snapshot) loadKeys(
+	ctx context.Context,
+) (map[string]sdk.StructuredData, map[string]sdk.RawData, error) {
+	params := make(map[string]any)
+
+	var whereClause string
+	if conditions := s.scopeConditions(params); len(conditions) > 0 {
+		whereClause = " AND " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(reconcileKeysQueryTemplate,
+		s.matchClause, s.notNullCondition(), whereClause, s.reconcileKeyExpressions(), s.reconcileStateReturnClause(),
+	)
+
+	querylog.Log(ctx, query, params, s.logRedactProperties)
+
+	session := s.driver.NewSession(ctx, s.bookmarks.sessionConfig(s.databaseName))
+	defer session.Close(ctx)
+
+	var states map[string]sdk.RawData
+
+	keys, err := neo4j.ExecuteRead(ctx, session, func(tx neo4j.ManagedTransaction) (map[string]sdk.StructuredData, error) {
+		result, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, fmt.Errorf("run tx: %w", err)
+		}
+
+		keys := make(map[string]sdk.StructuredData)
+
+		// the states are collected from scratch, as the transaction may be retried
+		states = nil
+		if s.reconciler.states != nil {
+			states = make(map[string]sdk.RawData)
+		}
+
+		var record *db.Record
+		for result.NextRecord(ctx, &record) {
+			if len(keys) >= s.reconciler.maxKeys {
+				return nil, errTooManyKeys
+			}
+
+			values, _ := record.Values[0].([]any)
+
+			key := s.reconciledKey(values)
+
+			canonical, err := canonicalKey(key)
+			if err != nil {
+				return nil, err
+			}
+
+			keys[canonical] = key
+
+			if states != nil {
+				if states[canonical], err = s.reconciledState(record); err != nil {
+					return nil, fmt.Errorf("get state: %w", err)
+				}
+			}
+		}
+
+		if err := result.Err(); err != nil {
+			return nil, fmt.Errorf("iterate result: %w", err)
+		}
+
+		return keys, nil
+	}, config.TxTimeout(s.queryTimeout))
+	if err != nil {
+		return nil, nil, fmt.Errorf("execute read: %w", err)
+	}
+
+	s.bookmarks.update(session)
+
+	return keys, states, nil
+}
+
+// reconcileStateReturnClause returns the clause of the reconciliation query that returns the states of elements
+// if the reconciler keeps them, or an empty string otherwise.
+func (s *Snapshot) reconcileStateReturnClause() string {
+	switch {
+	case s.reconciler == nil || s.reconciler.states == nil:
+		return ""
+
+	case s.entityType == config.EntityTypeRelationship:
+		return reconcileRelationshipStateReturnClause
+
+	default:
+		return reconcileNodeStateReturnClause
+	}
+}
+
+// reconciledState constructs the payload of an element from the values of the reconcileStateReturnClause,
+// so it has the same fields as the payload of the element record, except for the projections.
+func (s *Snapshot) reconciledState(record *db.Record) (sdk.RawData, error) {
+	stateRaw, _ := record.Get(reconcileStatePlaceholder)
+
+	props, ok := stateRaw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("record doesn't contain %q map", reconcileStatePlaceholder)
+	}
+
+	if s.entityType == config.EntityTypeRelationship {
+		sourceRaw, _ := record.Get(reconcileSourcePlaceholder)
+		targetRaw, _ := record.Get(reconcileTargetPlaceholder)
+
+		source, sourceOK := sourceRaw.(dbtype.Node)
+		target, targetOK := targetRaw.(dbtype.Node)
+
+		if !sourceOK || !targetOK {
+			return nil, errConvertRawNode
+		}
+
+		if err := resolveReservedFields(props, s.fieldCollision); err != nil {
+			return nil, fmt.Errorf("resolve reserved fields: %w", err)
+		}
+
+		props[sourceNodeField] = schema.Node{Labels: source.Labels, Key: source.Props}
+		props[targetNodeField] = schema.Node{Labels: target.Labels, Key: target.Props}
+	}
+
+	s.decodeHistory(props)
+
+	if err := s.vectors.Convert(props); err != nil {
+		return nil, fmt.Errorf("convert vectors: %w", err)
+	}
+
+	payload, err := marshalPayload(props, s.payloadFormat)
+	if err != nil {
+		return nil, fmt.Errorf("marshal state: %w", err)
+	}
+
+	return sdk.RawData(payload), nil
+}
+
+// reconcileKeyExpressions returns the expressions of the reconciliation query which values compose a key,
+// they're the properties of the endpoints if relationships are keyed by them, or the keyProperties otherwise.
+func (s *Snapshot) reconcileKeyExpressions() string {
+	if s.keyByEndpoints && s.entityType == config.EntityTypeRelationship {
+		return reconcileEndpointsKeyExpressions
+	}
+
+	keyExpressions := make([]string, len(s.keyProperties))
+	for i, keyProperty := range s.keyProperties {
+		keyExpressions[i] = objPlaceholder + "." + escapeIdentifier(keyProperty)
+	}
+
+	return strings.Join(keyExpressions, ", ")
+}
+
+// reconciledKey constructs a key of an element from the values of the reconcileKeyExpressions,
+// so it equals the key of the element record constructed by the recordKey.
+func (s *Snapshot) reconciledKey(values []any) sdk.StructuredData {
+	if s.keyByEndpoints && s.entityType == config.EntityTypeRelationship {
+		var sourceKey, targetKey map[string]any
+		if len(values) == 2 {
+			sourceKey, _ = values[0].(map[string]any)
+			targetKey, _ = values[1].(map[string]any)
+		}
+
+		return schema.EncodeValues(endpointsKey(sourceKey, targetKey))
+	}
+
+	key := make(sdk.StructuredData, len(s.keyProperties))
+	for i, keyProperty := range s.keyProperties {
+		if i < len(values) {
+			key[keyProperty] = values[i]
+		}
+	}
+
+	return schema.EncodeValues(key)
+}
+
+// nextDeleted returns a delete record of the next element that disappeared.
+// The record takes the position of the previous record, as deletes don't move the polling forward.
+func (s *Snapshot) nextDeleted() (sdk.Record, error) {
+	key := s.reconciler.popDeleted()
+
+	sdkPosition, err := s.CurrentPosition()
+	if err != nil {
+		return sdk.Record{}, fmt.Errorf("get current position: %w", err)
+	}
+
+	metadata := sdk.Metadata{metadataEntityLabelsField: s.entityLabels}
+	s.metadataFields.set(metadata)
+	metadata.SetCreatedAt(time.Now())
+
+	record := sdk.Util.Source.NewRecordDelete(sdkPosition, metadata, key)
+
+	// the payload before holds the last known state of the element if the states are kept
+	if state := s.reconciler.deletedState(key); state != nil {
+		record.Payload.Before = state
+	}
+
+	return record, nil
+}
+
+// keysDigest returns the sum of hashes of the keys by their canonical forms, see the [ReconcileState].
+func keysDigest(keys map[string]sdk.StructuredData) uint64 {
+	var digest uint64
+	for canonical := range keys {
+		digest += keyHash(canonical)
+	}
+
+	return digest
+}
+
+// keyHash returns a hash of the canonical form of a key, it's the first 8 bytes of its SHA-256 sum.
+func keyHash(canonical string) uint64 {
+	sum := sha256.Sum256([]byte(canonical))
+
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// formatDigest returns the hex form of the digest that is stored in positions.
+func formatDigest(digest uint64) string {
+	return fmt.Sprintf("%016x", digest)
+}
+
+// canonicalKey returns the JSON form of the key, which has sorted fields, so equal keys have equal forms.
+func canonicalKey(key sdk.StructuredData) (string, error) {
+	canonical, err := json.Marshal(key)
+	if err != nil {
+		return "", fmt.Errorf("marshal key: %w", err)
+	}
+
+	return string(canonical), nil
+}
@@ -0,0 +1,327 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// reconciledKeys returns keys of elements with the ids by their canonical JSON form.
+func reconciledKeys(t *testing.T, ids ...int64) map[string]sdk.StructuredData {
+	t.Helper()
+
+	keys := make(map[string]sdk.StructuredData, len(ids))
+	for _, id := range ids {
+		key := sdk.StructuredData{"id": id}
+
+		canonical, err := canonicalKey(key)
+		is.New(t).NoErr(err)
+
+		keys[canonical] = key
+	}
+
+	return keys
+}
+
+func TestReconciler_update(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	r := newReconciler(time.Minute, 10, false)
+	is.True(r.due(time.Now()))
+
+	// the first update only takes the baseline
+	r.update(reconciledKeys(t, 1, 2, 3), nil)
+	is.Equal(len(r.deleted), 0)
+
+	// an element created after the baseline is observed when it's emitted, so its deletion is detected too
+	r.observe(sdk.StructuredData{"id": int64(4)}, nil)
+
+	r.update(reconciledKeys(t, 2), nil)
+	is.Equal(r.deleted, []sdk.StructuredData{{"id": int64(1)}, {"id": int64(3)}, {"id": int64(4)}})
+
+	r.lastRun = time.Now()
+	is.True(!r.due(time.Now()))
+	is.True(r.due(time.Now().Add(time.Minute)))
+}
+
+func TestReconciler_state(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// there's no state until the baseline is taken
+	var disabled *reconciler
+	is.Equal(disabled.state(), nil)
+
+	r := newReconciler(time.Minute, 10, false)
+	is.Equal(r.state(), nil)
+
+	r.update(reconciledKeys(t, 1, 2, 3), nil)
+
+	baseline := r.state()
+	is.Equal(baseline.Keys, 3)
+
+	// the digest doesn't depend on the order the keys are known in
+	observed := newReconciler(time.Minute, 10, false)
+	observed.update(reconciledKeys(t, 3), nil)
+	observed.observe(sdk.StructuredData{"id": int64(2)}, nil)
+	observed.observe(sdk.StructuredData{"id": int64(1)}, nil)
+	observed.observe(sdk.StructuredData{"id": int64(1)}, nil)
+	is.Equal(observed.state(), baseline)
+
+	// the deleted keys are known to positions until they're emitted
+	r.update(reconciledKeys(t, 1), nil)
+	is.Equal(r.state(), baseline)
+
+	is.Equal(r.popDeleted(), sdk.StructuredData{"id": int64(2)})
+	is.Equal(r.popDeleted(), sdk.StructuredData{"id": int64(3)})
+
+	only := newReconciler(time.Minute, 10, false)
+	only.update(reconciledKeys(t, 1), nil)
+	is.Equal(r.state(), only.state())
+}
+
+func TestReconciler_verifyRestored(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	stopped := newReconciler(time.Minute, 10, false)
+	stopped.update(reconciledKeys(t, 1, 2), nil)
+
+	// the restored state is kept in positions until the first reconciliation
+	resumed := newReconciler(time.Minute, 10, false)
+	resumed.restored = stopped.state()
+	is.Equal(resumed.state(), stopped.state())
+
+	is.True(resumed.verifyRestored(reconciledKeys(t, 1, 2)))
+	is.Equal(resumed.restored, nil)
+
+	// an element was deleted while the connector was stopped
+	resumed = newReconciler(time.Minute, 10, false)
+	resumed.restored = stopped.state()
+	is.True(!resumed.verifyRestored(reconciledKeys(t, 1)))
+
+	// an element was replaced while the connector was stopped, so only the digest differs
+	resumed = newReconciler(time.Minute, 10, false)
+	resumed.restored = stopped.state()
+	is.True(!resumed.verifyRestored(reconciledKeys(t, 1, 3)))
+
+	// there's nothing to compare without the restored state
+	is.True(newReconciler(time.Minute, 10, false).verifyRestored(reconciledKeys(t, 1)))
+}
+
+func TestReconciler_states(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	alice := sdk.StructuredData{"id": int64(1)}
+	bob := sdk.StructuredData{"id": int64(2)}
+
+	aliceCanonical, err := canonicalKey(alice)
+	is.NoErr(err)
+
+	bobCanonical, err := canonicalKey(bob)
+	is.NoErr(err)
+
+	r := newReconciler(time.Minute, 10, true)
+
+	r.update(map[string]sdk.StructuredData{aliceCanonical: alice, bobCanonical: bob}, map[string]sdk.RawData{
+		aliceCanonical: sdk.RawData(`{"id":1,"name":"Alice"}`),
+		bobCanonical:   sdk.RawData(`{"id":2,"name":"Bob"}`),
+	})
+
+	// the emitted state replaces the one of the baseline
+	r.observe(alice, sdk.RawData(`{"id":1,"name":"Alicia"}`))
+
+	r.update(map[string]sdk.StructuredData{}, map[string]sdk.RawData{})
+	is.Equal(r.deleted, []sdk.StructuredData{alice, bob})
+
+	// the states of the deleted elements are kept until they're emitted
+	is.Equal(r.deletedState(alice), sdk.RawData(`{"id":1,"name":"Alicia"}`))
+	is.Equal(r.deletedState(bob), sdk.RawData(`{"id":2,"name":"Bob"}`))
+	is.Equal(len(r.states), 0)
+	is.Equal(r.deletedState(alice), nil)
+}
+
+func TestSnapshot_Next_deletedState(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	key := sdk.StructuredData{"id": int64(2)}
+
+	canonical, err := canonicalKey(key)
+	is.NoErr(err)
+
+	s := &Snapshot{
+		orderingProperty: "id",
+		keyProperties:    []string{"id"},
+		entityLabels:     "Person",
+		polling:          true,
+		records:          make(chan element, 1),
+		reconciler: &reconciler{
+			deleted: []sdk.StructuredData{key},
+			states:  map[string]sdk.RawData{canonical: sdk.RawData(`{"id":2,"name":"Bob"}`)},
+		},
+	}
+
+	record, err := s.Next(context.Background())
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationDelete)
+	is.Equal(record.Key, key)
+	is.Equal(record.Payload.Before, sdk.RawData(`{"id":2,"name":"Bob"}`))
+}
+
+func TestSnapshot_Next_reconcileState(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	s := &Snapshot{
+		orderingProperty: "id",
+		keyProperties:    []string{"id"},
+		entityLabels:     "Person",
+		polling:          true,
+		records:          make(chan element, 1),
+		reconciler:       newReconciler(time.Minute, 10, false),
+	}
+
+	s.reconciler.update(reconciledKeys(t, 1), nil)
+
+	s.records <- element{props: map[string]any{"id": int64(2)}}
+
+	// the position of the record includes its own key, so a restart right after it doesn't miss it
+	record, err := s.Next(context.Background())
+	is.NoErr(err)
+
+	position, err := ParsePosition(record.Position)
+	is.NoErr(err)
+	is.Equal(position.Reconcile, s.reconciler.state())
+	is.Equal(position.Reconcile.Keys, 2)
+}
+
+func TestSnapshot_reconciledState(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	s := &Snapshot{
+		entityType:     config.EntityTypeRelationship,
+		fieldCollision: FieldCollisionPrefix,
+		payloadFormat:  PayloadFormatJSON,
+		reconciler:     newReconciler(time.Minute, 10, true),
+	}
+
+	is.Equal(s.reconcileStateReturnClause(), reconcileRelationshipStateReturnClause)
+
+	state, err := s.reconciledState(&db.Record{
+		Keys: []string{reconcileKeyPlaceholder, reconcileStatePlaceholder, reconcileSourcePlaceholder,
+			reconcileTargetPlaceholder,
+		},
+		Values: []any{
+			[]any{int64(1)},
+			map[string]any{"id": int64(1), sourceNodeField: "colliding"},
+			dbtype.Node{Labels: []string{"Person"}, Props: map[string]any{"id": int64(2)}},
+			dbtype.Node{Labels: []string{"Company"}, Props: map[string]any{"id": int64(3)}},
+		},
+	})
+	is.NoErr(err)
+	is.Equal(string(state), `{"_sourceNode":"colliding","id":1,`+
+		`"sourceNode":{"labels":["Person"],"key":{"id":2}},"targetNode":{"labels":["Company"],"key":{"id":3}}}`)
+
+	// the states aren't returned unless they're kept
+	s.reconciler = newReconciler(time.Minute, 10, false)
+	is.Equal(s.reconcileStateReturnClause(), "")
+}
+
+func TestSnapshot_Next_deleted(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	s := &Snapshot{
+		orderingProperty: "id",
+		keyProperties:    []string{"id"},
+		entityLabels:     "Person",
+		polling:          true,
+		position:         &Position{Version: PositionVersion, Mode: ModeSnapshotPolling, LastProcessedValue: int64(5)},
+		records:          make(chan element, 1),
+		reconciler:       &reconciler{deleted: []sdk.StructuredData{{"id": int64(2)}}},
+	}
+
+	// the delete takes the position of the previous record, so the polling isn't moved by it
+	record, err := s.Next(context.Background())
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationDelete)
+	is.Equal(record.Key, sdk.StructuredData{"id": int64(2)})
+	is.Equal(record.Metadata[metadataEntityLabelsField], "Person")
+
+	position, err := ParsePosition(record.Position)
+	is.NoErr(err)
+	is.Equal(position.LastProcessedValue, float64(5))
+	is.Equal(len(s.reconciler.deleted), 0)
+}
+
+func TestSnapshot_reconciledKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		snapshot        *Snapshot
+		values          []any
+		wantExpressions string
+		want            sdk.StructuredData
+	}{
+		{
+			name:            "success_keyProperties",
+			snapshot:        &Snapshot{entityType: config.EntityTypeNode, keyProperties: []string{"id", "first name"}},
+			values:          []any{int64(1), "Alice"},
+			wantExpressions: "obj.`id`, obj.`first name`",
+			want:            sdk.StructuredData{"id": int64(1), "first name": "Alice"},
+		},
+		{
+			name: "success_keyByEndpoints",
+			snapshot: &Snapshot{
+				entityType: config.EntityTypeRelationship, keyProperties: []string{"id"}, keyByEndpoints: true,
+			},
+			values:          []any{map[string]any{"name": "Alice"}, map[string]any{"name": "Acme"}},
+			wantExpressions: reconcileEndpointsKeyExpressions,
+			want:            sdk.StructuredData{"source_name": "Alice", "target_name": "Acme"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			is.Equal(tt.snapshot.reconcileKeyExpressions(), tt.wantExpressions)
+			is.Equal(tt.snapshot.reconciledKey(tt.values), tt.want)
+		})
+	}
+}
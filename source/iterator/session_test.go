@@ -0,0 +1,136 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/mock"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/mock/gomock"
+)
+
+var errTestSession = errors.New("test session")
+
+// newFailingDriver returns a driver that creates a single session and stores its config in the config,
+// the session fails all transactions with the errTestSession.
+func newFailingDriver(ctrl *gomock.Controller, config *neo4j.SessionConfig) *mock.MockDriver {
+	session, sessionMock := mock.NewSession(ctrl)
+	sessionMock.EXPECT().ExecuteRead(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errTestSession)
+	sessionMock.EXPECT().Close(gomock.Any()).Return(nil)
+
+	driver := mock.NewMockDriver(ctrl)
+	driver.EXPECT().NewSession(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, sessionConfig neo4j.SessionConfig) neo4j.SessionWithContext {
+			*config = sessionConfig
+
+			return session
+		},
+	)
+
+	return driver
+}
+
+func TestSessionAccessMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		run  func(ctx context.Context, driver neo4j.DriverWithContext) error
+	}{
+		{
+			name: "snapshot_loadBatch",
+			run: func(ctx context.Context, driver neo4j.DriverWithContext) error {
+				return (&Snapshot{driver: driver, orderingProperty: "id"}).loadBatch(ctx)
+			},
+		},
+		{
+			name: "maxPropertyValue",
+			run: func(ctx context.Context, driver neo4j.DriverWithContext) error {
+				_, err := maxPropertyValue(ctx, SnapshotParams{
+					Driver:           driver,
+					OrderingProperty: "id",
+					EntityType:       config.EntityTypeNode,
+					EntityLabels:     []string{"Person"},
+				})
+
+				return err
+			},
+		},
+		{
+			name: "maxPropertyValue_query",
+			run: func(ctx context.Context, driver neo4j.DriverWithContext) error {
+				_, err := maxPropertyValue(ctx, SnapshotParams{
+					Driver:           driver,
+					OrderingProperty: "id",
+					Query:            "MATCH (obj:Person)",
+				})
+
+				return err
+			},
+		},
+		{
+			name: "reconcile",
+			run: func(ctx context.Context, driver neo4j.DriverWithContext) error {
+				return (&Snapshot{driver: driver, orderingProperty: "id", reconciler: newReconciler(0, 0, false)}).
+					reconcile(ctx)
+			},
+		},
+		{
+			name: "cdc_loadBatch",
+			run: func(ctx context.Context, driver neo4j.DriverWithContext) error {
+				return (&CDC{driver: driver}).loadBatch(ctx)
+			},
+		},
+		{
+			name: "CurrentChangeID",
+			run: func(ctx context.Context, driver neo4j.DriverWithContext) error {
+				_, err := CurrentChangeID(ctx, driver, "")
+
+				return err
+			},
+		},
+		{
+			name: "SampleOrderingProperty",
+			run: func(ctx context.Context, driver neo4j.DriverWithContext) error {
+				_, err := SampleOrderingProperty(ctx, driver, "", []string{"Person"}, LabelMatchAll, "id",
+					config.EntityTypeNode, OrderingDirectionAsc, 0,
+				)
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+			ctrl := gomock.NewController(t)
+
+			var sessionConfig neo4j.SessionConfig
+
+			// the source only reads, so routing drivers can send its queries to the read replicas
+			err := tt.run(context.Background(), newFailingDriver(ctrl, &sessionConfig))
+			is.True(errors.Is(err, errTestSession))
+			is.Equal(sessionConfig.AccessMode, neo4j.AccessModeRead)
+		})
+	}
+}
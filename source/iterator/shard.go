@@ -0,0 +1,38 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import "fmt"
+
+const (
+	// shardHashAlphabet holds the characters of element ids, an element id is hashed by the positions
+	// of its characters in the alphabet, as Cypher has no hash function without APOC.
+	// A character out of the alphabet is hashed as if it were right after its last character.
+	shardHashAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz-:"
+	// shardHashMultiplier and shardHashModulus define the polynomial hash of element ids,
+	// the modulus keeps the hash far below the max integer of Cypher.
+	shardHashMultiplier = 31
+	shardHashModulus    = 2147483647
+	// shardWhereClauseTemplate matches elements which element id hash modulo the shard count equals the shard index.
+	shardWhereClauseTemplate = "reduce(h = 0, c IN split(elementId(obj), '') | " +
+		"(h * %d + size(split('%s', c)[0])) %% %d) %% $shardCount = $shardIndex"
+)
+
+// shardWhereClause returns a condition that matches elements of the shard by a hash of their element ids,
+// which don't change during the lifetime of elements, unlike the deprecated internal ids,
+// so an element belongs to the same shard across restarts.
+func shardWhereClause() string {
+	return fmt.Sprintf(shardWhereClauseTemplate, shardHashMultiplier, shardHashAlphabet, shardHashModulus)
+}
@@ -0,0 +1,75 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+// shardOf computes the shard of the element id the same way as the shardWhereClause does in Cypher,
+// where the size of the alphabet part preceding a character is the position of the character.
+func shardOf(elementID string, shardCount int) int {
+	hash := 0
+	for _, char := range elementID {
+		position := strings.IndexRune(shardHashAlphabet, char)
+		if position < 0 {
+			position = len(shardHashAlphabet)
+		}
+
+		hash = (hash*shardHashMultiplier + position) % shardHashModulus
+	}
+
+	return hash % shardCount
+}
+
+func TestShardWhereClause(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.Equal(shardWhereClause(), "reduce(h = 0, c IN split(elementId(obj), '') | "+
+		"(h * 31 + size(split('0123456789abcdefghijklmnopqrstuvwxyz-:', c)[0])) % 2147483647)"+
+		" % $shardCount = $shardIndex")
+}
+
+func TestShardOf_split(t *testing.T) {
+	t.Parallel()
+
+	const elementsCount = 3000
+
+	for _, shardCount := range []int{2, 3, 4, 8} {
+		t.Run(fmt.Sprint(shardCount), func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			// element ids of a database consist of the database id and a sequential number
+			counts := make([]int, shardCount)
+			for i := 0; i < elementsCount; i++ {
+				counts[shardOf(fmt.Sprintf("4:0f6d8c1e-3b2a-4c5d-9e8f-7a6b5c4d3e2f:%d", i), shardCount)]++
+			}
+
+			// each shard gets a fair part of the elements
+			expected := elementsCount / shardCount
+			for _, count := range counts {
+				is.True(count > expected*3/4 && count < expected*5/4) // shard is out of balance
+			}
+		})
+	}
+}
@@ -16,13 +16,15 @@ package iterator
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/querylog"
 	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
@@ -33,39 +35,236 @@ import (
 const (
 	// all Cypher queries used by the [Snapshot] are listed below in the format of Go fmt.
 	getNodeMaxPropertyQueryTemplate = `
-	MATCH (obj:%s) WHERE obj.%s IS NOT NULL
-	RETURN obj.%s as %s ORDER BY obj.%s DESC LIMIT 1`
+	MATCH (obj%s) WHERE obj.%s IS NOT NULL%s
+	RETURN obj.%s as %s ORDER BY obj.%s %s LIMIT 1`
 
 	getRelationshipMaxPropertyQueryTemplate = `
-	MATCH ()-[obj:%s]-() WHERE obj.%s IS NOT NULL
-	RETURN obj.%s as %s ORDER BY obj.%s DESC LIMIT 1`
+	MATCH ()-[obj%s]-() WHERE obj.%s IS NOT NULL%s
+	RETURN obj.%s as %s ORDER BY obj.%s %s LIMIT 1`
+
+	getQueryMaxPropertyQueryTemplate = `
+	CALL { %s } WITH obj WHERE obj.%s IS NOT NULL
+	RETURN obj.%s as %s ORDER BY obj.%s %s LIMIT 1`
 
 	getNodesQueryTemplate = `
-	MATCH (obj:%s) WHERE obj.%s IS NOT NULL %s
-	RETURN obj ORDER BY obj.%s ASC LIMIT %d`
+	%s WHERE %s %s
+	RETURN obj%s ORDER BY %s LIMIT %d`
 
 	getRelationshipsQueryTemplate = `
-	MATCH (src)-[obj:%s]->(trgt) WHERE obj.%s IS NOT NULL %s
-	RETURN obj, src, trgt ORDER BY obj.%s ASC LIMIT %d`
-
-	opmvLTEWhereClause = "obj.%s <= $opmv"
-	opvGTWhereClause   = "obj.%s > $opv"
+	%s WHERE %s %s
+	RETURN obj, src, trgt%s ORDER BY %s LIMIT %d`
+
+	// match clauses that are added to the getNodesQueryTemplate and the getRelationshipsQueryTemplate,
+	// they take the labels pattern, see the [LabelMatch.pattern],
+	// and the relationship one also takes the arrows, see the [RelationshipDirection.arrows].
+	nodesMatchClauseTemplate         = "MATCH (obj%s)"
+	relationshipsMatchClauseTemplate = "MATCH (src)%s[obj%s]%s(trgt)"
+	// seedNodesMatchClauseTemplate matches nodes reachable from the seed node within the max hops,
+	// including the seed node itself if it has the entity labels.
+	seedNodesMatchClauseTemplate = "MATCH (seed%s)-[*0..%d]-(obj%s) WITH DISTINCT obj"
+	// queryMatchClauseTemplate wraps a custom query into a subquery, so its results are paginated as usual.
+	queryMatchClauseTemplate = "CALL { %s } WITH *"
+
+	// relationship counts return clauses that are added to the getNodesQueryTemplate.
+	depth1CountReturnClause = ", COUNT { (obj)--() } AS " + depth1CountPlaceholder
+	depth2CountReturnClause = ", COUNT { (obj)--()--() } AS " + depth2CountPlaceholder
+
+	// notNullWhereClause is the first condition of the getNodesQueryTemplate and the getRelationshipsQueryTemplate.
+	notNullWhereClause = "%s.%s IS NOT NULL"
+	// opmvWhereClause and opvWhereClause take a comparison operator that depends on the ordering direction.
+	opmvWhereClause = "obj.%s %s $opmv"
+	opvWhereClause  = "%s %s $opv"
+	// existingEndpointsWhereClause keeps only relationships which endpoints existed before them,
+	// endpoints without the ordering property don't match it.
+	existingEndpointsWhereClause = "src.%[1]s %[2]s obj.%[1]s AND trgt.%[1]s %[2]s obj.%[1]s"
+	// labelWhereClause matches a node having the label, the conditions of all labels are joined with OR.
+	labelWhereClause = "obj:%s"
+	// filterWhereClause wraps a user-defined filter, so its OR operators don't break the other conditions.
+	filterWhereClause = "(%s)"
 
 	// some helpers for Cypher queries.
 	orderingPropertyMaxValueFieldName = "opmv"
 	orderingPropertyValueFieldName    = "opv"
+	shardCountFieldName               = "shardCount"
+	shardIndexFieldName               = "shardIndex"
 	objPlaceholder                    = "obj"
 	srcPlaceholder                    = "src"
 	trgtPlaceholder                   = "trgt"
+	depth1CountPlaceholder            = "depth1Count"
+	depth2CountPlaceholder            = "depth2Count"
+	// elementIDOrderingExpression is an expression the snapshot paginates elements by
+	// if it's ordered by element ids.
+	elementIDOrderingExpression = "elementId(obj)"
+
+	// recordsPerRelationship is the number of records emitted per relationship
+	// if its endpoints are emitted as separate records.
+	recordsPerRelationship = 3
+	// maxRecordsCapacity is the max capacity the records channel is allocated with,
+	// so a big batchSize doesn't allocate a buffer for records that may never be loaded.
+	maxRecordsCapacity = 10000
 
 	// relationship payload-specific fields.
 	sourceNodeField = "sourceNode"
 	targetNodeField = "targetNode"
 
+	// prefixes of endpoint properties in keys of relationship records composed of their endpoint keys.
+	sourceKeyPrefix = "source_"
+	targetKeyPrefix = "target_"
+	// reservedFieldPrefix is a prefix that is added to relationship properties
+	// which names collide with the reserved relationship payload-specific fields.
+	reservedFieldPrefix = "_"
+
 	// metadataEntityLabelsField is a name of a metadata field that holds entity labels.
 	metadataEntityLabelsField = "neo4j.entityLabels"
+	// metadataEntityTypeField is a name of a metadata field that holds an entity type,
+	// it's set only if relationship endpoints are emitted as separate records or the snapshot is a subgraph one.
+	metadataEntityTypeField = "neo4j.entityType"
+	// metadataDepth1CountField is a name of a metadata field that holds
+	// the number of relationships of a node.
+	metadataDepth1CountField = "neo4j.relationshipCount.depth1"
+	// metadataDepth2CountField is a name of a metadata field that holds
+	// the number of two-relationship paths starting at a node.
+	metadataDepth2CountField = "neo4j.relationshipCount.depth2"
+)
+
+// FieldCollision defines what to do when a relationship property
+// collides with the reserved sourceNode or targetNode fields.
+type FieldCollision string
+
+// The available field collision behaviors are listed below.
+const (
+	FieldCollisionPrefix FieldCollision = "prefix"
+	FieldCollisionError  FieldCollision = "error"
+)
+
+// OrderingDirection defines the direction in which the ordering property values of new elements grow.
+type OrderingDirection string
+
+// The available ordering directions are listed below.
+const (
+	OrderingDirectionAsc  OrderingDirection = "asc"
+	OrderingDirectionDesc OrderingDirection = "desc"
 )
 
+// SnapshotOperation defines the operation of records emitted by the initial snapshot.
+type SnapshotOperation string
+
+// The available snapshot operations are listed below.
+const (
+	SnapshotOperationSnapshot SnapshotOperation = "snapshot"
+	SnapshotOperationCreate   SnapshotOperation = "create"
+)
+
+// LabelMatch defines whether captured elements must have all of the entity labels or any of them.
+type LabelMatch string
+
+// The available label matches are listed below.
+const (
+	LabelMatchAll LabelMatch = "all"
+	LabelMatchAny LabelMatch = "any"
+)
+
+// pattern returns the labels of the obj element pattern, e.g. ":`A`:`B`",
+// or ":`A`|`B`" for relationships of any of the types, the empty match is treated as the all one.
+// It's empty for nodes having any of the labels, as they're matched by the [LabelMatch.condition] instead.
+func (m LabelMatch) pattern(labels []string, entityType config.EntityType) string {
+	if m != LabelMatchAny {
+		return ":" + cypherLabels(labels)
+	}
+
+	labels = config.NormalizeLabels(labels)
+
+	if entityType == config.EntityTypeRelationship {
+		escapedTypes := make([]string, len(labels))
+		for i, label := range labels {
+			escapedTypes[i] = escapeIdentifier(label)
+		}
+
+		return ":" + strings.Join(escapedTypes, relationshipTypesSeparator)
+	}
+
+	return ""
+}
+
+// condition returns a condition that matches nodes having any of the labels, e.g. "(obj:`A` OR obj:`B`)",
+// it's empty if the elements are matched by the [LabelMatch.pattern].
+func (m LabelMatch) condition(labels []string, entityType config.EntityType) string {
+	if m != LabelMatchAny || entityType == config.EntityTypeRelationship || len(labels) == 0 {
+		return ""
+	}
+
+	labels = config.NormalizeLabels(labels)
+
+	conditions := make([]string, len(labels))
+	for i, label := range labels {
+		conditions[i] = fmt.Sprintf(labelWhereClause, escapeIdentifier(label))
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")"
+}
+
+// andCondition returns the condition prefixed with AND, so it can be appended to other conditions,
+// or an empty string if the condition is empty.
+func andCondition(condition string) string {
+	if condition == "" {
+		return ""
+	}
+
+	return " AND " + condition
+}
+
+// keyword returns the Cypher ORDER BY keyword of the direction,
+// the empty direction is treated as the ascending one.
+func (d OrderingDirection) keyword() string {
+	if d == OrderingDirectionDesc {
+		return "DESC"
+	}
+
+	return "ASC"
+}
+
+// reverseKeyword returns the Cypher ORDER BY keyword of the opposite direction,
+// it's used to get the last element in the direction.
+func (d OrderingDirection) reverseKeyword() string {
+	if d == OrderingDirectionDesc {
+		return "ASC"
+	}
+
+	return "DESC"
+}
+
+// after returns the Cypher operator that matches values following a value in the direction.
+func (d OrderingDirection) after() string {
+	if d == OrderingDirectionDesc {
+		return "<"
+	}
+
+	return ">"
+}
+
+// before returns the Cypher operator that matches values preceding a value in the direction.
+func (d OrderingDirection) before() string {
+	if d == OrderingDirectionDesc {
+		return ">"
+	}
+
+	return "<"
+}
+
+// element holds properties of a fetched Neo4j element
+// along with additional metadata that should be attached to its record.
+type element struct {
+	props    map[string]any
+	metadata sdk.Metadata
+	// endpoint defines if the element is an endpoint node of a relationship
+	// that is emitted as a separate record.
+	endpoint bool
+	// batchEnd defines if the element is the last element of a batch.
+	batchEnd bool
+	// elementID is an id of the element, it's used as a position value if the snapshot is ordered by element ids.
+	elementID string
+}
+
 // Snapshot implements a snapshot logic for the connector.
 type Snapshot struct {
 	driver                   neo4j.DriverWithContext
@@ -74,15 +273,127 @@ type Snapshot struct {
 	orderingPropertyMaxValue any
 	entityType               config.EntityType
 	entityLabels             string
-	batchSize                int
-	databaseName             string
-	position                 *Position
+	// labels holds the entity labels that are stored in positions.
+	labels []string
+	// matchClause is a MATCH clause that scopes the captured elements.
+	matchClause string
+	// labelsCondition is a condition that matches nodes having any of the entity labels,
+	// it's empty if the matchClause matches the labels on its own.
+	labelsCondition string
+	// endpointLabelsCondition is a condition that matches relationships which endpoints have the labels
+	// of their type, and the endpointLabelsParams hold the types it references.
+	endpointLabelsCondition string
+	endpointLabelsParams    map[string]any
+	batchSize               int
+	databaseName            string
+	fieldCollision          FieldCollision
+	payloadFormat           PayloadFormat
+	// relationshipDirection defines the direction relationships are matched in relative to the src node,
+	// if it's both, a relationship which both orientations match is captured once.
+	relationshipDirection RelationshipDirection
+	// relationshipCountsDepth defines the max depth of relationship counts
+	// that are attached to node records, zero means the counts are not included.
+	relationshipCountsDepth int
+	// softDeleteField is a name of a property that marks an element as soft-deleted
+	// if its value is equal to the softDeleteValue.
+	softDeleteField string
+	softDeleteValue string
+	// includeDeletedState defines if the payload before of soft-deleted element records holds their state.
+	includeDeletedState bool
+	// shardCount and shardIndex define a slice of elements the snapshot captures,
+	// only elements which element id hash modulo shardCount equals to shardIndex are captured.
+	shardCount int
+	shardIndex int
+	// historyProperty is a name of a property with a history maintained by APOC triggers,
+	// if historyDecodeJSON is true, its JSON entries are decoded.
+	historyProperty   string
+	historyDecodeJSON bool
+	// vectors defines the properties which values are converted into lists of floats.
+	vectors schema.Vectors
+	// emitEndpointsAsRecords defines if endpoint nodes of relationships
+	// are emitted as separate records before relationship records.
+	emitEndpointsAsRecords bool
+	// keyByEndpoints defines if keys of relationship records are composed of their endpoint keys.
+	keyByEndpoints bool
+	// existingEndpointsOnly defines if only relationships which endpoints have the ordering property
+	// preceding the relationships' one are captured.
+	existingEndpointsOnly bool
+	// orderingDirection defines the direction elements are paginated in,
+	// the orderingPropertyMaxValue is the last value in this direction, e.g. the minimum one for the descending.
+	orderingDirection OrderingDirection
+	// filter is a Cypher predicate referencing the obj that narrows the captured elements,
+	// filterParams are the query parameters it references.
+	filter       string
+	filterParams map[string]string
+	// degreeFilter narrows the captured nodes to the ones without incoming, outgoing or any relationships.
+	degreeFilter DegreeFilter
+	// includeElementID defines if element ids are put into the metadata of records,
+	// and into their payloads by the elementIDField if it's not empty.
+	includeElementID bool
+	elementIDField   string
+	// projections maps payload field names to Cypher expressions which values are computed
+	// by the snapshot queries and merged into the payload.
+	projections map[string]string
+	// logRedactProperties holds names of query parameters and properties
+	// which values are redacted in logged queries.
+	logRedactProperties []string
+	// alignPositionsToBatches defines if positions of records point to the batchStart value,
+	// except the last record of a batch, which points to itself.
+	alignPositionsToBatches bool
+	// batchStart holds the last processed value the current batch was loaded after.
+	batchStart any
+	// emitOrder defines if the records of a batch are emitted in the order of their keys,
+	// in which case positions are aligned to batches and the batchEndValue holds the position value
+	// of the end of the batch, as the last emitted record isn't the last one in the ordering.
+	emitOrder     EmitOrder
+	batchEndValue any
+	// changeID is an identifier of a CDC change the capture that follows the snapshot starts after.
+	changeID string
+	// byElementID defines if elements are paginated by their element ids instead of the orderingProperty.
+	byElementID bool
+	position    *Position
 	// records stores fetched and parsed Neo4j records,
 	// this channel works as a queue from which the Next method takes records.
-	records chan map[string]any
+	records chan element
 	// polling defines if the snapshot is used to detect insertions
 	// by polling for new documents.
 	polling bool
+	// snapshotOperation defines the operation of records emitted by the snapshot that isn't polling.
+	snapshotOperation SnapshotOperation
+	// subgraph defines if the snapshot is a part of a subgraph snapshot,
+	// so its records hold the entity type in the metadata.
+	subgraph bool
+	// relationshipTypes holds types of relationships that are captured by a subgraph snapshot
+	// between nodes captured by the snapshot of nodes, it's empty for other snapshots.
+	relationshipTypes []string
+	// metadataFields holds the optional fields put into the metadata of records.
+	metadataFields metadataFields
+	// reconciler detects deleted elements for the polling snapshot, it's nil if the detection is disabled.
+	reconciler *reconciler
+	// resumeGrace is a window behind the last processed value the polling re-reads on each poll,
+	// the graceCursor is the value the current poll pages elements after, it's nil between polls,
+	// and the graceSeen holds the ordering property values of elements emitted within the window by their keys.
+	resumeGrace time.Duration
+	graceCursor any
+	graceSeen   map[string]time.Time
+	// graceBaseline is the last processed value the first poll started with, the elements up to it
+	// are treated as emitted before, as the seen elements of the snapshot and of previous runs aren't known,
+	// and graceSeeded defines if the first poll has ended.
+	graceBaseline *time.Time
+	graceSeeded   bool
+	// customPositionStrategy defines the values stored in positions and how the capture resumes from them,
+	// the default strategy built from the ordering property is used if it's nil.
+	customPositionStrategy PositionStrategy
+	// sort holds the properties with their directions the snapshot paginates elements by
+	// instead of the orderingProperty, the polling still uses the orderingProperty.
+	sort []SortKey
+	// bookmarks are passed to the read sessions and updated after them, they're nil unless the causal consistency
+	// is enabled, see the [Bookmarks].
+	bookmarks *Bookmarks
+	// rateLimiter limits the rate of the queries that load batches, it's nil unless the rate limit is set.
+	rateLimiter *RateLimiter
+	// queryTimeout is the max execution time of the read transactions, zero keeps the server's default.
+	queryTimeout time.Duration
 }
 
 // SnapshotParams is incoming params for the [NewSnapshot] function.
@@ -94,28 +405,157 @@ type SnapshotParams struct {
 	EntityLabels     []string
 	BatchSize        int
 	DatabaseName     string
-	Position         *Position
+	FieldCollision   FieldCollision
+	PayloadFormat    PayloadFormat
+	// RelationshipDirection is the direction relationships are matched in relative to the src node,
+	// the empty RelationshipDirection is treated as the outgoing one.
+	RelationshipDirection RelationshipDirection
+	// RelationshipCountsDepth is the max depth of relationship counts
+	// attached to node records, zero disables the counts.
+	RelationshipCountsDepth int
+	// SoftDeleteField is a name of a property that marks an element as soft-deleted
+	// if its value is equal to the SoftDeleteValue, the empty SoftDeleteField disables the detection.
+	SoftDeleteField string
+	SoftDeleteValue string
+	// IncludeDeletedState defines if the payload before of soft-deleted element records holds their state.
+	IncludeDeletedState bool
+	// ShardCount and ShardIndex define a slice of elements to capture,
+	// if the ShardCount is less than 2, all elements are captured.
+	ShardCount int
+	ShardIndex int
+	// SeedNodeMatch is a Cypher node pattern of a seed node, e.g. ":Person {id: 1}",
+	// if it's set, only nodes reachable from the seed node within the MaxHops are captured.
+	SeedNodeMatch string
+	MaxHops       int
+	// HistoryProperty is a name of a property with a history maintained by APOC triggers,
+	// that is normalized into a list of entries, the empty HistoryProperty disables the normalization.
+	HistoryProperty   string
+	HistoryDecodeJSON bool
+	// Vectors defines the properties which values are converted into lists of floats
+	// and checked to have the expected dimensions, the zero Vectors converts none.
+	Vectors schema.Vectors
+	// EmitEndpointsAsRecords defines if endpoint nodes of relationships
+	// are emitted as separate records before relationship records.
+	EmitEndpointsAsRecords bool
+	// KeyByEndpoints defines if keys of relationship records are composed of the keys
+	// of their source and target nodes instead of the KeyProperties.
+	KeyByEndpoints bool
+	// ExistingEndpointsOnly defines if only relationships between nodes that existed before them are captured,
+	// i.e. which endpoints have the OrderingProperty preceding the relationships' one in the OrderingDirection.
+	ExistingEndpointsOnly bool
+	// OrderingDirection defines the direction in which the OrderingProperty values of new elements grow,
+	// elements are captured in this direction, the empty OrderingDirection is treated as the ascending one.
+	OrderingDirection OrderingDirection
+	// Filter is a Cypher predicate referencing the obj, e.g. "obj.active = true",
+	// only elements matching it are captured, the empty Filter disables the filtering.
+	// FilterParams are the query parameters the Filter references, e.g. $name, they must pass the [ValidateFilter].
+	Filter       string
+	FilterParams map[string]string
+	// DegreeFilter narrows the captured nodes depending on their relationships,
+	// the empty DegreeFilter is treated as the all one.
+	DegreeFilter DegreeFilter
+	// Projections maps payload field names to Cypher expressions referencing the obj, e.g. "toUpper(obj.name)",
+	// and the src and trgt if the EntityType is relationship, which values are merged into the payload,
+	// they must pass the [ValidateProjections].
+	Projections map[string]string
+	// IncludeElementID defines if the Neo4j element ids are put into the metadata of records,
+	// and into their payloads by the ElementIDField if it's not empty, it must pass the [ValidateElementIDField].
+	IncludeElementID bool
+	ElementIDField   string
+	// LogRedactProperties are names of properties which values are redacted in logged queries.
+	LogRedactProperties []string
+	// AlignPositionsToBatches defines if positions of records point to the end of the previous batch,
+	// except the last record of a batch, so a capture is resumed at a batch boundary.
+	AlignPositionsToBatches bool
+	// ChangeID is an identifier of a CDC change that is stored in positions of snapshot records,
+	// so the CDC capture that follows the snapshot starts after it, the empty ChangeID means there's no CDC.
+	ChangeID string
+	// SnapshotByElementID defines if the snapshot paginates elements by their immutable element ids
+	// instead of the OrderingProperty, the polling still uses the OrderingProperty.
+	SnapshotByElementID bool
+	// Sort defines properties with their directions the snapshot paginates elements by
+	// instead of the OrderingProperty, see the [ParseSort], the polling still uses the OrderingProperty.
+	Sort []SortKey
+	// Query is a custom Cypher query that replaces the MATCH clause, it must return the obj column,
+	// and the src and trgt columns if the EntityType is relationship, the empty Query disables it.
+	Query string
+	// IndexHint defines if the snapshot queries hint the planner to use the index of the OrderingProperty
+	// for the first of the EntityLabels.
+	IndexHint bool
+	// LabelMatch defines whether the captured elements must have all of the EntityLabels or any of them.
+	LabelMatch LabelMatch
+	// EndpointLabels maps relationship types of the EntityLabels to the labels their endpoints must have,
+	// relationships of the other types are captured regardless of their endpoints.
+	EndpointLabels map[string]EndpointLabels
+	// EmitOrder defines the order in which records of a batch are emitted,
+	// the empty EmitOrder is treated as the ordering one.
+	EmitOrder EmitOrder
+	// SnapshotOperation defines the operation of the snapshot records,
+	// the empty SnapshotOperation is treated as the snapshot one, polling records are always creates.
+	SnapshotOperation SnapshotOperation
+	// Subgraph defines if the snapshot of nodes is followed by a snapshot of relationships between them,
+	// see the [Snapshot.Relationships], so the records hold the entity type in the metadata.
+	Subgraph bool
+	// DetectDeletes defines if the polling snapshot reconciles keys of the captured elements
+	// every ReconcileInterval and emits deletes of the ones that disappeared,
+	// the reconciliation is skipped if there are more than ReconcileMaxKeys elements.
+	DetectDeletes     bool
+	ReconcileInterval time.Duration
+	ReconcileMaxKeys  int
+	// ResumeGrace is a window behind the last processed value the polling re-reads on each poll,
+	// so elements committed out of the order of the temporal OrderingProperty are captured,
+	// the elements emitted before are skipped, zero disables the re-reading.
+	ResumeGrace time.Duration
+	// PositionStrategy defines the values stored in positions and how the capture resumes from them,
+	// e.g. for a composite cursor, the nil PositionStrategy stores the ordering property values,
+	// the snapshot of relationships of a subgraph always uses the ordering property values.
+	PositionStrategy PositionStrategy
+	// OrderingPropertyType is the type the ordering property values of the Position are coerced to,
+	// the empty OrderingPropertyType keeps the values as they're parsed from the position.
+	OrderingPropertyType OrderingPropertyType
+	// IncludeMetadata holds the optional fields put into the metadata of records,
+	// they must pass the [ValidateMetadataFields]. The URI is put into the metadata without the password.
+	IncludeMetadata []MetadataField
+	URI             string
+	// RateLimiter is shared by the iterators of a source to limit the rate of the queries that load batches,
+	// the nil RateLimiter doesn't limit them.
+	RateLimiter *RateLimiter
+	// QueryTimeout is the max execution time of the read transactions, zero keeps the server's default.
+	QueryTimeout time.Duration
+	// Bookmarks are shared by the iterators of a source to read with the causal consistency,
+	// the nil Bookmarks disable it.
+	Bookmarks *Bookmarks
+	Position  *Position
 }
 
 // NewSnapshot creates a new instance of the [Snapshot].
 func NewSnapshot(ctx context.Context, params SnapshotParams) (*Snapshot, error) {
+	position, err := params.coercePosition()
+	if err != nil {
+		return nil, fmt.Errorf("coerce position: %w", err)
+	}
+
+	params.Position = position
+
 	var (
 		orderingPropertyMaxValue any
 		// join entity labels here to not do this for each individual element
 		entityLabels = strings.Join(params.EntityLabels, ":")
 	)
 
+	endpointsCondition, endpointsParams := endpointLabelsCondition(params.EntityLabels, params.EndpointLabels)
+
+	// the values of the sort properties are passed as a query parameter as well
+	redactProperties := sortLogRedactProperties(
+		logRedactProperties(params.LogRedactProperties, params.OrderingProperty), params.Sort,
+	)
+
 	switch position := params.Position; {
 	case position != nil && position.MaxElement != nil:
 		orderingPropertyMaxValue = position.MaxElement
 
 	default:
-		var err error
-		orderingPropertyMaxValue, err = getMaxPropertyValue(
-			ctx, params.Driver,
-			params.DatabaseName, entityLabels, params.OrderingProperty,
-			params.EntityType,
-		)
+		orderingPropertyMaxValue, err = maxPropertyValue(ctx, params)
 		if err != nil && !errors.Is(err, errNoElements) {
 			return nil, fmt.Errorf("get ordering property max value: %w", err)
 		}
@@ -128,43 +568,153 @@ func NewSnapshot(ctx context.Context, params SnapshotParams) (*Snapshot, error)
 		orderingPropertyMaxValue: orderingPropertyMaxValue,
 		entityType:               params.EntityType,
 		entityLabels:             entityLabels,
+		labels:                   params.EntityLabels,
+		matchClause:              matchClause(params, params.LabelMatch.pattern(params.EntityLabels, params.EntityType)),
+		labelsCondition:          labelsCondition(params),
+		endpointLabelsCondition:  endpointsCondition,
+		endpointLabelsParams:     endpointsParams,
 		batchSize:                params.BatchSize,
 		databaseName:             params.DatabaseName,
+		fieldCollision:           params.FieldCollision,
+		payloadFormat:            params.PayloadFormat,
+		relationshipDirection:    params.RelationshipDirection,
+		relationshipCountsDepth:  params.RelationshipCountsDepth,
+		softDeleteField:          params.SoftDeleteField,
+		softDeleteValue:          params.SoftDeleteValue,
+		includeDeletedState:      params.IncludeDeletedState,
+		shardCount:               params.ShardCount,
+		shardIndex:               params.ShardIndex,
+		historyProperty:          params.HistoryProperty,
+		historyDecodeJSON:        params.HistoryDecodeJSON,
+		vectors:                  params.Vectors,
+		logRedactProperties:      redactProperties,
+		alignPositionsToBatches:  params.AlignPositionsToBatches,
+		emitOrder:                params.EmitOrder,
+		snapshotOperation:        params.SnapshotOperation,
+		changeID:                 params.ChangeID,
+		byElementID:              params.SnapshotByElementID,
+		sort:                     params.Sort,
+		emitEndpointsAsRecords:   params.EmitEndpointsAsRecords,
+		keyByEndpoints:           params.KeyByEndpoints,
+		existingEndpointsOnly:    params.ExistingEndpointsOnly,
+		orderingDirection:        params.OrderingDirection,
+		filter:                   params.Filter,
+		degreeFilter:             params.DegreeFilter,
+		filterParams:             params.FilterParams,
+		projections:              params.Projections,
+		includeElementID:         params.IncludeElementID,
+		elementIDField:           params.ElementIDField,
+		subgraph:                 params.Subgraph,
 		position:                 params.Position,
-		records:                  make(chan map[string]any, params.BatchSize),
+		records:                  make(chan element, recordsCapacity(params)),
+		customPositionStrategy:   params.PositionStrategy,
+		bookmarks:                params.Bookmarks,
+		rateLimiter:              params.RateLimiter,
+		queryTimeout:             params.QueryTimeout,
+		metadataFields: newMetadataFields(
+			params.IncludeMetadata, params.DatabaseName, params.URI, params.EntityType,
+		),
 	}, nil
 }
 
 // NewPollingSnapshot creates a new instance of the [Snapshot] iterator prepared for polling.
 func NewPollingSnapshot(ctx context.Context, params SnapshotParams) (*Snapshot, error) {
+	var reconciler *reconciler
+	if params.DetectDeletes {
+		reconciler = newReconciler(params.ReconcileInterval, params.ReconcileMaxKeys, params.IncludeDeletedState)
+	}
+
 	// join entity labels here to not do this for each individual element
 	entityLabels := strings.Join(params.EntityLabels, ":")
 
-	if params.Position == nil || params.Position.Mode == ModeSnapshot {
-		orderingPropertyMaxValue, err := getMaxPropertyValue(ctx, params.Driver,
-			params.DatabaseName, entityLabels, params.OrderingProperty,
-			params.EntityType)
+	endpointsCondition, endpointsParams := endpointLabelsCondition(params.EntityLabels, params.EndpointLabels)
+
+	position, err := params.coercePosition()
+	if err != nil {
+		return nil, fmt.Errorf("coerce position: %w", err)
+	}
+
+	params.Position = position
+
+	// the resumed capture compares the keys of its first reconciliation with the ones of the stopped capture
+	if reconciler != nil && position != nil {
+		reconciler.restored = position.Reconcile
+	}
+
+	switch position := params.Position; {
+	case position != nil && position.Mode.snapshot() && position.MaxElement != nil:
+		// the snapshot was interrupted, so the polling must start right after the snapshot's max element,
+		// otherwise elements created while the connector was stopped would be skipped
+		params.Position = &Position{
+			Version:            PositionVersion,
+			Mode:               ModeSnapshotPolling,
+			LastProcessedValue: position.MaxElement,
+		}
+
+	case position == nil || position.Mode.snapshot():
+		orderingPropertyMaxValue, err := maxPropertyValue(ctx, params)
 		if err != nil && !errors.Is(err, errNoElements) {
 			return nil, fmt.Errorf("get ordering property max value: %w", err)
 		}
 
 		params.Position = &Position{
+			Version:            PositionVersion,
 			Mode:               ModeSnapshotPolling,
 			LastProcessedValue: orderingPropertyMaxValue,
 		}
 	}
 
 	return &Snapshot{
-		driver:           params.Driver,
-		keyProperties:    params.KeyProperties,
-		orderingProperty: params.OrderingProperty,
-		entityType:       params.EntityType,
-		entityLabels:     entityLabels,
-		batchSize:        params.BatchSize,
-		databaseName:     params.DatabaseName,
-		position:         params.Position,
-		records:          make(chan map[string]any, params.BatchSize),
-		polling:          true,
+		driver:                  params.Driver,
+		keyProperties:           params.KeyProperties,
+		orderingProperty:        params.OrderingProperty,
+		entityType:              params.EntityType,
+		entityLabels:            entityLabels,
+		labels:                  params.EntityLabels,
+		matchClause:             matchClause(params, params.LabelMatch.pattern(params.EntityLabels, params.EntityType)),
+		labelsCondition:         labelsCondition(params),
+		endpointLabelsCondition: endpointsCondition,
+		endpointLabelsParams:    endpointsParams,
+		batchSize:               params.BatchSize,
+		databaseName:            params.DatabaseName,
+		fieldCollision:          params.FieldCollision,
+		payloadFormat:           params.PayloadFormat,
+		relationshipDirection:   params.RelationshipDirection,
+		relationshipCountsDepth: params.RelationshipCountsDepth,
+		softDeleteField:         params.SoftDeleteField,
+		softDeleteValue:         params.SoftDeleteValue,
+		includeDeletedState:     params.IncludeDeletedState,
+		shardCount:              params.ShardCount,
+		shardIndex:              params.ShardIndex,
+		historyProperty:         params.HistoryProperty,
+		historyDecodeJSON:       params.HistoryDecodeJSON,
+		vectors:                 params.Vectors,
+		logRedactProperties:     logRedactProperties(params.LogRedactProperties, params.OrderingProperty),
+		alignPositionsToBatches: params.AlignPositionsToBatches,
+		emitOrder:               params.EmitOrder,
+		emitEndpointsAsRecords:  params.EmitEndpointsAsRecords,
+		keyByEndpoints:          params.KeyByEndpoints,
+		existingEndpointsOnly:   params.ExistingEndpointsOnly,
+		orderingDirection:       params.OrderingDirection,
+		filter:                  params.Filter,
+		degreeFilter:            params.DegreeFilter,
+		filterParams:            params.FilterParams,
+		projections:             params.Projections,
+		includeElementID:        params.IncludeElementID,
+		elementIDField:          params.ElementIDField,
+		position:                params.Position,
+		records:                 make(chan element, recordsCapacity(params)),
+		polling:                 true,
+		reconciler:              reconciler,
+		resumeGrace:             params.ResumeGrace,
+		graceSeen:               make(map[string]time.Time),
+		customPositionStrategy:  params.PositionStrategy,
+		bookmarks:               params.Bookmarks,
+		rateLimiter:             params.RateLimiter,
+		queryTimeout:            params.QueryTimeout,
+		metadataFields: newMetadataFields(
+			params.IncludeMetadata, params.DatabaseName, params.URI, params.EntityType,
+		),
 	}, nil
 }
 
@@ -174,6 +724,18 @@ func (s *Snapshot) HasNext(ctx context.Context) (bool, error) {
 		return true, nil
 	}
 
+	if s.reconciler != nil {
+		if s.reconciler.due(time.Now()) {
+			if err := s.reconcile(ctx); err != nil {
+				return false, fmt.Errorf("reconcile: %w", err)
+			}
+		}
+
+		if len(s.reconciler.deleted) > 0 {
+			return true, nil
+		}
+	}
+
 	if err := s.loadBatch(ctx); err != nil {
 		return false, fmt.Errorf("load batch: %w", err)
 	}
@@ -183,54 +745,79 @@ func (s *Snapshot) HasNext(ctx context.Context) (bool, error) {
 
 // Next returns the next available record.
 func (s *Snapshot) Next(ctx context.Context) (sdk.Record, error) {
+	// the deletes are only pending if there are no fetched records, see the HasNext
+	if s.reconciler != nil && len(s.reconciler.deleted) > 0 && len(s.records) == 0 {
+		return s.nextDeleted()
+	}
+
 	select {
 	case <-ctx.Done():
 		return sdk.Record{}, ctx.Err() //nolint:wrapcheck // there's no much to wrap here
 
-	case record := <-s.records:
-		// if the snapshot is polling new items,
-		// we mark its position as polling to identify it during pauses correctly
-		mode := ModeSnapshot
-		if s.polling {
-			mode = ModeSnapshotPolling
+	case elem := <-s.records:
+		if elem.endpoint {
+			return s.nextEndpoint(elem)
 		}
 
-		// construct the position
-		position := &Position{
-			Mode:               mode,
-			LastProcessedValue: record[s.orderingProperty],
-			MaxElement:         s.orderingPropertyMaxValue,
+		record := elem.props
+
+		// construct the position,
+		// if it's aligned to batches, only the last record of a batch moves it forward
+		lastProcessedValue := s.positionValue(elem)
+		if s.resumeGrace > 0 {
+			lastProcessedValue = s.latestValue(lastProcessedValue)
 		}
 
-		sdkPosition, err := position.MarshalSDKPosition()
+		if s.alignPositionsToBatches || s.emitOrder == EmitOrderKey {
+			switch {
+			case !elem.batchEnd:
+				lastProcessedValue = s.batchStart
+
+			case s.emitOrder == EmitOrderKey:
+				// the batch is sorted by keys, so the last record is not necessarily the end of the batch
+				lastProcessedValue = s.batchEndValue
+			}
+		}
+
+		key, err := s.recordKey(record)
 		if err != nil {
-			return sdk.Record{}, fmt.Errorf("marshal sdk position: %w", err)
+			return sdk.Record{}, fmt.Errorf("construct key: %w", err)
 		}
 
-		s.position = position
+		softDeleted := s.isSoftDeleted(record)
 
-		// construct the key
-		key := make(sdk.StructuredData)
-		for _, keyProperty := range s.keyProperties {
-			keyPropertyValue, ok := record[keyProperty]
-			if !ok {
-				return sdk.Record{}, fmt.Errorf("payload doesn't contain %q property", keyProperty)
+		// prepare the payload, soft-deleted elements are emitted as deletes,
+		// so they need a payload only if the state is included
+		var recordBytes []byte
+		if !softDeleted {
+			recordBytes, err = marshalPayload(record, s.payloadFormat)
+			if err != nil {
+				return sdk.Record{}, fmt.Errorf("marshal record: %w", err)
 			}
 
-			key[keyProperty] = keyPropertyValue
+			// the element is observed before the position is constructed,
+			// so the reconcile state of the position includes its key
+			if s.reconciler != nil {
+				s.reconciler.observe(key, recordBytes)
+			}
 		}
 
-		// construct the metadata
-		metadata := sdk.Metadata{metadataEntityLabelsField: s.entityLabels}
-		metadata.SetCreatedAt(time.Now())
+		position := s.newPosition(lastProcessedValue)
 
-		// prepare the payload
-		recordBytes, err := json.Marshal(record)
+		sdkPosition, err := position.MarshalSDKPosition()
 		if err != nil {
-			return sdk.Record{}, fmt.Errorf("marshal record: %w", err)
+			return sdk.Record{}, fmt.Errorf("marshal sdk position: %w", err)
 		}
 
-		if s.polling {
+		s.position = position
+
+		metadata := s.buildMetadata(elem)
+
+		if softDeleted {
+			return s.softDeleteRecord(sdkPosition, metadata, key, record)
+		}
+
+		if s.emitsCreates() {
 			return sdk.Util.Source.NewRecordCreate(sdkPosition, metadata, key, sdk.RawData(recordBytes)), nil
 		}
 
@@ -238,44 +825,286 @@ func (s *Snapshot) Next(ctx context.Context) (sdk.Record, error) {
 	}
 }
 
+// buildMetadata constructs the metadata of an element record. It holds the entity labels, the optional fields
+// of the metadataFields and the metadata of the element, which overrides them, e.g. the labels of endpoint nodes,
+// and the creation time. The ordering property value is included only for elements captured by the ordering.
+func (s *Snapshot) buildMetadata(elem element) sdk.Metadata {
+	metadata := sdk.Metadata{metadataEntityLabelsField: s.entityLabels}
+	s.metadataFields.set(metadata)
+
+	if s.metadataFields.orderingValue && !elem.endpoint {
+		if value, ok := elem.props[s.orderingProperty]; ok && value != nil {
+			metadata[metadataOrderingValueField] = metadataValue(value)
+		}
+	}
+
+	for name, value := range elem.metadata {
+		metadata[name] = value
+	}
+
+	metadata.SetCreatedAt(time.Now())
+
+	return metadata
+}
+
+// emitsCreates checks if the records are emitted as creates instead of snapshot records,
+// which is the case for the polling and for the snapshot configured to emit creates.
+func (s *Snapshot) emitsCreates() bool {
+	return s.polling || s.snapshotOperation == SnapshotOperationCreate
+}
+
+// positionValue returns the value of the element that is stored in positions,
+// which is the element id if elements are paginated by element ids, or the ordering property value otherwise,
+// or the value defined by the configured [PositionStrategy].
+func (s *Snapshot) positionValue(elem element) any {
+	return s.positionStrategy().NextPosition(elem.props, elem.elementID)
+}
+
+// softDeleteRecord returns a delete record of a soft-deleted element,
+// if the includeDeletedState is enabled, the payload before of the record holds the element properties.
+func (s *Snapshot) softDeleteRecord(
+	position sdk.Position, metadata sdk.Metadata, key sdk.Data, props map[string]any,
+) (sdk.Record, error) {
+	record := sdk.Util.Source.NewRecordDelete(position, metadata, key)
+	if !s.includeDeletedState {
+		return record, nil
+	}
+
+	payload, err := marshalPayload(props, s.payloadFormat)
+	if err != nil {
+		return sdk.Record{}, fmt.Errorf("marshal deleted state: %w", err)
+	}
+
+	record.Payload.Before = sdk.RawData(payload)
+
+	return record, nil
+}
+
+// recordKey constructs a key of an element record from the keyProperties,
+// or from the endpoint keys if it's a relationship and the keyByEndpoints is enabled.
+func (s *Snapshot) recordKey(props map[string]any) (sdk.StructuredData, error) {
+	if s.keyByEndpoints && s.entityType == config.EntityTypeRelationship {
+		sourceNode, _ := props[sourceNodeField].(schema.Node)
+		targetNode, _ := props[targetNodeField].(schema.Node)
+
+		return schema.EncodeValues(endpointsKey(sourceNode.Key, targetNode.Key)), nil
+	}
+
+	key := make(sdk.StructuredData)
+	for _, keyProperty := range s.keyProperties {
+		keyPropertyValue, ok := props[keyProperty]
+		if !ok {
+			return nil, fmt.Errorf("payload doesn't contain %q property", keyProperty)
+		}
+
+		key[keyProperty] = keyPropertyValue
+	}
+
+	return schema.EncodeValues(key), nil
+}
+
+// nextEndpoint returns a record of a relationship endpoint node.
+//
+// The record takes the position of the previous record, so if the capture is resumed after it,
+// the relationship is captured again along with its endpoints.
+// The key of the record contains all node properties, as the key of the endpoint in the relationship record.
+func (s *Snapshot) nextEndpoint(elem element) (sdk.Record, error) {
+	var lastProcessedValue any
+	if s.position != nil {
+		lastProcessedValue = s.position.LastProcessedValue
+	}
+
+	position := s.newPosition(lastProcessedValue)
+
+	sdkPosition, err := position.MarshalSDKPosition()
+	if err != nil {
+		return sdk.Record{}, fmt.Errorf("marshal sdk position: %w", err)
+	}
+
+	metadata := s.buildMetadata(elem)
+
+	payload, err := marshalPayload(elem.props, s.payloadFormat)
+	if err != nil {
+		return sdk.Record{}, fmt.Errorf("marshal endpoint: %w", err)
+	}
+
+	key := sdk.StructuredData(schema.EncodeValues(elem.props))
+
+	if s.emitsCreates() {
+		return sdk.Util.Source.NewRecordCreate(sdkPosition, metadata, key, sdk.RawData(payload)), nil
+	}
+
+	return sdk.Util.Source.NewRecordSnapshot(sdkPosition, metadata, key, sdk.RawData(payload)), nil
+}
+
+// newPosition returns a position of a record with the given last processed value.
+func (s *Snapshot) newPosition(lastProcessedValue any) *Position {
+	return &Position{
+		Version:            PositionVersion,
+		Mode:               s.mode(),
+		LastProcessedValue: lastProcessedValue,
+		MaxElement:         s.orderingPropertyMaxValue,
+		ChangeID:           s.changeID,
+		OrderingProperty:   s.orderingProperty,
+		EntityLabels:       s.labels,
+		Bookmarks:          s.bookmarks.list(),
+		Reconcile:          s.reconciler.state(),
+	}
+}
+
+// mode returns a mode of positions of the snapshot records.
+// If the snapshot is polling new items, we mark its position as polling to identify it during pauses correctly.
+func (s *Snapshot) mode() PositionMode {
+	if s.polling {
+		return ModeSnapshotPolling
+	}
+
+	if len(s.relationshipTypes) > 0 {
+		return ModeSubgraphSnapshot
+	}
+
+	return ModeSnapshot
+}
+
+// orderingExpression returns a Cypher expression the elements are paginated by.
+func (s *Snapshot) orderingExpression() string {
+	return s.orderingPositionStrategy().expression()
+}
+
+// orderByClause returns the items of the ORDER BY clause the elements are paginated by,
+// which are the sort keys if the snapshot is sorted by multiple properties, see the [sortPositionStrategy].
+func (s *Snapshot) orderByClause() string {
+	if len(s.sort) > 0 {
+		return sortPositionStrategy{keys: s.sort}.orderByClause()
+	}
+
+	return s.orderingExpression() + " " + s.orderingDirection.keyword()
+}
+
+// setElementLabels puts the labels of an element into the metadata if the entity labels are not configured,
+// which is possible if the elements are matched by a custom query.
+func (s *Snapshot) setElementLabels(metadata sdk.Metadata, labels string) {
+	if s.entityLabels != "" {
+		return
+	}
+
+	metadata[metadataEntityLabelsField] = labels
+}
+
+// notNullCondition returns the first condition of the query,
+// which makes sure the elements can be compared by the ordering property.
+// A subgraph snapshot of relationships compares their endpoints instead.
+func (s *Snapshot) notNullCondition() string {
+	orderingProperty := escapeIdentifier(s.orderingProperty)
+
+	if len(s.relationshipTypes) > 0 {
+		return fmt.Sprintf(notNullWhereClause, srcPlaceholder, orderingProperty) + " AND " +
+			fmt.Sprintf(notNullWhereClause, trgtPlaceholder, orderingProperty)
+	}
+
+	return fmt.Sprintf(notNullWhereClause, objPlaceholder, orderingProperty)
+}
+
+// isSoftDeleted checks if the element properties mark it as soft-deleted.
+// The property value is compared with the softDeleteValue using its string representation.
+func (s *Snapshot) isSoftDeleted(props map[string]any) bool {
+	if s.softDeleteField == "" {
+		return false
+	}
+
+	value, ok := props[s.softDeleteField]
+	if !ok || value == nil {
+		return false
+	}
+
+	return fmt.Sprint(value) == s.softDeleteValue
+}
+
+// logRedactProperties returns names of redacted properties extended with names of the ordering property parameters,
+// if the ordering property is redacted, as values of the parameters are the property values.
+func logRedactProperties(properties []string, orderingProperty string) []string {
+	if !slices.Contains(properties, orderingProperty) {
+		return properties
+	}
+
+	return append(slices.Clone(properties), orderingPropertyMaxValueFieldName, orderingPropertyValueFieldName)
+}
+
 // loadBatch finds a batch of elements in a Neo4j database,
 // based on labels and ordering property.
 //
 //nolint:funlen // the function is pretty straightforward
 func (s *Snapshot) loadBatch(ctx context.Context) error {
-	session := s.driver.NewSession(ctx, neo4j.SessionConfig{
-		DatabaseName: s.databaseName,
-	})
+	// there were no nodes at the start of the snapshot of nodes, so there are no relationships between them
+	if len(s.relationshipTypes) > 0 && s.orderingPropertyMaxValue == nil {
+		return nil
+	}
+
+	if err := s.rateLimiter.wait(ctx); err != nil {
+		return err
+	}
+
+	session := s.driver.NewSession(ctx, s.bookmarks.sessionConfig(s.databaseName))
 	defer session.Close(ctx)
 
 	var (
-		whereClause string
-		params      = make(map[string]any)
+		conditions []string
+		params     = make(map[string]any)
 	)
 
-	// put the AND here because we have the WHERE obj.%s IS NOT NULL part in the query
-	// and after it we need to put AND if there's more items in the query
-	if s.orderingPropertyMaxValue != nil || (s.position != nil && s.position.LastProcessedValue != nil) {
-		whereClause += " AND "
+	// if the ordering property max value isn't nil,
+	// we'll use it to get elements with ordering property up to the max value in the ordering direction,
+	// unless elements are paginated by element ids, so mutated elements don't drop out of the snapshot
+	if s.orderingPropertyMaxValue != nil && !s.byElementID {
+		conditions = append(conditions, fmt.Sprintf(opmvWhereClause,
+			escapeIdentifier(s.orderingProperty), s.orderingDirection.before()+"=",
+		))
+		params[orderingPropertyMaxValueFieldName] = s.orderingPropertyMaxValue
 	}
 
-	// if the ordering property max value isn't nil,
-	// we'll use it to get elements with ordering property less than or equal to the max value
-	if s.orderingPropertyMaxValue != nil {
-		whereClause += fmt.Sprintf(opmvLTEWhereClause, s.orderingProperty)
+	// if the position and its last processed value are not nil,
+	// we'll use the value to construct the where clause so we only get elements
+	// that have ordering field following the position's last processed value in the ordering direction,
+	// or following the start of the grace window behind it, see the [Snapshot.lastProcessedValue],
+	// the condition is defined by the position strategy, see the [PositionStrategy]
+	// if the snapshot is sorted by multiple properties, we'll only get elements that have all of them
+	if len(s.sort) > 0 {
+		conditions = append(conditions, sortPositionStrategy{keys: s.sort}.notNullCondition())
+	}
+
+	cursorCondition, err := s.cursorCondition(params)
+	if err != nil {
+		return err
+	}
+
+	if cursorCondition != "" {
+		conditions = append(conditions, cursorCondition)
+	}
+
+	// if it's a snapshot of relationships of a subgraph,
+	// we'll only get relationships which endpoints were captured by the snapshot of nodes
+	if len(s.relationshipTypes) > 0 {
+		conditions = append(conditions, fmt.Sprintf(subgraphEndpointsWhereClause,
+			escapeIdentifier(s.orderingProperty), s.orderingDirection.before()+"=",
+		))
 		params[orderingPropertyMaxValueFieldName] = s.orderingPropertyMaxValue
 	}
 
-	if s.orderingPropertyMaxValue != nil && s.position != nil && s.position.LastProcessedValue != nil {
-		whereClause += " AND "
+	// if only relationships between existing nodes are captured,
+	// we'll skip the ones which endpoints were created along with or after them
+	if s.existingEndpointsOnly && s.entityType == config.EntityTypeRelationship {
+		conditions = append(conditions, fmt.Sprintf(existingEndpointsWhereClause,
+			escapeIdentifier(s.orderingProperty), s.orderingDirection.before(),
+		))
 	}
 
-	// if the position and its last processed value are not nil,
-	// we'll use the value to construct the where clause so we only get elements
-	// that have ordering field greater than the position's last processed value
-	if s.position != nil && s.position.LastProcessedValue != nil {
-		whereClause += fmt.Sprintf(opvGTWhereClause, s.orderingProperty)
-		params[orderingPropertyValueFieldName] = s.position.LastProcessedValue
+	conditions = append(conditions, s.scopeConditions(params)...)
+
+	// put the AND here because we have the WHERE obj.%s IS NOT NULL part in the query
+	// and after it we need to put AND if there's more items in the query
+	var whereClause string
+	if len(conditions) > 0 {
+		whereClause = " AND " + strings.Join(conditions, " AND ")
 	}
 
 	getQueryTemplate := getNodesQueryTemplate
@@ -283,95 +1112,446 @@ func (s *Snapshot) loadBatch(ctx context.Context) error {
 		getQueryTemplate = getRelationshipsQueryTemplate
 	}
 
+	var returnClause string
+	if s.entityType == config.EntityTypeNode && s.relationshipCountsDepth >= 1 {
+		returnClause += depth1CountReturnClause
+	}
+
+	if s.entityType == config.EntityTypeNode && s.relationshipCountsDepth >= 2 {
+		returnClause += depth2CountReturnClause
+	}
+
+	returnClause += projectionsReturnClause(s.projections)
+
 	query := fmt.Sprintf(
-		getQueryTemplate, s.entityLabels, s.orderingProperty, whereClause, s.orderingProperty, s.batchSize,
+		getQueryTemplate, s.matchClause, s.notNullCondition(), whereClause, returnClause,
+		s.orderByClause(), s.batchSize,
 	)
 
-	_, err := neo4j.ExecuteRead(ctx, session, func(tx neo4j.ManagedTransaction) (neo4j.ResultWithContext, error) {
+	querylog.Log(ctx, query, params, s.logRedactProperties)
+
+	elements, err := neo4j.ExecuteRead(ctx, session, func(tx neo4j.ManagedTransaction) ([]element, error) {
 		result, err := tx.Run(ctx, query, params)
 		if err != nil {
 			return nil, fmt.Errorf("run tx: %w", err)
 		}
 
-		// collect and send records here,
+		// collect records here,
 		// because once the function exits the result won't contain any records
-		if err = s.processNeo4jResult(ctx, result); err != nil {
+		elements, err := s.processNeo4jResult(ctx, result)
+		if err != nil {
 			return nil, fmt.Errorf("process neo4j result: %w", err)
 		}
 
-		return result, nil
-	})
+		return elements, nil
+	}, config.TxTimeout(s.queryTimeout))
 	if err != nil {
 		return fmt.Errorf("execute read: %w", err)
 	}
 
+	s.bookmarks.update(session)
+
+	// if the polling re-reads the grace window, we'll skip the elements that were emitted before,
+	// and if all of them were, we'll go on with the next batch, so the poll doesn't stop within the window
+	if s.resumeGrace > 0 {
+		loaded := len(elements)
+
+		elements, err = s.skipSeen(elements)
+		if err != nil {
+			return fmt.Errorf("skip seen elements: %w", err)
+		}
+
+		if loaded > 0 && len(elements) == 0 {
+			return s.loadBatch(ctx)
+		}
+	}
+
+	// send the elements only after the transaction has completed,
+	// so a retried transaction doesn't send the same elements twice
+	s.batchStart = nil
+	if s.position != nil {
+		s.batchStart = s.position.LastProcessedValue
+	}
+
+	if len(elements) > 0 && s.emitOrder == EmitOrderKey {
+		s.batchEndValue = s.positionValue(elements[len(elements)-1])
+
+		if err := s.sortByKey(elements); err != nil {
+			return fmt.Errorf("sort by key: %w", err)
+		}
+	}
+
+	if len(elements) > 0 {
+		elements[len(elements)-1].batchEnd = true
+	}
+
+	// the records channel is empty here, and it's grown only if the loaded batch doesn't fit into it
+	if len(elements) > cap(s.records) {
+		s.records = make(chan element, len(elements))
+	}
+
+	for _, elem := range elements {
+		s.records <- elem
+	}
+
 	return nil
 }
 
-// processNeo4jResult parses the result records and sends them to the records channel.
-func (s *Snapshot) processNeo4jResult(ctx context.Context, result neo4j.ResultWithContext) error {
-	var record *db.Record
+// scopeConditions returns the conditions of the filters and the shard that narrow the captured elements,
+// and puts their parameters into the params.
+func (s *Snapshot) scopeConditions(params map[string]any) []string {
+	var conditions []string
+
+	// if the nodes have any of the entity labels, they're matched by the condition
+	if s.labelsCondition != "" {
+		conditions = append(conditions, s.labelsCondition)
+	}
+
+	// if relationship types have endpoint labels, we'll only get relationships which endpoints have them
+	if s.endpointLabelsCondition != "" {
+		conditions = append(conditions, s.endpointLabelsCondition)
+
+		for name, value := range s.endpointLabelsParams {
+			params[name] = value
+		}
+	}
+
+	// if the nodes are filtered by their relationships, we'll only get the ones of the degree filter
+	if condition := s.degreeFilter.condition(); condition != "" {
+		conditions = append(conditions, condition)
+	}
+
+	// if the filter is set, we'll only get elements matching it
+	if s.filter != "" {
+		conditions = append(conditions, fmt.Sprintf(filterWhereClause, s.filter))
+
+		for name, value := range s.filterParams {
+			params[name] = value
+		}
+	}
+
+	// if the capture is sharded, we'll only get elements belonging to the shard
+	if s.shardCount > 1 {
+		conditions = append(conditions, shardWhereClause())
+		params[shardCountFieldName] = s.shardCount
+		params[shardIndexFieldName] = s.shardIndex
+	}
+
+	return conditions
+}
+
+// processNeo4jResult parses the result records into elements.
+func (s *Snapshot) processNeo4jResult(ctx context.Context, result neo4j.ResultWithContext) ([]element, error) {
+	var (
+		elements []element
+		record   *db.Record
+		// seen holds the element ids of the relationships matched in both orientations,
+		// so each of them is captured once
+		seen = make(map[string]struct{})
+	)
+
 	for result.NextRecord(ctx, &record) {
 		elementRaw, ok := record.Get(objPlaceholder)
 		if !ok {
-			return fmt.Errorf("record doesn't contain %q key", objPlaceholder)
+			return nil, fmt.Errorf("record doesn't contain %q key", objPlaceholder)
 		}
 
-		var props map[string]any
+		var (
+			props     map[string]any
+			elementID string
+			metadata  = make(sdk.Metadata)
+		)
+
 		switch element := elementRaw.(type) {
 		case dbtype.Node:
 			props = element.Props
+			elementID = element.ElementId
+
+			if err := s.setRelationshipCounts(record, metadata); err != nil {
+				return nil, fmt.Errorf("set relationship counts: %w", err)
+			}
+
+			s.setSubgraphMetadata(metadata, config.EntityTypeNode, "")
+			s.setElementLabels(metadata, strings.Join(element.Labels, ":"))
+
 		case dbtype.Relationship:
+			if s.relationshipDirection == RelationshipDirectionBoth {
+				if _, ok := seen[element.ElementId]; ok {
+					continue
+				}
+
+				seen[element.ElementId] = struct{}{}
+			}
+
 			props = element.Props
+			elementID = element.ElementId
 
 			srcNodeRaw, ok := record.Get(srcPlaceholder)
 			if !ok {
-				return fmt.Errorf("record doesn't contain %q key", srcPlaceholder)
+				return nil, fmt.Errorf("record doesn't contain %q key", srcPlaceholder)
 			}
 
 			srcNode, ok := srcNodeRaw.(dbtype.Node)
 			if !ok {
-				return errConvertRawNode
+				return nil, errConvertRawNode
 			}
 
 			trgtNodeRaw, ok := record.Get(trgtPlaceholder)
 			if !ok {
-				return fmt.Errorf("record doesn't contain %q key", trgtPlaceholder)
+				return nil, fmt.Errorf("record doesn't contain %q key", trgtPlaceholder)
 			}
 
 			trgtNode, ok := trgtNodeRaw.(dbtype.Node)
 			if !ok {
-				return errConvertRawRelationship
+				return nil, errConvertRawRelationship
+			}
+
+			// the src and trgt are the start and end nodes only if the relationship is outgoing
+			srcNode, trgtNode = s.relationshipDirection.orientEndpoints(element, srcNode, trgtNode, metadata)
+
+			if err := resolveReservedFields(props, s.fieldCollision); err != nil {
+				return nil, fmt.Errorf("resolve reserved fields: %w", err)
+			}
+
+			if s.emitEndpointsAsRecords {
+				elements = append(elements, s.withElementID(endpointElement(srcNode), srcNode.ElementId),
+					s.withElementID(endpointElement(trgtNode), trgtNode.ElementId),
+				)
+
+				metadata[metadataEntityTypeField] = string(config.EntityTypeRelationship)
 			}
 
 			props[sourceNodeField] = schema.Node{Labels: srcNode.Labels, Key: srcNode.Props}
 			props[targetNodeField] = schema.Node{Labels: trgtNode.Labels, Key: trgtNode.Props}
+
+			s.setSubgraphMetadata(metadata, config.EntityTypeRelationship, element.Type)
+			s.setElementLabels(metadata, element.Type)
+		}
+
+		if err := setProjections(record, props, s.projections); err != nil {
+			return nil, fmt.Errorf("set projections: %w", err)
 		}
 
-		s.records <- props
+		if s.includeElementID {
+			setElementID(metadata, props, elementID, s.elementIDField)
+		}
+
+		s.decodeHistory(props)
+
+		if err := s.vectors.Convert(props); err != nil {
+			return nil, fmt.Errorf("convert vectors: %w", err)
+		}
+
+		elements = append(elements, element{props: props, metadata: metadata, elementID: elementID})
+	}
+
+	return elements, nil
+}
+
+// endpointElement returns an element of a relationship endpoint node that is emitted as a separate record.
+func endpointElement(node dbtype.Node) element {
+	return element{
+		props: node.Props,
+		metadata: sdk.Metadata{
+			metadataEntityLabelsField: strings.Join(node.Labels, ":"),
+			metadataEntityTypeField:   string(config.EntityTypeNode),
+		},
+		endpoint: true,
+	}
+}
+
+// withElementID puts the element id of a relationship endpoint into the metadata of its element
+// if the element ids are included, the payload doesn't get it, as endpoint keys are composed of all properties.
+func (s *Snapshot) withElementID(elem element, elementID string) element {
+	if s.includeElementID {
+		setElementID(elem.metadata, nil, elementID, "")
+	}
+
+	return elem
+}
+
+// recordsCapacity returns a capacity of the records channel, so a whole batch fits into it,
+// up to the maxRecordsCapacity, bigger batches grow the channel when they're loaded.
+// If relationship endpoints are emitted as separate records, each relationship takes three records.
+func recordsCapacity(params SnapshotParams) int {
+	if params.EntityType == config.EntityTypeRelationship && params.EmitEndpointsAsRecords {
+		return min(params.BatchSize*recordsPerRelationship, maxRecordsCapacity)
+	}
+
+	return min(params.BatchSize, maxRecordsCapacity)
+}
+
+// setRelationshipCounts puts the relationship counts of a node record into the metadata,
+// if the counts are enabled.
+func (s *Snapshot) setRelationshipCounts(record *db.Record, metadata sdk.Metadata) error {
+	counts := []struct {
+		depth       int
+		placeholder string
+		field       string
+	}{
+		{depth: 1, placeholder: depth1CountPlaceholder, field: metadataDepth1CountField},
+		{depth: 2, placeholder: depth2CountPlaceholder, field: metadataDepth2CountField},
+	}
+
+	for _, count := range counts {
+		if s.relationshipCountsDepth < count.depth {
+			continue
+		}
+
+		value, _, err := neo4j.GetRecordValue[int64](record, count.placeholder)
+		if err != nil {
+			return fmt.Errorf("get %q record value: %w", count.placeholder, err)
+		}
+
+		metadata[count.field] = strconv.FormatInt(value, 10)
 	}
 
 	return nil
 }
 
-// getMaxPropertyValue returns the maximum property value that can be found among Neo4j entities.
+// resolveReservedFields checks if the relationship properties contain
+// the reserved sourceNode or targetNode fields, and depending on the fieldCollision
+// either renames them by adding the reservedFieldPrefix or returns an error.
+func resolveReservedFields(props map[string]any, fieldCollision FieldCollision) error {
+	for _, field := range []string{sourceNodeField, targetNodeField} {
+		value, ok := props[field]
+		if !ok {
+			continue
+		}
+
+		if fieldCollision == FieldCollisionError {
+			return fmt.Errorf("%w: %q", errReservedFieldCollision, field)
+		}
+
+		// keep prepending the prefix until the name is free,
+		// so we never overwrite an existing property
+		renamedField := reservedFieldPrefix + field
+		for _, exists := props[renamedField]; exists; _, exists = props[renamedField] {
+			renamedField = reservedFieldPrefix + renamedField
+		}
+
+		props[renamedField] = value
+		delete(props, field)
+	}
+
+	return nil
+}
+
+// endpointsKey composes a key of a relationship record from the keys of its endpoints,
+// their properties are prefixed, so the properties of both endpoints are kept.
+func endpointsKey(sourceKey, targetKey map[string]any) sdk.StructuredData {
+	key := make(sdk.StructuredData, len(sourceKey)+len(targetKey))
+	for name, value := range sourceKey {
+		key[sourceKeyPrefix+name] = value
+	}
+
+	for name, value := range targetKey {
+		key[targetKeyPrefix+name] = value
+	}
+
+	return key
+}
+
+// matchClause returns a MATCH clause that scopes the captured elements based on the params.
+func matchClause(params SnapshotParams, entityLabels string) string {
+	switch {
+	case params.Query != "":
+		return fmt.Sprintf(queryMatchClauseTemplate, params.Query)
+
+	case params.EntityType == config.EntityTypeRelationship:
+		left, right := params.RelationshipDirection.arrows()
+
+		return fmt.Sprintf(relationshipsMatchClauseTemplate, left, entityLabels, right) + matchIndexHint(params)
+
+	case params.SeedNodeMatch != "":
+		return fmt.Sprintf(seedNodesMatchClauseTemplate, params.SeedNodeMatch, params.MaxHops, entityLabels)
+
+	default:
+		return fmt.Sprintf(nodesMatchClauseTemplate, entityLabels) + matchIndexHint(params)
+	}
+}
+
+// labelsCondition returns the condition that matches the entity labels if the match clause doesn't match them,
+// the custom query matches the elements on its own, so it doesn't need the condition.
+func labelsCondition(params SnapshotParams) string {
+	if params.Query != "" {
+		return ""
+	}
+
+	return params.LabelMatch.condition(params.EntityLabels, params.EntityType)
+}
+
+// matchIndexHint returns the index hint of the ordering property if the IndexHint is enabled.
+func matchIndexHint(params SnapshotParams) string {
+	if !params.IndexHint {
+		return ""
+	}
+
+	return indexHint(params.EntityLabels, params.OrderingProperty)
+}
+
+// getMaxPropertyValue returns the last property value in the direction that can be found among Neo4j entities,
+// i.e. the maximum value for the ascending direction and the minimum one for the descending direction.
 func getMaxPropertyValue(
 	ctx context.Context,
 	driver neo4j.DriverWithContext,
-	database, labels, property string,
-	entityType config.EntityType,
+	database string, labels []string, labelMatch LabelMatch, property string,
+	entityType config.EntityType, direction OrderingDirection, bookmarks *Bookmarks, queryTimeout time.Duration,
 ) (any, error) {
-	session := driver.NewSession(ctx, neo4j.SessionConfig{
-		DatabaseName: database,
-	})
-	defer session.Close(ctx)
-
 	maxPropertyQueryTemplate := getNodeMaxPropertyQueryTemplate
 	if entityType == config.EntityTypeRelationship {
 		maxPropertyQueryTemplate = getRelationshipMaxPropertyQueryTemplate
 	}
 
-	query := fmt.Sprintf(maxPropertyQueryTemplate, labels, property, property, property, property)
+	escapedProperty := escapeIdentifier(property)
+
+	query := fmt.Sprintf(maxPropertyQueryTemplate,
+		labelMatch.pattern(labels, entityType), escapedProperty, andCondition(labelMatch.condition(labels, entityType)),
+		escapedProperty, escapedProperty, escapedProperty, direction.reverseKeyword(),
+	)
+
+	return readPropertyValue(ctx, driver, database, query, property, bookmarks, queryTimeout)
+}
+
+// maxPropertyValue returns the last ordering property value in the direction among the elements
+// the snapshot captures, which are matched either by the custom query or by the entity labels.
+// If the elements must have any of the entity labels, the value is the last one of the per-label values.
+func maxPropertyValue(ctx context.Context, params SnapshotParams) (any, error) {
+	if params.Query == "" && params.LabelMatch == LabelMatchAny && len(params.EntityLabels) > 1 {
+		return anyLabelMaxPropertyValue(ctx, params)
+	}
+
+	if params.Query == "" {
+		return getMaxPropertyValue(ctx, params.Driver,
+			params.DatabaseName, params.EntityLabels, params.LabelMatch, params.OrderingProperty,
+			params.EntityType, params.OrderingDirection, params.Bookmarks, params.QueryTimeout,
+		)
+	}
+
+	escapedProperty := escapeIdentifier(params.OrderingProperty)
+
+	query := fmt.Sprintf(getQueryMaxPropertyQueryTemplate,
+		params.Query, escapedProperty, escapedProperty, escapedProperty, escapedProperty,
+		params.OrderingDirection.reverseKeyword(),
+	)
+
+	return readPropertyValue(ctx, params.Driver, params.DatabaseName, query, params.OrderingProperty,
+		params.Bookmarks, params.QueryTimeout,
+	)
+}
+
+// readPropertyValue runs the query that returns a single property value and returns the value,
+// or the errNoElements if the query returns nothing. The read waits for the bookmarks if they aren't nil,
+// and it's terminated if it runs longer than the non-zero queryTimeout.
+func readPropertyValue(
+	ctx context.Context,
+	driver neo4j.DriverWithContext,
+	database, query, property string,
+	bookmarks *Bookmarks,
+	queryTimeout time.Duration,
+) (any, error) {
+	session := driver.NewSession(ctx, bookmarks.sessionConfig(database))
+	defer session.Close(ctx)
 
 	propertyValue, err := neo4j.ExecuteRead(ctx, session, func(tx neo4j.ManagedTransaction) (any, error) {
 		result, err := tx.Run(ctx, query, nil)
@@ -395,10 +1575,30 @@ func getMaxPropertyValue(
 		}
 
 		return propertyValue, nil
-	})
+	}, config.TxTimeout(queryTimeout))
 	if err != nil {
 		return nil, fmt.Errorf("execute read: %w", err)
 	}
 
+	bookmarks.update(session)
+
 	return propertyValue, nil
 }
+
+// escapeIdentifier wraps a label or property name in backticks, doubling the embedded ones,
+// so it's interpolated into a query as a single identifier, whatever characters it contains.
+func escapeIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// cypherLabels normalizes labels with the [config.NormalizeLabels], escapes them and joins them with ":".
+func cypherLabels(labels []string) string {
+	labels = config.NormalizeLabels(labels)
+
+	escapedLabels := make([]string, len(labels))
+	for i, label := range labels {
+		escapedLabels[i] = escapeIdentifier(label)
+	}
+
+	return strings.Join(escapedLabels, ":")
+}
@@ -0,0 +1,702 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/schema"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestResolveReservedFields(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		props          map[string]any
+		fieldCollision FieldCollision
+		want           map[string]any
+		wantErr        error
+	}{
+		{
+			name:           "success_no_collision",
+			props:          map[string]any{"since": 2020},
+			fieldCollision: FieldCollisionError,
+			want:           map[string]any{"since": 2020},
+		},
+		{
+			name:           "success_prefix",
+			props:          map[string]any{"sourceNode": "Alice", "since": 2020},
+			fieldCollision: FieldCollisionPrefix,
+			want:           map[string]any{"_sourceNode": "Alice", "since": 2020},
+		},
+		{
+			name:           "success_prefix_taken",
+			props:          map[string]any{"sourceNode": "Alice", "_sourceNode": "Bob"},
+			fieldCollision: FieldCollisionPrefix,
+			want:           map[string]any{"__sourceNode": "Alice", "_sourceNode": "Bob"},
+		},
+		{
+			name:           "fail_error",
+			props:          map[string]any{"sourceNode": "Alice"},
+			fieldCollision: FieldCollisionError,
+			wantErr:        errReservedFieldCollision,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := resolveReservedFields(tt.props, tt.fieldCollision)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("resolveReservedFields() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(tt.props, tt.want) {
+				t.Errorf("resolveReservedFields() = %v, want %v", tt.props, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshot_isSoftDeleted(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		softDeleteField string
+		softDeleteValue string
+		props           map[string]any
+		want            bool
+	}{
+		{
+			name:            "disabled",
+			softDeleteValue: "true",
+			props:           map[string]any{"deleted": true},
+			want:            false,
+		},
+		{
+			name:            "bool_true",
+			softDeleteField: "deleted",
+			softDeleteValue: "true",
+			props:           map[string]any{"deleted": true},
+			want:            true,
+		},
+		{
+			name:            "bool_false",
+			softDeleteField: "deleted",
+			softDeleteValue: "true",
+			props:           map[string]any{"deleted": false},
+			want:            false,
+		},
+		{
+			name:            "string_value",
+			softDeleteField: "status",
+			softDeleteValue: "archived",
+			props:           map[string]any{"status": "archived"},
+			want:            true,
+		},
+		{
+			name:            "missing_field",
+			softDeleteField: "deleted",
+			softDeleteValue: "true",
+			props:           map[string]any{"name": "Alex"},
+			want:            false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Snapshot{softDeleteField: tt.softDeleteField, softDeleteValue: tt.softDeleteValue}
+			if got := s.isSoftDeleted(tt.props); got != tt.want {
+				t.Errorf("isSoftDeleted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchClause(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		params SnapshotParams
+		want   string
+	}{
+		{
+			name:   "nodes",
+			params: SnapshotParams{EntityType: config.EntityTypeNode},
+			want:   "MATCH (obj:`Person`)",
+		},
+		{
+			name:   "relationships",
+			params: SnapshotParams{EntityType: config.EntityTypeRelationship, SeedNodeMatch: ":Person {id: 1}"},
+			want:   "MATCH (src)-[obj:`Person`]->(trgt)",
+		},
+		{
+			name:   "seed_nodes",
+			params: SnapshotParams{EntityType: config.EntityTypeNode, SeedNodeMatch: ":Person {id: 1}", MaxHops: 2},
+			want:   "MATCH (seed:Person {id: 1})-[*0..2]-(obj:`Person`) WITH DISTINCT obj",
+		},
+		{
+			name:   "query",
+			params: SnapshotParams{EntityType: config.EntityTypeNode, Query: "MATCH (obj)--(:City) RETURN obj"},
+			want:   "CALL { MATCH (obj)--(:City) RETURN obj } WITH *",
+		},
+		{
+			name: "nodes_index_hint",
+			params: SnapshotParams{
+				EntityType:       config.EntityTypeNode,
+				EntityLabels:     []string{"Person", "Writer"},
+				OrderingProperty: "createdAt",
+				IndexHint:        true,
+			},
+			want: "MATCH (obj:`Person`) USING INDEX obj:`Person`(`createdAt`)",
+		},
+		{
+			name: "relationships_index_hint",
+			params: SnapshotParams{
+				EntityType:       config.EntityTypeRelationship,
+				EntityLabels:     []string{"Person"},
+				OrderingProperty: "createdAt",
+				IndexHint:        true,
+			},
+			want: "MATCH (src)-[obj:`Person`]->(trgt) USING INDEX obj:`Person`(`createdAt`)",
+		},
+		{
+			name: "relationships_incoming",
+			params: SnapshotParams{
+				EntityType:            config.EntityTypeRelationship,
+				RelationshipDirection: RelationshipDirectionIncoming,
+			},
+			want: "MATCH (src)<-[obj:`Person`]-(trgt)",
+		},
+		{
+			name:   "relationships_both",
+			params: SnapshotParams{EntityType: config.EntityTypeRelationship, RelationshipDirection: RelationshipDirectionBoth},
+			want:   "MATCH (src)-[obj:`Person`]-(trgt)",
+		},
+		{
+			name:   "nodes_any",
+			params: SnapshotParams{EntityType: config.EntityTypeNode, LabelMatch: LabelMatchAny},
+			want:   "MATCH (obj)",
+		},
+		{
+			name:   "relationships_any",
+			params: SnapshotParams{EntityType: config.EntityTypeRelationship, LabelMatch: LabelMatchAny},
+			want:   "MATCH (src)-[obj:`Person`]->(trgt)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pattern := tt.params.LabelMatch.pattern([]string{"Person"}, tt.params.EntityType)
+			if got := matchClause(tt.params, pattern); got != tt.want {
+				t.Errorf("matchClause() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		labelMatch    LabelMatch
+		entityType    config.EntityType
+		wantPattern   string
+		wantCondition string
+	}{
+		{
+			name:        "nodes_empty",
+			entityType:  config.EntityTypeNode,
+			wantPattern: ":`Person`:`Writer`",
+		},
+		{
+			name:        "nodes_all",
+			labelMatch:  LabelMatchAll,
+			entityType:  config.EntityTypeNode,
+			wantPattern: ":`Person`:`Writer`",
+		},
+		{
+			name:          "nodes_any",
+			labelMatch:    LabelMatchAny,
+			entityType:    config.EntityTypeNode,
+			wantCondition: "(obj:`Person` OR obj:`Writer`)",
+		},
+		{
+			name:        "relationships_any",
+			labelMatch:  LabelMatchAny,
+			entityType:  config.EntityTypeRelationship,
+			wantPattern: ":`Person`|`Writer`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			labels := []string{"Person", "Writer"}
+
+			if got := tt.labelMatch.pattern(labels, tt.entityType); got != tt.wantPattern {
+				t.Errorf("pattern() = %v, want %v", got, tt.wantPattern)
+			}
+
+			if got := tt.labelMatch.condition(labels, tt.entityType); got != tt.wantCondition {
+				t.Errorf("condition() = %v, want %v", got, tt.wantCondition)
+			}
+		})
+	}
+}
+
+func TestLabelMatch_normalized(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// the same set of labels produces the same query, whatever order it comes in
+	labels := []string{"Writer", " Person", "Writer"}
+
+	is.Equal(LabelMatchAll.pattern(labels, config.EntityTypeNode), ":`Person`:`Writer`")
+	is.Equal(LabelMatchAny.pattern(labels, config.EntityTypeRelationship), ":`Person`|`Writer`")
+	is.Equal(LabelMatchAny.condition(labels, config.EntityTypeNode), "(obj:`Person` OR obj:`Writer`)")
+}
+
+func TestSnapshot_Next_endpoints(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctx := context.Background()
+
+	s := &Snapshot{
+		orderingProperty:       "id",
+		keyProperties:          []string{"id"},
+		entityLabels:           "KNOWS",
+		emitEndpointsAsRecords: true,
+		position:               &Position{Version: PositionVersion, Mode: ModeSnapshot, LastProcessedValue: int64(1)},
+		records:                make(chan element, recordsPerRelationship),
+	}
+
+	s.records <- endpointElement(dbtype.Node{Labels: []string{"Person"}, Props: map[string]any{"name": "Alice"}})
+	s.records <- endpointElement(dbtype.Node{Labels: []string{"Person"}, Props: map[string]any{"name": "Bob"}})
+	s.records <- element{
+		props:    map[string]any{"id": int64(2)},
+		metadata: sdk.Metadata{metadataEntityTypeField: string(config.EntityTypeRelationship)},
+	}
+
+	// the endpoint records take the position of the previous relationship
+	for _, name := range []string{"Alice", "Bob"} {
+		record, err := s.Next(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{"name": name})
+		is.Equal(record.Metadata[metadataEntityLabelsField], "Person")
+		is.Equal(record.Metadata[metadataEntityTypeField], string(config.EntityTypeNode))
+
+		position, err := ParsePosition(record.Position)
+		is.NoErr(err)
+		is.Equal(position.LastProcessedValue, float64(1))
+	}
+
+	record, err := s.Next(ctx)
+	is.NoErr(err)
+	is.Equal(record.Key, sdk.StructuredData{"id": int64(2)})
+	is.Equal(record.Metadata[metadataEntityLabelsField], "KNOWS")
+	is.Equal(record.Metadata[metadataEntityTypeField], string(config.EntityTypeRelationship))
+
+	position, err := ParsePosition(record.Position)
+	is.NoErr(err)
+	is.Equal(position.LastProcessedValue, float64(2))
+}
+
+func TestSnapshot_Next_includeDeletedState(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctx := context.Background()
+
+	s := &Snapshot{
+		orderingProperty:    "id",
+		keyProperties:       []string{"id"},
+		entityLabels:        "Person",
+		softDeleteField:     "deleted",
+		softDeleteValue:     "true",
+		includeDeletedState: true,
+		records:             make(chan element, 1),
+	}
+
+	s.records <- element{props: map[string]any{"id": int64(1), "deleted": true}}
+
+	// the soft-deleted element is emitted as a delete with its last known state
+	record, err := s.Next(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationDelete)
+	is.Equal(record.Key, sdk.StructuredData{"id": int64(1)})
+	is.Equal(record.Payload.Before, sdk.RawData(`{"deleted":true,"id":1}`))
+	is.Equal(record.Payload.After, nil)
+}
+
+func TestSnapshot_Next_keyByEndpoints(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	s := &Snapshot{
+		orderingProperty: "id",
+		keyProperties:    []string{"id"},
+		entityType:       config.EntityTypeRelationship,
+		entityLabels:     "KNOWS",
+		keyByEndpoints:   true,
+		records:          make(chan element, 1),
+	}
+
+	s.records <- element{props: map[string]any{
+		"id":            int64(1),
+		sourceNodeField: schema.Node{Labels: []string{"Person"}, Key: map[string]any{"id": int64(10)}},
+		targetNodeField: schema.Node{Labels: []string{"Person"}, Key: map[string]any{"id": int64(20), "name": "Bob"}},
+	}}
+
+	record, err := s.Next(context.Background())
+	is.NoErr(err)
+	is.Equal(record.Key, sdk.StructuredData{
+		"source_id":   int64(10),
+		"target_id":   int64(20),
+		"target_name": "Bob",
+	})
+}
+
+func TestLogRedactProperties(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.Equal(logRedactProperties([]string{"email"}, "id"), []string{"email"})
+	is.Equal(logRedactProperties([]string{"email", "id"}, "id"),
+		[]string{"email", "id", orderingPropertyMaxValueFieldName, orderingPropertyValueFieldName})
+}
+
+func TestSnapshot_Next_alignPositionsToBatches(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctx := context.Background()
+
+	s := &Snapshot{
+		orderingProperty:        "id",
+		keyProperties:           []string{"id"},
+		alignPositionsToBatches: true,
+		batchStart:              int64(1),
+		position:                &Position{Version: PositionVersion, Mode: ModeSnapshot, LastProcessedValue: int64(1)},
+		records:                 make(chan element, 2),
+	}
+
+	s.records <- element{props: map[string]any{"id": int64(2)}}
+	s.records <- element{props: map[string]any{"id": int64(3)}, batchEnd: true}
+
+	// the record in the middle of the batch points to the end of the previous batch,
+	// so the capture restarted after it resumes at the batch boundary
+	record, err := s.Next(ctx)
+	is.NoErr(err)
+	is.Equal(record.Key, sdk.StructuredData{"id": int64(2)})
+
+	position, err := ParsePosition(record.Position)
+	is.NoErr(err)
+	is.Equal(position.LastProcessedValue, float64(1))
+
+	// the last record of the batch moves the position to the end of the batch
+	record, err = s.Next(ctx)
+	is.NoErr(err)
+	is.Equal(record.Key, sdk.StructuredData{"id": int64(3)})
+
+	position, err = ParsePosition(record.Position)
+	is.NoErr(err)
+	is.Equal(position.LastProcessedValue, float64(3))
+}
+
+func TestSnapshot_Next_byElementID(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	s := &Snapshot{
+		orderingProperty:         "updatedAt",
+		orderingPropertyMaxValue: int64(10),
+		keyProperties:            []string{"id"},
+		byElementID:              true,
+		records:                  make(chan element, 1),
+	}
+	is.Equal(s.orderingExpression(), elementIDOrderingExpression)
+
+	s.records <- element{props: map[string]any{"id": int64(1), "updatedAt": int64(5)}, elementID: "4:abc:1"}
+
+	record, err := s.Next(context.Background())
+	is.NoErr(err)
+
+	// the position holds the element id, and the max element is still the ordering property value for the polling
+	position, err := ParsePosition(record.Position)
+	is.NoErr(err)
+	is.Equal(position.LastProcessedValue, "4:abc:1")
+	is.Equal(position.MaxElement, float64(10))
+}
+
+func TestCypherLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		labels []string
+		want   string
+	}{
+		{
+			name:   "single",
+			labels: []string{"Person"},
+			want:   "`Person`",
+		},
+		{
+			name:   "space",
+			labels: []string{"Per son", "Writer"},
+			want:   "`Per son`:`Writer`",
+		},
+		{
+			name:   "backtick",
+			labels: []string{"Person`) DETACH DELETE (n"},
+			want:   "`Person``) DETACH DELETE (n`",
+		},
+		{
+			name:   "unsorted_duplicated",
+			labels: []string{"Writer", "Person", "Writer"},
+			want:   "`Person`:`Writer`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := cypherLabels(tt.labels); got != tt.want {
+				t.Errorf("cypherLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshot_orderingExpression_escaped(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	s := &Snapshot{orderingProperty: "audit.updatedAt"}
+	is.Equal(s.orderingExpression(), "obj.`audit.updatedAt`")
+}
+
+func TestOrderingDirection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		direction OrderingDirection
+		want      [4]string
+	}{
+		{
+			name: "empty",
+			want: [4]string{"ASC", "DESC", ">", "<"},
+		},
+		{
+			name:      "asc",
+			direction: OrderingDirectionAsc,
+			want:      [4]string{"ASC", "DESC", ">", "<"},
+		},
+		{
+			name:      "desc",
+			direction: OrderingDirectionDesc,
+			want:      [4]string{"DESC", "ASC", "<", ">"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := [4]string{
+				tt.direction.keyword(), tt.direction.reverseKeyword(), tt.direction.after(), tt.direction.before(),
+			}
+			if got != tt.want {
+				t.Errorf("OrderingDirection = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshot_Next_emitOrderKey(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctx := context.Background()
+
+	s := &Snapshot{
+		orderingProperty: "createdAt",
+		keyProperties:    []string{"name", "id"},
+		emitOrder:        EmitOrderKey,
+		batchStart:       int64(1),
+		records:          make(chan element, 4),
+	}
+
+	elements := []element{
+		{props: map[string]any{"createdAt": int64(2), "name": "Bob", "id": int64(10)}},
+		{props: map[string]any{"createdAt": int64(3), "name": "Alice", "id": int64(9)}},
+		{props: map[string]any{"createdAt": int64(4), "name": "Alice", "id": int64(2)}},
+		{props: map[string]any{"createdAt": int64(5), "name": "Alice", "id": int64(10)}},
+	}
+
+	// the batch is sorted as the loadBatch does it
+	s.batchEndValue = s.positionValue(elements[len(elements)-1])
+	is.NoErr(s.sortByKey(elements))
+	elements[len(elements)-1].batchEnd = true
+
+	for _, elem := range elements {
+		s.records <- elem
+	}
+
+	// the records are emitted in the key order, numbers are compared numerically,
+	// and only the last record moves the position to the end of the batch
+	for _, want := range []struct {
+		name     string
+		id       int64
+		position float64
+	}{
+		{name: "Alice", id: 2, position: 1},
+		{name: "Alice", id: 9, position: 1},
+		{name: "Alice", id: 10, position: 1},
+		{name: "Bob", id: 10, position: 5},
+	} {
+		record, err := s.Next(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{"name": want.name, "id": want.id})
+
+		position, err := ParsePosition(record.Position)
+		is.NoErr(err)
+		is.Equal(position.LastProcessedValue, want.position)
+	}
+}
+
+func TestSnapshot_Next_snapshotOperation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		snapshotOperation SnapshotOperation
+		polling           bool
+		want              sdk.Operation
+	}{
+		{
+			name: "empty",
+			want: sdk.OperationSnapshot,
+		},
+		{
+			name:              "snapshot",
+			snapshotOperation: SnapshotOperationSnapshot,
+			want:              sdk.OperationSnapshot,
+		},
+		{
+			name:              "create",
+			snapshotOperation: SnapshotOperationCreate,
+			want:              sdk.OperationCreate,
+		},
+		{
+			name:              "polling",
+			snapshotOperation: SnapshotOperationSnapshot,
+			polling:           true,
+			want:              sdk.OperationCreate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			s := &Snapshot{
+				orderingProperty:  "id",
+				keyProperties:     []string{"id"},
+				snapshotOperation: tt.snapshotOperation,
+				polling:           tt.polling,
+				records:           make(chan element, 2),
+			}
+
+			s.records <- element{props: map[string]any{"id": int64(1)}}
+			s.records <- endpointElement(dbtype.Node{Labels: []string{"Person"}, Props: map[string]any{"id": int64(2)}})
+
+			// both element and endpoint records get the operation
+			for range 2 {
+				record, err := s.Next(context.Background())
+				is.NoErr(err)
+				is.Equal(record.Operation, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordsCapacity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		params SnapshotParams
+		want   int
+	}{
+		{
+			name:   "success_batchSize",
+			params: SnapshotParams{EntityType: config.EntityTypeNode, BatchSize: 1000},
+			want:   1000,
+		},
+		{
+			name: "success_endpoints_as_records",
+			params: SnapshotParams{
+				EntityType: config.EntityTypeRelationship, BatchSize: 1000, EmitEndpointsAsRecords: true,
+			},
+			want: 1000 * recordsPerRelationship,
+		},
+		{
+			name:   "success_bounded",
+			params: SnapshotParams{EntityType: config.EntityTypeNode, BatchSize: 1000000},
+			want:   maxRecordsCapacity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			is.Equal(recordsCapacity(tt.params), tt.want)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// sortKeysSeparator separates the keys of a sort spec, and the sortDirectionSeparator separates
+	// the property of a key from its direction, e.g. "priority:desc,createdAt:asc".
+	sortKeysSeparator      = ","
+	sortDirectionSeparator = ":"
+
+	// sortValueTemplate references an item of the list of the last processed values by its index.
+	sortValueTemplate = "$" + orderingPropertyValueFieldName + "[%d]"
+)
+
+// SortKey is a property the snapshot is sorted by along with the direction of its values.
+type SortKey struct {
+	Property  string
+	Direction OrderingDirection
+}
+
+// ParseSort parses a sort spec of comma-separated properties with optional directions,
+// e.g. "priority:desc,createdAt:asc", a property without a direction is sorted in the ascending one.
+// It returns the [ErrInvalidSort] if a key is malformed or a property is repeated.
+func ParseSort(spec string) ([]SortKey, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	items := strings.Split(spec, sortKeysSeparator)
+
+	keys := make([]SortKey, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+
+	for _, item := range items {
+		property, direction, _ := strings.Cut(item, sortDirectionSeparator)
+
+		property = strings.TrimSpace(property)
+		if property == "" {
+			return nil, fmt.Errorf("%w: %q has no property", ErrInvalidSort, item)
+		}
+
+		if _, ok := seen[property]; ok {
+			return nil, fmt.Errorf("%w: %q is repeated", ErrInvalidSort, property)
+		}
+
+		seen[property] = struct{}{}
+
+		key := SortKey{Property: property, Direction: OrderingDirectionAsc}
+
+		switch direction := OrderingDirection(strings.ToLower(strings.TrimSpace(direction))); direction {
+		case "", OrderingDirectionAsc:
+
+		case OrderingDirectionDesc:
+			key.Direction = direction
+
+		default:
+			return nil, fmt.Errorf("%w: %q has an unsupported direction, it must be asc or desc", ErrInvalidSort, item)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// KeySort returns the sort keys of the key properties in the ascending order,
+// which along with the element id make the order of the snapshot independent of the ordering property.
+func KeySort(keyProperties []string) []SortKey {
+	keys := make([]SortKey, len(keyProperties))
+	for i, property := range keyProperties {
+		keys[i] = SortKey{Property: property, Direction: OrderingDirectionAsc}
+	}
+
+	return keys
+}
+
+// sortPositionStrategy is a [PositionStrategy] of a snapshot sorted by multiple properties with their own directions,
+// the element id is the last sort key, so elements with equal values of the properties are sorted stably.
+// It stores a list of the property values followed by the element id in positions, and it selects elements following
+// the list lexicographically, e.g. for "priority:desc,createdAt:asc" the elements with a lower priority,
+// or with the same priority and a later createdAt, or with the same values and a greater element id.
+type sortPositionStrategy struct {
+	keys []SortKey
+}
+
+// NextPosition returns the values of the sort properties followed by the element id.
+func (p sortPositionStrategy) NextPosition(props map[string]any, elementID string) any {
+	values := make([]any, 0, len(p.keys)+1)
+	for _, key := range p.keys {
+		values = append(values, props[key.Property])
+	}
+
+	return append(values, elementID)
+}
+
+// WhereClause matches elements which values follow the list of the last processed values lexicographically.
+func (p sortPositionStrategy) WhereClause() string {
+	alternatives := make([]string, 0, len(p.keys)+1)
+	equalities := make([]string, 0, len(p.keys))
+
+	for i, key := range p.keys {
+		expression := objPlaceholder + "." + escapeIdentifier(key.Property)
+		value := fmt.Sprintf(sortValueTemplate, i)
+
+		alternatives = append(alternatives, sortAlternative(equalities, expression+" "+key.Direction.after()+" "+value))
+		equalities = append(equalities, expression+" = "+value)
+	}
+
+	alternatives = append(alternatives, sortAlternative(equalities,
+		elementIDOrderingExpression+" "+OrderingDirectionAsc.after()+" "+fmt.Sprintf(sortValueTemplate, len(p.keys)),
+	))
+
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// Params returns the list of the last processed values as the opv parameter.
+func (p sortPositionStrategy) Params(lastProcessedValue any) map[string]any {
+	return map[string]any{orderingPropertyValueFieldName: lastProcessedValue}
+}
+
+// orderByClause returns the sort keys followed by the element id in the Cypher ORDER BY syntax.
+func (p sortPositionStrategy) orderByClause() string {
+	items := make([]string, 0, len(p.keys)+1)
+	for _, key := range p.keys {
+		items = append(items, objPlaceholder+"."+escapeIdentifier(key.Property)+" "+key.Direction.keyword())
+	}
+
+	return strings.Join(append(items, elementIDOrderingExpression+" "+OrderingDirectionAsc.keyword()), ", ")
+}
+
+// notNullCondition makes sure the elements can be compared by all sort properties.
+func (p sortPositionStrategy) notNullCondition() string {
+	conditions := make([]string, len(p.keys))
+	for i, key := range p.keys {
+		conditions[i] = fmt.Sprintf(notNullWhereClause, objPlaceholder, escapeIdentifier(key.Property))
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+// sortAlternative joins the equalities of the preceding sort keys with the comparison of the next one.
+func sortAlternative(equalities []string, comparison string) string {
+	if len(equalities) == 0 {
+		return comparison
+	}
+
+	return "(" + strings.Join(equalities, " AND ") + " AND " + comparison + ")"
+}
+
+// sortLogRedactProperties extends names of redacted properties with the name of the opv parameter
+// if any of the sort properties is redacted, as the parameter holds their values.
+func sortLogRedactProperties(properties []string, keys []SortKey) []string {
+	if slices.Contains(properties, orderingPropertyValueFieldName) {
+		return properties
+	}
+
+	for _, key := range keys {
+		if slices.Contains(properties, key.Property) {
+			return append(slices.Clone(properties), orderingPropertyValueFieldName)
+		}
+	}
+
+	return properties
+}
@@ -0,0 +1,183 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseSort(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		spec    string
+		want    []SortKey
+		wantErr error
+	}{
+		{
+			name: "success",
+			spec: "priority:desc,createdAt:asc",
+			want: []SortKey{
+				{Property: "priority", Direction: OrderingDirectionDesc},
+				{Property: "createdAt", Direction: OrderingDirectionAsc},
+			},
+		},
+		{
+			name: "success_default_direction",
+			spec: " priority : DESC , createdAt ",
+			want: []SortKey{
+				{Property: "priority", Direction: OrderingDirectionDesc},
+				{Property: "createdAt", Direction: OrderingDirectionAsc},
+			},
+		},
+		{
+			name: "success_empty",
+			spec: " ",
+		},
+		{
+			name:    "fail_no_property",
+			spec:    "priority:desc,:asc",
+			wantErr: ErrInvalidSort,
+		},
+		{
+			name:    "fail_direction",
+			spec:    "priority:down",
+			wantErr: ErrInvalidSort,
+		},
+		{
+			name:    "fail_repeated",
+			spec:    "priority:desc,priority:asc",
+			wantErr: ErrInvalidSort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSort(tt.spec)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseSort() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortPositionStrategy(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	strategy := sortPositionStrategy{keys: []SortKey{
+		{Property: "priority", Direction: OrderingDirectionDesc},
+		{Property: "createdAt", Direction: OrderingDirectionAsc},
+	}}
+
+	is.Equal(strategy.orderByClause(), "obj.`priority` DESC, obj.`createdAt` ASC, elementId(obj) ASC")
+	is.Equal(strategy.notNullCondition(), "obj.`priority` IS NOT NULL AND obj.`createdAt` IS NOT NULL")
+
+	// the elements with a lower priority, or with the same priority and a later createdAt,
+	// or with the same values and a greater element id follow the last processed one
+	is.Equal(strategy.WhereClause(), "(obj.`priority` < $opv[0]"+
+		" OR (obj.`priority` = $opv[0] AND obj.`createdAt` > $opv[1])"+
+		" OR (obj.`priority` = $opv[0] AND obj.`createdAt` = $opv[1] AND elementId(obj) > $opv[2]))")
+
+	is.Equal(strategy.NextPosition(map[string]any{"priority": int64(2), "createdAt": int64(5)}, "4:abc:1"),
+		[]any{int64(2), int64(5), "4:abc:1"})
+}
+
+func TestKeySort(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	keys := KeySort([]string{"tenant", "email"})
+	is.Equal(keys, []SortKey{
+		{Property: "tenant", Direction: OrderingDirectionAsc},
+		{Property: "email", Direction: OrderingDirectionAsc},
+	})
+
+	// elements with equal keys are sorted by their element ids
+	is.Equal(sortPositionStrategy{keys: keys}.orderByClause(),
+		"obj.`tenant` ASC, obj.`email` ASC, elementId(obj) ASC")
+}
+
+func TestSortLogRedactProperties(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	keys := []SortKey{{Property: "priority", Direction: OrderingDirectionDesc}, {Property: "email"}}
+
+	is.Equal(sortLogRedactProperties([]string{"name"}, keys), []string{"name"})
+	is.Equal(sortLogRedactProperties([]string{"email"}, keys), []string{"email", orderingPropertyValueFieldName})
+	is.Equal(sortLogRedactProperties([]string{"email", orderingPropertyValueFieldName}, keys),
+		[]string{"email", orderingPropertyValueFieldName})
+}
+
+func TestSnapshot_sort_resume(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	keys := []SortKey{
+		{Property: "priority", Direction: OrderingDirectionDesc},
+		{Property: "createdAt", Direction: OrderingDirectionAsc},
+	}
+
+	s := &Snapshot{
+		orderingProperty: "id",
+		keyProperties:    []string{"id"},
+		sort:             keys,
+		records:          make(chan element, 1),
+	}
+	is.Equal(s.orderByClause(), "obj.`priority` DESC, obj.`createdAt` ASC, elementId(obj) ASC")
+
+	s.records <- element{
+		props:     map[string]any{"id": int64(1), "priority": int64(2), "createdAt": int64(5)},
+		elementID: "4:abc:1",
+	}
+
+	record, err := s.Next(context.Background())
+	is.NoErr(err)
+
+	// the position holds the values of the sort keys followed by the element id
+	position, err := ParsePosition(record.Position)
+	is.NoErr(err)
+	is.NoErr(position.ValidateSort(keys))
+	is.Equal(position.LastProcessedValue, []any{float64(2), float64(5), "4:abc:1"})
+
+	// the resumed snapshot passes the values as the opv parameter of the lexicographic condition
+	resumed := &Snapshot{orderingProperty: "id", sort: keys, position: position}
+
+	params := make(map[string]any)
+
+	condition, err := resumed.cursorCondition(params)
+	is.NoErr(err)
+	is.Equal(condition, sortPositionStrategy{keys: keys}.WhereClause())
+	is.Equal(params, map[string]any{orderingPropertyValueFieldName: []any{float64(2), float64(5), "4:abc:1"}})
+}
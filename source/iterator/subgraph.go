@@ -0,0 +1,98 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+const (
+	// subgraphMatchClauseTemplate matches relationships of a subgraph snapshot along with their endpoints,
+	// it takes the node labels and the relationship types joined with "|".
+	subgraphMatchClauseTemplate = "MATCH (src:%[1]s)-[obj:%[2]s]->(trgt:%[1]s)"
+	// subgraphEndpointsWhereClause keeps only relationships which endpoints are within the snapshot of nodes,
+	// i.e. have the ordering property up to its max value.
+	subgraphEndpointsWhereClause = "src.%[1]s %[2]s $opmv AND trgt.%[1]s %[2]s $opmv"
+	// relationshipTypesSeparator separates alternative relationship types in a Cypher pattern.
+	relationshipTypesSeparator = "|"
+)
+
+// Relationships returns a snapshot of relationships of the given types between the nodes
+// captured by the snapshot, which follows it to capture a subgraph without dangling relationships.
+//
+// Both endpoints of a captured relationship have the entity labels and the ordering property
+// up to the max value the snapshot of nodes was bounded by. The relationships are paginated by element ids,
+// as they don't have to have the ordering property, and their keys are composed of the keys of their endpoints.
+// The position holds the element id of the last captured relationship, if it's nil, the capture starts over.
+func (s *Snapshot) Relationships(relationshipTypes []string, position *Position) *Snapshot {
+	escapedTypes := make([]string, len(relationshipTypes))
+	for i, relationshipType := range relationshipTypes {
+		escapedTypes[i] = escapeIdentifier(relationshipType)
+	}
+
+	return &Snapshot{
+		driver:                   s.driver,
+		orderingProperty:         s.orderingProperty,
+		orderingPropertyMaxValue: s.orderingPropertyMaxValue,
+		entityType:               config.EntityTypeRelationship,
+		entityLabels:             strings.Join(relationshipTypes, relationshipTypesSeparator),
+		labels:                   s.labels,
+		matchClause: fmt.Sprintf(subgraphMatchClauseTemplate,
+			cypherLabels(s.labels), strings.Join(escapedTypes, relationshipTypesSeparator),
+		),
+		batchSize:               s.batchSize,
+		databaseName:            s.databaseName,
+		fieldCollision:          s.fieldCollision,
+		payloadFormat:           s.payloadFormat,
+		historyProperty:         s.historyProperty,
+		historyDecodeJSON:       s.historyDecodeJSON,
+		vectors:                 s.vectors,
+		includeElementID:        s.includeElementID,
+		elementIDField:          s.elementIDField,
+		keyByEndpoints:          true,
+		orderingDirection:       s.orderingDirection,
+		logRedactProperties:     s.logRedactProperties,
+		alignPositionsToBatches: s.alignPositionsToBatches,
+		snapshotOperation:       s.snapshotOperation,
+		changeID:                s.changeID,
+		byElementID:             true,
+		position:                position,
+		records:                 make(chan element, min(s.batchSize, maxRecordsCapacity)),
+		subgraph:                true,
+		relationshipTypes:       relationshipTypes,
+		bookmarks:               s.bookmarks,
+		rateLimiter:             s.rateLimiter,
+		queryTimeout:            s.queryTimeout,
+		metadataFields:          s.metadataFields,
+	}
+}
+
+// setSubgraphMetadata puts the entity type and, for relationships, the relationship type into the metadata
+// if the snapshot is a part of a subgraph snapshot, so nodes and relationships can be told apart.
+func (s *Snapshot) setSubgraphMetadata(metadata sdk.Metadata, entityType config.EntityType, relationshipType string) {
+	if !s.subgraph {
+		return
+	}
+
+	metadata[metadataEntityTypeField] = string(entityType)
+
+	if relationshipType != "" {
+		metadata[metadataEntityLabelsField] = relationshipType
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+)
+
+func TestSnapshot_Relationships(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	nodes := &Snapshot{
+		orderingProperty:         "id",
+		orderingPropertyMaxValue: int64(10),
+		entityType:               config.EntityTypeNode,
+		labels:                   []string{"Person"},
+		batchSize:                100,
+		subgraph:                 true,
+	}
+	is.Equal(nodes.notNullCondition(), "obj.`id` IS NOT NULL")
+
+	relationships := nodes.Relationships([]string{"KNOWS", "FOLLOWS"}, nil)
+	is.Equal(relationships.matchClause, "MATCH (src:`Person`)-[obj:`KNOWS`|`FOLLOWS`]->(trgt:`Person`)")
+	is.Equal(relationships.notNullCondition(), "src.`id` IS NOT NULL AND trgt.`id` IS NOT NULL")
+	is.Equal(relationships.orderingExpression(), elementIDOrderingExpression)
+	is.Equal(relationships.mode(), ModeSubgraphSnapshot)
+
+	relationships.records <- element{
+		props:     map[string]any{sourceNodeField: nil, targetNodeField: nil},
+		metadata:  sdk.Metadata{metadataEntityLabelsField: "KNOWS"},
+		elementID: "5:abc:1",
+	}
+
+	record, err := relationships.Next(context.Background())
+	is.NoErr(err)
+	is.Equal(record.Metadata[metadataEntityLabelsField], "KNOWS")
+
+	// the position holds the element id of the relationship and the max value of the snapshot of nodes
+	position, err := ParsePosition(record.Position)
+	is.NoErr(err)
+	is.Equal(position.Mode, ModeSubgraphSnapshot)
+	is.Equal(position.LastProcessedValue, "5:abc:1")
+	is.Equal(position.MaxElement, float64(10))
+	is.Equal(position.EntityLabels, []string{"Person"})
+}
+
+func TestSnapshot_Relationships_noNodes(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// there were no nodes, so the relationships are not even queried
+	relationships := (&Snapshot{orderingProperty: "id", labels: []string{"Person"}}).Relationships([]string{"KNOWS"}, nil)
+
+	hasNext, err := relationships.HasNext(context.Background())
+	is.NoErr(err)
+	is.True(!hasNext)
+}
+
+func TestSnapshot_setSubgraphMetadata(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	metadata := make(sdk.Metadata)
+	(&Snapshot{}).setSubgraphMetadata(metadata, config.EntityTypeNode, "")
+	is.Equal(metadata, sdk.Metadata{})
+
+	(&Snapshot{subgraph: true}).setSubgraphMetadata(metadata, config.EntityTypeRelationship, "KNOWS")
+	is.Equal(metadata, sdk.Metadata{
+		metadataEntityTypeField:   string(config.EntityTypeRelationship),
+		metadataEntityLabelsField: "KNOWS",
+	})
+}
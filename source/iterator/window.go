@@ -0,0 +1,79 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// ChangedWithinStart returns an ordering property value from which the capture
+// of elements changed within the window should start.
+// The type of the value matches the type of the ordering property, which must be temporal,
+// so the value can be compared with the property in Cypher queries.
+// The read is terminated if it runs longer than the non-zero queryTimeout.
+func ChangedWithinStart(
+	ctx context.Context,
+	driver neo4j.DriverWithContext,
+	database string, labels []string, labelMatch LabelMatch, property string,
+	entityType config.EntityType,
+	window, queryTimeout time.Duration,
+) (any, error) {
+	// the max value is only used as a sample to detect the type of the ordering property
+	sample, err := getMaxPropertyValue(ctx, driver,
+		database, labels, labelMatch, property, entityType, OrderingDirectionAsc, nil, queryTimeout,
+	)
+	if err != nil && !errors.Is(err, errNoElements) {
+		return nil, fmt.Errorf("get ordering property max value: %w", err)
+	}
+
+	return windowStart(sample, time.Now(), window)
+}
+
+// windowStart converts the beginning of the window that ends at the now
+// to the type of the sample ordering property value.
+//
+// Local date-times don't have a time zone, so they are compared with the UTC wall clock,
+// which is the Neo4j default time zone. Dates are shifted by one day back,
+// so elements changed on the first day of the window are captured as well.
+func windowStart(sample any, now time.Time, window time.Duration) (any, error) {
+	start := now.Add(-window).UTC()
+
+	switch sample := sample.(type) {
+	case nil:
+		// there are no elements yet, so we expect them to have zoned date-times
+		return start, nil
+
+	case time.Time:
+		return start.In(sample.Location()), nil
+
+	case dbtype.LocalDateTime:
+		return dbtype.LocalDateTime(start), nil
+
+	case dbtype.Date:
+		year, month, day := start.AddDate(0, 0, -1).Date()
+
+		return dbtype.Date(time.Date(year, month, day, 0, 0, 0, 0, time.UTC)), nil
+
+	default:
+		return nil, fmt.Errorf("%w: %T", errUnsupportedWindowType, sample)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestWindowStart(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now    = time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)
+		window = 24 * time.Hour
+		berlin = time.FixedZone("Europe/Berlin", 60*60)
+	)
+
+	tests := []struct {
+		name    string
+		sample  any
+		want    any
+		wantErr error
+	}{
+		{
+			name: "success_no_elements",
+			want: time.Date(2024, 3, 9, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			name:   "success_date_time",
+			sample: time.Date(2024, 3, 10, 10, 0, 0, 0, berlin),
+			want:   time.Date(2024, 3, 9, 13, 30, 0, 0, berlin),
+		},
+		{
+			name:   "success_local_date_time",
+			sample: dbtype.LocalDateTime(time.Date(2024, 3, 10, 10, 0, 0, 0, time.Local)),
+			want:   dbtype.LocalDateTime(time.Date(2024, 3, 9, 12, 30, 0, 0, time.UTC)),
+		},
+		{
+			name:   "success_date",
+			sample: dbtype.Date(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)),
+			want:   dbtype.Date(time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:    "fail_unsupported_type",
+			sample:  int64(10),
+			wantErr: errUnsupportedWindowType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := windowStart(tt.sample, now, window)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("windowStart() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("windowStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/source/iterator"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// positioner is implemented by the iterators that can return their current position.
+type positioner interface {
+	CurrentPosition() (sdk.Position, error)
+}
+
+// snapshotCompleteRecord returns the marker record of the snapshot completion, see the [iterator.SnapshotCompleteRecord].
+// It takes the position the capture of changes starts from, so a restart after the marker doesn't repeat it,
+// or the last position of the completed snapshot if nothing is captured after it.
+func (s *Source) snapshotCompleteRecord(snapshot Iterator) (sdk.Record, error) {
+	var it Iterator = snapshot
+	if changes := s.changes(); changes != nil {
+		it = changes
+	}
+
+	var position sdk.Position
+	if positioner, ok := it.(positioner); ok {
+		var err error
+
+		position, err = positioner.CurrentPosition()
+		if err != nil {
+			return sdk.Record{}, fmt.Errorf("get current position: %w", err)
+		}
+	}
+
+	return iterator.SnapshotCompleteRecord(position, s.config.EntityLabels), nil
+}
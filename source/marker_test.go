@@ -0,0 +1,115 @@
+// Copyright © 2023 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/source/mock"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+	"go.uber.org/mock/gomock"
+)
+
+// positionedIterator is an [Iterator] that returns a fixed current position.
+type positionedIterator struct {
+	*mock.MockIterator
+	position sdk.Position
+}
+
+func (it positionedIterator) CurrentPosition() (sdk.Position, error) {
+	return it.position, nil
+}
+
+func TestSource_Read_snapshotCompleteMarker(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	snapshotRecord := sdk.Record{Position: sdk.Position(`{"mode":"snapshot","lastProcessedValue":1}`)}
+	pollingRecord := sdk.Record{Position: sdk.Position(`{"mode":"snapshot_polling","lastProcessedValue":2}`)}
+
+	snapshotIt := mock.NewMockIterator(ctrl)
+	gomock.InOrder(
+		snapshotIt.EXPECT().HasNext(ctx).Return(true, nil),
+		snapshotIt.EXPECT().Next(ctx).Return(snapshotRecord, nil),
+		snapshotIt.EXPECT().HasNext(ctx).Return(false, nil),
+	)
+
+	pollingSnapshotIt := positionedIterator{
+		MockIterator: mock.NewMockIterator(ctrl),
+		position:     sdk.Position(`{"mode":"snapshot_polling","lastProcessedValue":1}`),
+	}
+	pollingSnapshotIt.EXPECT().HasNext(ctx).Return(true, nil)
+	pollingSnapshotIt.EXPECT().Next(ctx).Return(pollingRecord, nil)
+
+	s := Source{
+		snapshot:        snapshotIt,
+		pollingSnapshot: pollingSnapshotIt,
+		config: Config{
+			Config:                 config.Config{EntityLabels: []string{"Person"}},
+			SnapshotCompleteMarker: true,
+		},
+	}
+
+	record, err := s.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record, snapshotRecord)
+
+	// the marker is emitted exactly once, between the snapshot and the polling
+	marker, err := s.Read(ctx)
+	is.NoErr(err)
+	is.Equal(marker.Operation, sdk.OperationSnapshot)
+	is.Equal(marker.Metadata["neo4j.snapshotComplete"], "true")
+	is.Equal(marker.Metadata["neo4j.entityLabels"], "Person")
+	is.Equal(marker.Position, pollingSnapshotIt.position)
+	is.Equal(marker.Key, nil)
+
+	record, err = s.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record, pollingRecord)
+}
+
+func TestSource_Read_snapshotCompleteMarkerSnapshotOnly(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	snapshotIt := positionedIterator{
+		MockIterator: mock.NewMockIterator(ctrl),
+		position:     sdk.Position(`{"mode":"snapshot","lastProcessedValue":1}`),
+	}
+	snapshotIt.EXPECT().HasNext(ctx).Return(false, nil)
+
+	s := Source{snapshot: snapshotIt, config: Config{SnapshotOnly: true, SnapshotCompleteMarker: true}}
+
+	marker, err := s.Read(ctx)
+	is.NoErr(err)
+	is.Equal(marker.Metadata["neo4j.snapshotComplete"], "true")
+	is.Equal(marker.Position, snapshotIt.position)
+
+	for range 2 {
+		_, err = s.Read(ctx)
+		is.Equal(err, sdk.ErrBackoffRetry)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/conduitio-labs/conduit-connector-neo4j/config"
 	"github.com/conduitio-labs/conduit-connector-neo4j/source/iterator"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
@@ -42,6 +43,26 @@ type Source struct {
 	driver          neo4j.DriverWithContext
 	snapshot        Iterator
 	pollingSnapshot Iterator
+	// cdc captures changes after the snapshot instead of the pollingSnapshot if the CDC is enabled.
+	cdc Iterator
+	// subgraphSnapshot captures relationships between the nodes captured by the snapshot after it,
+	// if the subgraphRelationshipTypes are set.
+	subgraphSnapshot Iterator
+	// endpointLabels holds the parsed endpointLabels of the relationship types.
+	endpointLabels map[string]iterator.EndpointLabels
+	// sort holds the parsed snapshotSort.
+	sort []iterator.SortKey
+	// bookmarks are shared by the iterators to read with the causal consistency,
+	// they're nil unless the causalConsistency is enabled.
+	bookmarks *iterator.Bookmarks
+	// rateLimiter is shared by the iterators, including the ones of the entities, to limit the rate of their reads,
+	// it's nil unless the readRateLimit is set.
+	rateLimiter *iterator.RateLimiter
+	// entities hold the sources of the entities if multiple entities are captured,
+	// the first of them captures the entity of the top-level config values.
+	entities []*entity
+	// nextEntity is the index of the entity the next record is read from.
+	nextEntity int
 }
 
 // New creates a new instance of the [Source].
@@ -55,15 +76,89 @@ func (s *Source) Parameters() map[string]sdk.Parameter {
 }
 
 // Configure parses and initializes the [Source] config.
-func (s *Source) Configure(_ context.Context, raw map[string]string) error {
+func (s *Source) Configure(ctx context.Context, raw map[string]string) error {
 	if err := sdk.Util.ParseConfig(raw, &s.config); err != nil {
 		return fmt.Errorf("parse config: %w", err)
 	}
 
+	// the entities inherit the top-level config values before they're normalized, so they're configured first
+	entities, err := s.configureEntities(ctx)
+	if err != nil {
+		return fmt.Errorf("configure entities: %w", err)
+	}
+
+	if err = s.configure(ctx); err != nil {
+		return err
+	}
+
+	s.entities = entities
+
+	return nil
+}
+
+// configure normalizes and validates the parsed config.
+func (s *Source) configure(ctx context.Context) error {
+	// the entityLabels are optional if the elements are matched by the custom query
+	if err := s.config.NormalizeEntityLabels(); err != nil &&
+		(s.config.Query == "" || !errors.Is(err, config.ErrNoEntityLabels)) {
+		return fmt.Errorf("normalize entity labels: %w", err)
+	}
+
+	if err := s.config.ValidateTLS(); err != nil {
+		return fmt.Errorf("validate tls: %w", err)
+	}
+
+	if err := s.config.ValidateResolver(); err != nil {
+		return fmt.Errorf("validate resolver: %w", err)
+	}
+
+	if err := s.config.ValidateConnectionPool(); err != nil {
+		return fmt.Errorf("validate connection pool: %w", err)
+	}
+
+	if err := s.config.ValidateQueryTimeout(); err != nil {
+		return fmt.Errorf("validate query timeout: %w", err)
+	}
+
+	if err := s.config.ValidateStartupRetryTimeout(); err != nil {
+		return fmt.Errorf("validate startup retry timeout: %w", err)
+	}
+
+	if err := s.config.Auth.Validate(); err != nil {
+		return fmt.Errorf("validate auth: %w", err)
+	}
+
 	// if the keyProperties is empty,
-	// we'll use the orderingProperty as a record key
-	if len(s.config.KeyProperties) == 0 {
+	// we'll use the orderingProperty as a record key unless it's excluded from it
+	if len(s.config.KeyProperties) == 0 && !s.config.ExcludeOrderingPropertyFromKey {
 		s.config.KeyProperties = []string{s.config.OrderingProperty}
+
+		if !s.config.keyedByEndpoints() {
+			sdk.Logger(ctx).Warn().Str("orderingProperty", s.config.OrderingProperty).
+				Msg("keyProperties is empty, so record keys consist of the orderingProperty; " +
+					"set keyProperties to choose the key explicitly")
+		}
+	}
+
+	if err := s.config.Validate(); err != nil {
+		return fmt.Errorf("validate config: %w", err)
+	}
+
+	endpointLabels, err := s.config.endpointLabels()
+	if err != nil {
+		return fmt.Errorf("parse endpoint labels: %w", err)
+	}
+
+	s.endpointLabels = endpointLabels
+
+	s.sort, err = iterator.ParseSort(s.config.SnapshotSort)
+	if err != nil {
+		return fmt.Errorf("parse snapshot sort: %w", err)
+	}
+
+	// the deterministic order sorts the snapshot by the key, and the element id breaks ties of equal keys
+	if s.config.DeterministicOrder {
+		s.sort = iterator.KeySort(s.config.KeyProperties)
 	}
 
 	return nil
@@ -71,52 +166,118 @@ func (s *Source) Configure(_ context.Context, raw map[string]string) error {
 
 // Open makes sure everything is prepared to read records.
 func (s *Source) Open(ctx context.Context, sdkPosition sdk.Position) error {
-	driver, err := neo4j.NewDriverWithContext(s.config.URI, s.config.Auth.AuthToken())
+	driver, err := s.config.NewDriver()
 	if err != nil {
 		return fmt.Errorf("create neo4j driver: %w", err)
 	}
 
-	if err = driver.VerifyConnectivity(ctx); err != nil {
+	if err = s.config.VerifyConnectivity(ctx, driver); err != nil {
 		return fmt.Errorf("ping neo4j instance: %w", err)
 	}
 
 	s.driver = driver
 
+	if s.config.ReadRateLimit > 0 {
+		s.rateLimiter = iterator.NewRateLimiter(s.config.ReadRateLimit)
+	}
+
+	if !s.config.SkipDatabaseCheck {
+		if err = s.config.VerifyDatabase(ctx, s.driver); err != nil {
+			return fmt.Errorf("verify database: %w", err)
+		}
+	}
+
+	if err = s.config.WarmUpConnections(ctx, s.driver, neo4j.AccessModeRead); err != nil {
+		return fmt.Errorf("warm up connections: %w", err)
+	}
+
 	position, err := iterator.ParsePosition(sdkPosition)
 	if err != nil && !errors.Is(err, iterator.ErrNilSDKPosition) {
 		return fmt.Errorf("parse position: %w", err)
 	}
 
-	s.pollingSnapshot, err = iterator.NewPollingSnapshot(ctx, iterator.SnapshotParams{
-		Driver:           driver,
-		OrderingProperty: s.config.OrderingProperty,
-		KeyProperties:    s.config.KeyProperties,
-		EntityType:       s.config.EntityType,
-		EntityLabels:     s.config.EntityLabels,
-		BatchSize:        s.config.BatchSize,
-		DatabaseName:     s.config.Database,
-		Position:         position,
-	})
-	if err != nil {
-		return fmt.Errorf("init polling snapshot iterator: %w", err)
-	}
-
-	if s.config.Snapshot && (position == nil || position.Mode == iterator.ModeSnapshot) {
-		s.snapshot, err = iterator.NewSnapshot(ctx, iterator.SnapshotParams{
-			Driver:           driver,
-			OrderingProperty: s.config.OrderingProperty,
-			KeyProperties:    s.config.KeyProperties,
-			EntityType:       s.config.EntityType,
-			EntityLabels:     s.config.EntityLabels,
-			BatchSize:        s.config.BatchSize,
-			DatabaseName:     s.config.Database,
-			Position:         position,
-		})
+	var positions map[string]*iterator.Position
+	if position != nil {
+		positions, err = position.EntityPositions(defaultEntityName)
+		if err != nil {
+			return fmt.Errorf("get entity positions: %w", err)
+		}
+	}
+
+	if len(s.entities) > 0 {
+		return s.openEntities(ctx, positions)
+	}
+
+	return s.open(ctx, positions[defaultEntityName])
+}
+
+// open makes sure everything is prepared to read records from the position of a single entity.
+func (s *Source) open(ctx context.Context, position *iterator.Position) error {
+	var err error
+
+	if s.config.IndexProperty != "" {
+		if err = iterator.VerifyIndex(ctx, s.driver, s.config.Database,
+			s.config.EntityLabels[0], s.config.IndexProperty, s.config.EntityType,
+		); err != nil {
+			return fmt.Errorf("verify index: %w", err)
+		}
+	}
+
+	if err = iterator.VerifyProjections(ctx, s.driver, s.config.Database,
+		s.config.EntityType, s.config.Projections,
+	); err != nil {
+		return fmt.Errorf("verify projections: %w", err)
+	}
+
+	// the ordering check samples elements by the entityLabels, so it's skipped for the custom query
+	if !s.config.SkipOrderingCheck && s.config.Query == "" {
+		s.checkOrderingProperty(ctx)
+	}
+
+	// if the position doesn't match the config, the capture may be restarted from scratch
+	if position != nil {
+		err = position.Validate(s.config.pagingProperty(), s.config.EntityLabels)
+		if err == nil {
+			err = position.ValidateSort(s.sort)
+		}
+
+		if err == nil {
+			err = s.snapshotParams(position).ValidatePosition()
+		}
+
 		if err != nil {
-			return fmt.Errorf("init snapshot iterator: %w", err)
+			if s.config.PositionMismatch != PositionMismatchRestart {
+				return fmt.Errorf("validate position: %w", err)
+			}
+
+			sdk.Logger(ctx).Warn().Err(err).Msg("the position doesn't match the config, restarting the capture from scratch")
+
+			position = nil
 		}
 	}
 
+	// if there's no position to resume from, the capture starts from the beginning of the window
+	if position == nil && s.config.ChangedWithin > 0 {
+		position, err = s.changedWithinPosition(ctx)
+		if err != nil {
+			return fmt.Errorf("get changed within position: %w", err)
+		}
+	}
+
+	// the reads resume from the bookmarks of the position, so they don't observe an older state of a cluster
+	if s.config.CausalConsistency {
+		var bookmarks []string
+		if position != nil {
+			bookmarks = position.Bookmarks
+		}
+
+		s.bookmarks = iterator.NewBookmarks(bookmarks)
+	}
+
+	if err = s.openIterators(ctx, position); err != nil {
+		return fmt.Errorf("open iterators: %w", err)
+	}
+
 	return nil
 }
 
@@ -124,6 +285,23 @@ func (s *Source) Open(ctx context.Context, sdkPosition sdk.Position) error {
 // It can return the error [sdk.ErrBackoffRetry] to signal to the SDK
 // it should call Read again with a backoff retry.
 func (s *Source) Read(ctx context.Context) (sdk.Record, error) {
+	record, err := s.readRecord(ctx)
+	if err != nil && config.IsQueryTimeout(err) {
+		// the query that exceeded the queryTimeout is transient, the iterators load the same batch again on retry
+		sdk.Logger(ctx).Warn().Err(err).Msg("the query exceeded the queryTimeout, retrying it with a backoff")
+
+		return sdk.Record{}, sdk.ErrBackoffRetry
+	}
+
+	return record, err
+}
+
+// readRecord returns a new [sdk.Record] of the entities, the snapshot or the capture of changes.
+func (s *Source) readRecord(ctx context.Context) (sdk.Record, error) {
+	if len(s.entities) > 0 {
+		return s.readEntities(ctx)
+	}
+
 	switch {
 	case s.snapshot != nil:
 		record, err := read(ctx, s.snapshot)
@@ -132,16 +310,44 @@ func (s *Source) Read(ctx context.Context) (sdk.Record, error) {
 				return sdk.Record{}, err
 			}
 
+			// the snapshot of nodes is followed by the snapshot of relationships between them
+			if s.subgraphSnapshot != nil {
+				s.snapshot, s.subgraphSnapshot = s.subgraphSnapshot, nil
+
+				return s.readRecord(ctx)
+			}
+
+			snapshot := s.snapshot
 			s.snapshot = nil
 
-			return read(ctx, s.pollingSnapshot)
+			if s.config.SnapshotOnly {
+				sdk.Logger(ctx).Info().Msg("the snapshot is complete, no more records will be read")
+			}
+
+			// the marker takes the place of the first record after the snapshot
+			if s.config.SnapshotCompleteMarker {
+				return s.snapshotCompleteRecord(snapshot)
+			}
+
+			if s.config.SnapshotOnly {
+				return sdk.Record{}, sdk.ErrBackoffRetry
+			}
+
+			return read(ctx, s.changes())
 		}
 
 		return record, nil
 
+	case s.cdc != nil:
+		return read(ctx, s.cdc)
+
 	case s.pollingSnapshot != nil:
 		return read(ctx, s.pollingSnapshot)
 
+	case s.config.SnapshotOnly:
+		// the snapshot is complete, so the connector idles until it's stopped
+		return sdk.Record{}, sdk.ErrBackoffRetry
+
 	default:
 		return sdk.Record{}, errNoIterator
 	}
@@ -165,6 +371,236 @@ func (s *Source) Teardown(ctx context.Context) error {
 	return nil
 }
 
+// openIterators initializes the iterators that capture the snapshot and the changes after it.
+func (s *Source) openIterators(ctx context.Context, position *iterator.Position) error {
+	var err error
+
+	params := s.snapshotParams(position)
+
+	switch {
+	case s.config.SnapshotOnly:
+		// nothing is captured after the snapshot, so neither the polling nor the CDC is needed
+
+	case s.config.CDCEnabled:
+		if err = s.openCDC(ctx, position, &params); err != nil {
+			return fmt.Errorf("open cdc: %w", err)
+		}
+
+	default:
+		s.pollingSnapshot, err = iterator.NewPollingSnapshot(ctx, params)
+		if err != nil {
+			return fmt.Errorf("init polling snapshot iterator: %w", err)
+		}
+	}
+
+	if !s.config.Snapshot || !s.snapshotPending(position) {
+		return nil
+	}
+
+	snapshot, err := iterator.NewSnapshot(ctx, params)
+	if err != nil {
+		return fmt.Errorf("init snapshot iterator: %w", err)
+	}
+
+	switch {
+	case len(s.config.SubgraphRelationshipTypes) == 0:
+		s.snapshot = snapshot
+
+	case position != nil && position.Mode == iterator.ModeSubgraphSnapshot:
+		// the snapshot of nodes is complete, so only the relationships between them are left
+		s.snapshot = snapshot.Relationships(s.config.SubgraphRelationshipTypes, position)
+
+	default:
+		s.snapshot = snapshot
+		s.subgraphSnapshot = snapshot.Relationships(s.config.SubgraphRelationshipTypes, nil)
+	}
+
+	return nil
+}
+
+// snapshotPending checks if the snapshot must be captured or resumed from the position.
+// A subgraph position is only resumed if the subgraph snapshot is still enabled.
+func (s *Source) snapshotPending(position *iterator.Position) bool {
+	switch {
+	case position == nil, position.Mode == iterator.ModeSnapshot:
+		return true
+
+	case position.Mode == iterator.ModeSubgraphSnapshot:
+		return len(s.config.SubgraphRelationshipTypes) > 0
+
+	default:
+		return false
+	}
+}
+
+// openCDC initializes the CDC iterator.
+// The capture starts after the change stored in the position, or after the current change if there's none,
+// and the change is passed to the snapshot params, so it's stored in positions of snapshot records as well.
+func (s *Source) openCDC(ctx context.Context, position *iterator.Position, params *iterator.SnapshotParams) error {
+	var changeID string
+	if position != nil {
+		changeID = position.ChangeID
+	}
+
+	if changeID == "" {
+		var err error
+
+		changeID, err = iterator.CurrentChangeID(ctx, s.driver, s.config.Database)
+		if err != nil {
+			return fmt.Errorf("get current change id: %w", err)
+		}
+	}
+
+	params.ChangeID = changeID
+
+	cdc, err := iterator.NewCDC(ctx, iterator.CDCParams{
+		Driver:              s.driver,
+		DatabaseName:        s.config.Database,
+		EntityType:          s.config.EntityType,
+		EntityLabels:        s.config.EntityLabels,
+		LabelMatch:          s.config.LabelMatch,
+		EndpointLabels:      s.endpointLabels,
+		KeyProperties:       s.config.KeyProperties,
+		BatchSize:           s.config.BatchSize,
+		FieldCollision:      s.config.RelationshipFieldCollision,
+		PayloadFormat:       s.config.PayloadFormat,
+		Vectors:             s.config.Vectors(),
+		KeyByEndpoints:      s.config.KeyByEndpoints,
+		IncludeDeletedState: s.config.IncludeDeletedState,
+		IncludeElementID:    s.config.IncludeElementID,
+		ElementIDField:      s.config.ElementIDField,
+		LogRedactProperties: s.config.LogRedactProperties,
+		ChangeID:            changeID,
+		IncludeMetadata:     s.config.includeMetadata(),
+		URI:                 s.config.URI,
+		RateLimiter:         s.rateLimiter,
+		QueryTimeout:        s.config.QueryTimeout,
+		Bookmarks:           s.bookmarks,
+	})
+	if err != nil {
+		return fmt.Errorf("init cdc iterator: %w", err)
+	}
+
+	s.cdc = cdc
+
+	return nil
+}
+
+// changes returns an iterator that captures changes after the snapshot.
+func (s *Source) changes() Iterator {
+	if s.cdc != nil {
+		return s.cdc
+	}
+
+	return s.pollingSnapshot
+}
+
+// checkOrderingProperty samples the ordering property and logs warnings if it looks unreliable.
+// The check is only a diagnostic, so any error is logged instead of being returned.
+func (s *Source) checkOrderingProperty(ctx context.Context) {
+	stats, err := iterator.SampleOrderingProperty(ctx, s.driver,
+		s.config.Database, s.config.EntityLabels, s.config.LabelMatch, s.config.pagingProperty(), s.config.EntityType,
+		s.config.OrderingDirection, s.config.QueryTimeout,
+	)
+	if err != nil {
+		sdk.Logger(ctx).Warn().Err(err).Msg("failed to sample the ordering property")
+
+		return
+	}
+
+	for _, warning := range stats.Warnings(s.config.pagingProperty()) {
+		sdk.Logger(ctx).Warn().Msg(warning)
+	}
+}
+
+// snapshotParams returns params for the snapshot iterators based on the config.
+func (s *Source) snapshotParams(position *iterator.Position) iterator.SnapshotParams {
+	var relationshipCountsDepth int
+	if s.config.IncludeRelationshipCounts {
+		relationshipCountsDepth = s.config.RelationshipCountsDepth
+	}
+
+	return iterator.SnapshotParams{
+		Driver:                  s.driver,
+		OrderingProperty:        s.config.pagingProperty(),
+		KeyProperties:           s.config.KeyProperties,
+		EntityType:              s.config.EntityType,
+		EntityLabels:            s.config.EntityLabels,
+		LabelMatch:              s.config.LabelMatch,
+		EndpointLabels:          s.endpointLabels,
+		BatchSize:               s.config.BatchSize,
+		DatabaseName:            s.config.Database,
+		FieldCollision:          s.config.RelationshipFieldCollision,
+		RelationshipDirection:   s.config.RelationshipDirection,
+		PayloadFormat:           s.config.PayloadFormat,
+		Vectors:                 s.config.Vectors(),
+		RelationshipCountsDepth: relationshipCountsDepth,
+		SoftDeleteField:         s.config.SoftDeleteField,
+		SoftDeleteValue:         s.config.SoftDeleteValue,
+		IncludeDeletedState:     s.config.IncludeDeletedState,
+		ShardCount:              s.config.ShardCount,
+		ShardIndex:              s.config.ShardIndex,
+		SeedNodeMatch:           s.config.SeedNodeMatch,
+		MaxHops:                 s.config.MaxHops,
+		HistoryProperty:         s.config.HistoryProperty,
+		HistoryDecodeJSON:       s.config.HistoryDecodeJSON,
+		EmitEndpointsAsRecords:  s.config.EmitEndpointsAsRecords,
+		KeyByEndpoints:          s.config.KeyByEndpoints,
+		ExistingEndpointsOnly:   s.config.ExistingEndpointsOnly,
+		OrderingDirection:       s.config.OrderingDirection,
+		Subgraph:                len(s.config.SubgraphRelationshipTypes) > 0,
+		Query:                   s.config.Query,
+		IndexHint:               s.config.IndexProperty != "",
+		Filter:                  s.config.Filter,
+		DegreeFilter:            s.config.DegreeFilter,
+		FilterParams:            s.config.FilterParams,
+		Projections:             s.config.Projections,
+		IncludeElementID:        s.config.IncludeElementID,
+		ElementIDField:          s.config.ElementIDField,
+		LogRedactProperties:     s.config.LogRedactProperties,
+		AlignPositionsToBatches: s.config.AlignPositionsToBatches,
+		EmitOrder:               s.config.EmitOrder,
+		SnapshotOperation:       s.config.SnapshotOperation,
+		SnapshotByElementID:     s.config.SnapshotByElementID,
+		Sort:                    s.sort,
+		DetectDeletes:           s.config.DetectDeletes,
+		ReconcileInterval:       s.config.ReconcileInterval,
+		ReconcileMaxKeys:        s.config.ReconcileMaxKeys,
+		ResumeGrace:             s.config.ResumeGrace,
+		OrderingPropertyType:    s.config.OrderingPropertyType,
+		IncludeMetadata:         s.config.includeMetadata(),
+		URI:                     s.config.URI,
+		RateLimiter:             s.rateLimiter,
+		QueryTimeout:            s.config.QueryTimeout,
+		Bookmarks:               s.bookmarks,
+		Position:                position,
+	}
+}
+
+// changedWithinPosition returns a position that starts the capture from the beginning of the changedWithin window.
+// If the snapshot is enabled, the position is a snapshot one,
+// so the snapshot captures elements changed within the window and the polling starts after them.
+func (s *Source) changedWithinPosition(ctx context.Context) (*iterator.Position, error) {
+	start, err := iterator.ChangedWithinStart(ctx, s.driver,
+		s.config.Database, s.config.EntityLabels, s.config.LabelMatch, s.config.pagingProperty(), s.config.EntityType,
+		s.config.ChangedWithin, s.config.QueryTimeout,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get changed within start: %w", err)
+	}
+
+	mode := iterator.ModeSnapshotPolling
+	if s.config.Snapshot {
+		mode = iterator.ModeSnapshot
+	}
+
+	return &iterator.Position{
+		Version:            iterator.PositionVersion,
+		Mode:               mode,
+		LastProcessedValue: start,
+	}, nil
+}
+
 // read is a helper function that accepts an [Iterator] and do a common read logic.
 func read(ctx context.Context, iterator Iterator) (sdk.Record, error) {
 	hasNext, err := iterator.HasNext(ctx)
@@ -17,12 +17,16 @@ package source
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/brianvoe/gofakeit"
 	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/source/iterator"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"github.com/matryer/is"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
@@ -120,11 +124,1186 @@ func TestSource_Read_successResumeSnapshotNode(t *testing.T) {
 	is.Equal(err, sdk.ErrBackoffRetry)
 }
 
+func TestSource_Read_successSnapshotOnly(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeySnapshotOnly] = "true"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	createTestElement(ctx, t, 1, sourceConfig)
+
+	source := New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, nil))
+	t.Cleanup(func() {
+		is.NoErr(source.Teardown(ctx))
+	})
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationSnapshot)
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+
+	// the polling isn't started, so the elements created after the snapshot aren't captured
+	createTestElement(ctx, t, 2, sourceConfig)
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successCausalConsistency(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyCausalConsistency] = "true"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	createTestElement(ctx, t, 1, sourceConfig)
+
+	source := New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, nil))
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: float64(1)})
+	is.NoErr(source.Teardown(ctx))
+
+	// the position holds the bookmarks of the read, which the resumed source waits for
+	position, err := iterator.ParsePosition(record.Position)
+	is.NoErr(err)
+	is.True(len(position.Bookmarks) > 0)
+
+	createTestElement(ctx, t, 2, sourceConfig)
+
+	source = New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, record.Position))
+	t.Cleanup(func() {
+		is.NoErr(source.Teardown(ctx))
+	})
+
+	// the polling starts after the snapshot and goes on threading the bookmarks
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationCreate)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: float64(2)})
+
+	position, err = iterator.ParsePosition(record.Position)
+	is.NoErr(err)
+	is.True(len(position.Bookmarks) > 0)
+}
+
+func TestSource_Read_successResumeMidBatch(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyBatchSize] = "2"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for id := 1; id <= 3; id++ {
+		createTestElement(ctx, t, float64(id), sourceConfig)
+	}
+
+	source := New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, nil))
+
+	// read the first record of the first batch and stop the source in the middle of the batch
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: float64(1)})
+	is.NoErr(source.Teardown(ctx))
+
+	// create an element while the source is stopped
+	createTestElement(ctx, t, 4, sourceConfig)
+
+	// resume from the last emitted record and make sure there are no gaps or duplicates
+	source = New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, record.Position))
+	t.Cleanup(func() {
+		is.NoErr(source.Teardown(ctx))
+	})
+
+	expected := []struct {
+		id        float64
+		operation sdk.Operation
+	}{
+		{id: 2, operation: sdk.OperationSnapshot},
+		{id: 3, operation: sdk.OperationSnapshot},
+		{id: 4, operation: sdk.OperationCreate},
+	}
+
+	for _, exp := range expected {
+		record, err = source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Operation, exp.operation)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: exp.id})
+	}
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successResumeAtBatchBoundary(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyBatchSize] = "2"
+	sourceConfig[ConfigKeyAlignPositionsToBatches] = "true"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for id := 1; id <= 3; id++ {
+		createTestElement(ctx, t, float64(id), sourceConfig)
+	}
+
+	source := New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, nil))
+
+	// read the first record of the first batch and stop the source in the middle of the batch
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: float64(1)})
+	is.NoErr(source.Teardown(ctx))
+
+	// resume from the last emitted record, the source restarts at the beginning of the first batch
+	source = New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, record.Position))
+
+	for id := 1; id <= 2; id++ {
+		record, err = source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: float64(id)})
+	}
+
+	is.NoErr(source.Teardown(ctx))
+
+	// resume from the last record of the first batch, the source restarts at the second batch
+	source = New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, record.Position))
+	t.Cleanup(func() {
+		is.NoErr(source.Teardown(ctx))
+	})
+
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: float64(3)})
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successSnapshotByElementID(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyBatchSize] = "1"
+	sourceConfig[ConfigKeyKeyProperties] = "name"
+	sourceConfig[ConfigKeySnapshotByElementID] = "true"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	names := make(map[any]bool)
+	for id := 1; id <= 3; id++ {
+		element := createTestElement(ctx, t, float64(id), sourceConfig)
+		names[element["name"]] = true
+	}
+
+	source := New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, nil))
+	t.Cleanup(func() {
+		is.NoErr(source.Teardown(ctx))
+	})
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationSnapshot)
+	delete(names, record.Key.(sdk.StructuredData)["name"])
+
+	// mutate the ordering property in the middle of the snapshot,
+	// so the remaining elements would be skipped if the snapshot was paginated by it
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"MATCH (n:%s) SET n.%s = -n.%s", sourceConfig[config.KeyEntityLabels], testOrderingProperty, testOrderingProperty,
+	))
+
+	for range 2 {
+		record, err = source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Operation, sdk.OperationSnapshot)
+		delete(names, record.Key.(sdk.StructuredData)["name"])
+	}
+
+	is.Equal(len(names), 0)
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successSnapshotSortResume(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeySnapshotSort] = "priority:desc,createdAt:asc"
+	sourceConfig[ConfigKeyBatchSize] = "2"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(`UNWIND [
+		{id: 1.0, priority: 1, createdAt: 3},
+		{id: 2.0, priority: 2, createdAt: 2},
+		{id: 3.0, priority: 2, createdAt: 1},
+		{id: 4.0, priority: 1, createdAt: 1},
+		{id: 5.0, priority: 3, createdAt: 5}
+	] AS row CREATE (n:%s) SET n = row`, sourceConfig[config.KeyEntityLabels]))
+
+	source := New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, nil))
+
+	// read into the second batch and stop the source in the middle of it
+	var record sdk.Record
+	for _, id := range []float64{5, 3, 2} {
+		var err error
+
+		record, err = source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: id})
+	}
+
+	is.NoErr(source.Teardown(ctx))
+
+	// resume after the priority of 2 and the createdAt of 2,
+	// so the elements with a lower priority are captured in the ascending order of the createdAt
+	source = New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, record.Position))
+	t.Cleanup(func() {
+		is.NoErr(source.Teardown(ctx))
+	})
+
+	for _, id := range []float64{4, 1} {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Operation, sdk.OperationSnapshot)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: id})
+	}
+
+	_, err := source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successDeterministicOrder(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyKeyProperties] = "email"
+	sourceConfig[ConfigKeyDeterministicOrder] = "true"
+	sourceConfig[ConfigKeyBatchSize] = "2"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// all elements share the same ordering property value, so only the key makes their order reproducible
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(`UNWIND ["d@example.com", "b@example.com", "e@example.com",
+		"a@example.com", "c@example.com"] AS email CREATE (n:%s {id: 1.0, email: email})`,
+		sourceConfig[config.KeyEntityLabels]))
+
+	readKeys := func() []sdk.Data {
+		source := New()
+		is.NoErr(source.Configure(ctx, sourceConfig))
+		is.NoErr(source.Open(ctx, nil))
+
+		defer func() {
+			is.NoErr(source.Teardown(ctx))
+		}()
+
+		var keys []sdk.Data
+		for {
+			record, err := source.Read(ctx)
+			if errors.Is(err, sdk.ErrBackoffRetry) {
+				return keys
+			}
+
+			is.NoErr(err)
+			keys = append(keys, record.Key)
+		}
+	}
+
+	want := []sdk.Data{
+		sdk.StructuredData{"email": "a@example.com"},
+		sdk.StructuredData{"email": "b@example.com"},
+		sdk.StructuredData{"email": "c@example.com"},
+		sdk.StructuredData{"email": "d@example.com"},
+		sdk.StructuredData{"email": "e@example.com"},
+	}
+
+	// both runs emit the records in the same order of the keys
+	is.Equal(readKeys(), want)
+	is.Equal(readKeys(), want)
+}
+
+func TestSource_Read_successEntitiesResume(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+
+	companyConfig := prepareConfig(t, config.EntityTypeNode)
+	companyConfig[config.KeyEntityLabels] += "_company"
+	sourceConfig[ConfigKeyEntities+".companies.entityLabels"] = companyConfig[config.KeyEntityLabels]
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	is.NoErr(source.Configure(ctx, sourceConfig))
+
+	var personPayloads, companyPayloads []sdk.Data
+	for i := 1; i <= 2; i++ {
+		rawPerson, err := json.Marshal(createTestElement(ctx, t, float64(i), sourceConfig))
+		is.NoErr(err)
+
+		rawCompany, err := json.Marshal(createTestElement(ctx, t, float64(i), companyConfig))
+		is.NoErr(err)
+
+		personPayloads = append(personPayloads, sdk.RawData(rawPerson))
+		companyPayloads = append(companyPayloads, sdk.RawData(rawCompany))
+	}
+
+	is.NoErr(source.Open(ctx, nil))
+
+	// the entities are read in turns, starting with the one of the top-level config values
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Payload.After, personPayloads[0])
+
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Payload.After, companyPayloads[0])
+
+	is.NoErr(source.Teardown(ctx))
+
+	// the position holds the cursors of both entities, so each of them is resumed
+	is.NoErr(source.Open(ctx, record.Position))
+
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Payload.After, personPayloads[1])
+
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Payload.After, companyPayloads[1])
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+
+	is.NoErr(source.Teardown(ctx))
+}
+
+func TestSource_Open_positionMismatch(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for id := 1; id <= 2; id++ {
+		createTestElement(ctx, t, float64(id), sourceConfig)
+	}
+
+	source := New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, nil))
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.NoErr(source.Teardown(ctx))
+
+	// resume with the position of the original labels, but the config has different labels now
+	sourceConfig[config.KeyEntityLabels] += "_renamed"
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (:%s {%s: 1, name: 'Alice'})", sourceConfig[config.KeyEntityLabels], testOrderingProperty,
+	))
+
+	source = New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+
+	err = source.Open(ctx, record.Position)
+	is.True(errors.Is(err, iterator.ErrPositionMismatch))
+	is.NoErr(source.Teardown(ctx))
+
+	// with the restart behavior, the capture starts from scratch
+	sourceConfig[ConfigKeyPositionMismatch] = string(PositionMismatchRestart)
+
+	source = New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, record.Position))
+	t.Cleanup(func() {
+		is.NoErr(source.Teardown(ctx))
+	})
+
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationSnapshot)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: int64(1)})
+}
+
 func TestSource_Read_successSnapshotPollingNode(t *testing.T) {
 	is := is.New(t)
 
-	// prepare a config, configure and open a new source
-	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	// prepare a config, configure and open a new source
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	testNode := createTestElement(ctx, t, 1, sourceConfig)
+	rawTestNode, err := json.Marshal(testNode)
+	is.NoErr(err)
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationSnapshot)
+	is.Equal(record.Payload.After, sdk.RawData(rawTestNode))
+
+	testNode = createTestElement(ctx, t, 2, sourceConfig)
+	rawTestNode, err = json.Marshal(testNode)
+	is.NoErr(err)
+
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationCreate)
+	is.Equal(record.Payload.After, sdk.RawData(rawTestNode))
+}
+
+func TestSource_Read_successRelationshipCounts(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyIncludeRelationshipCounts] = "true"
+	sourceConfig[ConfigKeyRelationshipCountsDepth] = "2"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	// create the (1)-[]->(2)-[]->(3) graph
+	for id := 1; id <= 3; id++ {
+		createTestElement(ctx, t, float64(id), sourceConfig)
+	}
+
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"MATCH (a:%[1]s {id: 1}), (b:%[1]s {id: 2}), (c:%[1]s {id: 3}) CREATE (a)-[:KNOWS]->(b)-[:KNOWS]->(c)",
+		sourceConfig[config.KeyEntityLabels],
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	expectedCounts := []struct{ depth1, depth2 string }{{"1", "1"}, {"2", "0"}, {"1", "1"}}
+	for _, expected := range expectedCounts {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Metadata["neo4j.relationshipCount.depth1"], expected.depth1)
+		is.Equal(record.Metadata["neo4j.relationshipCount.depth2"], expected.depth2)
+	}
+}
+
+func TestSource_Read_successSoftDelete(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeySoftDeleteField] = "deleted"
+	sourceConfig[ConfigKeySoftDeleteValue] = "true"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	createTestElement(ctx, t, 1, sourceConfig)
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationSnapshot)
+
+	// flip the soft-delete flag and move the node forward, so the polling detects it
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"MATCH (n:%s {id: 1}) SET n.id = 2, n.deleted = true", sourceConfig[config.KeyEntityLabels],
+	))
+
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationDelete)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: int64(2)})
+}
+
+func TestSource_Read_successShards(t *testing.T) {
+	is := is.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyShardCount] = "2"
+
+	const elementsCount = 20
+	for id := 1; id <= elementsCount; id++ {
+		createTestElement(ctx, t, float64(id), sourceConfig)
+	}
+
+	// read all elements of each shard and make sure the shards don't overlap,
+	// and that the hash of element ids actually splits the elements between them
+	seen := make(map[any]int)
+	for _, shardIndex := range []string{"0", "1"} {
+		shardElements := 0
+
+		sourceConfig[ConfigKeyShardIndex] = shardIndex
+
+		source := New()
+		is.NoErr(source.Configure(ctx, sourceConfig))
+		is.NoErr(source.Open(ctx, nil))
+
+		for {
+			record, err := source.Read(ctx)
+			if errors.Is(err, sdk.ErrBackoffRetry) {
+				break
+			}
+			is.NoErr(err)
+
+			seen[record.Key.(sdk.StructuredData)[testOrderingProperty]]++
+			shardElements++
+		}
+
+		is.NoErr(source.Teardown(ctx))
+		is.True(shardElements > 0) // the shard is empty
+	}
+
+	is.Equal(len(seen), elementsCount)
+	for _, count := range seen {
+		is.Equal(count, 1)
+	}
+}
+
+func TestSource_Read_successChangedWithin(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyOrderingProperty] = "updatedAt"
+	sourceConfig[ConfigKeyKeyProperties] = testOrderingProperty
+	sourceConfig[ConfigKeyChangedWithin] = "24h"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	// only the node updated an hour ago is within the window
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (:%[1]s {id: 1, updatedAt: datetime() - duration('P2D')}), "+
+			"(:%[1]s {id: 2, updatedAt: datetime() - duration('PT1H')})",
+		sourceConfig[config.KeyEntityLabels],
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: int64(2)})
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successResumeGrace(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyOrderingProperty] = "updatedAt"
+	sourceConfig[ConfigKeyKeyProperties] = testOrderingProperty
+	sourceConfig[ConfigKeyResumeGrace] = "1m"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (:%[1]s {id: 1, updatedAt: datetime() - duration('PT10S')}), (:%[1]s {id: 2, updatedAt: datetime()})",
+		sourceConfig[config.KeyEntityLabels],
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	for _, id := range []int64{1, 2} {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: id})
+	}
+
+	// the first poll doesn't emit the elements captured by the snapshot again
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+
+	// the node is committed after the polled ones, but its timestamp precedes them
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (:%s {id: 3, updatedAt: datetime() - duration('PT5S')})",
+		sourceConfig[config.KeyEntityLabels],
+	))
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationCreate)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: int64(3)})
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successSeedNodeMatch(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeySeedNodeMatch] = fmt.Sprintf(":%s {id: 1}", sourceConfig[config.KeyEntityLabels])
+	sourceConfig[ConfigKeyMaxHops] = "2"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	// the chain is 1-2-3-4, so the node 4 is three hops away from the seed node 1,
+	// and the node 5 is not connected at all
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (:%[1]s {id: 1})-[:KNOWS]->(:%[1]s {id: 2})-[:KNOWS]->(:%[1]s {id: 3})-[:KNOWS]->(:%[1]s {id: 4}), "+
+			"(:%[1]s {id: 5})",
+		sourceConfig[config.KeyEntityLabels],
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	for _, id := range []int64{1, 2, 3} {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: id})
+	}
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successHistory(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyHistoryProperty] = "_history"
+	sourceConfig[ConfigKeyHistoryDecodeJSON] = "true"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	// simulate a history property maintained by an APOC trigger
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		`CREATE (:%s {id: 1, name: "Alexander", _history: [
+			'{"property":"name","old":null,"new":"Alex"}',
+			'{"property":"name","old":"Alex","new":"Alexander"}'
+		]})`,
+		sourceConfig[config.KeyEntityLabels],
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+
+	var payload map[string]any
+	is.NoErr(json.Unmarshal(record.Payload.After.Bytes(), &payload))
+	is.Equal(payload["_history"], []any{
+		map[string]any{"property": "name", "old": nil, "new": "Alex"},
+		map[string]any{"property": "name", "old": "Alex", "new": "Alexander"},
+	})
+}
+
+func TestSource_Read_successEmitEndpointsAsRecords(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeRelationship)
+	sourceConfig[ConfigKeyEmitEndpointsAsRecords] = "true"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (:Person {name: 'Alice'})-[:%s {id: 1}]->(:Person {name: 'Bob'})",
+		sourceConfig[config.KeyEntityLabels],
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// the source and target nodes are emitted before the relationship
+	for _, name := range []string{"Alice", "Bob"} {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{"name": name})
+		is.Equal(record.Metadata["neo4j.entityType"], string(config.EntityTypeNode))
+	}
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: int64(1)})
+	is.Equal(record.Metadata["neo4j.entityType"], string(config.EntityTypeRelationship))
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successExistingEndpointsOnly(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeRelationship)
+	sourceConfig[ConfigKeySnapshot] = "false"
+	sourceConfig[ConfigKeyExistingEndpointsOnly] = "true"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	// the existing relationship isn't captured, as the snapshot is disabled
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (:Person {name: 'Alice', id: 1})-[:%s {id: 2}]->(:Person {name: 'Bob', id: 2})",
+		sourceConfig[config.KeyEntityLabels],
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// the new relationship between the existing nodes is captured,
+	// while the one created along with its endpoints is not
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"MATCH (src:Person {name: 'Alice'}), (trgt:Person {name: 'Bob'}) CREATE (trgt)-[:%s {id: 3}]->(src)",
+		sourceConfig[config.KeyEntityLabels],
+	))
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (:Person {name: 'Carol', id: 4})-[:%s {id: 4}]->(:Person {name: 'Dave', id: 4})",
+		sourceConfig[config.KeyEntityLabels],
+	))
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationCreate)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: int64(3)})
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successOrderingDirectionDesc(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyOrderingDirection] = string(iterator.OrderingDirectionDesc)
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	createTestElement(ctx, t, 2, sourceConfig)
+	createTestElement(ctx, t, 3, sourceConfig)
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// the snapshot captures the elements in the descending order
+	for _, id := range []float64{3, 2} {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Operation, sdk.OperationSnapshot)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: id})
+	}
+
+	// the polling captures only the new element which value is less than the captured ones
+	createTestElement(ctx, t, 4, sourceConfig)
+	createTestElement(ctx, t, 1, sourceConfig)
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationCreate)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: float64(1)})
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successFilter(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyFilter] = "obj.id = 1 OR obj.id = toFloat($id)"
+	sourceConfig[ConfigKeyFilterParams+".id"] = "3"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	for _, id := range []float64{1, 2, 3} {
+		createTestElement(ctx, t, id, sourceConfig)
+	}
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// the element that doesn't match the filter is skipped
+	for _, id := range []float64{1, 3} {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: id})
+	}
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successDegreeFilter(t *testing.T) {
+	tests := []struct {
+		degreeFilter iterator.DegreeFilter
+		want         []float64
+	}{
+		{degreeFilter: iterator.DegreeFilterRootsOnly, want: []float64{1, 5}},
+		{degreeFilter: iterator.DegreeFilterLeavesOnly, want: []float64{3, 4, 5}},
+		{degreeFilter: iterator.DegreeFilterIsolatedOnly, want: []float64{5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.degreeFilter), func(t *testing.T) {
+			is := is.New(t)
+
+			sourceConfig := prepareConfig(t, config.EntityTypeNode)
+			sourceConfig[ConfigKeyDegreeFilter] = string(tt.degreeFilter)
+
+			source := New()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := source.Configure(ctx, sourceConfig)
+			is.NoErr(err)
+
+			// the tree is 1 -> 2 -> 3 and 1 -> 4, and 5 is a standalone node
+			runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+				"CREATE (n1:%[1]s {id: 1}), (n2:%[1]s {id: 2}), (n3:%[1]s {id: 3}), (n4:%[1]s {id: 4}), (:%[1]s {id: 5}), "+
+					"(n1)-[:CHILD]->(n2), (n2)-[:CHILD]->(n3), (n1)-[:CHILD]->(n4)",
+				sourceConfig[config.KeyEntityLabels],
+			))
+
+			err = source.Open(ctx, nil)
+			is.NoErr(err)
+
+			for _, id := range tt.want {
+				record, err := source.Read(ctx)
+				is.NoErr(err)
+				is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: id})
+			}
+
+			_, err = source.Read(ctx)
+			is.Equal(err, sdk.ErrBackoffRetry)
+		})
+	}
+}
+
+func TestSource_Read_successProjections(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyProjections+".name_upper"] = "toUpper(obj.name)"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	createTestElement(ctx, t, 1, sourceConfig)
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+
+	var payload map[string]any
+	err = json.Unmarshal(record.Payload.After.Bytes(), &payload)
+	is.NoErr(err)
+	is.Equal(payload["name_upper"], strings.ToUpper(payload["name"].(string)))
+}
+
+func TestSource_Read_successIncludeElementID(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyIncludeElementID] = "true"
+	sourceConfig[ConfigKeyElementIDField] = "_elementId"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	createTestElement(ctx, t, 1, sourceConfig)
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+
+	elementID := record.Metadata["neo4j.elementId"]
+	is.True(elementID != "")
+
+	var payload map[string]any
+	err = json.Unmarshal(record.Payload.After.Bytes(), &payload)
+	is.NoErr(err)
+	is.Equal(payload["_elementId"], elementID)
+}
+
+func TestSource_Open_failInvalidProjection(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyProjections+".name_upper"] = "unknownFunction(obj.name)"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(source.Teardown(ctx))
+	})
+
+	err = source.Open(ctx, nil)
+	is.True(errors.Is(err, iterator.ErrInvalidProjection))
+}
+
+func TestSource_Read_successLabelMatchAny(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyLabelMatch] = string(iterator.LabelMatchAny)
+
+	label := sourceConfig[config.KeyEntityLabels]
+	sourceConfig[config.KeyEntityLabels] = fmt.Sprintf("%[1]s_A,%[1]s_B", label)
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (:%[1]s_A {id: 1}), (:%[1]s_B {id: 2}), (:%[1]s_A:%[1]s_B {id: 3}), (:%[1]s {id: 4})", label,
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// the nodes having any of the labels are captured, and the metadata holds the configured labels
+	for _, id := range []float64{1, 2, 3} {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: id})
+		is.Equal(record.Metadata["neo4j.entityLabels"], fmt.Sprintf("%[1]s_A:%[1]s_B", label))
+	}
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successEndpointLabels(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeRelationship)
+	sourceConfig[ConfigKeyLabelMatch] = string(iterator.LabelMatchAny)
+
+	// the node labels are derived from the unique label, so the test doesn't capture other tests' elements
+	label := sourceConfig[config.KeyEntityLabels]
+	sourceConfig[config.KeyEntityLabels] = fmt.Sprintf("%[1]s_WORKS_AT,%[1]s_EMPLOYS", label)
+	sourceConfig[ConfigKeyEndpointLabelsSource+"."+label+"_WORKS_AT"] = label + "_Person"
+	sourceConfig[ConfigKeyEndpointLabelsTarget+"."+label+"_WORKS_AT"] = label + "_Company"
+	sourceConfig[ConfigKeyEndpointLabelsSource+"."+label+"_EMPLOYS"] = label + "_Company"
+	sourceConfig[ConfigKeyEndpointLabelsTarget+"."+label+"_EMPLOYS"] = label + "_Person"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	// each type is captured only in its own direction between the two labels
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (p:%[1]s_Person), (c:%[1]s_Company), "+
+			"(p)-[:%[1]s_WORKS_AT {id: 1}]->(c), (c)-[:%[1]s_WORKS_AT {id: 2}]->(p), "+
+			"(c)-[:%[1]s_EMPLOYS {id: 3}]->(p), (p)-[:%[1]s_EMPLOYS {id: 4}]->(c)",
+		label,
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	for _, id := range []float64{1, 3} {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: id})
+	}
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successRelationshipDirectionBoth(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeRelationship)
+	sourceConfig[ConfigKeyRelationshipDirection] = string(iterator.RelationshipDirectionBoth)
+
+	label := sourceConfig[config.KeyEntityLabels]
+	sourceConfig[ConfigKeyEndpointLabelsSource+"."+label] = label + "_Person"
+	sourceConfig[ConfigKeyEndpointLabelsTarget+"."+label] = label + "_Company"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	// the relationships between the person and the company are captured in both directions,
+	// but the one between the two people isn't
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (p:%[1]s_Person {name: 'Alice'}), (p2:%[1]s_Person {name: 'Bob'}), (c:%[1]s_Company {name: 'Acme'}), "+
+			"(p)-[:%[1]s {id: 1}]->(c), (c)-[:%[1]s {id: 2}]->(p), (p)-[:%[1]s {id: 3}]->(p2)",
+		label,
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	for _, want := range []struct {
+		id        float64
+		direction string
+		source    string
+		target    string
+	}{
+		{id: 1, direction: string(iterator.RelationshipDirectionOutgoing), source: "Alice", target: "Acme"},
+		{id: 2, direction: string(iterator.RelationshipDirectionIncoming), source: "Acme", target: "Alice"},
+	} {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: want.id})
+		is.Equal(record.Metadata["neo4j.relationshipDirection"], want.direction)
+
+		// the sourceNode and targetNode are the actual start and end nodes of the relationship
+		var payload map[string]any
+		is.NoErr(json.Unmarshal(record.Payload.After.Bytes(), &payload))
+		is.Equal(payload["sourceNode"].(map[string]any)["key"], map[string]any{"name": want.source})
+		is.Equal(payload["targetNode"].(map[string]any)["key"], map[string]any{"name": want.target})
+	}
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successDetectRelationshipDeletes(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeRelationship)
+	sourceConfig[ConfigKeyKeyByEndpoints] = "true"
+	sourceConfig[ConfigKeyDetectDeletes] = "true"
+	sourceConfig[ConfigKeyReconcileInterval] = "100ms"
 
 	source := New()
 
@@ -134,29 +1313,345 @@ func TestSource_Read_successSnapshotPollingNode(t *testing.T) {
 	err := source.Configure(ctx, sourceConfig)
 	is.NoErr(err)
 
+	label := sourceConfig[config.KeyEntityLabels]
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (p:%[1]s_Person {name: 'Alice'}), "+
+			"(c:%[1]s_Company {name: 'Acme'}), (c2:%[1]s_Company {name: 'Initech'}), "+
+			"(p)-[:%[1]s {id: 1}]->(c), (p)-[:%[1]s {id: 2}]->(c2)",
+		label,
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	for range 2 {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Operation, sdk.OperationSnapshot)
+	}
+
+	// the polling takes the baseline of the relationship keys on its first reconciliation
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf("MATCH ()-[r:%s {id: 1}]->() DELETE r", label))
+
+	// wait for the next reconciliation to be due
+	time.Sleep(200 * time.Millisecond)
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationDelete)
+	is.Equal(record.Key, sdk.StructuredData{"source_name": "Alice", "target_name": "Acme"})
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successDetectDeletesState(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyDetectDeletes] = "true"
+	sourceConfig[ConfigKeyIncludeDeletedState] = "true"
+	sourceConfig[ConfigKeyReconcileInterval] = "100ms"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	is.NoErr(source.Configure(ctx, sourceConfig))
+
 	testNode := createTestElement(ctx, t, 1, sourceConfig)
+
 	rawTestNode, err := json.Marshal(testNode)
 	is.NoErr(err)
 
+	is.NoErr(source.Open(ctx, nil))
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationSnapshot)
+
+	// the polling takes the baseline of the keys and states on its first reconciliation
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf("MATCH (n:%s) DELETE n", sourceConfig[config.KeyEntityLabels]))
+
+	// wait for the next reconciliation to be due
+	time.Sleep(200 * time.Millisecond)
+
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationDelete)
+	is.Equal(record.Payload.Before, sdk.RawData(rawTestNode))
+}
+
+func TestSource_Read_successPoint(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (:%s {id: 1, location: point({latitude: 50.45, longitude: 30.52})})",
+		sourceConfig[config.KeyEntityLabels],
+	))
+
 	err = source.Open(ctx, nil)
 	is.NoErr(err)
 
 	record, err := source.Read(ctx)
 	is.NoErr(err)
-	is.Equal(record.Operation, sdk.OperationSnapshot)
-	is.Equal(record.Payload.After, sdk.RawData(rawTestNode))
 
-	testNode = createTestElement(ctx, t, 2, sourceConfig)
-	rawTestNode, err = json.Marshal(testNode)
+	var payload map[string]any
+	is.NoErr(json.Unmarshal(record.Payload.After.Bytes(), &payload))
+	is.Equal(payload["location"], map[string]any{"srid": float64(4326), "x": 30.52, "y": 50.45})
+}
+
+func TestSource_Read_successIndexProperty(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyKeyProperties] = testOrderingProperty
+	sourceConfig[ConfigKeyIndexProperty] = "rank"
+
+	label := sourceConfig[config.KeyEntityLabels]
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	// the index is missing, so the source cannot be opened
+	err = source.Open(ctx, nil)
+	is.True(errors.Is(err, iterator.ErrIndexNotFound))
+	is.NoErr(source.Teardown(ctx))
+
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf("CREATE INDEX FOR (n:%s) ON (n.rank)", label))
+	runTestQuery(ctx, t, sourceConfig, "CALL db.awaitIndexes()")
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (:%[1]s {id: 1, rank: 20}), (:%[1]s {id: 2, rank: 10})", label,
+	))
+
+	// the planner honors the hint in the form the snapshot uses
+	neo4jDriver, err := neo4j.NewDriverWithContext(sourceConfig[config.KeyURI], testAuthToken)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(neo4jDriver.Close(context.Background()))
+	})
+
+	result, err := neo4j.ExecuteQuery(ctx, neo4jDriver, fmt.Sprintf(
+		"EXPLAIN MATCH (obj:%s) USING INDEX obj:%[1]s(rank) WHERE obj.rank > 0 RETURN obj ORDER BY obj.rank LIMIT 1",
+		label,
+	), nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(sourceConfig[config.KeyDatabase]))
+	is.NoErr(err)
+	is.True(planUsesIndex(result.Summary.Plan()))
+
+	source = New()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, nil))
+
+	// the elements are paged by the index property instead of the ordering one
+	for _, id := range []float64{2, 1} {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: id})
+	}
+}
+
+func TestSource_Read_successSubgraph(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[ConfigKeyKeyProperties] = testOrderingProperty
+	sourceConfig[ConfigKeySubgraphRelationshipTypes] = "KNOWS,FOLLOWS"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	// the relationship to the node without the entity labels is not a part of the subgraph
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"CREATE (a:%[1]s {id: 1})-[:KNOWS {since: 2020}]->(b:%[1]s {id: 2}), "+
+			"(b)-[:FOLLOWS]->(a), (a)-[:KNOWS]->(:Other {id: 3})",
+		sourceConfig[config.KeyEntityLabels],
+	))
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// the relationship to the node created after the start of the snapshot is not a part of the subgraph either
+	runTestQuery(ctx, t, sourceConfig, fmt.Sprintf(
+		"MATCH (a:%[1]s {id: 1}) CREATE (a)-[:KNOWS]->(:%[1]s {id: 4})",
+		sourceConfig[config.KeyEntityLabels],
+	))
+
+	// the nodes are emitted before the relationships
+	nodes := make(map[any]bool)
+	for range 2 {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Operation, sdk.OperationSnapshot)
+		is.Equal(record.Metadata["neo4j.entityType"], string(config.EntityTypeNode))
+
+		nodes[record.Key.(sdk.StructuredData)[testOrderingProperty]] = true
+	}
+
+	// each relationship references only the captured nodes
+	relationshipTypes := make([]string, 0, 2)
+	for range 2 {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Operation, sdk.OperationSnapshot)
+		is.Equal(record.Metadata["neo4j.entityType"], string(config.EntityTypeRelationship))
+
+		key := record.Key.(sdk.StructuredData)
+		is.True(nodes[key["source_"+testOrderingProperty]])
+		is.True(nodes[key["target_"+testOrderingProperty]])
+
+		relationshipTypes = append(relationshipTypes, record.Metadata["neo4j.entityLabels"])
+	}
+
+	slices.Sort(relationshipTypes)
+	is.Equal(relationshipTypes, []string{"FOLLOWS", "KNOWS"})
+
+	// the polling captures the new node
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationCreate)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: int64(4)})
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+}
+
+func TestSource_Read_successQuery(t *testing.T) {
+	is := is.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	elementConfig := prepareConfig(t, config.EntityTypeNode)
+	labels := elementConfig[config.KeyEntityLabels]
+
+	createTestElement(ctx, t, 1, elementConfig)
+	createTestElement(ctx, t, 2, elementConfig)
+
+	// the entityLabels are optional, as the query matches the elements
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	delete(sourceConfig, config.KeyEntityLabels)
+	sourceConfig[ConfigKeyQuery] = fmt.Sprintf("MATCH (obj:%s) WHERE obj.id > 1 RETURN obj", labels)
+
+	source := New()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	err = source.Open(ctx, nil)
 	is.NoErr(err)
 
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, sdk.OperationSnapshot)
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: float64(2)})
+	is.Equal(record.Metadata["neo4j.entityLabels"], labels)
+
+	// the polling applies the query as well
+	createTestElement(ctx, t, 3, elementConfig)
+
 	record, err = source.Read(ctx)
 	is.NoErr(err)
 	is.Equal(record.Operation, sdk.OperationCreate)
-	is.Equal(record.Payload.After, sdk.RawData(rawTestNode))
+	is.Equal(record.Key, sdk.StructuredData{testOrderingProperty: float64(3)})
+}
+
+func TestSource_Open_failDatabaseNotFound(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+	sourceConfig[config.KeyDatabase] = "nonexistent"
+
+	source := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(source.Teardown(ctx))
+	})
+
+	err = source.Open(ctx, nil)
+	is.True(errors.Is(err, config.ErrDatabaseNotFound))
+}
+
+func TestSampleOrderingProperty_poorOrderingProperty(t *testing.T) {
+	is := is.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceConfig := prepareConfig(t, config.EntityTypeNode)
+
+	// create elements which ordering property values are decreasing and not unique
+	createTestElement(ctx, t, 3, sourceConfig)
+	createTestElement(ctx, t, 2, sourceConfig)
+	createTestElement(ctx, t, 2, sourceConfig)
+	createTestElement(ctx, t, 1, sourceConfig)
+
+	driver, err := neo4j.NewDriverWithContext(testURI, testAuthToken)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(driver.Close(context.Background()))
+	})
+
+	stats, err := iterator.SampleOrderingProperty(ctx, driver, testDatabase,
+		[]string{sourceConfig[config.KeyEntityLabels]}, iterator.LabelMatchAll, testOrderingProperty,
+		config.EntityTypeNode, iterator.OrderingDirectionAsc, 0,
+	)
+	is.NoErr(err)
+	is.Equal(stats, iterator.OrderingStats{Total: 4, Distinct: 3, Inversions: 2})
+	is.Equal(len(stats.Warnings(testOrderingProperty)), 2)
 }
 
 // prepareConfig prepares a config with the required fields.
+// planUsesIndex checks if the plan or any of its children reads elements from an index.
+func planUsesIndex(plan neo4j.Plan) bool {
+	if plan == nil {
+		return false
+	}
+
+	if strings.Contains(plan.Operator(), "Index") {
+		return true
+	}
+
+	for _, child := range plan.Children() {
+		if planUsesIndex(child) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func prepareConfig(t *testing.T, entityType config.EntityType) map[string]string {
 	t.Helper()
 
@@ -215,3 +1710,21 @@ func createTestElement(ctx context.Context, t *testing.T, id float64, cfg map[st
 
 	return output
 }
+
+// runTestQuery runs a write Cypher query in Neo4j.
+func runTestQuery(ctx context.Context, t *testing.T, cfg map[string]string, query string) {
+	t.Helper()
+
+	is := is.New(t)
+
+	neo4jDriver, err := neo4j.NewDriverWithContext(cfg[config.KeyURI], testAuthToken)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(neo4jDriver.Close(context.Background()))
+	})
+
+	_, err = neo4j.ExecuteQuery(ctx, neo4jDriver, query, nil, neo4j.EagerResultTransformer,
+		neo4j.ExecuteQueryWithDatabase(cfg[config.KeyDatabase]),
+	)
+	is.NoErr(err)
+}
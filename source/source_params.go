@@ -9,6 +9,12 @@ import (
 
 func (Config) Parameters() map[string]sdk.Parameter {
 	return map[string]sdk.Parameter{
+		"alignPositionsToBatches": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will align record positions to boundaries of batches, so a resumed capture restarts at the beginning of the last batch that wasn't fully emitted.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
 		"auth.password": {
 			Default:     "",
 			Description: "The password to use when performing basic auth.",
@@ -21,6 +27,26 @@ func (Config) Parameters() map[string]sdk.Parameter {
 			Type:        sdk.ParameterTypeString,
 			Validations: []sdk.Validation{},
 		},
+		"auth.scheme": {
+			Default:     "basic",
+			Description: "The scheme of the authentication. If it's \"basic\", the username, password and realm are used, or no auth is performed if they're all empty, if it's \"bearer\", the token is used, if it's \"kerberos\", the ticket is used, and if it's \"none\", no auth is performed.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"basic", "bearer", "kerberos", "none"}},
+			},
+		},
+		"auth.ticket": {
+			Default:     "",
+			Description: "The base64-encoded ticket to use when performing kerberos auth.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"auth.token": {
+			Default:     "",
+			Description: "The token to use when performing bearer auth, e.g. an SSO access token.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
 		"auth.username": {
 			Default:     "",
 			Description: "The username to use when performing basic auth.",
@@ -33,23 +59,134 @@ func (Config) Parameters() map[string]sdk.Parameter {
 			Type:        sdk.ParameterTypeInt,
 			Validations: []sdk.Validation{
 				sdk.ValidationGreaterThan{Value: 0},
-				sdk.ValidationLessThan{Value: 100001},
 			},
 		},
+		"causalConsistency": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will pass the Neo4j bookmarks of each read to the next one and store them in positions, so reads routed to different cluster members never observe an older state than the previous reads did, including the reads after a restart.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"cdcEnabled": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will capture changes using the Neo4j native CDC after the snapshot instead of polling. It requires Neo4j 5.13+ with CDC enabled.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"changedWithin": {
+			Default:     "",
+			Description: "The window of the capture, e.g. \"24h\". If it's set and there's no position to resume from, only elements which ordering property is later than the current time minus the window are captured. The ordering property must be a date or a date-time.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"connectionAcquisitionTimeout": {
+			Default:     "1m",
+			Description: "The maximum amount of time to wait for a pooled connection to become available, including the time to establish a new connection.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"connectionLivenessCheckTimeout": {
+			Default:     "",
+			Description: "The duration after which an idle pooled connection is tested for liveness before it's reused. If it's not set, idle connections are not tested.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"connectionTimeout": {
+			Default:     "5s",
+			Description: "The maximum amount of time to wait for a TCP connection to a server to be established.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
 		"database": {
 			Default:     "neo4j",
 			Description: "The name of a database the connector should work with.",
 			Type:        sdk.ParameterTypeString,
 			Validations: []sdk.Validation{},
 		},
-		"entityLabels": {
+		"degreeFilter": {
+			Default:     "all",
+			Description: "Determines which nodes are captured by the snapshot and the polling depending on their relationships. If it's \"rootsOnly\", only nodes without incoming relationships are captured, if it's \"leavesOnly\", only nodes without outgoing relationships, and if it's \"isolatedOnly\", only nodes without any relationships. It's supported only if the entityType is node.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"all", "rootsOnly", "leavesOnly", "isolatedOnly"}},
+			},
+		},
+		"detectDeletes": {
+			Default:     "false",
+			Description: "Determines whether or not the polling will emit deletes of elements that disappeared. The polling periodically reads keys of all captured elements and compares them with the previous ones, which are held in memory, so it takes memory proportional to the number of captured elements. Elements deleted while the connector is stopped are not detected. Relationships keyed by the keyByEndpoints are reconciled by the properties of their endpoints.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"deterministicOrder": {
+			Default:     "false",
+			Description: "Determines whether or not the snapshot is sorted and paginated by the keyProperties in the ascending order instead of the orderingProperty, and elements with equal keys are sorted by their element ids, so every run of the snapshot over the same data emits records in the same order. Elements without any of the keyProperties aren't captured by the snapshot.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"elementIdField": {
 			Default:     "",
-			Description: "Holds a list of labels belonging to an entity.",
+			Description: "The name of a payload field the element id is put into if the includeElementId is enabled. If it's empty, the element id is put only into the metadata.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"emitEndpointsAsRecords": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will emit endpoint nodes of a relationship as separate records before the relationship record. It's supported only if the entityType is relationship.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"emitOrder": {
+			Default:     "ordering",
+			Description: "The order in which records of a batch are emitted. If it's \"key\", the records of each batch are sorted by their keys and positions are aligned to batches, but the key order isn't kept across batches.",
 			Type:        sdk.ParameterTypeString,
 			Validations: []sdk.Validation{
-				sdk.ValidationRequired{},
+				sdk.ValidationInclusion{List: []string{"ordering", "key"}},
+			},
+		},
+		"endpointLabels.source.*": {
+			Default:     "",
+			Description: "The labels the source node must have per relationship type, e.g. \"endpointLabels.source.WORKS_AT\" set to \"Person\". If a node must have multiple labels, they're separated by commas.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"endpointLabels.target.*": {
+			Default:     "",
+			Description: "The labels the target node must have per relationship type, e.g. \"endpointLabels.target.WORKS_AT\" set to \"Company\". If a node must have multiple labels, they're separated by commas.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"entities.*.entityLabels": {
+			Default:     "",
+			Description: "Holds a list of labels belonging to the entity.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"entities.*.entityType": {
+			Default:     "",
+			Description: "Defines an entity type of the entity.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"node", "relationship"}},
 			},
 		},
+		"entities.*.keyProperties": {
+			Default:     "",
+			Description: "The list of property names that are used for constructing a record key of the entity.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"entities.*.orderingProperty": {
+			Default:     "",
+			Description: "The name of a property that is used for ordering elements of the entity.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"entityLabels": {
+			Default:     "",
+			Description: "Holds a list of labels belonging to an entity.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
 		"entityType": {
 			Default:     "",
 			Description: "Defines an entity type the connector should work with.",
@@ -59,12 +196,135 @@ func (Config) Parameters() map[string]sdk.Parameter {
 				sdk.ValidationInclusion{List: []string{"node", "relationship"}},
 			},
 		},
+		"excludeOrderingPropertyFromKey": {
+			Default:     "false",
+			Description: "Determines whether or not the orderingProperty is kept out of the record key. If it's true, the keyProperties must be set and must not contain the orderingProperty.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"existingEndpointsOnly": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will capture only relationships between nodes that existed before them, i.e. which source and target nodes have the orderingProperty less than the relationship's one. It's supported only if the entityType is relationship.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"filter": {
+			Default:     "",
+			Description: "The Cypher predicate referencing the captured element as \"obj\", e.g. \"obj.active = true\". If it's set, only elements matching it are captured by the snapshot and the polling.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"filterParams.*": {
+			Default:     "",
+			Description: "The query parameters referenced by the filter, e.g. \"filterParams.status\" for \"$status\". Their values are passed as strings, the $opv, $opmv, $shardCount and $shardIndex parameters are reserved.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"historyDecodeJSON": {
+			Default:     "true",
+			Description: "Determines whether or not the connector will decode JSON string entries of the historyProperty.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"historyProperty": {
+			Default:     "",
+			Description: "The name of a property with a change history maintained by APOC triggers. If it's set, the property is normalized into a list of history entries in the payload.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"includeDeletedState": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will put the last known state of a deleted element into the payload before of delete records, of soft-deleted elements, of CDC deletes and of detected deletes, the detection keeps the states of the captured elements in memory then.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"includeElementId": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will put the Neo4j element id of the captured element into the \"neo4j.elementId\" metadata field of its record.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"includeMetadata": {
+			Default:     "",
+			Description: "The list of optional fields put into the metadata of records, so records of multiple sources can be routed and debugged. The supported fields are \"database\", \"uri\" without the password, \"entityType\" and \"orderingValue\" of snapshot and polling records, they're put into \"neo4j.<field>\" metadata fields.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"includeRelationshipCounts": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will attach counts of node relationships to record metadata. It's supported only if the entityType is node.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"indexProperty": {
+			Default:     "",
+			Description: "The name of an index-backed property the snapshot and the polling page elements by instead of the orderingProperty, with a hint that makes Neo4j use its index. A range index of the property must exist for the first of the entityLabels. The orderingProperty is still used as the record key if the keyProperties is empty.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"keyByEndpoints": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will compose keys of relationship records of the keys of their source and target nodes prefixed with \"source_\" and \"target_\" instead of the keyProperties. It's supported only if the entityType is relationship.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
 		"keyProperties": {
 			Default:     "",
-			Description: "The list of property names that are used for constructing a record key.",
+			Description: "The list of property names that are used for constructing a record key. If it's empty, the orderingProperty is used, unless the excludeOrderingPropertyFromKey is enabled.",
 			Type:        sdk.ParameterTypeString,
 			Validations: []sdk.Validation{},
 		},
+		"labelMatch": {
+			Default:     "all",
+			Description: "Determines whether the captured elements must have all of the entityLabels or any of them. If it's \"any\", nodes having at least one of the labels and relationships of any of the types are captured.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"all", "any"}},
+			},
+		},
+		"logRedactProperties": {
+			Default:     "",
+			Description: "Holds a list of property names which values are replaced with \"***\" in logged queries and parameters.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"maxBatchSize": {
+			Default:     "100000",
+			Description: "The upper bound of the batchSize, which guards against batches too big to be held in memory. If it's 0, the batchSize is unlimited.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{
+				sdk.ValidationGreaterThan{Value: -1},
+			},
+		},
+		"maxConnectionLifetime": {
+			Default:     "1h",
+			Description: "The maximum lifetime of a pooled connection, after which it's closed and replaced with a new one.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"maxConnectionPoolSize": {
+			Default:     "100",
+			Description: "The maximum number of connections in the pool per server.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{},
+		},
+		"maxHops": {
+			Default:     "1",
+			Description: "The max number of relationships between the seed node and a captured node.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{
+				sdk.ValidationGreaterThan{Value: 0},
+				sdk.ValidationLessThan{Value: 6},
+			},
+		},
+		"orderingDirection": {
+			Default:     "asc",
+			Description: "The direction in which values of the orderingProperty grow for new elements. If it's \"desc\", the snapshot and the polling capture elements in the descending order, so the polling captures elements which values are less than the ones captured before.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"asc", "desc"}},
+			},
+		},
 		"orderingProperty": {
 			Default:     "",
 			Description: "The name of a property that is used for ordering nodes or relationships when capturing a snapshot.",
@@ -73,12 +333,218 @@ func (Config) Parameters() map[string]sdk.Parameter {
 				sdk.ValidationRequired{},
 			},
 		},
+		"orderingPropertyType": {
+			Default:     "",
+			Description: "The type of the orderingProperty values, which positions are coerced to before the capture resumes, as positions are stored as JSON, which loses the types, e.g. of datetimes or integers beyond 2^53. It's one of \"int\", \"float\", \"string\" or \"datetime\". If it's empty, the values are kept as they're parsed. A position that cannot be coerced is treated as a position mismatch.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"int", "float", "string", "datetime"}},
+			},
+		},
+		"payloadFormat": {
+			Default:     "json",
+			Description: "The format which element properties are serialized into a record payload with. The Neo4j destination can consume only the \"json\" and \"jsonPretty\" formats.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"json", "jsonPretty", "msgpack"}},
+			},
+		},
+		"positionMismatch": {
+			Default:     "error",
+			Description: "Determines what to do if the position to resume from was created for a different orderingProperty or entityLabels. If it's \"error\", the connector fails, if it's \"restart\", the capture starts from scratch.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"error", "restart"}},
+			},
+		},
+		"projections.*": {
+			Default:     "",
+			Description: "The Cypher expressions computed by Neo4j for each captured element and merged into the payload by their names, e.g. \"projections.name_upper\" set to \"toUpper(obj.name)\". They reference the captured element as \"obj\", and its source and target nodes as \"src\" and \"trgt\" if the entityType is relationship.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"query": {
+			Default:     "",
+			Description: "The custom Cypher query that matches the captured elements instead of the entityLabels, so they're optional. It must return the captured element as \"obj\", and if the entityType is relationship, its source and target nodes as \"src\" and \"trgt\", e.g. \"MATCH (src:Person)-[obj:KNOWS]->(trgt) RETURN *\". The query is wrapped into a subquery, which results are paginated by the orderingProperty of the \"obj\".",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"queryTimeout": {
+			Default:     "",
+			Description: "The maximum amount of time a transaction that reads a batch or writes records may run, after which Neo4j terminates it and the error is treated as a transient one. If it's not set, the server's default transaction timeout applies.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"readRateLimit": {
+			Default:     "0",
+			Description: "The max number of queries per second that load batches of elements or changes, which can be fractional, e.g. 0.5 permits a batch every two seconds, so the capture keeps the load of the database bounded. The limit is shared by all entities. If it's 0, the queries are not limited.",
+			Type:        sdk.ParameterTypeFloat,
+			Validations: []sdk.Validation{},
+		},
+		"reconcileInterval": {
+			Default:     "1m",
+			Description: "The interval between the reconciliations of keys that detect deleted elements.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"reconcileMaxKeys": {
+			Default:     "100000",
+			Description: "The max number of keys held in memory for the delete detection. If there are more captured elements, the reconciliation is skipped with a warning.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{
+				sdk.ValidationGreaterThan{Value: 0},
+			},
+		},
+		"relationshipCountsDepth": {
+			Default:     "1",
+			Description: "The max depth of the relationship counts. If it's 1, only the number of relationships is attached, if it's 2, the number of two-relationship paths is attached as well.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"1", "2"}},
+			},
+		},
+		"relationshipDirection": {
+			Default:     "outgoing",
+			Description: "The direction relationships are matched in relative to their source node, which the endpointLabels.source constrain. If it's \"incoming\" or \"both\", the actual direction is put into the \"neo4j.relationshipDirection\" metadata field, and the sourceNode and targetNode are always the start and end nodes of the relationship.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"outgoing", "incoming", "both"}},
+			},
+		},
+		"relationshipFieldCollision": {
+			Default:     "prefix",
+			Description: "Determines what to do if a relationship property collides with the reserved sourceNode or targetNode fields. If it's \"prefix\", the property is renamed by adding the \"_\" prefix, if it's \"error\", the connector fails.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"prefix", "error"}},
+			},
+		},
+		"resolver": {
+			Default:     "",
+			Description: "Holds a list of \"advertised=actual\" address entries, e.g. \"neo4j.example.com:7687=10.0.0.1:7687\", the initial address of the uri is resolved to the actual addresses of its entries. The driver resolves only routed neo4j://, neo4j+s:// and neo4j+ssc:// uris, so it fails with other schemes.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"resumeGrace": {
+			Default:     "",
+			Description: "The window behind the last processed value the polling re-reads on each poll, e.g. \"5s\", so elements committed with ordering property values preceding the captured ones, e.g. due to a clock skew, are captured too. The elements emitted before are skipped. The ordering property must be a date or a date-time.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"seedNodeMatch": {
+			Default:     "",
+			Description: "The Cypher node pattern of a seed node, e.g. \":Person {email: 'alice@example.com'}\". If it's set, only nodes reachable from the seed node within the maxHops are captured. It's supported only if the entityType is node.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"shardCount": {
+			Default:     "1",
+			Description: "The number of shards the capture is split into, so multiple connector instances can capture disjoint slices of elements in parallel. Elements are assigned to shards by a hash of their element ids, which no index can serve, so each instance scans all elements on each query.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{
+				sdk.ValidationGreaterThan{Value: 0},
+			},
+		},
+		"shardIndex": {
+			Default:     "0",
+			Description: "The index of the shard this connector instance captures, it must be less than the shardCount.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{
+				sdk.ValidationGreaterThan{Value: -1},
+			},
+		},
+		"skipDatabaseCheck": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will skip checking that the database exists on start.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"skipOrderingCheck": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will skip sampling the ordering property on start. The sampling detects duplicate and non-monotonic values that can lead to missed elements.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
 		"snapshot": {
 			Default:     "true",
 			Description: "Determines whether or not the connector will take a snapshot of all nodes or relationships before starting polling mode.",
 			Type:        sdk.ParameterTypeBool,
 			Validations: []sdk.Validation{},
 		},
+		"snapshotByElementId": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will paginate the snapshot by immutable element ids instead of the orderingProperty, so changes of the property don't make the snapshot skip or re-read elements. The polling still uses the orderingProperty.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"snapshotCompleteMarker": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will emit a marker record once the snapshot is complete, so consumers know the capture of changes begins. The marker has neither a key nor a payload, and its \"neo4j.snapshotComplete\" metadata field is \"true\". It requires the snapshot.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"snapshotOnly": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will stop reading after the snapshot instead of starting polling mode, so it can run as a one-shot export. It requires the snapshot.",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"snapshotOperation": {
+			Default:     "snapshot",
+			Description: "The operation of records emitted by the snapshot. If it's \"create\", the snapshot records are emitted as creates, so sinks can't tell them apart from the polling ones.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{
+				sdk.ValidationInclusion{List: []string{"snapshot", "create"}},
+			},
+		},
+		"snapshotSort": {
+			Default:     "",
+			Description: "Comma-separated properties with their directions the snapshot is sorted and paginated by instead of the orderingProperty, e.g. \"priority:desc,createdAt:asc\". A property without a direction is sorted in the ascending one, and elements with equal values are sorted by their element ids. Elements without any of the properties aren't captured by the snapshot. The polling still uses the orderingProperty.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"softDeleteField": {
+			Default:     "",
+			Description: "The name of a property that marks an element as soft-deleted. If it's set, elements which property value is equal to the softDeleteValue are emitted as deletes.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"softDeleteValue": {
+			Default:     "true",
+			Description: "The value of the softDeleteField that marks an element as soft-deleted.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"startupRetryTimeout": {
+			Default:     "",
+			Description: "The maximum amount of time to retry connecting to Neo4j on start with an exponential backoff, so the connector waits for a server that is still starting up. If it's not set, connecting is not retried.",
+			Type:        sdk.ParameterTypeDuration,
+			Validations: []sdk.Validation{},
+		},
+		"subgraphRelationshipTypes": {
+			Default:     "",
+			Description: "The list of relationship types that are captured between the captured nodes after the snapshot of nodes, so the snapshot is a subgraph without relationships referencing nodes that weren't captured. It's supported only if the entityType is node and the snapshot is enabled.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"tls.caFile": {
+			Default:     "",
+			Description: "The path to a PEM file with certificates of the authorities the connector trusts, instead of the system ones, e.g. if the server certificate is signed by a custom CA.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"tls.enabled": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will encrypt the connection. If it's true, the \"bolt\" and \"neo4j\" URI schemes are replaced with \"bolt+s\" and \"neo4j+s\".",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
+		"tls.insecureSkipVerify": {
+			Default:     "false",
+			Description: "Determines whether or not the connector will skip verifying the server certificate. If it's true, the URI scheme is replaced with \"bolt+ssc\" or \"neo4j+ssc\".",
+			Type:        sdk.ParameterTypeBool,
+			Validations: []sdk.Validation{},
+		},
 		"uri": {
 			Default:     "",
 			Description: "The connection uri pointed to a Neo4j instance.",
@@ -87,5 +553,25 @@ func (Config) Parameters() map[string]sdk.Parameter {
 				sdk.ValidationRequired{},
 			},
 		},
+		"vectorDimensions": {
+			Default:     "0",
+			Description: "The number of dimensions each of the vectorProperties must have, a vector of another size fails the record. If it's 0, the dimensions are not validated.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{
+				sdk.ValidationGreaterThan{Value: -1},
+			},
+		},
+		"vectorProperties": {
+			Default:     "",
+			Description: "Holds a list of property names which values are vectors, e.g. node embeddings, that are read and written as lists of floats, including the ones stored as JSON strings.",
+			Type:        sdk.ParameterTypeString,
+			Validations: []sdk.Validation{},
+		},
+		"warmupConnections": {
+			Default:     "0",
+			Description: "The number of connections opened and verified concurrently on start to pre-fill the pool, so the first queries don't wait for connections to be established. If it's not set, no connections are opened in advance.",
+			Type:        sdk.ParameterTypeInt,
+			Validations: []sdk.Validation{},
+		},
 	}
 }
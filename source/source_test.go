@@ -17,14 +17,17 @@ package source
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/conduitio-labs/conduit-connector-neo4j/config"
+	"github.com/conduitio-labs/conduit-connector-neo4j/source/iterator"
 	"github.com/conduitio-labs/conduit-connector-neo4j/source/mock"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"github.com/matryer/is"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/mock/gomock"
 )
 
@@ -35,8 +38,6 @@ import (
 func TestSource_Configure(t *testing.T) {
 	t.Parallel()
 
-	s := Source{}
-
 	tests := []struct {
 		name          string
 		raw           map[string]string
@@ -66,6 +67,29 @@ func TestSource_Configure(t *testing.T) {
 			},
 			expectedError: "cannot parse 'batchSize' as int",
 		},
+		{
+			name: "fail_batchSize_exceeds_maxBatchSize",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyBatchSize:        "200001",
+				ConfigKeyMaxBatchSize:     "200000",
+			},
+			expectedError: errBatchSizeTooLarge.Error(),
+		},
+		{
+			name: "success_batchSize_unlimited",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyBatchSize:        "1000000",
+				ConfigKeyMaxBatchSize:     "0",
+			},
+		},
 		{
 			name: "fail_invalid_snapshot",
 			raw: map[string]string{
@@ -78,10 +102,362 @@ func TestSource_Configure(t *testing.T) {
 			},
 			expectedError: "cannot parse 'snapshot' as bool",
 		},
+		{
+			name: "fail_invalid_connectionTimeout",
+			raw: map[string]string{
+				config.KeyURI:               "bolt://localhost:7687",
+				config.KeyEntityType:        "node",
+				config.KeyEntityLabels:      "Person",
+				ConfigKeyOrderingProperty:   "created_at",
+				config.KeyConnectionTimeout: "ten seconds",
+			},
+			expectedError: "time: invalid duration",
+		},
+		{
+			name: "fail_invalid_maxConnectionPoolSize",
+			raw: map[string]string{
+				config.KeyURI:                   "bolt://localhost:7687",
+				config.KeyEntityType:            "node",
+				config.KeyEntityLabels:          "Person",
+				ConfigKeyOrderingProperty:       "created_at",
+				config.KeyMaxConnectionPoolSize: "-1",
+			},
+			expectedError: "maxConnectionPoolSize must be positive",
+		},
+		{
+			name: "fail_invalid_shardIndex",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person,Writer",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyShardCount:       "2",
+				ConfigKeyShardIndex:       "2",
+			},
+			expectedError: "shardIndex must be less than shardCount",
+		},
+		{
+			name: "fail_indexProperty_query",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyIndexProperty:    "updated_at",
+				ConfigKeyQuery:            "MATCH (obj:Person) RETURN obj",
+			},
+			expectedError: errIndexPropertyConflict.Error(),
+		},
+		{
+			name: "fail_detectDeletes_cdcEnabled",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyDetectDeletes:    "true",
+				ConfigKeyCDCEnabled:       "true",
+			},
+			expectedError: errDetectDeletesConflict.Error(),
+		},
+		{
+			name: "fail_snapshotOnly_cdcEnabled",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeySnapshot:         "true",
+				ConfigKeySnapshotOnly:     "true",
+				ConfigKeyCDCEnabled:       "true",
+			},
+			expectedError: errSnapshotOnlyConflict.Error(),
+		},
+		{
+			name: "success_includeMetadata",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyIncludeMetadata:  "database,uri,entityType,orderingValue",
+			},
+		},
+		{
+			name: "success_readRateLimit",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyReadRateLimit:    "0.5",
+			},
+		},
+		{
+			name: "fail_negative_readRateLimit",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyReadRateLimit:    "-1",
+			},
+			expectedError: errNegativeReadRateLimit.Error(),
+		},
+		{
+			name: "success_orderingPropertyType",
+			raw: map[string]string{
+				config.KeyURI:                 "bolt://localhost:7687",
+				config.KeyEntityType:          "node",
+				config.KeyEntityLabels:        "Person",
+				ConfigKeyOrderingProperty:     "created_at",
+				ConfigKeyOrderingPropertyType: "datetime",
+			},
+		},
+		{
+			name: "fail_includeMetadata_unsupported",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyIncludeMetadata:  "database,password",
+			},
+			expectedError: iterator.ErrUnsupportedMetadataField.Error(),
+		},
+		{
+			name: "fail_snapshotCompleteMarker_no_snapshot",
+			raw: map[string]string{
+				config.KeyURI:                   "bolt://localhost:7687",
+				config.KeyEntityType:            "node",
+				config.KeyEntityLabels:          "Person",
+				ConfigKeyOrderingProperty:       "created_at",
+				ConfigKeySnapshot:               "false",
+				ConfigKeySnapshotCompleteMarker: "true",
+			},
+			expectedError: errSnapshotCompleteMarkerNoSnapshot.Error(),
+		},
+		{
+			name: "success_snapshotSort",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeySnapshotSort:     "priority:desc,created_at",
+			},
+		},
+		{
+			name: "fail_snapshotSort_invalid",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeySnapshotSort:     "priority:down",
+			},
+			expectedError: iterator.ErrInvalidSort.Error(),
+		},
+		{
+			name: "fail_snapshotSort_snapshotByElementId",
+			raw: map[string]string{
+				config.KeyURI:                "bolt://localhost:7687",
+				config.KeyEntityType:         "node",
+				config.KeyEntityLabels:       "Person",
+				ConfigKeyOrderingProperty:    "created_at",
+				ConfigKeySnapshotSort:        "priority:desc",
+				ConfigKeySnapshotByElementID: "true",
+			},
+			expectedError: errSnapshotSortConflict.Error(),
+		},
+		{
+			name: "success_deterministicOrder",
+			raw: map[string]string{
+				config.KeyURI:               "bolt://localhost:7687",
+				config.KeyEntityType:        "node",
+				config.KeyEntityLabels:      "Person",
+				ConfigKeyOrderingProperty:   "created_at",
+				ConfigKeyKeyProperties:      "email",
+				ConfigKeyDeterministicOrder: "true",
+			},
+		},
+		{
+			name: "fail_deterministicOrder_snapshotSort",
+			raw: map[string]string{
+				config.KeyURI:               "bolt://localhost:7687",
+				config.KeyEntityType:        "node",
+				config.KeyEntityLabels:      "Person",
+				ConfigKeyOrderingProperty:   "created_at",
+				ConfigKeySnapshotSort:       "priority:desc",
+				ConfigKeyDeterministicOrder: "true",
+			},
+			expectedError: errDeterministicOrderConflict.Error(),
+		},
+		{
+			name: "fail_deterministicOrder_no_keyProperties",
+			raw: map[string]string{
+				config.KeyURI:                           "bolt://localhost:7687",
+				config.KeyEntityType:                    "relationship",
+				config.KeyEntityLabels:                  "KNOWS",
+				ConfigKeyOrderingProperty:               "created_at",
+				ConfigKeyKeyByEndpoints:                 "true",
+				ConfigKeyExcludeOrderingPropertyFromKey: "true",
+				ConfigKeyDeterministicOrder:             "true",
+			},
+			expectedError: errDeterministicOrderNoKeyProperties.Error(),
+		},
+		{
+			name: "fail_labelMatch_any_indexProperty",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person,Writer",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyIndexProperty:    "updated_at",
+				ConfigKeyLabelMatch:       "any",
+			},
+			expectedError: errLabelMatchAnyConflict.Error(),
+		},
+		{
+			name: "fail_emitOrder_key_emitEndpointsAsRecords",
+			raw: map[string]string{
+				config.KeyURI:                   "bolt://localhost:7687",
+				config.KeyEntityType:            "relationship",
+				config.KeyEntityLabels:          "KNOWS",
+				ConfigKeyOrderingProperty:       "created_at",
+				ConfigKeyEmitOrder:              "key",
+				ConfigKeyEmitEndpointsAsRecords: "true",
+			},
+			expectedError: errEmitOrderKeyEndpoints.Error(),
+		},
+		{
+			name: "fail_seedNodeMatch_relationship",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "relationship",
+				config.KeyEntityLabels:    "KNOWS",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeySeedNodeMatch:    ":Person {id: 1}",
+				ConfigKeyMaxHops:          "2",
+			},
+			expectedError: "seedNodeMatch is supported only if the entityType is node",
+		},
+		{
+			name: "fail_invalid_maxHops",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeySeedNodeMatch:    ":Person {id: 1}",
+				ConfigKeyMaxHops:          "10",
+			},
+			expectedError: "maxHops is out of range",
+		},
+		{
+			name: "fail_changedWithin_snapshotByElementId",
+			raw: map[string]string{
+				config.KeyURI:                "bolt://localhost:7687",
+				config.KeyEntityType:         "node",
+				config.KeyEntityLabels:       "Person",
+				ConfigKeyOrderingProperty:    "created_at",
+				ConfigKeyChangedWithin:       "24h",
+				ConfigKeySnapshotByElementID: "true",
+			},
+			expectedError: "changedWithin cannot be used with snapshotByElementId",
+		},
+		{
+			name: "fail_projections_cdcEnabled",
+			raw: map[string]string{
+				config.KeyURI:                        "bolt://localhost:7687",
+				config.KeyEntityType:                 "node",
+				config.KeyEntityLabels:               "Person",
+				ConfigKeyOrderingProperty:            "created_at",
+				ConfigKeyProjections + ".name_upper": "toUpper(obj.name)",
+				ConfigKeyCDCEnabled:                  "true",
+			},
+			expectedError: "projections cannot be used with cdcEnabled",
+		},
+		{
+			name: "fail_projections_reserved_field",
+			raw: map[string]string{
+				config.KeyURI:                        "bolt://localhost:7687",
+				config.KeyEntityType:                 "relationship",
+				config.KeyEntityLabels:               "KNOWS",
+				ConfigKeyOrderingProperty:            "created_at",
+				ConfigKeyProjections + ".sourceNode": "src.name",
+			},
+			expectedError: "invalid projection",
+		},
+		{
+			name: "fail_elementIdField_without_includeElementId",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyElementIDField:   "_elementId",
+			},
+			expectedError: "elementIdField requires includeElementId",
+		},
+		{
+			name: "fail_degreeFilter_relationship",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "relationship",
+				config.KeyEntityLabels:    "KNOWS",
+				ConfigKeyOrderingProperty: "created_at",
+				ConfigKeyDegreeFilter:     "rootsOnly",
+			},
+			expectedError: "degreeFilter is supported only if the entityType is node",
+		},
+		{
+			name: "fail_relationshipDirection_node",
+			raw: map[string]string{
+				config.KeyURI:                  "bolt://localhost:7687",
+				config.KeyEntityType:           "node",
+				config.KeyEntityLabels:         "Person",
+				ConfigKeyOrderingProperty:      "created_at",
+				ConfigKeyRelationshipDirection: "both",
+			},
+			expectedError: "relationshipDirection is supported only if the entityType is relationship",
+		},
+		{
+			name: "fail_resumeGrace_alignPositionsToBatches",
+			raw: map[string]string{
+				config.KeyURI:                    "bolt://localhost:7687",
+				config.KeyEntityType:             "node",
+				config.KeyEntityLabels:           "Person",
+				ConfigKeyOrderingProperty:        "updatedAt",
+				ConfigKeyResumeGrace:             "10s",
+				ConfigKeyAlignPositionsToBatches: "true",
+			},
+			expectedError: "resumeGrace cannot be used with",
+		},
+		{
+			name: "success_entityLabels_empty_elements",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    "Person,, Writer ",
+				ConfigKeyOrderingProperty: "created_at",
+			},
+		},
+		{
+			name: "fail_entityLabels_only_empty_elements",
+			raw: map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      "node",
+				config.KeyEntityLabels:    " , ,",
+				ConfigKeyOrderingProperty: "created_at",
+			},
+			expectedError: config.ErrNoEntityLabels.Error(),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			s := Source{}
+
 			err := s.Configure(context.Background(), tt.raw)
 			if err != nil {
 				if tt.expectedError == "" || !strings.Contains(err.Error(), tt.expectedError) {
@@ -90,6 +466,10 @@ func TestSource_Configure(t *testing.T) {
 					return
 				}
 			}
+
+			if err == nil && tt.expectedError != "" {
+				t.Errorf("Configure() error = nil, expectedError is %s", tt.expectedError)
+			}
 		})
 	}
 }
@@ -167,6 +547,62 @@ func TestSource_Read_successPolling(t *testing.T) {
 	is.Equal(r, record)
 }
 
+func TestSource_Read_successCDC(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	key := sdk.StructuredData{"id": 1}
+
+	record := sdk.Record{
+		Position:  sdk.Position(`{"mode":"cdc","changeId":"A1"}`),
+		Operation: sdk.OperationUpdate,
+		Key:       key,
+		Payload: sdk.Change{
+			Before: key,
+			After:  key,
+		},
+	}
+
+	snapshotIt := mock.NewMockIterator(ctrl)
+	snapshotIt.EXPECT().HasNext(ctx).Return(false, nil)
+
+	// the CDC takes the place of the polling once the snapshot is done
+	cdcIt := mock.NewMockIterator(ctrl)
+	cdcIt.EXPECT().HasNext(ctx).Return(true, nil)
+	cdcIt.EXPECT().Next(ctx).Return(record, nil)
+
+	s := Source{snapshot: snapshotIt, cdc: cdcIt}
+
+	r, err := s.Read(ctx)
+	is.NoErr(err)
+
+	is.Equal(r, record)
+}
+
+func TestSource_Read_snapshotOnly(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	snapshotIt := mock.NewMockIterator(ctrl)
+	snapshotIt.EXPECT().HasNext(ctx).Return(false, nil)
+
+	// there's neither the polling nor the CDC, so the source idles once the snapshot is done
+	s := Source{snapshot: snapshotIt, config: Config{SnapshotOnly: true}}
+
+	for range 2 {
+		_, err := s.Read(ctx)
+		is.Equal(err, sdk.ErrBackoffRetry)
+	}
+}
+
 func TestSource_Read_failHasNext(t *testing.T) {
 	t.Parallel()
 
@@ -201,3 +637,176 @@ func TestSource_Read_failNext(t *testing.T) {
 	_, err := s.Read(ctx)
 	is.True(err != nil)
 }
+
+func TestSource_Read_queryTimeout(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	timeoutErr := &neo4j.Neo4jError{Code: "Neo.ClientError.Transaction.TransactionTimedOutClientConfiguration"}
+
+	snapshotIt := mock.NewMockIterator(ctrl)
+	snapshotIt.EXPECT().HasNext(ctx).Return(false, fmt.Errorf("load batch: %w", timeoutErr))
+
+	s := Source{snapshot: snapshotIt}
+
+	// the timed out batch is retried with a backoff, and the snapshot isn't treated as complete
+	_, err := s.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+	is.Equal(s.snapshot, snapshotIt)
+}
+
+// The sdk.Util.ParseConfig has problems with concurrent access, so the t.Parallel isn't placed inside the loop.
+//
+//nolint:paralleltest,tparallel,nolintlint
+func TestSource_Configure_keyProperties(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		raw     map[string]string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "success_coinciding_by_default",
+			raw:  map[string]string{},
+			want: []string{"id"},
+		},
+		{
+			name: "success_coinciding_explicitly",
+			raw:  map[string]string{ConfigKeyKeyProperties: "id,email"},
+			want: []string{"id", "email"},
+		},
+		{
+			name: "success_distinct",
+			raw: map[string]string{
+				ConfigKeyKeyProperties:                  "email",
+				ConfigKeyExcludeOrderingPropertyFromKey: "true",
+			},
+			want: []string{"email"},
+		},
+		{
+			name: "success_excluded_keyByEndpoints",
+			raw: map[string]string{
+				config.KeyEntityType:                    string(config.EntityTypeRelationship),
+				ConfigKeyKeyByEndpoints:                 "true",
+				ConfigKeyExcludeOrderingPropertyFromKey: "true",
+			},
+		},
+		{
+			name:    "fail_excluded_no_keyProperties",
+			raw:     map[string]string{ConfigKeyExcludeOrderingPropertyFromKey: "true"},
+			wantErr: errNoKeyProperties,
+		},
+		{
+			name: "fail_excluded_coinciding",
+			raw: map[string]string{
+				ConfigKeyKeyProperties:                  "id,email",
+				ConfigKeyExcludeOrderingPropertyFromKey: "true",
+			},
+			wantErr: errOrderingPropertyInKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			raw := map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      string(config.EntityTypeNode),
+				config.KeyEntityLabels:    "Person",
+				ConfigKeyOrderingProperty: "id",
+			}
+			for key, value := range tt.raw {
+				raw[key] = value
+			}
+
+			s := Source{}
+
+			err := s.Configure(context.Background(), raw)
+			is.True(errors.Is(err, tt.wantErr))
+
+			if tt.wantErr == nil {
+				is.Equal(s.config.KeyProperties, tt.want)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest,tparallel,nolintlint
+func TestSource_Configure_endpointLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		raw     map[string]string
+		want    map[string]iterator.EndpointLabels
+		wantErr error
+	}{
+		{
+			name: "success_two_types",
+			raw: map[string]string{
+				ConfigKeyEndpointLabelsSource + ".WORKS_AT": "Person",
+				ConfigKeyEndpointLabelsTarget + ".WORKS_AT": "Company",
+				ConfigKeyEndpointLabelsTarget + ".KNOWS":    "Person, Writer",
+			},
+			want: map[string]iterator.EndpointLabels{
+				"WORKS_AT": {Source: []string{"Person"}, Target: []string{"Company"}},
+				"KNOWS":    {Target: []string{"Person", "Writer"}},
+			},
+		},
+		{
+			name: "success_empty",
+			raw:  map[string]string{},
+		},
+		{
+			name:    "fail_unknown_type",
+			raw:     map[string]string{ConfigKeyEndpointLabelsSource + ".LIKES": "Person"},
+			wantErr: errEndpointLabelsUnknownType,
+		},
+		{
+			name:    "fail_empty_label",
+			raw:     map[string]string{ConfigKeyEndpointLabelsTarget + ".KNOWS": "Person,"},
+			wantErr: errEndpointLabelsEmptyLabel,
+		},
+		{
+			name: "fail_node",
+			raw: map[string]string{
+				config.KeyEntityType:                     string(config.EntityTypeNode),
+				ConfigKeyEndpointLabelsSource + ".KNOWS": "Person",
+			},
+			wantErr: errEndpointLabelsConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			raw := map[string]string{
+				config.KeyURI:             "bolt://localhost:7687",
+				config.KeyEntityType:      string(config.EntityTypeRelationship),
+				config.KeyEntityLabels:    "KNOWS,WORKS_AT",
+				ConfigKeyLabelMatch:       "any",
+				ConfigKeyOrderingProperty: "id",
+			}
+			for key, value := range tt.raw {
+				raw[key] = value
+			}
+
+			s := Source{}
+
+			err := s.Configure(context.Background(), raw)
+			is.True(errors.Is(err, tt.wantErr))
+
+			if tt.wantErr == nil {
+				is.Equal(s.endpointLabels, tt.want)
+			}
+		})
+	}
+}